@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/cassette"
+	"github.com/ymatsukawa/jak/internal/engine"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// cassetteOptions holds the record and replay options shared by the bat and chain commands.
+type cassetteOptions struct {
+	// Record is the cassette file the performed requests are written to
+	Record string
+
+	// Replay is the cassette file requests are answered from
+	Replay string
+
+	// Match lists the request parts compared when replaying
+	Match string
+
+	// Strict fails requests that match no recorded interaction when replaying
+	Strict bool
+}
+
+// addCassetteFlags registers the record and replay flags on a command.
+//
+// Parameters:
+//   - cmd: Command to add the flags to
+//   - opts: Options the flags are parsed into
+func addCassetteFlags(cmd *cobra.Command, opts *cassetteOptions) {
+	cmd.Flags().StringVar(&opts.Record, "record", "", "record every request and response to a cassette file (e.g. cassette.json)")
+	cmd.Flags().StringVar(&opts.Replay, "replay", "", "answer requests from a cassette file instead of the network")
+	cmd.Flags().StringVar(&opts.Match, "match", cassette.DefaultMatch, "request parts matched when replaying: method, url, body, header:<name>")
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "fail requests that match no recorded interaction when replaying")
+}
+
+// NewCassetteClient creates the client for a run, recording or replaying as requested.
+// Without --record and --replay the client is built from the configuration as usual.
+//
+// Parameters:
+//   - config: Configuration containing transport settings
+//   - opts: Record and replay options
+//   - extra: Additional client options, e.g. a cookie jar
+//
+// Returns:
+//   - http.Client: Client for the run
+//   - func(): Function saving the recording, to be called when the run ends
+//   - error: Any error encountered while loading the cassette or creating the client
+func NewCassetteClient(config *rule.Config, opts *cassetteOptions, extra ...http.ClientOption) (http.Client, func(), error) {
+	if opts.Record != "" && opts.Replay != "" {
+		return nil, nil, fmt.Errorf("%w: --record and --replay cannot be combined", se.ErrCLIInput)
+	}
+	if opts.Strict && opts.Replay == "" {
+		return nil, nil, fmt.Errorf("%w: --strict requires --replay", se.ErrCLIInput)
+	}
+
+	if opts.Replay != "" {
+		matcher, err := cassette.ParseMatch(opts.Match)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: --match: %s", se.ErrCLIInput, err)
+		}
+		recording, err := cassette.Load(opts.Replay)
+		if err != nil {
+			return nil, nil, se.WrapError(err, "failed to load cassette")
+		}
+		return cassette.NewReplayer(recording, matcher, opts.Strict), func() {}, nil
+	}
+
+	client, err := engine.NewClientFromConfig(config, extra...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Record == "" {
+		return client, func() {}, nil
+	}
+
+	recorder := cassette.NewRecorder(client, apiKeyNames(config, rule.APIKeyInHeader), apiKeyNames(config, rule.APIKeyInQuery))
+	save := func() {
+		if err := recorder.Save(opts.Record); err != nil {
+			format.PrintError(se.WrapError(err, "failed to save cassette"))
+		}
+	}
+	return recorder, save, nil
+}
+
+// apiKeyNames returns the names of the headers or query parameters carrying API keys in the configuration.
+//
+// Parameters:
+//   - config: Configuration containing authentication settings
+//   - in: rule.APIKeyInHeader or rule.APIKeyInQuery
+//
+// Returns:
+//   - []string: Header or parameter names in order of first use
+func apiKeyNames(config *rule.Config, in string) []string {
+	var names []string
+	for i := range config.Request {
+		auth := config.AuthFor(&config.Request[i])
+		if auth == nil || auth.Type != rule.AuthTypeAPIKey {
+			continue
+		}
+		// API keys are sent as headers unless placed in the query string
+		placement := rule.APIKeyInHeader
+		if auth.In == rule.APIKeyInQuery {
+			placement = rule.APIKeyInQuery
+		}
+		if placement == in && !slices.Contains(names, auth.Name) {
+			names = append(names, auth.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+func TestAPIKeyNames(t *testing.T) {
+	config := &rule.Config{
+		Auth: &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "api_key", Value: "secret", In: rule.APIKeyInQuery},
+		Request: []rule.Request{
+			{Name: "config key"},
+			{Name: "request key", Auth: &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "token", Value: "secret", In: rule.APIKeyInQuery}},
+			{Name: "header key", Auth: &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "X-Key", Value: "secret"}},
+			{Name: "same key", Auth: &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "api_key", Value: "other", In: rule.APIKeyInQuery}},
+		},
+	}
+
+	assert.Equal(t, []string{"X-Key"}, apiKeyNames(config, rule.APIKeyInHeader))
+	assert.Equal(t, []string{"api_key", "token"}, apiKeyNames(config, rule.APIKeyInQuery))
+}
+
+func TestRunBatchRequest_RecordRedactsAPIKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "supersecret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	defer server.Close()
+
+	configPath := writeTestConfig(t, fmt.Sprintf(`
+base_url = "%s"
+
+[auth]
+type = "api_key"
+name = "X-Api-Key"
+value = "supersecret"
+in = "header"
+
+[[request]]
+name = "Get Users"
+method = "GET"
+path = "/users"
+`, server.URL))
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+
+	opts := &batchOptions{cassette: cassetteOptions{Record: cassettePath, Match: "method,url,header:X-Api-Key"}}
+	require.NoError(t, runBatchRequest(opts, []string{configPath}))
+
+	data, err := os.ReadFile(cassettePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "supersecret")
+	assert.Contains(t, string(data), `"X-Api-Key": "[REDACTED]"`)
+
+	// The redacted header still matches when replaying
+	server.Close()
+	config, err := LoadAndValidateConfig(configPath)
+	require.NoError(t, err)
+	client, _, err := NewCassetteClient(config, &cassetteOptions{Replay: cassettePath, Match: "method,url,header:X-Api-Key", Strict: true})
+	require.NoError(t, err)
+	resp, err := client.Do(jakhttp.NewRequest(server.URL+"/users", "GET", jakhttp.WithHeaderValue("X-Api-Key", "supersecret")))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
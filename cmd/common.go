@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
 	"github.com/ymatsukawa/jak/internal/rule"
 	se "github.com/ymatsukawa/jak/internal/sys_error"
 )
@@ -19,9 +21,10 @@ import (
 //   - *rule.Config: Validated configuration object
 //   - error: Any error encountered during loading or validation
 //
-// The function performs two steps:
+// The function performs three steps:
 //  1. Loads the configuration from the specified path
-//  2. Validates the loaded configuration
+//  2. Applies global command-line overrides such as --proxy
+//  3. Validates the loaded configuration
 //
 // If any step fails, an appropriate error is returned with context.
 func LoadAndValidateConfig(configPath string) (*rule.Config, error) {
@@ -30,6 +33,8 @@ func LoadAndValidateConfig(configPath string) (*rule.Config, error) {
 		return nil, se.WrapError(err, "failed to load config")
 	}
 
+	applyGlobalOptions(config, globalOpts)
+
 	if err := config.Validate(); err != nil {
 		return nil, se.WrapError(err, "invalid config")
 	}
@@ -37,6 +42,73 @@ func LoadAndValidateConfig(configPath string) (*rule.Config, error) {
 	return config, nil
 }
 
+// applyGlobalOptions overrides configuration settings with global command-line flags.
+// Only flags that were given a value take precedence over the configuration.
+//
+// Parameters:
+//   - config: Configuration object to update
+//   - opts: Global options parsed from the command line
+func applyGlobalOptions(config *rule.Config, opts *globalOptions) {
+	if opts.Proxy != "" {
+		config.Proxy = opts.Proxy
+	}
+	if opts.NoProxy != "" {
+		config.NoProxy = opts.NoProxy
+	}
+}
+
+// NewCookieJar creates the cookie jar for a run.
+// If --cookie-jar is given, cookies are loaded from that file and saved back by SaveCookieJar;
+// otherwise an in-memory jar is created when enabled is true.
+//
+// Parameters:
+//   - enabled: Whether an in-memory jar should be used without --cookie-jar
+//
+// Returns:
+//   - *http.CookieJar: Cookie jar, or nil if cookies are disabled
+//   - error: Any error encountered while loading the cookie file
+func NewCookieJar(enabled bool) (*http.CookieJar, error) {
+	if globalOpts.CookieJar != "" {
+		jar, err := http.LoadCookieJar(globalOpts.CookieJar)
+		if err != nil {
+			return nil, se.WrapError(err, "failed to load cookie jar")
+		}
+		return jar, nil
+	}
+	if enabled {
+		return http.NewCookieJar(), nil
+	}
+	return nil, nil
+}
+
+// cookieJarOptions returns the client options that attach a cookie jar.
+//
+// Parameters:
+//   - jar: Cookie jar, may be nil
+//
+// Returns:
+//   - []http.ClientOption: Options for the client, empty if jar is nil
+func cookieJarOptions(jar *http.CookieJar) []http.ClientOption {
+	if jar == nil {
+		return nil
+	}
+	return []http.ClientOption{http.WithCookieJar(jar)}
+}
+
+// SaveCookieJar persists the cookie jar if it is bound to a file.
+// Failures are reported but do not fail the command.
+//
+// Parameters:
+//   - jar: Cookie jar, may be nil
+func SaveCookieJar(jar *http.CookieJar) {
+	if jar == nil {
+		return
+	}
+	if err := jar.Save(); err != nil {
+		format.PrintError(se.WrapError(err, "failed to save cookie jar"))
+	}
+}
+
 // NewTimeoutContext creates a context with timeout based on the configured value.
 // If no timeout is specified in the configuration, DefaultTimeout is used.
 //
@@ -49,12 +121,20 @@ func LoadAndValidateConfig(configPath string) (*rule.Config, error) {
 //
 // The timeout value is taken from the config.Timeout field, which represents seconds.
 // If this value is 0, DefaultTimeout (30 seconds) is used instead.
+// The poll timeouts of polling requests and the pacing of delay_between and
+// rate_limit are added on top.
 func NewTimeoutContext(config *rule.Config) (context.Context, context.CancelFunc) {
 	timeout := time.Duration(config.Timeout) * time.Second
 	if timeout == 0 {
 		timeout = DefaultTimeout
 	}
 
+	// Polling requests may wait up to their poll timeout on top of the request timeout
+	timeout += config.PollTimeout()
+
+	// Pacing waits between requests must not use up the request timeout
+	timeout += config.PacingTimeout()
+
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	return ctx, cancel
 }
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// cookiesOptions holds configuration options for the cookies commands.
+type cookiesOptions struct {
+	// Domain limits clearing to cookies of a domain and its subdomains
+	Domain string
+}
+
+// newCookiesCmd creates and returns a cobra command for managing a persisted cookie jar.
+//
+// Returns:
+//   - *cobra.Command: Configured command object ready to be added to the root command
+//
+// The created command:
+//   - Has the name "cookies" with the subcommands "list" and "clear"
+//   - Reads the jar file from the argument or the global --cookie-jar flag
+func newCookiesCmd() *cobra.Command {
+	opts := &cookiesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "cookies",
+		Short: "manage stored cookies",
+		Long: `List or clear cookies persisted with --cookie-jar
+
+Examples:
+  jak cookies list session.json
+  jak cookies clear session.json --domain example.com`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [jar_file]",
+		Short: "list stored cookies",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCookiesList(args)
+		},
+	}
+
+	clearCmd := &cobra.Command{
+		Use:   "clear [jar_file]",
+		Short: "clear stored cookies",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCookiesClear(opts, args)
+		},
+	}
+	clearCmd.Flags().StringVar(&opts.Domain, "domain", "", "only clear cookies for this domain and its subdomains")
+
+	cmd.AddCommand(listCmd, clearCmd)
+	return cmd
+}
+
+// runCookiesList prints the cookies stored in a cookie jar file.
+//
+// Parameters:
+//   - args: Command-line arguments, where args[0] is the optional jar file path
+//
+// Returns:
+//   - error: Any error encountered while loading the jar
+func runCookiesList(args []string) error {
+	jar, err := loadCookieJarFile(args)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	format.PrintCookies(jar.All())
+	return nil
+}
+
+// runCookiesClear removes cookies from a cookie jar file.
+//
+// Parameters:
+//   - opts: Options including the optional domain filter
+//   - args: Command-line arguments, where args[0] is the optional jar file path
+//
+// Returns:
+//   - error: Any error encountered while loading or saving the jar
+func runCookiesClear(opts *cookiesOptions, args []string) error {
+	jar, err := loadCookieJarFile(args)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	removed := jar.Clear(opts.Domain)
+	if err := jar.Save(); err != nil {
+		wrappedErr := se.WrapError(err, "failed to save cookie jar")
+		format.PrintError(wrappedErr)
+		return wrappedErr
+	}
+
+	fmt.Println(format.ColorizeSuccess(fmt.Sprintf("Removed %d cookie(s)", removed)))
+	return nil
+}
+
+// loadCookieJarFile loads the cookie jar named by the argument or the --cookie-jar flag.
+//
+// Parameters:
+//   - args: Command-line arguments, where args[0] is the optional jar file path
+//
+// Returns:
+//   - *http.CookieJar: Loaded cookie jar
+//   - error: se.ErrCLIInput if no file is given, or any error encountered while loading
+func loadCookieJarFile(args []string) (*http.CookieJar, error) {
+	path := globalOpts.CookieJar
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		return nil, se.WrapError(se.ErrCLIInput, "cookie jar file is required (argument or --cookie-jar)")
+	}
+
+	jar, err := http.LoadCookieJar(path)
+	if err != nil {
+		return nil, se.WrapError(err, "failed to load cookie jar")
+	}
+	return jar, nil
+}
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/diff"
+	"github.com/ymatsukawa/jak/internal/engine"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// diffOptions holds configuration options specific to the diff command.
+type diffOptions struct {
+	// EnvA is the name of the first environment, or its base URL
+	EnvA string
+
+	// EnvB is the name of the second environment, or its base URL
+	EnvB string
+
+	// Headers overrides the compared response headers
+	Headers []string
+
+	// Ignore adds JSON paths left out of body comparisons
+	Ignore []string
+}
+
+// envResponse is the outcome of a request in one environment.
+type envResponse struct {
+	// method is the HTTP method
+	method string
+
+	// url is the full URL of the request
+	url string
+
+	// resp is the response, nil if the request failed
+	resp *http.Response
+
+	// body is the response body
+	body []byte
+
+	// err is the error of a failed request
+	err error
+}
+
+// newDiffCmd creates and returns a cobra command for comparing two environments.
+// The command requires exactly one argument: the path to the configuration file.
+//
+// Returns:
+//   - *cobra.Command: Configured command object ready to be added to the root command
+//
+// The created command:
+//   - Has the name "diff" with usage "diff [config_file]"
+//   - Accepts exactly one argument (the configuration file path)
+//   - When executed, calls runDiff with parsed options and arguments
+func newDiffCmd() *cobra.Command {
+	opts := &diffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff [config_file]",
+		Short: "compare two environments",
+		Long: `Execute every request defined in configuration file against two environments and
+report the differences in status, selected headers and JSON bodies.
+
+Environments are [env.<name>] tables with a base_url, or base URLs given directly.
+JSON bodies are compared structurally, so key order and formatting do not matter;
+values that differ between environments anyway are left out with [diff] ignore paths
+or --ignore. Requests are sent sequentially, all of them to A before B.
+
+Examples:
+  jak diff config.toml --env-a legacy --env-b new
+  jak diff config.toml --env-a https://old.example.com --env-b https://new.example.com --ignore meta.generated_at`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(opts, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.EnvA, "env-a", "", "first environment: name of an [env.<name>] table or a base URL")
+	cmd.Flags().StringVar(&opts.EnvB, "env-b", "", "second environment: name of an [env.<name>] table or a base URL")
+	cmd.Flags().StringSliceVar(&opts.Headers, "header", nil, "response headers to compare, comma separated (default Content-Type)")
+	cmd.Flags().StringSliceVar(&opts.Ignore, "ignore", nil, "JSON paths left out of body comparisons, comma separated (e.g. data.#.id)")
+
+	return cmd
+}
+
+// runDiff compares the responses of two environments.
+// This is the main function executed when the "diff" command is invoked.
+//
+// Parameters:
+//   - opts: Diff-specific options
+//   - args: Command-line arguments, where args[0] is the configuration file path
+//
+// Returns:
+//   - error: Any error encountered while loading the configuration or resolving the environments,
+//     or se.ErrResponsesDiffer if any request got different responses
+//
+// The function performs the following steps:
+//  1. Loads and validates the configuration from the specified path
+//  2. Resolves the base URLs of both environments
+//  3. Executes all requests against environment A, then against environment B
+//  4. Compares and prints the responses of every request, including the full
+//     responses of divergent requests in verbose mode
+//  5. Prints a summary listing the divergent endpoints and fails if there are any
+func runDiff(opts *diffOptions, args []string) error {
+	configPath := args[0]
+	if configPath == "" || opts.EnvA == "" || opts.EnvB == "" {
+		err := fmt.Errorf("%w: --env-a and --env-b are required", se.ErrCLIInput)
+		format.PrintError(err)
+		return err
+	}
+
+	config, err := LoadAndValidateConfig(configPath)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	baseA, err := resolveEnvironment(config, opts.EnvA)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	baseB, err := resolveEnvironment(config, opts.EnvB)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	label := func(env, baseUrl string) string {
+		if env == baseUrl {
+			return env
+		}
+		return fmt.Sprintf("%s (%s)", env, baseUrl)
+	}
+	fmt.Println(format.ColorizeInfo(fmt.Sprintf("Comparing %s with %s", label(opts.EnvA, baseA), label(opts.EnvB, baseB))))
+
+	responsesA, order, err := collectResponses(config.WithBaseUrl(baseA))
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	responsesB, orderB, err := collectResponses(config.WithBaseUrl(baseB))
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	for _, name := range orderB {
+		if _, ok := responsesA[name]; !ok {
+			order = append(order, name)
+		}
+	}
+
+	compareOpts := diffCompareOptions(config, opts)
+
+	var results []format.DiffResult
+	for _, name := range order {
+		a, b := responsesA[name], responsesB[name]
+		if a == nil {
+			a = &envResponse{method: b.method, url: b.url, err: fmt.Errorf("request was not executed")}
+		}
+		if b == nil {
+			b = &envResponse{method: a.method, url: a.url, err: fmt.Errorf("request was not executed")}
+		}
+
+		result := format.DiffResult{
+			Name:   name,
+			Method: a.method,
+			Path:   strings.TrimPrefix(a.url, strings.TrimSuffix(baseA, "/")),
+			Result: diff.Compare(a.toDiff(opts.EnvA), b.toDiff(opts.EnvB), compareOpts),
+		}
+		results = append(results, result)
+		format.PrintComparison(result)
+
+		if globalOpts.Verbose && !result.Result.Identical() {
+			for _, env := range []struct {
+				name string
+				resp *envResponse
+			}{{opts.EnvA, a}, {opts.EnvB, b}} {
+				if env.resp.resp != nil {
+					fmt.Println(format.ColorizeHeader(env.name + ":"))
+					format.PrintResponse(env.resp.resp)
+				}
+			}
+		}
+	}
+
+	format.PrintDiffSummary(opts.EnvA, opts.EnvB, results)
+
+	divergent := 0
+	for _, result := range results {
+		if !result.Result.Identical() {
+			divergent++
+		}
+	}
+	if divergent > 0 {
+		return fmt.Errorf("%w: %d of %d requests differ", se.ErrResponsesDiffer, divergent, len(results))
+	}
+	return nil
+}
+
+// resolveEnvironment returns the base URL of an environment.
+//
+// Parameters:
+//   - config: Configuration containing the [env.<name>] tables
+//   - env: Environment name or base URL
+//
+// Returns:
+//   - string: Base URL of the environment
+//   - error: Error if the name is neither a configured environment nor a URL
+func resolveEnvironment(config *rule.Config, env string) (string, error) {
+	if environment, ok := config.Env[env]; ok {
+		return environment.BaseUrl, nil
+	}
+	if strings.Contains(env, "://") {
+		return env, nil
+	}
+
+	names := config.EnvironmentNames()
+	if len(names) == 0 {
+		return "", fmt.Errorf("%w: unknown environment '%s'; define [env.%s] with a base_url or pass a URL", se.ErrCLIInput, env, env)
+	}
+	return "", fmt.Errorf("%w: unknown environment '%s' (configured: %s)", se.ErrCLIInput, env, strings.Join(names, ", "))
+}
+
+// diffCompareOptions determines the compared headers and ignored paths.
+// --header replaces the configured headers; --ignore adds to the configured paths.
+//
+// Parameters:
+//   - config: Configuration containing the [diff] settings
+//   - opts: Diff-specific options
+//
+// Returns:
+//   - diff.Options: Options for comparing responses
+func diffCompareOptions(config *rule.Config, opts *diffOptions) diff.Options {
+	compareOpts := diff.Options{Headers: diff.DefaultHeaders}
+	if config.Diff != nil {
+		if len(config.Diff.Headers) > 0 {
+			compareOpts.Headers = config.Diff.Headers
+		}
+		compareOpts.Ignore = append(compareOpts.Ignore, config.Diff.Ignore...)
+	}
+	if len(opts.Headers) > 0 {
+		compareOpts.Headers = opts.Headers
+	}
+	compareOpts.Ignore = append(compareOpts.Ignore, opts.Ignore...)
+	return compareOpts
+}
+
+// collectResponses executes all requests of a configuration and keeps their responses.
+// Failed requests do not stop the run.
+//
+// Parameters:
+//   - config: Configuration with the base URL of the environment
+//
+// Returns:
+//   - map[string]*envResponse: Outcome of each request by request name
+//   - []string: Request names in execution order
+//   - error: Any error encountered while creating the client or loading data
+func collectResponses(config *rule.Config) (map[string]*envResponse, []string, error) {
+	config.IgnoreFail = true
+
+	ctx, cancel := NewTimeoutContext(config)
+	defer cancel()
+
+	// Each environment has its own cookies
+	var jar *http.CookieJar
+	if config.CookiesEnabled(false) {
+		jar = http.NewCookieJar()
+	}
+	client, err := engine.NewClientFromConfig(config, cookieJarOptions(jar)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	executor := engine.NewExecutor(ctx).WithClient(client)
+
+	responses := make(map[string]*envResponse)
+	var order []string
+
+	// Keep the response and its body before the executor reports the result
+	executor.SetResponseChecker(func(req *rule.Request, resp *http.Response) error {
+		var body []byte
+		if resp.Body != nil {
+			var err error
+			body, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return se.WrapError(err, "failed to read response body")
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		responses[req.Name] = &envResponse{resp: resp, body: body}
+		return nil
+	})
+	executor.SetResultCollector(func(name, method, url string, statusCode int, reqErr error, duration time.Duration, timing *http.Timing) {
+		response, ok := responses[name]
+		if !ok {
+			response = &envResponse{}
+			responses[name] = response
+		}
+		response.method = method
+		response.url = url
+		response.err = reqErr
+		order = append(order, name)
+	})
+
+	if err := executor.ExecuteBatchSequential(config); err != nil {
+		return nil, nil, se.WrapError(err, "failed to execute requests")
+	}
+	return responses, order, nil
+}
+
+// toDiff converts the outcome of a request for comparison.
+//
+// Parameters:
+//   - env: Name of the environment
+//
+// Returns:
+//   - diff.Response: Response to compare
+func (r *envResponse) toDiff(env string) diff.Response {
+	response := diff.Response{Env: env, Body: r.body, Err: r.err}
+	if r.resp != nil {
+		response.StatusCode = r.resp.StatusCode
+		response.Header = r.resp.Header
+	}
+	return response
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+func TestRunDiff(t *testing.T) {
+	// newServer answers every request with the given version
+	newServer := func(version int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"version": %d}`, version)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	legacy, same, changed := newServer(1), newServer(1), newServer(2)
+
+	configPath := writeTestConfig(t, fmt.Sprintf(`
+base_url = "%s"
+
+[[request]]
+name = "Get Version"
+method = "GET"
+path = "/version"
+`, legacy.URL))
+
+	// Identical responses succeed
+	require.NoError(t, runDiff(&diffOptions{EnvA: legacy.URL, EnvB: same.URL}, []string{configPath}))
+
+	// Divergent responses fail the command
+	err := runDiff(&diffOptions{EnvA: legacy.URL, EnvB: changed.URL}, []string{configPath})
+	assert.ErrorIs(t, err, se.ErrResponsesDiffer)
+	assert.ErrorContains(t, err, "1 of 1 requests differ")
+
+	// Ignored paths do not count as differences
+	require.NoError(t, runDiff(&diffOptions{EnvA: legacy.URL, EnvB: changed.URL, Ignore: []string{"version"}}, []string{configPath}))
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/engine"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/load"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// loadOptions holds configuration options specific to the load command.
+type loadOptions struct {
+	// RPS is the target request rate
+	RPS int
+
+	// Duration is how long the test runs
+	Duration time.Duration
+
+	// Users is the number of virtual users
+	Users int
+
+	// Model is the load model ("open" or "closed")
+	Model string
+
+	// Stages is the ramp specification, e.g. "10s:50,1m:50,10s:0"
+	Stages string
+}
+
+// newLoadCmd creates and returns a cobra command for load testing.
+// The command requires exactly one argument: the path to the configuration file.
+//
+// Returns:
+//   - *cobra.Command: Configured command object ready to be added to the root command
+//
+// The created command:
+//   - Has the name "load" with usage "load [config_file]"
+//   - Accepts exactly one argument (the configuration file path)
+//   - When executed, calls runLoad with parsed options and arguments
+func newLoadCmd() *cobra.Command {
+	opts := &loadOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "load [config_file]",
+		Short: "load test",
+		Long: `Replay the requests defined in configuration file under load and report throughput,
+error rate and latency percentiles.
+
+The open model starts requests at --rps regardless of response times; --users caps the
+requests in flight. The closed model runs --users virtual users that each send the next
+request when the previous one completes; --rps then caps their combined rate.
+Stages ramp the rate (open) or the active users (closed), e.g. --stages 10s:50,1m:50,10s:0`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLoad(opts, args)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.RPS, "rps", 0, "target requests per second")
+	cmd.Flags().DurationVar(&opts.Duration, "duration", 0, "test duration (default 10s without stages)")
+	cmd.Flags().IntVar(&opts.Users, "users", 0, "number of virtual users (default 10)")
+	cmd.Flags().StringVar(&opts.Model, "model", "", `load model: "open" or "closed" (default open with --rps, closed otherwise)`)
+	cmd.Flags().StringVar(&opts.Stages, "stages", "", "ramp stages as duration:target pairs, e.g. 10s:50,1m:50,10s:0")
+
+	return cmd
+}
+
+// runLoad runs a load test against the requests of the configuration file.
+// This is the main function executed when the "load" command is invoked.
+//
+// Parameters:
+//   - opts: Load-specific options
+//   - args: Command-line arguments, where args[0] is the configuration file path
+//
+// Returns:
+//   - error: Any error encountered while preparing the test
+//
+// The function performs the following steps:
+//  1. Loads and validates the configuration from the specified path
+//  2. Builds the load options; the config timeout limits each request
+//  3. Creates a client with a connection pool sized for the virtual users
+//  4. Runs the test, printing progress every second, until it ends or is interrupted
+//  5. Prints the load test report
+func runLoad(opts *loadOptions, args []string) error {
+	configPath := args[0]
+	if configPath == "" {
+		return se.ErrCLIInput
+	}
+
+	config, err := LoadAndValidateConfig(configPath)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	stages, err := load.ParseStages(opts.Stages)
+	if err != nil {
+		err = se.WrapError(err, "invalid stages")
+		format.PrintError(err)
+		return err
+	}
+
+	loadOpts := load.Options{
+		Model:          load.Model(opts.Model),
+		RPS:            opts.RPS,
+		Users:          opts.Users,
+		Duration:       opts.Duration,
+		Stages:         stages,
+		RequestTimeout: time.Duration(config.Timeout) * time.Second,
+	}
+
+	// Create cookie jar, only used when enabled in config or persisted to a file
+	jar, err := NewCookieJar(config.CookiesEnabled(false))
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer SaveCookieJar(jar)
+
+	// Keep enough idle connections for every user to reuse its own
+	poolSize := opts.Users
+	if poolSize == 0 {
+		poolSize = load.DefaultUsers
+	}
+	clientOpts := append(cookieJarOptions(jar), http.WithMaxIdleConnsPerHost(poolSize))
+
+	client, err := engine.NewClientFromConfig(config, clientOpts...)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	runner := load.NewRunner(config, loadOpts).WithClient(client)
+	runner.SetProgressCollector(format.PrintLoadProgress)
+
+	// Stop early on Ctrl+C and still print the report
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := runner.Run(ctx)
+	if err != nil {
+		err = se.WrapError(err, "failed to run load test")
+		format.PrintError(err)
+		return err
+	}
+
+	format.PrintLoadReport(report)
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/capture"
+	"github.com/ymatsukawa/jak/internal/format"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// recordOptions holds configuration options specific to the record command.
+type recordOptions struct {
+	// Listen is the address the proxy listens on
+	Listen string
+
+	// Target is the upstream URL of a reverse proxy; empty for a forward proxy
+	Target string
+
+	// Out is the configuration file the captured requests are written to
+	Out string
+
+	// Extract turns values reused between responses and later requests into variables
+	Extract bool
+}
+
+// newRecordCmd creates and returns a cobra command for recording live traffic.
+//
+// Returns:
+//   - *cobra.Command: Configured command object ready to be added to the root command
+//
+// The created command:
+//   - Has the name "record" and accepts no arguments
+//   - When executed, calls runRecord with parsed options
+func newRecordCmd() *cobra.Command {
+	opts := &recordOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "record traffic into a config",
+		Long: `Run a proxy that forwards traffic and writes every observed request as a [[request]]
+entry of a chain config.
+
+With --target the proxy forwards every request to the target (reverse proxy); point the
+client at the proxy instead of the API. Without --target it is a forward proxy for plain
+HTTP (HTTP_PROXY=http://localhost:8888). Noise headers are left out and JSON bodies become
+json_body. With --extract, values of responses reused by later requests become extract
+settings and ${var} references. The config is rewritten after every request.
+
+Examples:
+  jak record --listen :8888 --target https://api.example.com --out captured.toml
+  jak record --listen :8888 --out captured.toml --extract`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRecord(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Listen, "listen", ":8888", "address the proxy listens on")
+	cmd.Flags().StringVar(&opts.Target, "target", "", "upstream URL to forward every request to (reverse proxy)")
+	cmd.Flags().StringVarP(&opts.Out, "out", "o", "captured.toml", "config file the captured requests are written to")
+	cmd.Flags().BoolVar(&opts.Extract, "extract", false, "turn values reused from earlier responses into extract and ${var} pairs")
+
+	return cmd
+}
+
+// runRecord runs the recording proxy until it is interrupted.
+// This is the main function executed when the "record" command is invoked.
+//
+// Parameters:
+//   - opts: Record-specific options
+//
+// Returns:
+//   - error: Any error encountered while starting the proxy or writing the config
+//
+// The function performs the following steps:
+//  1. Creates the proxy for the target, or a forward proxy without one
+//  2. Logs every forwarded request and rewrites the config file after each one
+//  3. Shuts down gracefully on Ctrl+C and writes the final config
+func runRecord(opts *recordOptions) error {
+	if opts.Out == "" {
+		return se.ErrCLIInput
+	}
+
+	proxy, err := capture.NewProxy(opts.Target)
+	if err != nil {
+		err = fmt.Errorf("%w: %s", se.ErrCLIInput, err)
+		format.PrintError(err)
+		return err
+	}
+
+	var mu sync.Mutex
+	save := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		config := capture.Generate(proxy.Exchanges(), proxy.Target(), capture.Options{Extract: opts.Extract})
+		return capture.WriteFile(opts.Out, config)
+	}
+
+	proxy.SetExchangeCollector(func(exchange capture.Exchange) {
+		format.PrintRequestResult(format.ReqResult{
+			Method:     exchange.Method,
+			URL:        exchange.URL.String(),
+			StatusCode: exchange.StatusCode,
+			Duration:   exchange.Duration,
+			Success:    true,
+		})
+		if err := save(); err != nil {
+			format.PrintError(se.WrapError(err, "failed to save captured config"))
+		}
+	})
+	proxy.SetErrorCollector(func(method, url string, err error) {
+		format.PrintRequestResult(format.ReqResult{Method: method, URL: url, Error: err})
+	})
+
+	listener, err := net.Listen("tcp", opts.Listen)
+	if err != nil {
+		err = se.WrapError(err, "failed to listen")
+		format.PrintError(err)
+		return err
+	}
+
+	server := &http.Server{Handler: proxy}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	mode := "forward proxy"
+	if opts.Target != "" {
+		mode = "proxy to " + opts.Target
+	}
+	fmt.Println(format.ColorizeInfo(fmt.Sprintf("Recording %s on %s into %s (Ctrl+C to stop)", mode, listener.Addr(), opts.Out)))
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		err = se.WrapError(err, "proxy failed")
+		format.PrintError(err)
+		return err
+	}
+
+	count := len(proxy.Exchanges())
+	if count == 0 {
+		fmt.Println(format.ColorizeWarning("No requests captured"))
+		return nil
+	}
+	if err := save(); err != nil {
+		err = se.WrapError(err, "failed to save captured config")
+		format.PrintError(err)
+		return err
+	}
+	fmt.Println(format.ColorizeInfo(fmt.Sprintf("Captured %d requests into %s", count, opts.Out)))
+	return nil
+}
@@ -1,17 +1,43 @@
 package cmd
 
 import (
+	"errors"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/dataset"
 	"github.com/ymatsukawa/jak/internal/engine"
 	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	"github.com/ymatsukawa/jak/internal/snapshot"
 	se "github.com/ymatsukawa/jak/internal/sys_error"
 )
 
 // batchOptions holds configuration options specific to batch request command.
-// Currently empty but provides extensibility for future batch-specific options.
-type batchOptions struct{}
+// Options that are set override the corresponding config settings.
+type batchOptions struct {
+	// MaxWorkers overrides max_workers
+	MaxWorkers int
+
+	// RateLimit overrides rate_limit
+	RateLimit float64
+
+	// RateBurst overrides rate_burst
+	RateBurst int
+
+	// DelayBetween overrides delay_between
+	DelayBetween time.Duration
+
+	// Watch re-runs the batch whenever the config or a referenced file changes
+	Watch bool
+
+	// cassette holds the record and replay options
+	cassette cassetteOptions
+
+	// snapshot holds the snapshot testing options
+	snapshot snapshotOptions
+}
 
 // newReqBatCmd creates and returns a cobra command for executing batch requests.
 // The command requires exactly one argument: the path to the configuration file.
@@ -23,41 +49,88 @@ type batchOptions struct{}
 //   - Has the name "bat" with usage "bat [config_file]"
 //   - Accepts exactly one argument (the configuration file path)
 //   - When executed, calls runBatchRequest with parsed options and arguments
+//   - With --watch, calls it again whenever the config or a referenced file changes
 func newReqBatCmd() *cobra.Command {
 	opts := &batchOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "bat [config_file]",
 		Short: "batch request",
-		Long:  `Execute batch requests defined in configuration file`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Execute batch requests defined in configuration file
+
+With --snapshot-dir, every response body is normalized (pretty JSON with sorted keys,
+snapshot_ignore paths masked) and compared with the snapshot stored for its request.
+Missing snapshots are created; differing ones fail the request with a diff, or are
+overwritten with --update-snapshots.
+
+Examples:
+  jak bat config.toml --snapshot-dir snapshots/
+  jak bat config.toml --snapshot-dir snapshots/ --update-snapshots
+  jak bat config.toml --watch`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Watch {
+				return runWatched(args[0], func() error { return runBatchRequest(opts, args) })
+			}
 			return runBatchRequest(opts, args)
 		},
 	}
 
+	cmd.Flags().IntVar(&opts.MaxWorkers, "max-workers", 0, "number of concurrent workers (default 5)")
+	cmd.Flags().Float64Var(&opts.RateLimit, "rate-limit", 0, "maximum requests per second across all workers")
+	cmd.Flags().IntVar(&opts.RateBurst, "rate-burst", 0, "requests that may be sent at once before the rate limit applies (default 1)")
+	cmd.Flags().DurationVar(&opts.DelayBetween, "delay-between", 0, "pause between sequential requests (e.g. 500ms)")
+	addCassetteFlags(cmd, &opts.cassette)
+	addWatchFlag(cmd, &opts.Watch)
+	addSnapshotFlags(cmd, &opts.snapshot)
+
 	return cmd
 }
 
+// applyBatchOptions overrides configuration settings with batch command-line flags.
+// Only flags that were given a value take precedence over the configuration.
+//
+// Parameters:
+//   - config: Configuration object to update
+//   - opts: Batch options parsed from the command line
+func applyBatchOptions(config *rule.Config, opts *batchOptions) {
+	if opts.MaxWorkers != 0 {
+		config.MaxWorkers = opts.MaxWorkers
+	}
+	if opts.RateLimit != 0 {
+		config.RateLimit = opts.RateLimit
+	}
+	if opts.RateBurst != 0 {
+		config.RateBurst = opts.RateBurst
+	}
+	if opts.DelayBetween != 0 {
+		config.DelayBetween = opts.DelayBetween
+	}
+}
+
 // runBatchRequest executes a batch of HTTP requests as defined in the configuration file.
 // This is the main function executed when the "bat" command is invoked.
 //
 // Parameters:
-//   - opts: Batch-specific options (currently unused but provides future extensibility)
+//   - opts: Batch-specific options overriding concurrency and pacing settings, cassette and snapshot options
 //   - args: Command-line arguments, where args[0] is the configuration file path
 //
 // Returns:
 //   - error: Any error encountered during batch execution
 //
 // The function performs the following steps:
-//  1. Loads and validates the configuration from the specified path
-//  2. Creates a context with timeout based on configuration
-//  3. Initializes an executor with the context
-//  4. Sets up a result collector to track execution results
+//  1. Loads and validates the configuration from the specified path, applying batch flags
+//  2. Expands data-driven requests and creates a context with timeout based on configuration
+//  3. Initializes an executor with the context and a client built from the configuration
+//     (with a cookie jar if enabled, saved afterwards when --cookie-jar is given),
+//     recording to or replaying from a cassette when --record or --replay is given
+//  4. Sets up a result collector to track execution results, and a snapshot check of
+//     every response when --snapshot-dir is given
 //  5. Executes requests either sequentially or concurrently based on configuration
 //  6. Prints a summary of execution results
 //
-// If execution fails, a wrapped error is returned with context information.
+// If execution fails, a wrapped error is printed. An error is returned when a
+// response differs from its snapshot.
 func runBatchRequest(opts *batchOptions, args []string) error {
 	configPath := args[0]
 	if configPath == "" {
@@ -71,18 +144,57 @@ func runBatchRequest(opts *batchOptions, args []string) error {
 		return err
 	}
 
+	// Apply batch flags and validate the overridden settings
+	applyBatchOptions(config, opts)
+	if err := config.Validate(); err != nil {
+		err = se.WrapError(err, "invalid batch options")
+		format.PrintError(err)
+		return err
+	}
+
+	// Expand data-driven requests up front, so the pacing budget of the timeout covers every iteration
+	config, err = dataset.Expand(config)
+	if err != nil {
+		err = se.WrapError(err, "failed to load data")
+		format.PrintError(err)
+		return err
+	}
+
+	// Create snapshot checker, only used with --snapshot-dir
+	snapshots, err := newSnapshotChecker(config, &opts.snapshot)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
 	// Create context with timeout
 	ctx, cancel := NewTimeoutContext(config)
 	defer cancel()
 
+	// Create cookie jar, only used when enabled in config or persisted to a file
+	jar, err := NewCookieJar(config.CookiesEnabled(false))
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer SaveCookieJar(jar)
+
+	// Create HTTP client with transport settings from config, recording or replaying a cassette
+	client, saveCassette, err := NewCassetteClient(config, &opts.cassette, cookieJarOptions(jar)...)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer saveCassette()
+
 	// Create executor with timeout from config
-	executor := engine.NewExecutor(ctx)
+	executor := engine.NewExecutor(ctx).WithClient(client)
 
 	// Collection for tracking results
 	var results []format.ReqResult
 
 	// Create a result collector
-	resultCollector := func(name, method, url string, statusCode int, reqErr error, duration time.Duration) {
+	resultCollector := func(name, method, url string, statusCode int, reqErr error, duration time.Duration, timing *http.Timing) {
 		result := format.ReqResult{
 			Name:       name,
 			Method:     method,
@@ -91,14 +203,23 @@ func runBatchRequest(opts *batchOptions, args []string) error {
 			Duration:   duration,
 			Success:    reqErr == nil,
 			Error:      reqErr,
+			Timing:     timing,
 		}
 
 		results = append(results, result)
 		format.PrintRequestResult(result)
+
+		var mismatch *snapshot.MismatchError
+		if errors.As(reqErr, &mismatch) {
+			format.PrintDiff(mismatch.Diff)
+		}
 	}
 
 	// Set the collector on the executor
 	executor.SetResultCollector(resultCollector)
+	if snapshots != nil {
+		executor.SetResponseChecker(snapshots.Check)
+	}
 
 	// Choose execution method based on concurrency flag
 	executeFn := executor.ExecuteBatchSequential
@@ -111,11 +232,20 @@ func runBatchRequest(opts *batchOptions, args []string) error {
 
 	// Print batch summary
 	format.PrintBatchSummary(results)
+	if snapshots != nil {
+		snapshots.PrintSummary()
+	}
 
 	if err != nil {
 		wrappedErr := se.WrapError(err, "failed to execute batch requests")
 		format.PrintError(wrappedErr)
-		return nil
+	}
+
+	// Differing snapshots fail the command, so snapshot testing can gate CI
+	if snapshots != nil {
+		if err := snapshots.Err(); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// writeTestConfig writes a configuration into a temporary directory and returns its path
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestRunBatchRequest_SnapshotMismatch(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"version": %d}`, version.Load())
+	}))
+	defer server.Close()
+
+	configPath := writeTestConfig(t, fmt.Sprintf(`
+base_url = "%s"
+
+[[request]]
+name = "Get Version"
+method = "GET"
+path = "/version"
+`, server.URL))
+	opts := &batchOptions{snapshot: snapshotOptions{Dir: t.TempDir()}}
+
+	// The first run creates the snapshot, the second matches it
+	require.NoError(t, runBatchRequest(opts, []string{configPath}))
+	require.NoError(t, runBatchRequest(opts, []string{configPath}))
+
+	// A changed response fails the command
+	version.Store(2)
+	err := runBatchRequest(opts, []string{configPath})
+	assert.ErrorIs(t, err, se.ErrSnapshotMismatch)
+
+	// Updating the snapshot accepts the change
+	opts.snapshot.Update = true
+	require.NoError(t, runBatchRequest(opts, []string{configPath}))
+	opts.snapshot.Update = false
+	require.NoError(t, runBatchRequest(opts, []string{configPath}))
+}
+
+func TestRunBatchRequest_PacingExceedsTimeout(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	// Three requests 600ms apart take longer than the timeout of one second in total
+	configPath := writeTestConfig(t, fmt.Sprintf(`
+base_url = "%s"
+timeout = 1
+delay_between = "600ms"
+
+[[request]]
+name = "First"
+method = "GET"
+path = "/first"
+
+[[request]]
+name = "Second"
+method = "GET"
+path = "/second"
+
+[[request]]
+name = "Third"
+method = "GET"
+path = "/third"
+`, server.URL))
+
+	require.NoError(t, runBatchRequest(&batchOptions{}, []string{configPath}))
+	assert.Equal(t, int32(3), hits.Load())
+}
@@ -1,18 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/ymatsukawa/jak/internal/chain"
 	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
 	se "github.com/ymatsukawa/jak/internal/sys_error"
 )
 
 // chainOptions holds configuration options specific to chain request command.
-// Currently empty but provides extensibility for future chain-specific options.
-type chainOptions struct{}
+type chainOptions struct {
+	// Watch re-runs the chain whenever the config or a referenced file changes
+	Watch bool
+
+	// cassette holds the record and replay options
+	cassette cassetteOptions
+}
 
 // newReqChainCmd creates and returns a cobra command for executing chain requests.
 // Chain requests allow variable extraction and substitution between dependent requests.
@@ -24,19 +31,30 @@ type chainOptions struct{}
 //   - Has the name "chain" with usage "chain [config_file]"
 //   - Accepts exactly one argument (the configuration file path)
 //   - When executed, calls runChainRequest with parsed options and arguments
+//   - With --watch, calls it again whenever the config or a referenced file changes
 func newReqChainCmd() *cobra.Command {
 	opts := &chainOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "chain [config_file]",
 		Short: "chain request",
-		Long:  `Execute chain requests defined in configuration file, allowing variable extraction and substitution between requests`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Execute chain requests defined in configuration file, allowing variable extraction and substitution between requests
+
+Examples:
+  jak chain config.toml
+  jak chain config.toml --watch`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Watch {
+				return runWatched(args[0], func() error { return runChainRequest(opts, args) })
+			}
 			return runChainRequest(opts, args)
 		},
 	}
 
+	addCassetteFlags(cmd, &opts.cassette)
+	addWatchFlag(cmd, &opts.Watch)
+
 	return cmd
 }
 
@@ -44,7 +62,7 @@ func newReqChainCmd() *cobra.Command {
 // This is the main function executed when the "chain" command is invoked.
 //
 // Parameters:
-//   - opts: Chain-specific options (currently unused but provides future extensibility)
+//   - opts: Chain-specific options such as recording or replaying a cassette
 //   - args: Command-line arguments, where args[0] is the configuration file path
 //
 // Returns:
@@ -54,7 +72,8 @@ func newReqChainCmd() *cobra.Command {
 //  1. Loads and validates the configuration from the specified path
 //  2. Creates a context with timeout based on configuration
 //  3. Sets up a result collector to track execution results and extracted variables
-//  4. Creates a chain executor and applies the result collector
+//  4. Creates a chain executor with a cookie jar shared across requests and applies the result collector;
+//     the client records to or replays from a cassette when --record or --replay is given
 //  5. Executes the chain of requests according to their dependencies
 //  6. Prints a summary of execution results
 //
@@ -71,7 +90,7 @@ func runChainRequest(opts *chainOptions, args []string) error {
 	config, err := LoadAndValidateConfig(configPath)
 	if err != nil {
 		format.PrintError(err)
-		return nil
+		return err
 	}
 
 	// Create context with timeout
@@ -82,7 +101,7 @@ func runChainRequest(opts *chainOptions, args []string) error {
 	var results []format.ReqResult
 
 	// Create a result collector function
-	resultCollector := func(name, method, url string, statusCode int, reqErr error, duration time.Duration, variables map[string]string) {
+	resultCollector := func(name, method, url string, statusCode int, reqErr error, duration time.Duration, timing *http.Timing, variables map[string]string) {
 		result := format.ReqResult{
 			Name:       name,
 			Method:     method,
@@ -91,6 +110,8 @@ func runChainRequest(opts *chainOptions, args []string) error {
 			Duration:   duration,
 			Success:    reqErr == nil,
 			Error:      reqErr,
+			Timing:     timing,
+			Skipped:    errors.Is(reqErr, se.ErrRequestSkipped),
 		}
 
 		results = append(results, result)
@@ -114,8 +135,24 @@ func runChainRequest(opts *chainOptions, args []string) error {
 		}
 	}
 
+	// Create cookie jar shared by the whole chain, enabled unless disabled in config
+	jar, err := NewCookieJar(config.CookiesEnabled(true))
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer SaveCookieJar(jar)
+
+	// Create HTTP client with transport settings from config, recording or replaying a cassette
+	client, saveCassette, err := NewCassetteClient(config, &opts.cassette, cookieJarOptions(jar)...)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer saveCassette()
+
 	// Create and execute chain with result collector
-	executor := chain.NewChainExecutor()
+	executor := chain.NewChainExecutor().WithClient(client)
 	executor.SetResultCollector(resultCollector)
 
 	err = executor.Execute(ctx, config)
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunChainRequest_InvalidClient(t *testing.T) {
+	configPath := writeTestConfig(t, `
+base_url = "http://example.com"
+
+[tls]
+ca_cert = "missing-ca.pem"
+
+[[request]]
+name = "Get Users"
+method = "GET"
+path = "/users"
+`)
+
+	err := runChainRequest(&chainOptions{}, []string{configPath})
+	assert.ErrorContains(t, err, "missing-ca.pem")
+}
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/ymatsukawa/jak/internal/engine"
 	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
 	se "github.com/ymatsukawa/jak/internal/sys_error"
 )
 
@@ -16,6 +18,10 @@ type simpleOptions struct {
 	Header  string
 	Json    string
 	Timeout time.Duration
+	TLS     http.TLSOptions
+
+	NoFollow     bool
+	MaxRedirects int
 }
 
 // NewSimpleOptions creates and returns a new simpleOptions instance with default values.
@@ -25,7 +31,8 @@ type simpleOptions struct {
 //   - *simpleOptions: Initialized options struct with default values
 func NewSimpleOptions() *simpleOptions {
 	return &simpleOptions{
-		Timeout: DefaultTimeout,
+		Timeout:      DefaultTimeout,
+		MaxRedirects: http.DefaultMaxRedirects,
 	}
 }
 
@@ -39,6 +46,7 @@ func NewSimpleOptions() *simpleOptions {
 //   - Has the name "req" with usage "req [method] [url]"
 //   - Accepts exactly two arguments (method and URL)
 //   - Provides flags for setting headers (-H/--header), JSON body (-j/--json), and timeout (-t/--timeout)
+//   - Provides flags for TLS settings and redirect handling
 //   - When executed, calls runSimpleRequest with parsed options and arguments
 func newReqSimpleCmd() *cobra.Command {
 	opts := NewSimpleOptions()
@@ -56,6 +64,16 @@ func newReqSimpleCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&opts.Header, "header", "H", "", "header - one key:value only")
 	cmd.Flags().StringVarP(&opts.Json, "json", "j", "", "json data")
 	cmd.Flags().DurationVarP(&opts.Timeout, "timeout", "t", DefaultTimeout, "request timeout (e.g. 10s, 1m)")
+	cmd.Flags().StringVar(&opts.TLS.CACert, "ca-cert", "", "PEM file with additional trusted CA certificates")
+	cmd.Flags().StringVar(&opts.TLS.ClientCert, "client-cert", "", "PEM client certificate for mutual TLS")
+	cmd.Flags().StringVar(&opts.TLS.ClientKey, "client-key", "", "PEM private key for the client certificate")
+	cmd.Flags().BoolVarP(&opts.TLS.InsecureSkipVerify, "insecure", "k", false, "skip server certificate verification")
+	cmd.Flags().StringVar(&opts.TLS.ServerName, "server-name", "", "server name for SNI and certificate verification")
+	cmd.Flags().StringVar(&opts.TLS.MinVersion, "tls-min", "", "minimum TLS version (1.0, 1.1, 1.2, 1.3)")
+	cmd.Flags().StringVar(&opts.TLS.MaxVersion, "tls-max", "", "maximum TLS version (1.0, 1.1, 1.2, 1.3)")
+	cmd.Flags().StringSliceVar(&opts.TLS.CipherSuites, "ciphers", nil, "allowed cipher suites, comma separated")
+	cmd.Flags().BoolVar(&opts.NoFollow, "no-follow", false, "do not follow redirects")
+	cmd.Flags().IntVar(&opts.MaxRedirects, "max-redirects", http.DefaultMaxRedirects, "maximum number of redirects to follow")
 
 	return cmd
 }
@@ -74,9 +92,9 @@ func newReqSimpleCmd() *cobra.Command {
 //  1. Extracts method and URL from arguments
 //  2. Validates the URL format
 //  3. Creates a context with the specified timeout
-//  4. Initializes an executor with the context
+//  4. Initializes an executor with the context and a client built from the TLS flags
 //  5. Executes the request with provided options
-//  6. Prints the request result and detailed response
+//  6. Prints the request result, connection details (verbose only) and detailed response
 //
 // If execution fails, a wrapped error is returned with context information.
 func runSimpleRequest(opts *simpleOptions, args []string) error {
@@ -87,15 +105,30 @@ func runSimpleRequest(opts *simpleOptions, args []string) error {
 
 	if err := validateURL(urlStr); err != nil {
 		format.PrintError(err)
-		return nil
+		return err
 	}
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
 	defer cancel()
 
+	// Create cookie jar, only used when persisted to a file
+	jar, err := NewCookieJar(false)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer SaveCookieJar(jar)
+
+	// Create HTTP client with TLS settings from flags
+	client, err := newSimpleClient(opts, cookieJarOptions(jar)...)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
 	// Create executor with context
-	executor := engine.NewExecutor(ctx)
+	executor := engine.NewExecutor(ctx).WithClient(client)
 
 	// Track timing
 	startTime := time.Now()
@@ -114,6 +147,9 @@ func runSimpleRequest(opts *simpleOptions, args []string) error {
 		Success:  err == nil,
 		Error:    err,
 	}
+	if response != nil {
+		result.Timing = response.Timing
+	}
 
 	if err != nil {
 		wrappedErr := se.WrapError(err, "failed to execute simple request")
@@ -128,8 +164,57 @@ func runSimpleRequest(opts *simpleOptions, args []string) error {
 	// Print result summary
 	format.PrintRequestResult(result)
 
+	// Print connection details
+	if globalOpts.Verbose {
+		format.PrintTLSInfo(response.TLS)
+	}
+
 	// Print detailed response
 	format.PrintResponse(response)
 
 	return nil
 }
+
+// newSimpleClient creates an HTTP client configured by the simple request flags
+// (TLS and redirect handling) and the global proxy flags.
+//
+// Parameters:
+//   - opts: Simple request options including TLS settings
+//   - extra: Additional client options such as a cookie jar
+//
+// Returns:
+//   - http.Client: Configured client
+//   - error: Any error encountered while building the TLS or proxy configuration
+func newSimpleClient(opts *simpleOptions, extra ...http.ClientOption) (http.Client, error) {
+	clientOpts := []http.ClientOption{
+		http.WithRedirectPolicy(http.RedirectPolicy{
+			Follow:       !opts.NoFollow,
+			MaxRedirects: opts.MaxRedirects,
+			KeepMethod:   true,
+		}),
+	}
+
+	if !opts.TLS.IsEmpty() {
+		tlsConfig, err := http.BuildTLSConfig(opts.TLS)
+		if err != nil {
+			return nil, se.WrapError(err, "invalid tls settings")
+		}
+		clientOpts = append(clientOpts, http.WithTLSConfig(tlsConfig))
+	}
+
+	if globalOpts.Proxy != "" || globalOpts.NoProxy != "" {
+		if globalOpts.Proxy != "" && !strings.EqualFold(globalOpts.Proxy, http.ProxyDirect) {
+			if _, err := http.ParseProxyURL(globalOpts.Proxy); err != nil {
+				return nil, se.WrapError(err, "invalid proxy settings")
+			}
+		}
+		clientOpts = append(clientOpts, http.WithProxy(http.ProxyOptions{
+			URL:     globalOpts.Proxy,
+			NoProxy: globalOpts.NoProxy,
+		}))
+	}
+
+	clientOpts = append(clientOpts, extra...)
+
+	return http.NewClient(clientOpts...), nil
+}
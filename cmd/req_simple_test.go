@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSimpleRequest_InvalidClient(t *testing.T) {
+	opts := NewSimpleOptions()
+	opts.TLS.CACert = "missing-ca.pem"
+
+	err := runSimpleRequest(opts, []string{"GET", "http://example.com"})
+	assert.ErrorContains(t, err, "invalid tls settings")
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/format"
 )
 
 var rootCmd = &cobra.Command{
@@ -12,15 +13,56 @@ var rootCmd = &cobra.Command{
 Examples:
   jak req GET https://example.com
   jak bat config.toml
-  jak chain config.toml`,
+  jak chain config.toml
+  jak load config.toml --rps 200 --duration 60s --users 50
+  jak diff config.toml --env-a legacy --env-b new
+  jak cookies list session.json
+  jak serve mocks.toml --port 8080
+  jak record --target https://api.example.com --out captured.toml
+  jak shell --base-url https://api.example.com
+  jak tui config.toml`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		format.SetVerbose(globalOpts.Verbose)
+	},
 }
 
+// globalOptions holds options shared by all commands.
+// These options are set via persistent command-line flags on the root command.
+type globalOptions struct {
+	// Proxy overrides the proxy URL for all requests
+	Proxy string
+
+	// NoProxy overrides the list of hosts that bypass the proxy
+	NoProxy string
+
+	// CookieJar is the file cookies are loaded from and saved to
+	CookieJar string
+
+	// Verbose enables detailed output such as TLS details and timing waterfalls
+	Verbose bool
+}
+
+// globalOpts holds the parsed persistent flags
+var globalOpts = &globalOptions{}
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&globalOpts.Proxy, "proxy", "", `proxy URL (http, https or socks5; "direct" disables proxying)`)
+	rootCmd.PersistentFlags().StringVar(&globalOpts.NoProxy, "no-proxy", "", "comma separated hosts, domains or CIDR ranges that bypass the proxy")
+	rootCmd.PersistentFlags().BoolVarP(&globalOpts.Verbose, "verbose", "v", false, "show connection details and timing breakdown")
+	rootCmd.PersistentFlags().StringVar(&globalOpts.CookieJar, "cookie-jar", "", "file to load cookies from and save them to (e.g. session.json)")
+
 	rootCmd.AddCommand(newReqSimpleCmd())
 	rootCmd.AddCommand(newReqBatCmd())
 	rootCmd.AddCommand(newReqChainCmd())
+	rootCmd.AddCommand(newCookiesCmd())
+	rootCmd.AddCommand(newLoadCmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newRecordCmd())
+	rootCmd.AddCommand(newShellCmd())
+	rootCmd.AddCommand(newTuiCmd())
 }
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/rule"
+	"github.com/ymatsukawa/jak/internal/stub"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// serveOptions holds configuration options specific to the serve command.
+type serveOptions struct {
+	// Host is the address the server listens on
+	Host string
+
+	// Port is the port the server listens on
+	Port int
+}
+
+// shutdownTimeout is how long in-flight requests may take to finish on shutdown
+const shutdownTimeout = 5 * time.Second
+
+// newServeCmd creates and returns a cobra command for running a mock server.
+// The command requires exactly one argument: the path to the stub file.
+//
+// Returns:
+//   - *cobra.Command: Configured command object ready to be added to the root command
+//
+// The created command:
+//   - Has the name "serve" with usage "serve [stub_file]"
+//   - Accepts exactly one argument (the stub file path)
+//   - When executed, calls runServe with parsed options and arguments
+func newServeCmd() *cobra.Command {
+	opts := &serveOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve [stub_file]",
+		Short: "run a mock server",
+		Long: `Start a local HTTP server answering requests from the [[route]] definitions of a stub file.
+
+Routes are matched in order by method and path pattern, e.g. /users/{id}; a trailing *
+matches any remainder. Responses may refer to the request with ${params.id}, ${query.page},
+${headers.X-Request-Id}, ${body.user.name}, ${method}, ${path} and ${body}.
+A route with responses = [...] steps through them on repeated calls.
+
+Examples:
+  jak serve mocks.toml --port 8080`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(opts, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Host, "host", "127.0.0.1", "address to listen on")
+	cmd.Flags().IntVarP(&opts.Port, "port", "p", 8080, "port to listen on")
+
+	return cmd
+}
+
+// runServe runs a mock server until it is interrupted.
+// This is the main function executed when the "serve" command is invoked.
+//
+// Parameters:
+//   - opts: Serve-specific options
+//   - args: Command-line arguments, where args[0] is the stub file path
+//
+// Returns:
+//   - error: Any error encountered while loading the stub file or serving
+//
+// The function performs the following steps:
+//  1. Loads and validates the route definitions from the specified path
+//  2. Starts listening and logs every request like a request result
+//  3. Shuts down gracefully on Ctrl+C
+func runServe(opts *serveOptions, args []string) error {
+	stubPath := args[0]
+	if stubPath == "" {
+		return se.ErrCLIInput
+	}
+
+	config, err := rule.LoadStubConfig(stubPath)
+	if err != nil {
+		err = se.WrapError(err, "failed to load stub file")
+		format.PrintError(err)
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		err = se.WrapError(err, "invalid stub file")
+		format.PrintError(err)
+		return err
+	}
+
+	server := stub.NewServer(config)
+	server.SetHitCollector(func(route, method, url string, statusCode int, duration time.Duration) {
+		format.PrintRequestResult(format.ReqResult{
+			Name:       route,
+			Method:     method,
+			URL:        url,
+			StatusCode: statusCode,
+			Duration:   duration,
+			Success:    statusCode > 0,
+		})
+	})
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)))
+	if err != nil {
+		err = se.WrapError(err, "failed to listen")
+		format.PrintError(err)
+		return err
+	}
+
+	httpServer := &http.Server{Handler: server}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Println(format.ColorizeInfo(fmt.Sprintf("Serving %d routes on http://%s (Ctrl+C to stop)", len(config.Route), listener.Addr())))
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		err = se.WrapError(err, "server failed")
+		format.PrintError(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/engine"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/rule"
+	"github.com/ymatsukawa/jak/internal/shell"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// shellPrompt is the prompt printed before each line
+const shellPrompt = "jak> "
+
+// shellOptions holds configuration options specific to the shell command.
+type shellOptions struct {
+	// BaseUrl is the URL request paths are resolved against
+	BaseUrl string
+
+	// Headers are sent with every request, in format "Key: Value"
+	Headers []string
+}
+
+// newShellCmd creates and returns a cobra command for the interactive shell.
+//
+// Returns:
+//   - *cobra.Command: Configured command object ready to be added to the root command
+//
+// The created command:
+//   - Has the name "shell" and accepts no arguments
+//   - When executed, calls runShell with parsed options
+func newShellCmd() *cobra.Command {
+	opts := &shellOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "explore an API interactively",
+		Long: `Start an interactive prompt for sending requests to an API.
+
+Type a method and a path to send a request; a JSON body may follow the path.
+Variables are set with "set name=value" and used as ${name}; ${last.body.<path>},
+${last.headers.<name>} and ${last.status} refer to the last response. Headers set
+with "header Name: value" are sent with every request. Type help for all commands.
+
+History is kept in ~/.jak_history; the up and down keys browse it and tab completes
+commands and paths used before. Cookies are kept for the session.
+
+Examples:
+  jak shell --base-url https://api.example.com
+  jak shell --base-url https://api.example.com -H "Authorization: Bearer abc"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShell(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.BaseUrl, "base-url", "", "URL request paths are resolved against")
+	cmd.Flags().StringArrayVarP(&opts.Headers, "header", "H", nil, `header sent with every request (e.g. "Authorization: Bearer abc"), repeatable`)
+
+	return cmd
+}
+
+// runShell reads and executes commands until the user leaves the shell.
+// This is the main function executed when the "shell" command is invoked.
+//
+// Parameters:
+//   - opts: Shell-specific options
+//
+// Returns:
+//   - error: Any error encountered while creating the client or reading the input
+//
+// The function performs the following steps:
+//  1. Validates the base URL and creates a client with the global proxy flags and a cookie jar
+//  2. Creates a session with the headers from the flags and loads the history
+//  3. Reads lines with editing and completion, executing each one; Ctrl+C cancels
+//     a running request
+//  4. Saves the history and cookies when the user leaves with exit or Ctrl+D
+func runShell(opts *shellOptions) error {
+	if opts.BaseUrl != "" {
+		if err := validateURL(opts.BaseUrl); err != nil {
+			format.PrintError(err)
+			return err
+		}
+	}
+
+	config := &rule.Config{BaseUrl: opts.BaseUrl, Timeout: rule.DefaultTimeout}
+	applyGlobalOptions(config, globalOpts)
+
+	jar, err := NewCookieJar(true)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer SaveCookieJar(jar)
+
+	client, err := engine.NewClientFromConfig(config, cookieJarOptions(jar)...)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	session := shell.NewSession(config, client, os.Stdout)
+	for _, header := range opts.Headers {
+		if err := session.SetHeader(header); err != nil {
+			err = fmt.Errorf("%w: %s", se.ErrCLIInput, err)
+			format.PrintError(err)
+			return err
+		}
+	}
+
+	editor := shell.NewEditor(os.Stdin, os.Stdout, session.Complete)
+	historyPath := shellHistoryPath()
+	if historyPath != "" {
+		if err := editor.LoadHistory(historyPath); err != nil {
+			fmt.Println(format.ColorizeWarning(err.Error()))
+		}
+	}
+
+	base := opts.BaseUrl
+	if base == "" {
+		base = "no base URL"
+	}
+	fmt.Println(format.ColorizeInfo(fmt.Sprintf("jak shell (%s); type help for commands, exit or Ctrl+D to leave", base)))
+
+	for {
+		line, err := editor.ReadLine(shellPrompt)
+		if errors.Is(err, se.ErrInterrupted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			err = se.WrapError(err, "failed to read input")
+			format.PrintError(err)
+			return err
+		}
+		editor.AddHistory(line)
+
+		// Ctrl+C cancels the running request instead of leaving the shell
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		quit, err := session.Execute(ctx, line)
+		stop()
+		if err != nil {
+			fmt.Println(format.ColorizeError(err.Error()))
+		}
+		if quit {
+			break
+		}
+	}
+
+	if historyPath != "" {
+		if err := editor.SaveHistory(historyPath); err != nil {
+			fmt.Println(format.ColorizeWarning(err.Error()))
+		}
+	}
+	return nil
+}
+
+// shellHistoryPath returns the path of the history file in the home directory.
+//
+// Returns:
+//   - string: Path of the history file, or empty if there is no home directory
+func shellHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, shell.DefaultHistoryFile)
+}
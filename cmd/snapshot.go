@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	"github.com/ymatsukawa/jak/internal/snapshot"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// snapshotOptions holds the snapshot testing options of the bat command.
+type snapshotOptions struct {
+	// Dir is the directory holding the response snapshots; empty disables snapshot testing
+	Dir string
+
+	// Update overwrites snapshots that differ from the responses
+	Update bool
+}
+
+// addSnapshotFlags registers the snapshot flags on a command.
+//
+// Parameters:
+//   - cmd: Command to add the flags to
+//   - opts: Options the flags are parsed into
+func addSnapshotFlags(cmd *cobra.Command, opts *snapshotOptions) {
+	cmd.Flags().StringVar(&opts.Dir, "snapshot-dir", "", "compare response bodies with the snapshots in a directory, storing missing ones")
+	cmd.Flags().BoolVar(&opts.Update, "update-snapshots", false, "overwrite snapshots that differ from the responses")
+}
+
+// snapshotChecker compares the responses of a run with their snapshots and counts the outcomes.
+type snapshotChecker struct {
+	// store holds the snapshot files
+	store *snapshot.Store
+
+	// config provides the snapshot_ignore paths
+	config *rule.Config
+
+	// mu guards counts
+	mu sync.Mutex
+
+	// counts is the number of responses per outcome
+	counts map[snapshot.Status]int
+}
+
+// newSnapshotChecker creates the snapshot checker for a run.
+//
+// Parameters:
+//   - config: Configuration providing the snapshot_ignore paths
+//   - opts: Snapshot options
+//
+// Returns:
+//   - *snapshotChecker: Checker, or nil if --snapshot-dir is not given
+//   - error: Error if --update-snapshots is given without --snapshot-dir
+func newSnapshotChecker(config *rule.Config, opts *snapshotOptions) (*snapshotChecker, error) {
+	if opts.Dir == "" {
+		if opts.Update {
+			return nil, fmt.Errorf("%w: --update-snapshots requires --snapshot-dir", se.ErrCLIInput)
+		}
+		return nil, nil
+	}
+
+	return &snapshotChecker{
+		store:  snapshot.NewStore(opts.Dir, opts.Update),
+		config: config,
+		counts: make(map[snapshot.Status]int),
+	}, nil
+}
+
+// Check compares the body of a response with the snapshot of its request.
+// The body is restored so it can be read again.
+//
+// Parameters:
+//   - req: Executed request configuration
+//   - resp: HTTP response of the request
+//
+// Returns:
+//   - error: *snapshot.MismatchError if the body differs, or any error accessing the snapshot
+func (c *snapshotChecker) Check(req *rule.Request, resp *http.Response) error {
+	var body []byte
+	if resp.Body != nil {
+		var err error
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return se.WrapError(err, "failed to read response body")
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	status, err := c.store.Check(req.Name, body, c.config.SnapshotIgnorePaths(req))
+
+	c.mu.Lock()
+	c.counts[status]++
+	c.mu.Unlock()
+
+	return err
+}
+
+// PrintSummary prints the number of matched, created, updated and failed snapshots.
+func (c *snapshotChecker) PrintSummary() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	summary := fmt.Sprintf("Snapshots: %d matched, %d created, %d updated, %d failed",
+		c.counts[snapshot.StatusMatched],
+		c.counts[snapshot.StatusCreated],
+		c.counts[snapshot.StatusUpdated],
+		c.counts[snapshot.StatusMismatched])
+	if c.counts[snapshot.StatusMismatched] > 0 {
+		fmt.Println(format.ColorizeError(summary))
+		return
+	}
+	fmt.Println(format.ColorizeInfo(summary))
+}
+
+// Err reports whether any response differed from its snapshot.
+//
+// Returns:
+//   - error: se.ErrSnapshotMismatch with the number of differing snapshots, nil if none differed
+func (c *snapshotChecker) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if mismatched := c.counts[snapshot.StatusMismatched]; mismatched > 0 {
+		return fmt.Errorf("%w: %d of %d responses differ", se.ErrSnapshotMismatch, mismatched, c.total())
+	}
+	return nil
+}
+
+// total returns the number of checked responses. The caller must hold mu.
+//
+// Returns:
+//   - int: Number of responses of all outcomes
+func (c *snapshotChecker) total() int {
+	total := 0
+	for _, count := range c.counts {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/format"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+	"github.com/ymatsukawa/jak/internal/tui"
+)
+
+// tuiOptions holds configuration options specific to the tui command.
+type tuiOptions struct {
+	// cassette holds the record and replay options
+	cassette cassetteOptions
+}
+
+// newTuiCmd creates and returns a cobra command for the terminal interface.
+// The command requires exactly one argument: the path to the configuration file.
+//
+// Returns:
+//   - *cobra.Command: Configured command object ready to be added to the root command
+//
+// The created command:
+//   - Has the name "tui" with usage "tui [config_file]"
+//   - Accepts exactly one argument (the configuration file path)
+//   - When executed, calls runTui with parsed options and arguments
+func newTuiCmd() *cobra.Command {
+	opts := &tuiOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "tui [config_file]",
+		Short: "browse and run a config in a terminal interface",
+		Long: `Open a full-screen interface listing the requests of a configuration file.
+
+Run the request under the cursor with enter, the selected requests with s (select with
+space) or the whole chain with r. Requests run in dependency order; variables extracted
+by earlier runs stay available, so a request can be run again on its own. The response
+of the request under the cursor is shown next to the list with foldable headers and JSON,
+and the extracted-variable store next to it. Tab moves between panes, / searches the
+response and q quits.
+
+Examples:
+  jak tui config.toml
+  jak tui config.toml --replay session.cassette.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTui(opts, args)
+		},
+	}
+
+	addCassetteFlags(cmd, &opts.cassette)
+
+	return cmd
+}
+
+// runTui shows the terminal interface for a configuration.
+// This is the main function executed when the "tui" command is invoked.
+//
+// Parameters:
+//   - opts: Tui-specific options such as recording or replaying a cassette
+//   - args: Command-line arguments, where args[0] is the configuration file path
+//
+// Returns:
+//   - error: Any error encountered while loading the configuration or driving the terminal
+//
+// The function performs the following steps:
+//  1. Loads and validates the configuration from the specified path
+//  2. Creates a client with a cookie jar shared by all runs; the client records to
+//     or replays from a cassette when --record or --replay is given
+//  3. Shows the interface until the user quits or sends an interrupt
+func runTui(opts *tuiOptions, args []string) error {
+	configPath := args[0]
+	if configPath == "" {
+		return se.ErrCLIInput
+	}
+
+	config, err := LoadAndValidateConfig(configPath)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+
+	// Create cookie jar shared by all runs, enabled unless disabled in config
+	jar, err := NewCookieJar(config.CookiesEnabled(true))
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer SaveCookieJar(jar)
+
+	client, saveCassette, err := NewCassetteClient(config, &opts.cassette, cookieJarOptions(jar)...)
+	if err != nil {
+		format.PrintError(err)
+		return err
+	}
+	defer saveCassette()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	app := tui.NewApp(configPath, config)
+	runner := tui.NewRunner(config, client)
+	if err := tui.Run(ctx, app, runner, tui.Options{In: os.Stdin, Out: os.Stdout}); err != nil {
+		err = se.WrapError(err, "terminal interface failed")
+		format.PrintError(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/rule"
+	"github.com/ymatsukawa/jak/internal/watch"
+)
+
+// addWatchFlag registers the watch flag on a command.
+//
+// Parameters:
+//   - cmd: Command to add the flag to
+//   - enabled: Option the flag is parsed into
+func addWatchFlag(cmd *cobra.Command, enabled *bool) {
+	cmd.Flags().BoolVarP(enabled, "watch", "w", false, "re-run whenever the config or a file it references changes")
+}
+
+// runWatched runs a command and runs it again whenever the configuration file
+// or a file it references changes, until interrupted with Ctrl+C.
+// Errors of a run are printed by the run itself and do not stop watching.
+//
+// Parameters:
+//   - configPath: Path to the configuration file
+//   - run: Function executing the command once
+//
+// Returns:
+//   - error: Always nil; watching ends on Ctrl+C
+func runWatched(configPath string, run func() error) error {
+	var changed []string
+	var err error
+	for {
+		// Record the file states before the run, so that edits during the run trigger the next one
+		watcher := watch.NewWatcher(watchedFiles(configPath), watch.DefaultInterval, watch.DefaultDebounce)
+
+		format.ClearScreen()
+		header := fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), configPath)
+		if len(changed) > 0 {
+			header += " (changed: " + strings.Join(relativePaths(changed), ", ") + ")"
+		}
+		fmt.Println(format.ColorizeInfo(header))
+
+		run()
+
+		files := fmt.Sprintf("%d files", len(watcher.Files()))
+		if len(watcher.Files()) == 1 {
+			files = "1 file"
+		}
+		fmt.Println(format.ColorizeInfo(fmt.Sprintf("Watching %s for changes (Ctrl+C to stop)", files)))
+
+		// Ctrl+C ends a running command as usual and stops watching while waiting
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		changed, err = watcher.Wait(ctx)
+		stop()
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// watchedFiles returns the configuration file and the files it references.
+// If the configuration cannot be loaded, only the configuration file is watched
+// so that fixing it triggers the next run.
+//
+// Parameters:
+//   - configPath: Path to the configuration file
+//
+// Returns:
+//   - []string: Files to watch
+func watchedFiles(configPath string) []string {
+	path, err := filepath.Abs(configPath)
+	if err != nil {
+		return []string{configPath}
+	}
+
+	config, err := rule.LoadConfig(path)
+	if err != nil {
+		return []string{path}
+	}
+	return append([]string{path}, config.ReferencedFiles()...)
+}
+
+// relativePaths shortens paths relative to the working directory for display.
+//
+// Parameters:
+//   - paths: Absolute or relative paths
+//
+// Returns:
+//   - []string: Paths relative to the working directory where possible
+func relativePaths(paths []string) []string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return paths
+	}
+
+	relative := make([]string, len(paths))
+	for i, path := range paths {
+		relative[i] = path
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			relative[i] = rel
+		}
+	}
+	return relative
+}
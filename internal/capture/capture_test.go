@@ -0,0 +1,79 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/chain"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+func TestRecordAndReplayChain(t *testing.T) {
+	// Every login issues a new token; later requests must use the current one
+	var logins atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := fmt.Sprintf("token-%04d", logins.Load())
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/login":
+			token = fmt.Sprintf("token-%04d", logins.Add(1))
+			fmt.Fprintf(w, `{"access_token": %q}`, token)
+		case r.Header.Get("Authorization") != "Bearer "+token:
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/orders":
+			fmt.Fprint(w, `{"data": [{"id": "ord-5001"}]}`)
+		case strings.HasPrefix(r.URL.Path, "/orders/"):
+			fmt.Fprintf(w, `{"id": %q}`, strings.TrimPrefix(r.URL.Path, "/orders/"))
+		}
+	}))
+	defer upstream.Close()
+
+	// Record the traffic of a client talking to the proxy
+	proxy, err := NewProxy(upstream.URL)
+	require.NoError(t, err)
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	token := ""
+	for _, step := range []struct{ method, path string }{{"POST", "/login"}, {"GET", "/orders"}, {"GET", "/orders/ord-5001"}} {
+		req, _ := http.NewRequest(step.method, server.URL+step.path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+		token = "token-0001"
+	}
+
+	path := filepath.Join(t.TempDir(), "captured.toml")
+	require.NoError(t, WriteFile(path, Generate(proxy.Exchanges(), proxy.Target(), Options{Extract: true})))
+
+	// The generated chain logs in again and uses the new token
+	config, err := rule.LoadConfig(path)
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	statuses := map[string]int{}
+	executor := chain.NewChainExecutor()
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, variables map[string]string) {
+		require.NoError(t, err)
+		statuses[name] = statusCode
+	})
+	require.NoError(t, executor.Execute(context.Background(), config))
+
+	assert.Equal(t, map[string]int{"Create Login": 200, "Get Orders": 200, "Get Orders 2": 200}, statuses)
+	assert.Equal(t, int32(2), logins.Load())
+	assert.Equal(t, "/orders/${id}", config.Request[2].Path)
+}
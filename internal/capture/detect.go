@@ -0,0 +1,364 @@
+package capture
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// Minimum lengths of values considered for reuse detection.
+// Shorter values such as "ok" or 1 match too many unrelated places.
+const (
+	// minStringLength is the minimum length of a string value
+	minStringLength = 4
+
+	// minNumberLength is the minimum number of digits of an integer value
+	minNumberLength = 3
+)
+
+// variableNamePattern matches characters not allowed in generated variable names
+var variableNamePattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// producer is a response field whose value may be reused by later requests.
+type producer struct {
+	// request is the index of the request whose response contains the value
+	request int
+
+	// path is the gjson path of the value in the response body
+	path string
+
+	// variable is the name of the variable once the value has been reused
+	variable string
+}
+
+// detector replaces values that later requests reuse from earlier responses.
+type detector struct {
+	// config is the configuration being generated
+	config *rule.Config
+
+	// values maps response values to the latest response containing them
+	values map[string]*producer
+
+	// variables holds the variable names in use
+	variables map[string]bool
+}
+
+// detectVariables turns values of responses reused by later requests into
+// extract settings on the producing request and ${var} references in the later ones.
+// Values are matched whole: path segments, query and form values, header values
+// (or their last word, e.g. a bearer token) and JSON body fields.
+//
+// Parameters:
+//   - config: Generated configuration, modified in place
+//   - exchanges: Recorded exchanges the requests were generated from
+func detectVariables(config *rule.Config, exchanges []Exchange) {
+	d := &detector{
+		config:    config,
+		values:    make(map[string]*producer),
+		variables: make(map[string]bool),
+	}
+
+	for i, exchange := range exchanges {
+		req := &config.Request[i]
+		d.substitute(req)
+
+		// Values the request sent itself are echoes, not produced by the server
+		sent := make(map[string]bool)
+		for _, value := range requestValues(exchange) {
+			sent[value] = true
+		}
+		// Values of a truncated body may be cut off
+		if exchange.ResponseBodyTruncated {
+			continue
+		}
+		walkJSON(gjson.ParseBytes(exchange.ResponseBody), "", func(path string, value gjson.Result) {
+			text, ok := candidate(value)
+			if ok && !sent[text] {
+				d.values[text] = &producer{request: i, path: path}
+			}
+		})
+	}
+}
+
+// substitute replaces reused values in a request with variable references.
+//
+// Parameters:
+//   - req: Request to modify
+func (d *detector) substitute(req *rule.Request) {
+	req.Path = d.substituteURL(req.Path)
+
+	for i, header := range req.Headers {
+		name, value, _ := strings.Cut(header, ": ")
+		token := value
+		if index := strings.LastIndexByte(value, ' '); index >= 0 {
+			token = value[index+1:]
+		}
+		if variable := d.use(token); variable != "" {
+			req.Headers[i] = name + ": " + strings.TrimSuffix(value, token) + "${" + variable + "}"
+		}
+	}
+
+	if req.JsonBody != nil {
+		body := d.substituteJSON(*req.JsonBody)
+		req.JsonBody = &body
+	}
+	if req.FormBody != nil {
+		body := d.substituteQuery(*req.FormBody)
+		req.FormBody = &body
+	}
+}
+
+// substituteURL replaces reused path segments and query values.
+//
+// Parameters:
+//   - rawURL: Path with query string, or absolute URL
+//
+// Returns:
+//   - string: URL with variable references
+func (d *detector) substituteURL(rawURL string) string {
+	path, query, hasQuery := strings.Cut(rawURL, "?")
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		value, err := url.PathUnescape(segment)
+		if err != nil {
+			continue
+		}
+		if variable := d.use(value); variable != "" {
+			segments[i] = "${" + variable + "}"
+		}
+	}
+	path = strings.Join(segments, "/")
+
+	if !hasQuery {
+		return path
+	}
+	return path + "?" + d.substituteQuery(query)
+}
+
+// substituteQuery replaces reused values of a query string or form body.
+//
+// Parameters:
+//   - query: Raw query string
+//
+// Returns:
+//   - string: Query string with variable references
+func (d *detector) substituteQuery(query string) string {
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			continue
+		}
+		if variable := d.use(unescaped); variable != "" {
+			pairs[i] = key + "=${" + variable + "}"
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// substituteJSON replaces reused field values of a JSON body.
+//
+// Parameters:
+//   - body: JSON body
+//
+// Returns:
+//   - string: Body with variable references
+func (d *detector) substituteJSON(body string) string {
+	var fields []gjson.Result
+	walkJSON(gjson.Parse(body), "", func(path string, value gjson.Result) {
+		fields = append(fields, value)
+	})
+
+	for _, field := range fields {
+		text, ok := candidate(field)
+		if !ok {
+			continue
+		}
+		variable := d.use(text)
+		if variable == "" {
+			continue
+		}
+
+		if field.Type == gjson.String {
+			body = strings.ReplaceAll(body, field.Raw, `"${`+variable+`}"`)
+			continue
+		}
+		number := regexp.MustCompile(`([:\[,]\s*)` + regexp.QuoteMeta(field.Raw) + `(\s*[,\]}])`)
+		body = number.ReplaceAllString(body, "${1}$${"+variable+"}${2}")
+	}
+	return body
+}
+
+// use returns the variable holding a value produced by an earlier response,
+// adding the extract setting to the producing request on first use.
+//
+// Parameters:
+//   - value: Value found in a request
+//
+// Returns:
+//   - string: Variable name, or empty if no earlier response produced the value
+func (d *detector) use(value string) string {
+	producer, ok := d.values[value]
+	if !ok {
+		return ""
+	}
+	if producer.variable != "" {
+		return producer.variable
+	}
+
+	producer.variable = d.variableName(producer.path)
+	req := &d.config.Request[producer.request]
+	if req.Extract == nil {
+		req.Extract = make(map[string]string)
+	}
+	req.Extract[producer.variable] = producer.path
+	return producer.variable
+}
+
+// variableName derives an unused variable name from a gjson path,
+// e.g. "data.0.access_token" becomes "access_token".
+//
+// Parameters:
+//   - path: gjson path of the value
+//
+// Returns:
+//   - string: Unused variable name
+func (d *detector) variableName(path string) string {
+	name := "value"
+	parts := strings.Split(strings.ReplaceAll(path, `\.`, "_"), ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(parts[i]); err != nil {
+			name = variableNamePattern.ReplaceAllString(parts[i], "_")
+			break
+		}
+	}
+	name = strings.Trim(name, "_")
+	if name == "" {
+		name = "value"
+	}
+
+	unique := name
+	for n := 2; d.variables[unique]; n++ {
+		unique = fmt.Sprintf("%s_%d", name, n)
+	}
+	d.variables[unique] = true
+	return unique
+}
+
+// candidate returns the text of a JSON value eligible for reuse detection.
+//
+// Parameters:
+//   - value: JSON value
+//
+// Returns:
+//   - string: Text of the value
+//   - bool: True for long enough strings and integers
+func candidate(value gjson.Result) (string, bool) {
+	switch value.Type {
+	case gjson.String:
+		text := value.String()
+		return text, len(text) >= minStringLength
+	case gjson.Number:
+		text := value.Raw
+		if strings.ContainsAny(text, ".eE") {
+			return "", false
+		}
+		return text, len(strings.TrimPrefix(text, "-")) >= minNumberLength
+	default:
+		return "", false
+	}
+}
+
+// requestValues returns the values a request sent: path segments, query and
+// form values, header values and their last words, and JSON body fields.
+//
+// Parameters:
+//   - exchange: Recorded exchange
+//
+// Returns:
+//   - []string: Values sent by the request
+func requestValues(exchange Exchange) []string {
+	var values []string
+	for _, segment := range strings.Split(exchange.URL.Path, "/") {
+		values = append(values, segment)
+	}
+	for _, query := range exchange.URL.Query() {
+		values = append(values, query...)
+	}
+	for _, header := range exchange.Header {
+		for _, value := range header {
+			values = append(values, value)
+			if index := strings.LastIndexByte(value, ' '); index >= 0 {
+				values = append(values, value[index+1:])
+			}
+		}
+	}
+	if form, err := url.ParseQuery(string(exchange.Body)); err == nil {
+		for _, field := range form {
+			values = append(values, field...)
+		}
+	}
+	walkJSON(gjson.ParseBytes(exchange.Body), "", func(path string, value gjson.Result) {
+		if text, ok := candidate(value); ok {
+			values = append(values, text)
+		}
+	})
+	return values
+}
+
+// walkJSON calls fn for every scalar value of a JSON document in document order.
+//
+// Parameters:
+//   - value: JSON value to walk
+//   - path: gjson path of the value, empty for the root
+//   - fn: Function receiving the path and value of each scalar
+func walkJSON(value gjson.Result, path string, fn func(path string, value gjson.Result)) {
+	if value.IsObject() || value.IsArray() {
+		index := 0
+		value.ForEach(func(key, child gjson.Result) bool {
+			component := strconv.Itoa(index)
+			if value.IsObject() {
+				component = gjson.Escape(key.String())
+			}
+			index++
+
+			childPath := component
+			if path != "" {
+				childPath = path + "." + component
+			}
+			walkJSON(child, childPath, fn)
+			return true
+		})
+		return
+	}
+	if path != "" && value.Exists() {
+		fn(path, value)
+	}
+}
+
+// sortedKeys returns the keys of a header map in sorted order.
+//
+// Parameters:
+//   - header: Header map
+//
+// Returns:
+//   - []string: Sorted header names
+func sortedKeys(header map[string][]string) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
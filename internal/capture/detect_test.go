@@ -0,0 +1,87 @@
+package capture
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_Extract(t *testing.T) {
+	exchanges := []Exchange{
+		newExchange("POST", "http://api.test/login", http.Header{"Content-Type": {"application/json"}},
+			`{"user": "alice"}`, `{"user": "alice", "access_token": "tok-123456", "ok": "yes"}`),
+		newExchange("GET", "http://api.test/orders", http.Header{"Authorization": {"Bearer tok-123456"}},
+			"", `{"data": [{"id": 1001, "customer": {"id": "c-777"}}, {"id": 1002}], "total": 2}`),
+		newExchange("POST", "http://api.test/orders/1002/items?customer=c-777&user=alice", http.Header{
+			"Authorization": {"Bearer tok-123456"},
+			"Content-Type":  {"application/json"},
+		}, `{"order": 1002, "orders": [1001, 1002], "customer": "c-777", "qty": 2}`, `{"id": "item-1"}`),
+		newExchange("POST", "http://api.test/checkout", http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+			"item=item-1&order=1001", ``),
+	}
+
+	config := Generate(exchanges, nil, Options{Extract: true})
+
+	login := config.Request[0]
+	assert.Equal(t, map[string]string{"access_token": "access_token"}, login.Extract)
+
+	list := config.Request[1]
+	assert.Equal(t, []string{"Authorization: Bearer ${access_token}"}, list.Headers)
+	assert.Equal(t, map[string]string{"id": "data.1.id", "id_2": "data.0.customer.id", "id_3": "data.0.id"}, list.Extract)
+
+	items := config.Request[2]
+	// "alice" was sent by the login request itself, so it is not a produced value
+	assert.Equal(t, "/orders/${id}/items?customer=${id_2}&user=alice", items.Path)
+	assert.Equal(t, []string{"Authorization: Bearer ${access_token}"}, items.Headers)
+	require.NotNil(t, items.JsonBody)
+	assert.Equal(t, `{"order":${id},"orders":[${id_3},${id}],"customer":"${id_2}","qty":2}`, *items.JsonBody)
+	assert.Equal(t, map[string]string{"id_4": "id"}, items.Extract)
+
+	checkout := config.Request[3]
+	require.NotNil(t, checkout.FormBody)
+	assert.Equal(t, "item=${id_4}&order=${id_3}", *checkout.FormBody)
+
+	require.NoError(t, config.Validate())
+}
+
+func TestGenerate_NoExtract(t *testing.T) {
+	exchanges := []Exchange{
+		newExchange("POST", "http://api.test/login", nil, "", `{"token": "tok-123456"}`),
+		newExchange("GET", "http://api.test/me", http.Header{"Authorization": {"Bearer tok-123456"}}, "", ``),
+	}
+
+	config := Generate(exchanges, nil, Options{})
+
+	// Tokens not turned into variables are not written to the configuration
+	assert.Nil(t, config.Request[0].Extract)
+	assert.Equal(t, []string{"Authorization: [REDACTED]"}, config.Request[1].Headers)
+}
+
+func TestCandidate(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+		ok   bool
+	}{
+		{json: `"abcd"`, want: "abcd", ok: true},
+		{json: `"abc"`, ok: false, want: "abc"},
+		{json: `123`, want: "123", ok: true},
+		{json: `12`, want: "12", ok: false},
+		{json: `1.5e3`, ok: false},
+		{json: `true`, ok: false},
+		{json: `null`, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var value = parseJSON(tt.json)
+			text, ok := candidate(value)
+			assert.Equal(t, tt.ok, ok)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, text)
+			}
+		})
+	}
+}
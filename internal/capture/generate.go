@@ -0,0 +1,323 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ymatsukawa/jak/internal/cassette"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// noiseHeaders lists request headers that are not written to generated requests,
+// because the client sets them itself or they only describe the recording session.
+var noiseHeaders = map[string]bool{
+	"Accept-Encoding":           true,
+	"Accept-Language":           true,
+	"Cache-Control":             true,
+	"Connection":                true,
+	"Content-Length":            true,
+	"Cookie":                    true,
+	"Dnt":                       true,
+	"Host":                      true,
+	"If-Modified-Since":         true,
+	"If-None-Match":             true,
+	"Origin":                    true,
+	"Pragma":                    true,
+	"Priority":                  true,
+	"Proxy-Authorization":       true,
+	"Proxy-Connection":          true,
+	"Referer":                   true,
+	"Te":                        true,
+	"Upgrade-Insecure-Requests": true,
+	"User-Agent":                true,
+}
+
+// noiseHeaderPrefixes lists prefixes of request headers that are not written to generated requests.
+var noiseHeaderPrefixes = []string{"Sec-", "X-Forwarded-"}
+
+// methodVerbs maps HTTP methods to the verb used in generated request names.
+var methodVerbs = map[string]string{
+	"GET":    "Get",
+	"POST":   "Create",
+	"PUT":    "Update",
+	"PATCH":  "Update",
+	"DELETE": "Delete",
+}
+
+// idSegmentPattern matches path segments that look like identifiers rather than resource names
+var idSegmentPattern = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F-]{8,}|.*[0-9].*[0-9].*)$`)
+
+// Options controls how exchanges are turned into a configuration.
+type Options struct {
+	// Extract detects values of responses reused by later requests and turns them
+	// into extract settings and ${var} references
+	Extract bool
+}
+
+// Generate builds a chain configuration from recorded exchanges.
+// Each exchange becomes a request depending on the previous one, so the chain
+// replays the traffic in the order it was observed.
+//
+// Parameters:
+//   - exchanges: Recorded exchanges in completion order
+//   - target: Upstream URL of a reverse proxy, or nil to use the origin of the first exchange
+//   - opts: Generation options
+//
+// Returns:
+//   - *rule.Config: Generated configuration
+func Generate(exchanges []Exchange, target *url.URL, opts Options) *rule.Config {
+	base := baseURL(exchanges, target)
+	config := &rule.Config{BaseUrl: base.String(), Timeout: rule.DefaultTimeout}
+
+	names := make(map[string]int)
+	for i, exchange := range exchanges {
+		req := newRequest(exchange, base)
+		req.Name = uniqueName(requestName(req.Method, req.Path), names)
+		if i > 0 {
+			req.DependsOn = config.Request[i-1].Name
+		}
+		config.Request = append(config.Request, req)
+	}
+
+	if opts.Extract {
+		detectVariables(config, exchanges)
+	}
+	redactHeaders(config)
+	return config
+}
+
+// baseURL determines the base URL of the generated configuration.
+//
+// Parameters:
+//   - exchanges: Recorded exchanges
+//   - target: Upstream URL of a reverse proxy, may be nil
+//
+// Returns:
+//   - *url.URL: Target of a reverse proxy, or the origin of the first exchange
+func baseURL(exchanges []Exchange, target *url.URL) *url.URL {
+	if target != nil {
+		base := *target
+		base.RawQuery = ""
+		base.Fragment = ""
+		return &base
+	}
+	if len(exchanges) == 0 {
+		return &url.URL{Scheme: "http", Host: "localhost"}
+	}
+	return &url.URL{Scheme: exchanges[0].URL.Scheme, Host: exchanges[0].URL.Host}
+}
+
+// newRequest converts an exchange into a request without name and dependency.
+//
+// Parameters:
+//   - exchange: Recorded exchange
+//   - base: Base URL of the configuration
+//
+// Returns:
+//   - rule.Request: Request reproducing the exchange
+func newRequest(exchange Exchange, base *url.URL) rule.Request {
+	req := rule.Request{
+		Method: exchange.Method,
+		Path:   requestPath(exchange.URL, base),
+	}
+
+	// A truncated body cannot be replayed, so the request is generated without it
+	kind := bodyKind(exchange)
+	if len(exchange.Body) > 0 && !exchange.BodyTruncated {
+		body := string(exchange.Body)
+		switch kind {
+		case "json":
+			var compact bytes.Buffer
+			if json.Compact(&compact, exchange.Body) == nil {
+				body = compact.String()
+			}
+			req.JsonBody = &body
+		case "form":
+			req.FormBody = &body
+		default:
+			req.RawBody = &body
+		}
+	}
+
+	req.Headers = requestHeaders(exchange.Header, kind)
+	return req
+}
+
+// requestPath returns the path of a request relative to the base URL.
+// Requests to another origin keep their absolute URL.
+//
+// Parameters:
+//   - target: URL the request was forwarded to
+//   - base: Base URL of the configuration
+//
+// Returns:
+//   - string: Path with query string, or absolute URL
+func requestPath(target *url.URL, base *url.URL) string {
+	if target.Scheme != base.Scheme || target.Host != base.Host {
+		return target.String()
+	}
+
+	path := target.EscapedPath()
+	prefix := strings.TrimSuffix(base.EscapedPath(), "/")
+	if prefix != "" && (path == prefix || strings.HasPrefix(path, prefix+"/")) {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+	return path
+}
+
+// bodyKind classifies the body of a request by its content type.
+//
+// Parameters:
+//   - exchange: Recorded exchange
+//
+// Returns:
+//   - string: "json", "form" or "raw"
+func bodyKind(exchange Exchange) string {
+	mediaType, _, _ := mime.ParseMediaType(exchange.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		return "form"
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return "json"
+	case mediaType == "" && json.Valid(exchange.Body):
+		return "json"
+	default:
+		return "raw"
+	}
+}
+
+// requestHeaders returns the headers worth keeping in a generated request.
+// Content-Type is dropped for JSON and form bodies, which set it themselves.
+//
+// Parameters:
+//   - header: Recorded request headers
+//   - kind: Body kind of the request
+//
+// Returns:
+//   - []string: Headers in format "Key: Value", sorted by name
+func requestHeaders(header http.Header, kind string) []string {
+	var headers []string
+	for _, name := range sortedKeys(header) {
+		if isNoiseHeader(name) || (name == "Content-Type" && kind != "raw") {
+			continue
+		}
+		for _, value := range header[name] {
+			headers = append(headers, name+": "+value)
+		}
+	}
+	return headers
+}
+
+// redactHeaders replaces the values of sensitive headers, such as Authorization,
+// with a placeholder, so recorded credentials are not written to the configuration.
+// Values turned into variable references by detection are kept.
+//
+// Parameters:
+//   - config: Generated configuration, modified in place
+func redactHeaders(config *rule.Config) {
+	for i := range config.Request {
+		for j, header := range config.Request[i].Headers {
+			name, value, _ := strings.Cut(header, ": ")
+			if cassette.IsRedactedHeader(name) && !strings.Contains(value, "${") {
+				config.Request[i].Headers[j] = name + ": " + cassette.Redacted
+			}
+		}
+	}
+}
+
+// isNoiseHeader reports whether a request header is left out of generated requests.
+//
+// Parameters:
+//   - name: Canonical header name
+//
+// Returns:
+//   - bool: True if the header is noise
+func isNoiseHeader(name string) bool {
+	if noiseHeaders[name] {
+		return true
+	}
+	for _, prefix := range noiseHeaderPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestName derives a readable name from the method and path of a request,
+// e.g. "GET /users/42/orders" becomes "Get Users Orders".
+//
+// Parameters:
+//   - method: HTTP method
+//   - path: Path relative to the base URL, or absolute URL
+//
+// Returns:
+//   - string: Request name
+func requestName(method, path string) string {
+	verb, ok := methodVerbs[method]
+	if !ok {
+		verb = titleCase(strings.ToLower(method))
+	}
+
+	if parsed, err := url.Parse(path); err == nil {
+		path = parsed.Path
+	}
+
+	words := []string{verb}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || idSegmentPattern.MatchString(segment) {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		}) {
+			words = append(words, titleCase(word))
+		}
+	}
+	if len(words) == 1 {
+		words = append(words, "Root")
+	}
+	return strings.Join(words, " ")
+}
+
+// uniqueName makes a request name unique by numbering repeated names.
+//
+// Parameters:
+//   - name: Derived request name
+//   - names: Number of times each name has been used
+//
+// Returns:
+//   - string: Name, or name followed by a number if it was used before
+func uniqueName(name string, names map[string]int) string {
+	names[name]++
+	if names[name] == 1 {
+		return name
+	}
+	return fmt.Sprintf("%s %d", name, names[name])
+}
+
+// titleCase upper-cases the first letter of a word.
+//
+// Parameters:
+//   - word: Word to convert
+//
+// Returns:
+//   - string: Word starting with an upper-case letter
+func titleCase(word string) string {
+	if word == "" {
+		return word
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
@@ -0,0 +1,135 @@
+package capture
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExchange creates an exchange for tests
+func newExchange(method, rawURL string, header http.Header, body, responseBody string) Exchange {
+	parsed, _ := url.Parse(rawURL)
+	if header == nil {
+		header = http.Header{}
+	}
+	return Exchange{
+		Method:       method,
+		URL:          parsed,
+		Header:       header,
+		Body:         []byte(body),
+		StatusCode:   200,
+		ResponseBody: []byte(responseBody),
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	target, _ := url.Parse("https://api.test/v1")
+	exchanges := []Exchange{
+		newExchange("GET", "https://api.test/v1/users?page=2", http.Header{
+			"Accept":          {"application/json"},
+			"User-Agent":      {"curl/8.0"},
+			"Accept-Encoding": {"gzip"},
+			"Sec-Fetch-Mode":  {"cors"},
+			"X-Tenant":        {"acme"},
+		}, "", `[]`),
+		newExchange("POST", "https://api.test/v1/users", http.Header{"Content-Type": {"application/json"}}, "{\n  \"name\": \"a\"\n}", `{}`),
+		newExchange("PUT", "https://api.test/v1/users/42/profile-settings", http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}, "a=1&b=2", ``),
+		newExchange("POST", "https://api.test/v1/upload", http.Header{"Content-Type": {"text/csv"}}, "a,b\n1,2", ``),
+		newExchange("GET", "https://api.test/v1/users", nil, "", ``),
+		newExchange("OPTIONS", "https://api.test/v1/", nil, "", ``),
+		newExchange("GET", "https://cdn.test/assets/app.js", nil, "", ``),
+	}
+
+	config := Generate(exchanges, target, Options{})
+
+	assert.Equal(t, "https://api.test/v1", config.BaseUrl)
+	require.Len(t, config.Request, 7)
+
+	list := config.Request[0]
+	assert.Equal(t, "Get Users", list.Name)
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "/users?page=2", list.Path)
+	assert.Equal(t, []string{"Accept: application/json", "X-Tenant: acme"}, list.Headers)
+	assert.Empty(t, list.DependsOn)
+
+	create := config.Request[1]
+	assert.Equal(t, "Create Users", create.Name)
+	assert.Equal(t, "Get Users", create.DependsOn)
+	require.NotNil(t, create.JsonBody)
+	assert.Equal(t, `{"name":"a"}`, *create.JsonBody)
+	assert.Empty(t, create.Headers)
+
+	update := config.Request[2]
+	assert.Equal(t, "Update Users Profile Settings", update.Name)
+	assert.Equal(t, "/users/42/profile-settings", update.Path)
+	require.NotNil(t, update.FormBody)
+	assert.Equal(t, "a=1&b=2", *update.FormBody)
+
+	upload := config.Request[3]
+	require.NotNil(t, upload.RawBody)
+	assert.Equal(t, "a,b\n1,2", *upload.RawBody)
+	assert.Equal(t, []string{"Content-Type: text/csv"}, upload.Headers)
+
+	assert.Equal(t, "Get Users 2", config.Request[4].Name)
+	assert.Equal(t, "Options Root", config.Request[5].Name)
+	assert.Equal(t, "/", config.Request[5].Path)
+	assert.Equal(t, "https://cdn.test/assets/app.js", config.Request[6].Path)
+
+	require.NoError(t, config.Validate())
+}
+
+func TestGenerate_Credentials(t *testing.T) {
+	exchanges := []Exchange{
+		newExchange("GET", "http://api.test/me", http.Header{
+			"Authorization":       {"Basic YWxpY2U6c2VjcmV0"},
+			"Cookie":              {"session=abc"},
+			"Proxy-Authorization": {"Basic cHJveHk6c2VjcmV0"},
+			"X-Tenant":            {"acme"},
+		}, "", ``),
+	}
+
+	config := Generate(exchanges, nil, Options{})
+
+	assert.Equal(t, []string{"Authorization: [REDACTED]", "X-Tenant: acme"}, config.Request[0].Headers)
+}
+
+func TestGenerate_TruncatedBody(t *testing.T) {
+	exchange := newExchange("POST", "http://api.test/upload", http.Header{"Content-Type": {"application/json"}}, `{"data": "aaa`, ``)
+	exchange.BodyTruncated = true
+
+	config := Generate([]Exchange{exchange}, nil, Options{})
+
+	assert.Nil(t, config.Request[0].JsonBody)
+	assert.Nil(t, config.Request[0].RawBody)
+}
+
+func TestGenerate_ForwardProxy(t *testing.T) {
+	config := Generate([]Exchange{newExchange("GET", "http://api.test:8080/health", nil, "", "")}, nil, Options{})
+
+	assert.Equal(t, "http://api.test:8080", config.BaseUrl)
+	assert.Equal(t, "/health", config.Request[0].Path)
+}
+
+func TestRequestName(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: "GET", path: "/users/42", want: "Get Users"},
+		{method: "DELETE", path: "/users/550e8400-e29b-41d4-a716-446655440000", want: "Delete Users"},
+		{method: "PATCH", path: "/v2/orders/ab12cd/items", want: "Update V2 Orders Items"},
+		{method: "HEAD", path: "/status.json", want: "Head Status Json"},
+		{method: "GET", path: "/2024/reports?page=2", want: "Get Reports"},
+		{method: "GET", path: "https://cdn.test/assets/app.js", want: "Get Assets App Js"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, requestName(tt.method, tt.path))
+		})
+	}
+}
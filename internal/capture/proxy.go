@@ -0,0 +1,359 @@
+// Package capture records live HTTP traffic through a proxy and turns the
+// observed exchanges into a jak configuration.
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// maxBodySize limits the size of a request or response body captured by the proxy.
+// Larger bodies are forwarded in full, but only their beginning is captured.
+var maxBodySize = 10 * 1024 * 1024 // 10MB
+
+// Exchange is a request observed by the proxy and the response it received.
+type Exchange struct {
+	// Method is the HTTP method
+	Method string
+
+	// URL is the full URL the request was forwarded to
+	URL *url.URL
+
+	// Header contains the request headers
+	Header http.Header
+
+	// Body is the request body
+	Body []byte
+
+	// BodyTruncated is true if the request body exceeded the capture limit
+	BodyTruncated bool
+
+	// StatusCode is the status code of the response
+	StatusCode int
+
+	// ResponseHeader contains the response headers
+	ResponseHeader http.Header
+
+	// ResponseBody is the response body
+	ResponseBody []byte
+
+	// ResponseBodyTruncated is true if the response body exceeded the capture limit
+	ResponseBodyTruncated bool
+
+	// Duration is the time taken by the upstream server
+	Duration time.Duration
+}
+
+// ExchangeCollector is called for every exchange the proxy completes.
+//
+// Parameters:
+//   - exchange: Completed exchange
+type ExchangeCollector func(exchange Exchange)
+
+// ErrorCollector is called for every request the proxy fails to forward.
+//
+// Parameters:
+//   - method: Request method
+//   - url: Request URL
+//   - err: Error encountered while forwarding
+type ErrorCollector func(method, url string, err error)
+
+// captureKey is the context key of the capture state of a request
+type captureKey struct{}
+
+// captureState holds the request body and start time of a forwarded request.
+type captureState struct {
+	body  *capturedBody
+	start time.Time
+}
+
+// capturedBody keeps a bounded copy of a body streamed through the proxy.
+type capturedBody struct {
+	// mu guards buf and truncated, which are written while the body is forwarded
+	mu sync.Mutex
+
+	// buf holds the captured beginning of the body
+	buf bytes.Buffer
+
+	// truncated is true if the body exceeded maxBodySize
+	truncated bool
+}
+
+// Write captures data up to maxBodySize and discards the rest.
+//
+// Parameters:
+//   - data: Data read from the body
+//
+// Returns:
+//   - int: Length of data, so the forwarded stream is never interrupted
+//   - error: Always nil
+func (c *capturedBody) Write(data []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	remaining := maxBodySize - c.buf.Len()
+	if len(data) > remaining {
+		c.buf.Write(data[:remaining])
+		c.truncated = true
+	} else {
+		c.buf.Write(data)
+	}
+	return len(data), nil
+}
+
+// bytes returns the captured data.
+//
+// Returns:
+//   - []byte: Copy of the captured beginning of the body
+//   - bool: True if the body was truncated
+func (c *capturedBody) bytes() ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return bytes.Clone(c.buf.Bytes()), c.truncated
+}
+
+// recordingBody forwards a response body, capturing it on the way, and records
+// the exchange once the body has been read or closed.
+type recordingBody struct {
+	io.Reader
+
+	// body is the upstream response body
+	body io.ReadCloser
+
+	// once records the exchange a single time
+	once sync.Once
+
+	// done records the exchange
+	done func()
+}
+
+// Read reads from the body and records the exchange at the end of the body.
+//
+// Parameters:
+//   - data: Buffer to read into
+//
+// Returns:
+//   - int: Number of bytes read
+//   - error: Error of the underlying body, io.EOF at its end
+func (b *recordingBody) Read(data []byte) (int, error) {
+	n, err := b.Reader.Read(data)
+	if err == io.EOF {
+		b.once.Do(b.done)
+	}
+	return n, err
+}
+
+// Close closes the body and records the exchange if it was not read to the end.
+//
+// Returns:
+//   - error: Error closing the underlying body
+func (b *recordingBody) Close() error {
+	err := b.body.Close()
+	b.once.Do(b.done)
+	return err
+}
+
+// Proxy forwards requests and records the exchanges.
+// With a target it is a reverse proxy forwarding every request to the target;
+// without one it is a forward proxy for plain HTTP requests with absolute URLs.
+type Proxy struct {
+	// proxy forwards the requests
+	proxy *httputil.ReverseProxy
+
+	// target is the upstream of a reverse proxy, nil for a forward proxy
+	target *url.URL
+
+	// mu guards exchanges
+	mu sync.Mutex
+
+	// exchanges are the completed exchanges in completion order
+	exchanges []Exchange
+
+	// exchangeCollector receives every completed exchange
+	exchangeCollector ExchangeCollector
+
+	// errorCollector receives every failed request
+	errorCollector ErrorCollector
+}
+
+// NewProxy creates a recording proxy.
+//
+// Parameters:
+//   - target: Upstream URL of a reverse proxy, e.g. "https://api.example.com"; empty for a forward proxy
+//
+// Returns:
+//   - *Proxy: Proxy ready to be used as an http.Handler
+//   - error: Error if the target is not an absolute http or https URL
+func NewProxy(target string) (*Proxy, error) {
+	p := &Proxy{}
+
+	if target != "" {
+		parsed, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target '%s': %w", target, err)
+		}
+		if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid target '%s': must be an absolute http or https URL", target)
+		}
+		p.target = parsed
+	}
+
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite:        p.rewrite,
+		ModifyResponse: p.record,
+		ErrorHandler:   p.handleError,
+	}
+	return p, nil
+}
+
+// SetExchangeCollector sets the function called for every completed exchange.
+//
+// Parameters:
+//   - collector: Function receiving the exchanges
+func (p *Proxy) SetExchangeCollector(collector ExchangeCollector) {
+	p.exchangeCollector = collector
+}
+
+// SetErrorCollector sets the function called for every request that fails to forward.
+//
+// Parameters:
+//   - collector: Function receiving the failures
+func (p *Proxy) SetErrorCollector(collector ErrorCollector) {
+	p.errorCollector = collector
+}
+
+// Target returns the upstream URL of a reverse proxy.
+//
+// Returns:
+//   - *url.URL: Target URL, or nil for a forward proxy
+func (p *Proxy) Target() *url.URL {
+	return p.target
+}
+
+// Exchanges returns the exchanges recorded so far.
+//
+// Returns:
+//   - []Exchange: Copy of the recorded exchanges in completion order
+func (p *Proxy) Exchanges() []Exchange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Exchange(nil), p.exchanges...)
+}
+
+// ServeHTTP forwards a request and records the exchange.
+//
+// Parameters:
+//   - w: Response writer
+//   - r: Incoming request
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.target == nil {
+		if r.Method == http.MethodConnect {
+			http.Error(w, "HTTPS cannot be recorded by the forward proxy; use --target to record as a reverse proxy", http.StatusNotImplemented)
+			return
+		}
+		if !r.URL.IsAbs() {
+			http.Error(w, "request URL must be absolute; configure this server as HTTP proxy or use --target", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The body is streamed to the upstream server; only a bounded copy is kept
+	state := &captureState{body: &capturedBody{}, start: time.Now()}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(r.Body, state.body), r.Body}
+	}
+
+	p.proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), captureKey{}, state)))
+}
+
+// rewrite points the outbound request at the upstream server.
+//
+// Parameters:
+//   - pr: Inbound and outbound request
+func (p *Proxy) rewrite(pr *httputil.ProxyRequest) {
+	if p.target != nil {
+		pr.SetURL(p.target)
+	}
+	pr.Out.Header.Del("Proxy-Connection")
+
+	// Let the transport negotiate compression so captured bodies are plain text
+	pr.Out.Header.Del("Accept-Encoding")
+}
+
+// record captures the response of a forwarded request. The body is streamed
+// to the client and the exchange is recorded once it has been transferred.
+//
+// Parameters:
+//   - resp: Upstream response
+//
+// Returns:
+//   - error: Always nil
+func (p *Proxy) record(resp *http.Response) error {
+	state, _ := resp.Request.Context().Value(captureKey{}).(*captureState)
+	if state == nil {
+		return nil
+	}
+
+	captured := &capturedBody{}
+	resp.Body = &recordingBody{
+		Reader: io.TeeReader(resp.Body, captured),
+		body:   resp.Body,
+		done:   func() { p.complete(resp, state, captured) },
+	}
+	return nil
+}
+
+// complete records an exchange whose response body has been transferred.
+//
+// Parameters:
+//   - resp: Upstream response
+//   - state: Capture state of the request
+//   - captured: Captured response body
+func (p *Proxy) complete(resp *http.Response, state *captureState, captured *capturedBody) {
+	body, truncated := state.body.bytes()
+	responseBody, responseTruncated := captured.bytes()
+
+	exchange := Exchange{
+		Method:                resp.Request.Method,
+		URL:                   resp.Request.URL,
+		Header:                resp.Request.Header.Clone(),
+		Body:                  body,
+		BodyTruncated:         truncated,
+		StatusCode:            resp.StatusCode,
+		ResponseHeader:        resp.Header.Clone(),
+		ResponseBody:          responseBody,
+		ResponseBodyTruncated: responseTruncated,
+		Duration:              time.Since(state.start),
+	}
+
+	p.mu.Lock()
+	p.exchanges = append(p.exchanges, exchange)
+	p.mu.Unlock()
+
+	if p.exchangeCollector != nil {
+		p.exchangeCollector(exchange)
+	}
+}
+
+// handleError answers a request that could not be forwarded with 502.
+//
+// Parameters:
+//   - w: Response writer
+//   - r: Outbound request
+//   - err: Error encountered while forwarding
+func (p *Proxy) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	if p.errorCollector != nil {
+		p.errorCollector(r.Method, r.URL.String(), err)
+	}
+	http.Error(w, fmt.Sprintf("failed to forward request: %s", err), http.StatusBadGateway)
+}
@@ -0,0 +1,158 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUpstream starts a server echoing the method, path, host and body of requests
+func newUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"method": %q, "path": %q, "host": %q, "body": %q, "gzip": %q}`,
+			r.Method, r.URL.RequestURI(), r.Host, body, r.Header.Get("Accept-Encoding"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// startProxy starts a recording proxy and collects its exchanges and errors
+func startProxy(t *testing.T, target string) (*httptest.Server, *Proxy, func() []error) {
+	t.Helper()
+	proxy, err := NewProxy(target)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var errs []error
+	proxy.SetErrorCollector(func(method, url string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	})
+
+	server := httptest.NewServer(proxy)
+	t.Cleanup(server.Close)
+	return server, proxy, func() []error {
+		mu.Lock()
+		defer mu.Unlock()
+		return errs
+	}
+}
+
+func TestProxy_Reverse(t *testing.T) {
+	upstream := newUpstream(t)
+	server, proxy, _ := startProxy(t, upstream.URL+"/api")
+
+	var collected []Exchange
+	proxy.SetExchangeCollector(func(exchange Exchange) { collected = append(collected, exchange) })
+
+	req, _ := http.NewRequest("POST", server.URL+"/users?page=2", strings.NewReader(`{"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.JSONEq(t, fmt.Sprintf(`{"method": "POST", "path": "/api/users?page=2", "host": %q, "body": "{\"name\":\"a\"}", "gzip": "gzip"}`, upstreamURL.Host), string(body))
+
+	exchanges := proxy.Exchanges()
+	require.Len(t, exchanges, 1)
+	exchange := exchanges[0]
+	assert.Equal(t, "POST", exchange.Method)
+	assert.Equal(t, upstream.URL+"/api/users?page=2", exchange.URL.String())
+	assert.Equal(t, "application/json", exchange.Header.Get("Content-Type"))
+	assert.Equal(t, `{"name":"a"}`, string(exchange.Body))
+	assert.Equal(t, http.StatusCreated, exchange.StatusCode)
+	assert.Equal(t, string(body), string(exchange.ResponseBody))
+	assert.Equal(t, exchanges, collected)
+}
+
+func TestProxy_LargeBody(t *testing.T) {
+	limit := maxBodySize
+	maxBodySize = 16
+	t.Cleanup(func() { maxBodySize = limit })
+
+	upstream := newUpstream(t)
+	server, proxy, _ := startProxy(t, upstream.URL)
+
+	recorded := make(chan Exchange, 1)
+	proxy.SetExchangeCollector(func(exchange Exchange) { recorded <- exchange })
+
+	payload := strings.Repeat("x", 100)
+	resp, err := http.Post(server.URL+"/upload", "text/plain", strings.NewReader(payload))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// The whole body is forwarded both ways, only the captured copy is cut off
+	assert.Contains(t, string(body), payload)
+	exchange := <-recorded
+	assert.Equal(t, payload[:16], string(exchange.Body))
+	assert.True(t, exchange.BodyTruncated)
+	assert.Equal(t, string(body[:16]), string(exchange.ResponseBody))
+	assert.True(t, exchange.ResponseBodyTruncated)
+}
+
+func TestProxy_Forward(t *testing.T) {
+	upstream := newUpstream(t)
+	server, proxy, _ := startProxy(t, "")
+
+	proxyURL, _ := url.Parse(server.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+	resp, err := client.Get(upstream.URL + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, proxy.Exchanges(), 1)
+	assert.Equal(t, upstream.URL+"/health", proxy.Exchanges()[0].URL.String())
+
+	// Relative requests and HTTPS tunnels cannot be forwarded
+	resp, err = http.Get(server.URL + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodConnect, server.URL, nil)
+	req.Host = "example.com:443"
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+	assert.Len(t, proxy.Exchanges(), 1)
+}
+
+func TestProxy_UpstreamDown(t *testing.T) {
+	upstream := newUpstream(t)
+	upstream.Close()
+	server, proxy, errs := startProxy(t, upstream.URL)
+
+	resp, err := http.Get(server.URL + "/users")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Empty(t, proxy.Exchanges())
+	assert.Len(t, errs(), 1)
+}
+
+func TestNewProxy_InvalidTarget(t *testing.T) {
+	for _, target := range []string{"api.example.com", "ftp://example.com", "http://"} {
+		_, err := NewProxy(target)
+		assert.ErrorContains(t, err, "must be an absolute http or https URL", target)
+	}
+}
@@ -0,0 +1,139 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// Write writes a generated configuration as TOML.
+// Only the settings Generate produces are written, in the layout of the sample configs.
+//
+// Parameters:
+//   - w: Writer receiving the TOML document
+//   - config: Generated configuration
+//
+// Returns:
+//   - error: Any error encountered while writing
+func Write(w io.Writer, config *rule.Config) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "base_url = %s\n", quote(config.BaseUrl))
+	fmt.Fprintf(&b, "timeout = %d\n", config.Timeout)
+
+	for _, req := range config.Request {
+		b.WriteString("\n[[request]]\n")
+		fmt.Fprintf(&b, "name = %s\n", quote(req.Name))
+		fmt.Fprintf(&b, "method = %s\n", quote(req.Method))
+		fmt.Fprintf(&b, "path = %s\n", quote(req.Path))
+		if len(req.Headers) > 0 {
+			quoted := make([]string, len(req.Headers))
+			for i, header := range req.Headers {
+				quoted[i] = quote(header)
+			}
+			fmt.Fprintf(&b, "headers = [%s]\n", strings.Join(quoted, ", "))
+		}
+		if req.JsonBody != nil {
+			fmt.Fprintf(&b, "json_body = %s\n", literal(*req.JsonBody))
+		}
+		if req.FormBody != nil {
+			fmt.Fprintf(&b, "form_body = %s\n", literal(*req.FormBody))
+		}
+		if req.RawBody != nil {
+			fmt.Fprintf(&b, "raw_body = %s\n", literal(*req.RawBody))
+		}
+		if len(req.Extract) > 0 {
+			names := make([]string, 0, len(req.Extract))
+			for name := range req.Extract {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			pairs := make([]string, len(names))
+			for i, name := range names {
+				pairs[i] = fmt.Sprintf("%s = %s", name, quote(req.Extract[name]))
+			}
+			fmt.Fprintf(&b, "extract = { %s }\n", strings.Join(pairs, ", "))
+		}
+		if req.DependsOn != "" {
+			fmt.Fprintf(&b, "depends_on = %s\n", quote(req.DependsOn))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteFile writes a generated configuration to a TOML file.
+//
+// Parameters:
+//   - path: Path of the output file
+//   - config: Generated configuration
+//
+// Returns:
+//   - error: Any error encountered while writing the file
+func WriteFile(path string, config *rule.Config) error {
+	var b strings.Builder
+	if err := Write(&b, config); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// quote returns a value as a TOML basic string.
+//
+// Parameters:
+//   - value: String to quote
+//
+// Returns:
+//   - string: Double-quoted string with escapes
+func quote(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// literal returns a value as a TOML literal string when possible, so bodies
+// such as JSON stay readable, and as a basic string otherwise.
+//
+// Parameters:
+//   - value: String to quote
+//
+// Returns:
+//   - string: Single-quoted string, or double-quoted string with escapes
+func literal(value string) string {
+	for _, r := range value {
+		if r == '\'' || r == '\n' || r == '\r' || (r < 0x20 && r != '\t') || r == 0x7f {
+			return quote(value)
+		}
+	}
+	return "'" + value + "'"
+}
@@ -0,0 +1,66 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// parseJSON parses a JSON value for tests
+func parseJSON(raw string) gjson.Result {
+	return gjson.Parse(raw)
+}
+
+func TestWriteFile_RoundTrip(t *testing.T) {
+	jsonBody := `{"name":"it's \"quoted\"","tags":["a"]}`
+	formBody := "a=1&b=${token}"
+	rawBody := "line 1\nline 2\ttab"
+	config := &rule.Config{
+		BaseUrl: "https://api.test/v1",
+		Timeout: rule.DefaultTimeout,
+		Request: []rule.Request{
+			{
+				Name:     "Login",
+				Method:   "POST",
+				Path:     "/login",
+				Headers:  []string{`X-Quote: say "hi" \ bye`},
+				FormBody: &formBody,
+				Extract:  map[string]string{"token": "access_token", "id": `data\.v1.id`},
+			},
+			{Name: "Create", Method: "POST", Path: "/users?x=${id}", JsonBody: &jsonBody, DependsOn: "Login"},
+			{Name: "Upload", Method: "PUT", Path: "/files/a b", RawBody: &rawBody, DependsOn: "Create"},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "captured.toml")
+	require.NoError(t, WriteFile(path, config))
+
+	loaded, err := rule.LoadConfig(path)
+	require.NoError(t, err)
+	require.NoError(t, loaded.Validate())
+
+	assert.Equal(t, config.BaseUrl, loaded.BaseUrl)
+	assert.Equal(t, config.Timeout, loaded.Timeout)
+	require.Len(t, loaded.Request, 3)
+	for i := range config.Request {
+		assert.Equal(t, config.Request[i].Name, loaded.Request[i].Name)
+		assert.Equal(t, config.Request[i].Method, loaded.Request[i].Method)
+		assert.Equal(t, config.Request[i].Path, loaded.Request[i].Path)
+		assert.Equal(t, config.Request[i].DependsOn, loaded.Request[i].DependsOn)
+		assert.Equal(t, config.Request[i].JsonBody, loaded.Request[i].JsonBody)
+		assert.Equal(t, config.Request[i].FormBody, loaded.Request[i].FormBody)
+		assert.Equal(t, config.Request[i].RawBody, loaded.Request[i].RawBody)
+	}
+	assert.Equal(t, config.Request[0].Headers, loaded.Request[0].Headers)
+	assert.Equal(t, config.Request[0].Extract, loaded.Request[0].Extract)
+
+	// Bodies without quotes or newlines stay readable as literal strings
+	content, _ := os.ReadFile(path)
+	assert.True(t, strings.Contains(string(content), "form_body = 'a=1&b=${token}'"))
+}
@@ -0,0 +1,327 @@
+// Package cassette records the requests a client performs to a JSON file and
+// replays them from that file without network access.
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// Version is the version of the cassette file format.
+const Version = 1
+
+// Body encodings of recorded bodies.
+const (
+	// EncodingBase64 marks a body that is not valid UTF-8 and is stored base64 encoded
+	EncodingBase64 = "base64"
+)
+
+// Redacted replaces the value of sensitive request headers and query parameters in a cassette.
+const Redacted = "[REDACTED]"
+
+// redactedHeaders lists request headers whose values are not written to cassettes
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// Cassette is a recording of the requests performed during a run.
+type Cassette struct {
+	// Version is the version of the file format
+	Version int `json:"version"`
+
+	// RedactedHeaders lists request headers, such as API key headers, whose values are redacted
+	// in addition to the default sensitive headers; they are redacted in replayed requests before matching
+	RedactedHeaders []string `json:"redacted_headers,omitempty"`
+
+	// RedactedQuery lists query parameters, such as API keys, whose values are redacted in URLs;
+	// the same parameters are redacted in replayed requests before matching
+	RedactedQuery []string `json:"redacted_query,omitempty"`
+
+	// Interactions are the recorded request/response pairs, in the order they completed
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response it received.
+type Interaction struct {
+	// Request is the recorded request
+	Request RecordedRequest `json:"request"`
+
+	// Response is the recorded response
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as stored in a cassette.
+type RecordedRequest struct {
+	// Method is the HTTP method
+	Method string `json:"method"`
+
+	// URL is the request URL
+	URL string `json:"url"`
+
+	// Headers are the request headers; sensitive values are redacted
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body is the request body
+	Body string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as stored in a cassette.
+type RecordedResponse struct {
+	// Status is the HTTP status code
+	Status int `json:"status"`
+
+	// Headers are the response headers
+	Headers http.Header `json:"headers,omitempty"`
+
+	// Body is the response body, base64 encoded if BodyEncoding says so
+	Body string `json:"body,omitempty"`
+
+	// BodyEncoding is EncodingBase64 for binary bodies, empty for text
+	BodyEncoding string `json:"body_encoding,omitempty"`
+
+	// Redirects are the redirect responses followed before the response
+	Redirects []RecordedRedirect `json:"redirects,omitempty"`
+}
+
+// RecordedRedirect is a redirect hop as stored in a cassette.
+type RecordedRedirect struct {
+	// Method is the method of the request that was redirected
+	Method string `json:"method"`
+
+	// URL is the URL of the request that was redirected
+	URL string `json:"url"`
+
+	// Status is the status code of the redirect response
+	Status int `json:"status"`
+
+	// Headers are the headers of the redirect response
+	Headers http.Header `json:"headers,omitempty"`
+}
+
+// Load reads a cassette file.
+//
+// Parameters:
+//   - path: Path of the cassette file
+//
+// Returns:
+//   - *Cassette: Loaded cassette
+//   - error: Error if the file cannot be read or parsed
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", se.ErrInvalidCassette, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s: %s", se.ErrInvalidCassette, path, err)
+	}
+	if cassette.Version != Version {
+		return nil, fmt.Errorf("%w: unsupported version %d in %s", se.ErrInvalidCassette, cassette.Version, path)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to a file.
+//
+// Parameters:
+//   - path: Path of the cassette file
+//
+// Returns:
+//   - error: Error if the file cannot be written
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// newRecordedRequest converts a request for storage.
+//
+// Parameters:
+//   - req: Request to record
+//   - redactedHeaders: Headers redacted in addition to the default sensitive headers
+//
+// Returns:
+//   - RecordedRequest: Request with sensitive header values redacted
+func newRecordedRequest(req *jakhttp.Request, redactedHeaders []string) RecordedRequest {
+	recorded := RecordedRequest{
+		Method: strings.ToUpper(req.GetMethod()),
+		URL:    req.GetURL(),
+	}
+
+	headers := map[string]string{}
+	if req.Headers != nil {
+		for key, value := range req.Headers.GetAllHeaders() {
+			headers[key] = value
+		}
+	}
+	if contentType := req.GetContentType(); contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+	for key := range headers {
+		if IsRedactedHeader(key) || containsFold(redactedHeaders, key) {
+			headers[key] = Redacted
+		}
+	}
+	if len(headers) > 0 {
+		recorded.Headers = headers
+	}
+
+	if req.Body != nil {
+		recorded.Body = req.Body.Content()
+	}
+	return recorded
+}
+
+// newRecordedResponse converts a response for storage.
+// The response body is read and replaced so the caller can still consume it.
+//
+// Parameters:
+//   - resp: Response to record
+//
+// Returns:
+//   - RecordedResponse: Recorded response
+//   - error: Error if the body cannot be read
+func newRecordedResponse(resp *jakhttp.Response) (RecordedResponse, error) {
+	recorded := RecordedResponse{
+		Status:  resp.StatusCode,
+		Headers: resp.Header,
+	}
+
+	if resp.Body != nil {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return recorded, fmt.Errorf("%w: %s", se.ErrResponseReadFailed, err)
+		}
+		resp.Body = io.NopCloser(strings.NewReader(string(body)))
+
+		if utf8.Valid(body) {
+			recorded.Body = string(body)
+		} else {
+			recorded.Body = base64.StdEncoding.EncodeToString(body)
+			recorded.BodyEncoding = EncodingBase64
+		}
+	}
+
+	for _, hop := range resp.Redirects {
+		recorded.Redirects = append(recorded.Redirects, RecordedRedirect{
+			Method:  hop.Method,
+			URL:     hop.URL,
+			Status:  hop.StatusCode,
+			Headers: hop.Header,
+		})
+	}
+	return recorded, nil
+}
+
+// response recreates the recorded response.
+//
+// Returns:
+//   - *jakhttp.Response: Response with the recorded status, headers, body and redirects
+//   - error: Error if a base64 body cannot be decoded
+func (r RecordedResponse) response() (*jakhttp.Response, error) {
+	body := []byte(r.Body)
+	if r.BodyEncoding == EncodingBase64 {
+		decoded, err := base64.StdEncoding.DecodeString(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base64 body: %s", se.ErrInvalidCassette, err)
+		}
+		body = decoded
+	}
+
+	header := r.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	var redirects []jakhttp.RedirectHop
+	for _, hop := range r.Redirects {
+		redirects = append(redirects, jakhttp.RedirectHop{
+			Method:     hop.Method,
+			URL:        hop.URL,
+			StatusCode: hop.Status,
+			Header:     hop.Headers.Clone(),
+		})
+	}
+
+	return &jakhttp.Response{
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+		StatusCode: r.Status,
+		Redirects:  redirects,
+	}, nil
+}
+
+// IsRedactedHeader reports whether the value of a request header is sensitive and
+// is not written to cassettes or other recorded files.
+//
+// Parameters:
+//   - name: Header name
+//
+// Returns:
+//   - bool: True if the header is sensitive
+func IsRedactedHeader(name string) bool {
+	return containsFold(redactedHeaders, name)
+}
+
+// containsFold reports whether a list contains a header name, ignoring case.
+//
+// Parameters:
+//   - names: Header names
+//   - name: Header name to look for
+//
+// Returns:
+//   - bool: True if the name is in the list
+func containsFold(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(name, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactQuery replaces the values of query parameters in a URL with Redacted.
+// Other parameters and their order are kept as they are.
+//
+// Parameters:
+//   - rawURL: URL to redact
+//   - names: Names of the query parameters to redact
+//
+// Returns:
+//   - string: URL with the parameter values redacted, or the input if it cannot be parsed
+func redactQuery(rawURL string, names []string) string {
+	if len(names) == 0 {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.RawQuery == "" {
+		return rawURL
+	}
+
+	pairs := strings.Split(parsed.RawQuery, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err == nil && slices.Contains(names, name) {
+			pairs[i] = key + "=" + Redacted
+		}
+	}
+	parsed.RawQuery = strings.Join(pairs, "&")
+	return parsed.String()
+}
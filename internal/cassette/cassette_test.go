@@ -0,0 +1,123 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+func TestCassette_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := &Cassette{Version: Version, Interactions: []Interaction{{
+		Request: RecordedRequest{Method: "POST", URL: "http://api.test/users", Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"name":"a"}`},
+		Response: RecordedResponse{
+			Status:    201,
+			Headers:   http.Header{"Location": {"/users/1"}},
+			Body:      `{"id":1}`,
+			Redirects: []RecordedRedirect{{Method: "POST", URL: "http://api.test/u", Status: 307, Headers: http.Header{"Location": {"/users"}}}},
+		},
+	}}}
+
+	require.NoError(t, cassette.Save(path))
+	loaded, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, cassette, loaded)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "missing file", path: filepath.Join(dir, "missing.json"), wantErr: "no such file"},
+		{name: "invalid json", path: write("invalid.json", "{"), wantErr: "failed to parse"},
+		{name: "unsupported version", path: write("version.json", `{"version": 2, "interactions": []}`), wantErr: "unsupported version 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.path)
+
+			assert.ErrorIs(t, err, se.ErrInvalidCassette)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewRecordedRequest(t *testing.T) {
+	req := jakhttp.NewRequest("http://api.test/users", "post",
+		jakhttp.WithHeaders([]string{"Authorization: Bearer secret", "X-Tenant: acme", "Cookie: sid=1", "X-Api-Key: secret"}),
+		jakhttp.WithJsonBody(`{"name":"a"}`),
+	)
+
+	recorded := newRecordedRequest(req, []string{"x-api-key"})
+
+	assert.Equal(t, RecordedRequest{
+		Method: "POST",
+		URL:    "http://api.test/users",
+		Headers: map[string]string{
+			"Authorization": Redacted,
+			"Cookie":        Redacted,
+			"X-Api-Key":     Redacted,
+			"X-Tenant":      "acme",
+			"Content-Type":  "application/json",
+		},
+		Body: `{"name":"a"}`,
+	}, recorded)
+}
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		names    []string
+		expected string
+	}{
+		{"redacted", "http://api.test/users?page=2&api_key=secret", []string{"api_key"}, "http://api.test/users?page=2&api_key=[REDACTED]"},
+		{"repeated and escaped", "http://api.test/users?api%5Fkey=a&api_key=b", []string{"api_key"}, "http://api.test/users?api%5Fkey=[REDACTED]&api_key=[REDACTED]"},
+		{"other parameters", "http://api.test/users?key=1", []string{"api_key"}, "http://api.test/users?key=1"},
+		{"no query", "http://api.test/users", []string{"api_key"}, "http://api.test/users"},
+		{"no names", "http://api.test/users?api_key=secret", nil, "http://api.test/users?api_key=secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactQuery(tt.url, tt.names))
+		})
+	}
+}
+
+func TestRecordedResponse_Binary(t *testing.T) {
+	body := []byte{0xff, 0x00, 0xfe}
+	resp := &jakhttp.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(string(body)))}
+
+	recorded, err := newRecordedResponse(resp)
+	require.NoError(t, err)
+	assert.Equal(t, EncodingBase64, recorded.BodyEncoding)
+
+	// The original response can still be read
+	original, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, body, original)
+
+	replayed, err := recorded.response()
+	require.NoError(t, err)
+	content, _ := io.ReadAll(replayed.Body)
+	assert.Equal(t, body, content)
+}
@@ -0,0 +1,232 @@
+package cassette
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// MissHeader marks the response a non-strict Replayer returns for an unmatched request.
+const MissHeader = "X-Jak-Cassette"
+
+// Recorder is a client that records every request/response pair performed by another client.
+// Requests that fail without a response are not recorded.
+type Recorder struct {
+	// client performs the requests
+	client jakhttp.Client
+
+	// mu guards cassette
+	mu sync.Mutex
+
+	// cassette receives the interactions
+	cassette *Cassette
+}
+
+// NewRecorder creates a client recording the requests of another client.
+//
+// Parameters:
+//   - client: Client performing the requests
+//   - redactedHeaders: Request headers redacted in addition to the default sensitive headers, e.g. API key headers
+//   - redactedQuery: Query parameters whose values are redacted in recorded URLs, e.g. API keys
+//
+// Returns:
+//   - *Recorder: Recording client
+func NewRecorder(client jakhttp.Client, redactedHeaders, redactedQuery []string) *Recorder {
+	return &Recorder{
+		client: client,
+		cassette: &Cassette{
+			Version:         Version,
+			RedactedHeaders: redactedHeaders,
+			RedactedQuery:   redactedQuery,
+			Interactions:    []Interaction{},
+		},
+	}
+}
+
+// Do performs a request and records it together with its response.
+//
+// Parameters:
+//   - req: Request to execute
+//
+// Returns:
+//   - *jakhttp.Response: Response from the server
+//   - error: Any error encountered during execution or while reading the response
+func (r *Recorder) Do(req *jakhttp.Request) (*jakhttp.Response, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, err := newRecordedResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	request := newRecordedRequest(req, r.cassette.RedactedHeaders)
+	request.URL = redactQuery(request.URL, r.cassette.RedactedQuery)
+	for i, hop := range recorded.Redirects {
+		recorded.Redirects[i].URL = redactQuery(hop.URL, r.cassette.RedactedQuery)
+		if location := hop.Headers.Get("Location"); location != "" && len(r.cassette.RedactedQuery) > 0 {
+			// The headers are shared with the response returned to the caller
+			recorded.Redirects[i].Headers = hop.Headers.Clone()
+			recorded.Redirects[i].Headers.Set("Location", redactQuery(location, r.cassette.RedactedQuery))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  request,
+		Response: recorded,
+	})
+	return resp, nil
+}
+
+// SetTimeout sets the timeout of the underlying client.
+//
+// Parameters:
+//   - timeout: Timeout duration
+func (r *Recorder) SetTimeout(timeout time.Duration) {
+	r.client.SetTimeout(timeout)
+}
+
+// Save writes the recorded interactions to a cassette file.
+//
+// Parameters:
+//   - path: Path of the cassette file
+//
+// Returns:
+//   - error: Error if the file cannot be written
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(path)
+}
+
+// Replayer is a client answering requests from a cassette without network access.
+// A request is answered by the first unplayed matching interaction, so repeated
+// identical requests replay their responses in recorded order; once all matching
+// interactions have been played, the last one is repeated.
+// An unmatched request fails in strict mode and gets a 404 response otherwise.
+type Replayer struct {
+	// cassette holds the recorded interactions
+	cassette *Cassette
+
+	// matcher decides which interactions match a request
+	matcher Matcher
+
+	// strict fails unmatched requests instead of answering them with 404
+	strict bool
+
+	// mu guards played
+	mu sync.Mutex
+
+	// played marks the interactions already replayed
+	played []bool
+}
+
+// NewReplayer creates a client replaying a cassette.
+//
+// Parameters:
+//   - cassette: Cassette to replay
+//   - matcher: Request matching
+//   - strict: Fail unmatched requests with se.ErrCassetteMismatch
+//
+// Returns:
+//   - *Replayer: Replaying client
+func NewReplayer(cassette *Cassette, matcher Matcher, strict bool) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		matcher:  matcher,
+		strict:   strict,
+		played:   make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// Do answers a request with the response of a matching interaction.
+//
+// Parameters:
+//   - req: Request to answer
+//
+// Returns:
+//   - *jakhttp.Response: Recorded response
+//   - error: se.ErrCassetteMismatch for an unmatched request in strict mode,
+//     or the context error if the request was canceled
+func (r *Replayer) Do(req *jakhttp.Request) (*jakhttp.Response, error) {
+	if ctx := req.GetContext(); ctx != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	recorded := newRecordedRequest(req, r.cassette.RedactedHeaders)
+	recorded.URL = redactQuery(recorded.URL, r.cassette.RedactedQuery)
+	interaction, ok := r.next(recorded)
+	if ok {
+		return interaction.Response.response()
+	}
+
+	if r.strict {
+		return nil, fmt.Errorf("%w: %s %s", se.ErrCassetteMismatch, recorded.Method, recorded.URL)
+	}
+	return missResponse(recorded), nil
+}
+
+// SetTimeout does nothing; replayed responses are immediate.
+//
+// Parameters:
+//   - timeout: Ignored
+func (r *Replayer) SetTimeout(timeout time.Duration) {}
+
+// next finds the interaction answering a request and marks it played.
+//
+// Parameters:
+//   - req: Request being replayed
+//
+// Returns:
+//   - Interaction: Matching interaction
+//   - bool: True if an interaction matches
+func (r *Replayer) next(req RecordedRequest) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !r.matcher.Matches(interaction.Request, req) {
+			continue
+		}
+		if !r.played[i] {
+			r.played[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+
+	if last < 0 {
+		return Interaction{}, false
+	}
+	return r.cassette.Interactions[last], true
+}
+
+// missResponse creates the 404 response for an unmatched request.
+//
+// Parameters:
+//   - req: Unmatched request
+//
+// Returns:
+//   - *jakhttp.Response: Response describing the miss
+func missResponse(req RecordedRequest) *jakhttp.Response {
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set(MissHeader, "miss")
+
+	return &jakhttp.Response{
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("%s: %s %s", se.ErrCassetteMismatch, req.Method, req.URL))),
+		StatusCode: http.StatusNotFound,
+	}
+}
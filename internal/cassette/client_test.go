@@ -0,0 +1,204 @@
+package cassette
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/chain"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// readBody reads the body of a response in tests
+func readBody(t *testing.T, resp *jakhttp.Response) string {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		fmt.Fprintf(w, "%s %s", r.URL.Path, body)
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(jakhttp.NewClient(), nil, nil)
+
+	resp, err := recorder.Do(jakhttp.NewRequest(server.URL+"/a", "GET"))
+	require.NoError(t, err)
+	assert.Equal(t, "/a ", readBody(t, resp))
+
+	resp, err = recorder.Do(jakhttp.NewRequest(server.URL+"/b", "POST", jakhttp.WithJsonBody(`{"x":1}`)))
+	require.NoError(t, err)
+	assert.Equal(t, `/b {"x":1}`, readBody(t, resp))
+
+	// Failed requests are not recorded
+	_, err = recorder.Do(jakhttp.NewRequest("http://127.0.0.1:1/unreachable", "GET"))
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, recorder.Save(path))
+
+	cassette, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, cassette.Interactions, 2)
+	assert.Equal(t, RecordedRequest{Method: "GET", URL: server.URL + "/a"}, cassette.Interactions[0].Request)
+	assert.Equal(t, 200, cassette.Interactions[0].Response.Status)
+	assert.Equal(t, "GET", cassette.Interactions[0].Response.Headers.Get("X-Method"))
+	assert.Equal(t, `/b {"x":1}`, cassette.Interactions[1].Response.Body)
+}
+
+func TestRecordReplay_QueryAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/users?"+r.URL.RawQuery, http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, r.URL.Query().Get("page"))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(jakhttp.NewClient(), nil, []string{"api_key"})
+	resp, err := recorder.Do(jakhttp.NewRequest(server.URL+"/old?page=2&api_key=secret", "GET"))
+	require.NoError(t, err)
+	assert.Equal(t, "2", readBody(t, resp))
+	assert.Equal(t, "/users?page=2&api_key=secret", resp.Redirects[0].Location())
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, recorder.Save(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	cassette, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api_key"}, cassette.RedactedQuery)
+	assert.Equal(t, server.URL+"/old?page=2&api_key=[REDACTED]", cassette.Interactions[0].Request.URL)
+	assert.Equal(t, server.URL+"/old?page=2&api_key=[REDACTED]", cassette.Interactions[0].Response.Redirects[0].URL)
+	assert.Equal(t, "/users?page=2&api_key=[REDACTED]", cassette.Interactions[0].Response.Redirects[0].Headers.Get("Location"))
+
+	// Replayed requests match regardless of the key they carry
+	replayer := NewReplayer(cassette, Matcher{Method: true, URL: true}, true)
+	resp, err = replayer.Do(jakhttp.NewRequest(server.URL+"/old?api_key=other&page=2", "GET"))
+	require.NoError(t, err)
+	assert.Equal(t, "2", readBody(t, resp))
+}
+
+func TestReplayer(t *testing.T) {
+	cassette := &Cassette{Version: Version, Interactions: []Interaction{
+		{Request: RecordedRequest{Method: "GET", URL: "http://api.test/jobs/1"}, Response: RecordedResponse{Status: 202, Body: "pending"}},
+		{Request: RecordedRequest{Method: "GET", URL: "http://api.test/users"}, Response: RecordedResponse{Status: 200, Body: "users"}},
+		{Request: RecordedRequest{Method: "GET", URL: "http://api.test/jobs/1"}, Response: RecordedResponse{Status: 200, Body: "done", Headers: http.Header{"X-Done": {"1"}}}},
+	}}
+	replayer := NewReplayer(cassette, Matcher{Method: true, URL: true}, false)
+
+	// Repeated requests replay the matching interactions in order, then repeat the last one
+	var got []string
+	for i := 0; i < 3; i++ {
+		resp, err := replayer.Do(jakhttp.NewRequest("http://api.test/jobs/1", "GET"))
+		require.NoError(t, err)
+		got = append(got, fmt.Sprintf("%d %s", resp.StatusCode, readBody(t, resp)))
+	}
+	assert.Equal(t, []string{"202 pending", "200 done", "200 done"}, got)
+
+	resp, err := replayer.Do(jakhttp.NewRequest("http://api.test/users", "GET"))
+	require.NoError(t, err)
+	assert.Equal(t, "users", readBody(t, resp))
+
+	// Unmatched requests get 404 unless strict
+	resp, err = replayer.Do(jakhttp.NewRequest("http://api.test/users", "DELETE"))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.Equal(t, "miss", resp.Header.Get(MissHeader))
+	assert.Contains(t, readBody(t, resp), "DELETE http://api.test/users")
+}
+
+func TestReplayer_Strict(t *testing.T) {
+	cassette := &Cassette{Version: Version, Interactions: []Interaction{
+		{Request: RecordedRequest{Method: "GET", URL: "http://api.test/users"}, Response: RecordedResponse{Status: 200}},
+	}}
+	replayer := NewReplayer(cassette, Matcher{Method: true, URL: true}, true)
+
+	_, err := replayer.Do(jakhttp.NewRequest("http://api.test/users", "GET"))
+	assert.NoError(t, err)
+
+	_, err = replayer.Do(jakhttp.NewRequest("http://api.test/other", "GET"))
+	assert.ErrorIs(t, err, se.ErrCassetteMismatch)
+	assert.ErrorContains(t, err, "GET http://api.test/other")
+}
+
+func TestReplayer_Canceled(t *testing.T) {
+	cassette := &Cassette{Version: Version, Interactions: []Interaction{
+		{Request: RecordedRequest{Method: "GET", URL: "http://api.test/users"}, Response: RecordedResponse{Status: 200}},
+	}}
+	replayer := NewReplayer(cassette, Matcher{Method: true, URL: true}, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := replayer.Do(jakhttp.NewRequest("http://api.test/users", "GET", jakhttp.WithContext(ctx)))
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRecordReplay_Chain(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/login":
+			fmt.Fprint(w, `{"token": "t-1"}`)
+		case "/me":
+			fmt.Fprintf(w, `{"token": "%s"}`, r.Header.Get("X-Token"))
+		}
+	}))
+
+	config := &rule.Config{BaseUrl: server.URL, Timeout: 5, Request: []rule.Request{
+		{Name: "Login", Method: "POST", Path: "/login", Extract: map[string]string{"token": "token"}},
+		{Name: "Me", Method: "GET", Path: "/me", Headers: []string{"X-Token: ${token}"}, DependsOn: "Login", Extract: map[string]string{"echo": "token"}},
+	}}
+	require.NoError(t, config.Validate())
+
+	run := func(client jakhttp.Client) map[string]int {
+		statuses := map[string]int{}
+		executor := chain.NewChainExecutor().WithClient(client)
+		executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, variables map[string]string) {
+			require.NoError(t, err)
+			statuses[name] = statusCode
+		})
+		require.NoError(t, executor.Execute(context.Background(), config))
+		return statuses
+	}
+
+	// Record against the live server
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := NewRecorder(jakhttp.NewClient(), nil, nil)
+	recorded := run(recorder)
+	require.NoError(t, recorder.Save(path))
+	server.Close()
+
+	// Replay without the server
+	cassette, err := Load(path)
+	require.NoError(t, err)
+	matcher, err := ParseMatch("method,url,header:X-Token")
+	require.NoError(t, err)
+	replayed := run(NewReplayer(cassette, matcher, true))
+
+	assert.Equal(t, recorded, replayed)
+	assert.Equal(t, map[string]int{"Login": 200, "Me": 200}, replayed)
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,154 @@
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Request parts a Matcher can compare.
+const (
+	// MatchMethod compares the HTTP method
+	MatchMethod = "method"
+
+	// MatchURL compares the URL; the order of query parameters does not matter
+	MatchURL = "url"
+
+	// MatchBody compares the body; JSON bodies are compared by value
+	MatchBody = "body"
+
+	// MatchHeaderPrefix compares a header, e.g. "header:X-Tenant"
+	MatchHeaderPrefix = "header:"
+)
+
+// DefaultMatch is the request matching used when none is configured.
+const DefaultMatch = MatchMethod + "," + MatchURL
+
+// Matcher decides whether a request matches a recorded one.
+type Matcher struct {
+	// Method compares the HTTP method
+	Method bool
+
+	// URL compares the URL
+	URL bool
+
+	// Body compares the body
+	Body bool
+
+	// Headers lists the headers to compare
+	Headers []string
+}
+
+// ParseMatch parses a comma separated list of request parts to match on.
+//
+// Parameters:
+//   - spec: Parts to match, e.g. "method,url,body,header:X-Tenant"; empty uses DefaultMatch
+//
+// Returns:
+//   - Matcher: Matcher comparing the listed parts
+//   - error: Error if a part is unknown
+func ParseMatch(spec string) (Matcher, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = DefaultMatch
+	}
+
+	var matcher Matcher
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.EqualFold(part, MatchMethod):
+			matcher.Method = true
+		case strings.EqualFold(part, MatchURL):
+			matcher.URL = true
+		case strings.EqualFold(part, MatchBody):
+			matcher.Body = true
+		case len(part) > len(MatchHeaderPrefix) && strings.EqualFold(part[:len(MatchHeaderPrefix)], MatchHeaderPrefix):
+			matcher.Headers = append(matcher.Headers, http.CanonicalHeaderKey(strings.TrimSpace(part[len(MatchHeaderPrefix):])))
+		default:
+			return Matcher{}, fmt.Errorf("unknown match %q (use method, url, body or header:<name>)", part)
+		}
+	}
+	return matcher, nil
+}
+
+// Matches reports whether a request matches a recorded one.
+//
+// Parameters:
+//   - recorded: Request stored in the cassette
+//   - req: Request being replayed
+//
+// Returns:
+//   - bool: True if all compared parts are equal
+func (m Matcher) Matches(recorded, req RecordedRequest) bool {
+	if m.Method && !strings.EqualFold(recorded.Method, req.Method) {
+		return false
+	}
+	if m.URL && normalizeURL(recorded.URL) != normalizeURL(req.URL) {
+		return false
+	}
+	if m.Body && !equalBodies(recorded.Body, req.Body) {
+		return false
+	}
+	for _, name := range m.Headers {
+		if headerValue(recorded.Headers, name) != headerValue(req.Headers, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeURL returns a URL with its query parameters sorted.
+//
+// Parameters:
+//   - rawURL: URL to normalize
+//
+// Returns:
+//   - string: Normalized URL, or the input if it cannot be parsed
+func normalizeURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	parsed.RawQuery = parsed.Query().Encode()
+	return parsed.String()
+}
+
+// equalBodies compares two bodies, by value if both are JSON.
+//
+// Parameters:
+//   - a: First body
+//   - b: Second body
+//
+// Returns:
+//   - bool: True if the bodies are equal
+func equalBodies(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	var valueA, valueB interface{}
+	if json.Unmarshal([]byte(a), &valueA) != nil || json.Unmarshal([]byte(b), &valueB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+// headerValue returns a header value regardless of the case of its name.
+//
+// Parameters:
+//   - headers: Recorded headers
+//   - name: Header name
+//
+// Returns:
+//   - string: Header value, or empty if the header is not present
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
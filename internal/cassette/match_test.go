@@ -0,0 +1,74 @@
+package cassette
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMatch(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Matcher
+		wantErr string
+	}{
+		{spec: "", want: Matcher{Method: true, URL: true}},
+		{spec: "method, url, body", want: Matcher{Method: true, URL: true, Body: true}},
+		{spec: "URL,header:x-tenant,Header:Accept", want: Matcher{URL: true, Headers: []string{"X-Tenant", "Accept"}}},
+		{spec: "method,query", wantErr: `unknown match "query"`},
+		{spec: "header:", wantErr: `unknown match "header:"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			matcher, err := ParseMatch(tt.spec)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, matcher)
+		})
+	}
+}
+
+func TestMatcher_Matches(t *testing.T) {
+	recorded := RecordedRequest{
+		Method:  "POST",
+		URL:     "http://api.test/users?b=2&a=1",
+		Headers: map[string]string{"X-Tenant": "acme"},
+		Body:    `{"name": "a", "age": 1}`,
+	}
+
+	tests := []struct {
+		name    string
+		matcher Matcher
+		req     RecordedRequest
+		want    bool
+	}{
+		{name: "method and url", matcher: Matcher{Method: true, URL: true}, req: RecordedRequest{Method: "post", URL: "http://api.test/users?a=1&b=2"}, want: true},
+		{name: "different method", matcher: Matcher{Method: true}, req: RecordedRequest{Method: "GET"}, want: false},
+		{name: "different url", matcher: Matcher{URL: true}, req: RecordedRequest{URL: "http://api.test/users?a=2&b=2"}, want: false},
+		{name: "json body by value", matcher: Matcher{Body: true}, req: RecordedRequest{Body: `{"age":1,"name":"a"}`}, want: true},
+		{name: "different body", matcher: Matcher{Body: true}, req: RecordedRequest{Body: `{"name": "b", "age": 1}`}, want: false},
+		{name: "header", matcher: Matcher{Headers: []string{"X-Tenant"}}, req: RecordedRequest{Headers: map[string]string{"x-tenant": "acme"}}, want: true},
+		{name: "missing header", matcher: Matcher{Headers: []string{"X-Tenant"}}, req: RecordedRequest{}, want: false},
+		{name: "nothing compared", matcher: Matcher{}, req: RecordedRequest{}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.matcher.Matches(recorded, tt.req))
+		})
+	}
+}
+
+func TestEqualBodies(t *testing.T) {
+	assert.True(t, equalBodies("", ""))
+	assert.True(t, equalBodies("a=1", "a=1"))
+	assert.False(t, equalBodies("a=1", "a=2"))
+	assert.True(t, equalBodies(`[1, 2]`, `[1,2]`))
+	assert.False(t, equalBodies(`[1, 2]`, `[2, 1]`))
+}
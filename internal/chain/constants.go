@@ -22,4 +22,13 @@ const (
 	// When calculating limits for request bodies, this factor is multiplied with maxVariableValueLength.
 	// This allows request bodies to be larger than individual variable values.
 	maxBodySizeMultiplier = 10
+
+	// redirectsPathPrefix marks an extraction path that targets the redirect chain
+	// instead of the response body. The rest of the path is a gjson path over an array
+	// of hops, e.g. "redirects:0.location" or "redirects:1.header.Set-Cookie".
+	redirectsPathPrefix = "redirects:"
+
+	// statusVariableSuffix marks a condition variable holding the status code of an
+	// executed request, e.g. ${Lookup.status}.
+	statusVariableSuffix = ".status"
 )
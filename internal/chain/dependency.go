@@ -21,6 +21,9 @@ type DependencyResolver struct {
 	// dependsOn maps a request name to the name of the request it depends on
 	// Example: If B depends on A, then dependsOn["B"] = "A"
 	dependsOn map[string]string
+
+	// names lists the request names in the order they are defined in the configuration
+	names []string
 }
 
 // NewDependencyResolver creates a new dependency resolver with initialized maps.
@@ -53,6 +56,9 @@ func (dr *DependencyResolver) BuildRequestGraph(ctx context.Context, config *rul
 	// Index requests by name for easy lookup
 	for i := range config.Request {
 		req := &config.Request[i]
+		if _, exists := dr.requests[req.Name]; !exists {
+			dr.names = append(dr.names, req.Name)
+		}
 		dr.requests[req.Name] = req
 	}
 
@@ -152,7 +158,9 @@ func (dr *DependencyResolver) hasCycle(nodeName string, visited map[string]bool)
 }
 
 // CalculateExecutionOrder determines the order in which requests should be executed
-// based on their dependencies. It returns a topologically sorted list of request names.
+// based on their dependencies. It returns a topologically sorted list of request names
+// in which every request follows the request it depends on. Independent requests keep
+// the order in which they are defined in the configuration.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control
@@ -205,24 +213,13 @@ func (dr *DependencyResolver) CalculateExecutionOrder(ctx context.Context) ([]st
 		return nil
 	}
 
-	// Process all requests
-	for name := range dr.requests {
+	// Process all requests in configuration order; the post-order traversal
+	// already places dependencies before the requests that depend on them
+	for _, name := range dr.names {
 		if err := visit(name); err != nil {
 			return nil, err
 		}
 	}
 
-	// Reverse the result to get the correct execution order
-	// (Dependencies should be executed before the requests that depend on them)
-	dr.reverseStringSlice(result)
-
 	return result, nil
 }
-
-// reverseStringSlice reverses the order of elements in a string slice in-place.
-// This is used to convert the post-order traversal result to the correct execution order.
-func (dr *DependencyResolver) reverseStringSlice(slice []string) {
-	for i, j := 0, len(slice)-1; i < j; i, j = i+1, j-1 {
-		slice[i], slice[j] = slice[j], slice[i]
-	}
-}
@@ -81,8 +81,7 @@ func TestBuildRequestGraph(t *testing.T) {
 	}
 }
 
-// unstable. should be recreate testcase
-func TestUnstable_CalculateExecutionOrder(t *testing.T) {
+func TestCalculateExecutionOrder(t *testing.T) {
 	tests := []struct {
 		name        string
 		config      *rule.Config
@@ -111,12 +110,9 @@ func TestUnstable_CalculateExecutionOrder(t *testing.T) {
 					{Name: "edge", DependsOn: "A2"},
 				},
 			},
-			// edge -> B2
-			// B2 -> B1
-			// B1 -> root
-			// A2 -> A1
-			// A1 -> root
-			expectOrder: []string{"edge", "A2", "A1", "B2", "B1", "root"},
+			// root -> A1 -> A2 -> edge
+			// root -> B1 -> B2
+			expectOrder: []string{"root", "A1", "A2", "B1", "B2", "edge"},
 			expectErr:   nil,
 		},
 		{
@@ -135,7 +131,7 @@ func TestUnstable_CalculateExecutionOrder(t *testing.T) {
 					{Name: "edgeC", DependsOn: "C2"},
 				},
 			},
-			expectOrder: []string{"edgeC", "C2", "C1", "edegB", "B2", "B1", "edgeA", "A2", "A1", "root"},
+			expectOrder: []string{"root", "A1", "A2", "B1", "B2", "C1", "C2", "edgeA", "edgeB", "edgeC"},
 			expectErr:   nil,
 		},
 		{
@@ -154,8 +150,8 @@ func TestUnstable_CalculateExecutionOrder(t *testing.T) {
 					{Name: "F", DependsOn: "D"},
 				},
 			},
-			// E,F -> (B,C,D) -> A
-			expectOrder: []string{"F", "E", "D", "C", "B", "A"},
+			// A -> (B,C,D) -> E,F; the last definition of E and F wins, so both follow D
+			expectOrder: []string{"A", "B", "C", "D", "E", "F"},
 			expectErr:   nil,
 		},
 		{
@@ -194,3 +190,57 @@ func TestUnstable_CalculateExecutionOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateExecutionOrder_DependenciesFirst(t *testing.T) {
+	tests := []struct {
+		name        string
+		requests    []rule.Request
+		expectOrder []string
+	}{
+		{
+			name: "chain defined in order",
+			requests: []rule.Request{
+				{Name: "Auth"},
+				{Name: "Get Profile", DependsOn: "Auth"},
+				{Name: "Get Posts", DependsOn: "Get Profile"},
+			},
+			expectOrder: []string{"Auth", "Get Profile", "Get Posts"},
+		},
+		{
+			name: "dependency defined after dependent",
+			requests: []rule.Request{
+				{Name: "Get Posts", DependsOn: "Get Profile"},
+				{Name: "Get Profile", DependsOn: "Auth"},
+				{Name: "Auth"},
+			},
+			expectOrder: []string{"Auth", "Get Profile", "Get Posts"},
+		},
+		{
+			name: "branches keep configuration order",
+			requests: []rule.Request{
+				{Name: "root"},
+				{Name: "A1", DependsOn: "root"},
+				{Name: "B1", DependsOn: "root"},
+				{Name: "A2", DependsOn: "A1"},
+				{Name: "independent"},
+			},
+			expectOrder: []string{"root", "A1", "B1", "A2", "independent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dr := NewDependencyResolver()
+			ctx := context.Background()
+
+			assert.NoError(t, dr.BuildRequestGraph(ctx, &rule.Config{Request: tt.requests}))
+
+			// The order is deterministic, not subject to map iteration
+			for i := 0; i < 10; i++ {
+				order, err := dr.CalculateExecutionOrder(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectOrder, order)
+			}
+		})
+	}
+}
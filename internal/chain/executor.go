@@ -4,15 +4,23 @@ package chain
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/ymatsukawa/jak/internal/condition"
+	"github.com/ymatsukawa/jak/internal/dataset"
 	"github.com/ymatsukawa/jak/internal/engine"
 	"github.com/ymatsukawa/jak/internal/http"
 	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
 )
 
 // ChainResultCollector is a function type that receives information about chain request execution results.
 // It is called after each request is executed to provide feedback and track progress.
+// Requests skipped by their when or skip_if condition are reported with a status code of 0
+// and an error wrapping se.ErrRequestSkipped; use errors.Is to tell them apart from failures.
 //
 // Parameters:
 //   - name: Name of the executed request
@@ -21,8 +29,9 @@ import (
 //   - statusCode: HTTP status code of the response
 //   - err: Error encountered during execution, or nil if successful
 //   - duration: Time taken to execute the request
+//   - timing: Timing breakdown of the request (nil if no response was received)
 //   - variables: Map of variable names to extracted values from the response
-type ChainResultCollector func(name, method, url string, statusCode int, err error, duration time.Duration, variables map[string]string)
+type ChainResultCollector func(name, method, url string, statusCode int, err error, duration time.Duration, timing *http.Timing, variables map[string]string)
 
 // Executor defines the interface for chain request execution.
 // Implementations of this interface are responsible for executing a chain of
@@ -64,6 +73,111 @@ type ChainExecutor struct {
 
 	// resultCollector collects execution results for reporting
 	resultCollector ChainResultCollector
+
+	// collectorMu serializes result collection when dataset rows run in parallel
+	collectorMu sync.Mutex
+}
+
+// chainRun holds the state of one pass through the chain.
+// Without config-level data there is a single pass; otherwise there is one per dataset row.
+type chainRun struct {
+	// processor processes the requests of this pass with its own variable store
+	processor RequestProcessor
+
+	// variables is the variable store of processor, used to evaluate conditions
+	variables VariableResolver
+
+	// statuses holds the status code of each executed request by name, for ${name.status}
+	statuses map[string]int
+
+	// skipped holds whether a request was skipped by its conditions, by name;
+	// false once any iteration of the request was sent
+	skipped map[string]bool
+
+	// vars are the dataset row variables of this pass, nil without config-level data
+	vars map[string]string
+
+	// suffix is appended to the reported request names, e.g. "[0]"
+	suffix string
+
+	// limiter paces the requests of all passes, nil for no limit
+	limiter *engine.RateLimiter
+}
+
+// newChainRun creates the state of a pass through the chain.
+//
+// Parameters:
+//   - processor: Request processor of the pass
+//   - variables: Variable store used by processor
+//
+// Returns:
+//   - *chainRun: Pass state without dataset variables or rate limit
+func newChainRun(processor RequestProcessor, variables VariableResolver) *chainRun {
+	return &chainRun{
+		processor: processor,
+		variables: variables,
+		statuses:  make(map[string]int),
+		skipped:   make(map[string]bool),
+	}
+}
+
+// lookup resolves a variable referenced in a condition.
+// Dataset row variables come first, then ${name.status} of executed requests,
+// then variables extracted earlier in the pass.
+//
+// Parameters:
+//   - vars: Dataset row variables of the request, nil for none
+//
+// Returns:
+//   - condition.Lookup: Variable lookup for condition evaluation
+func (run *chainRun) lookup(vars map[string]string) condition.Lookup {
+	return func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		if requestName, ok := strings.CutSuffix(name, statusVariableSuffix); ok {
+			if status, ok := run.statuses[requestName]; ok {
+				return strconv.Itoa(status), true
+			}
+		}
+		if run.variables != nil {
+			return run.variables.Get(name)
+		}
+		return "", false
+	}
+}
+
+// skipReason evaluates the when and skip_if conditions of a request.
+//
+// Parameters:
+//   - req: Request whose conditions are evaluated
+//   - vars: Dataset row variables of the request, nil for none
+//
+// Returns:
+//   - string: Why the request is skipped, or empty if it should run
+//   - error: Any error encountered while evaluating a condition
+func (run *chainRun) skipReason(req *rule.Request, vars map[string]string) (string, error) {
+	lookup := run.lookup(vars)
+
+	if req.When != "" {
+		ok, err := condition.Evaluate(req.When, lookup)
+		if err != nil {
+			return "", fmt.Errorf("when: %w", err)
+		}
+		if !ok {
+			return fmt.Sprintf("when %q is false", req.When), nil
+		}
+	}
+	if req.SkipIf != "" {
+		ok, err := condition.Evaluate(req.SkipIf, lookup)
+		if err != nil {
+			return "", fmt.Errorf("skip_if: %w", err)
+		}
+		if ok {
+			return fmt.Sprintf("skip_if %q is true", req.SkipIf), nil
+		}
+	}
+	return "", nil
 }
 
 // NewChainExecutor creates a new chain executor with default dependencies.
@@ -149,6 +263,7 @@ func (executor *ChainExecutor) updateProcessor() {
 
 // Execute executes the chain of requests according to their dependencies.
 // It builds a dependency graph, calculates the execution order, and processes requests in that order.
+// With config-level data the whole chain is executed once per dataset row.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control
@@ -169,8 +284,95 @@ func (executor *ChainExecutor) Execute(ctx context.Context, config *rule.Config)
 		return fmt.Errorf("failed to calculate execution order: %w", err)
 	}
 
+	if config.Data != "" {
+		return executor.executeRows(ctx, executionOrder, depResolver.requests, config)
+	}
+
 	// Execute requests in calculated order
-	return executor.executeRequestsInOrder(ctx, executionOrder, depResolver.requests, config)
+	run := newChainRun(executor.requestProcessor, executor.variableResolver)
+	return executor.executeRequestsInOrder(ctx, run, executionOrder, depResolver.requests, config)
+}
+
+// executeRows executes the whole chain once per row of the config-level dataset.
+// Each row has its own variable store. With concurrency enabled, rows run in parallel
+// on up to max_workers workers; rate_limit paces the requests of all rows together.
+// Unless ignore_fail is set, the first failing row cancels the remaining rows.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - executionOrder: Ordered list of request names to execute
+//   - requestMap: Map of request names to request objects
+//   - config: Configuration containing the dataset and global settings
+//
+// Returns:
+//   - error: Any error encountered during execution
+func (executor *ChainExecutor) executeRows(
+	ctx context.Context,
+	executionOrder []string,
+	requestMap map[string]*rule.Request,
+	config *rule.Config,
+) error {
+	rows, err := dataset.Load(config.ResolvePath(config.Data))
+	if err != nil {
+		return fmt.Errorf("failed to load data: %w", err)
+	}
+
+	workers := 1
+	if config.Concurrency {
+		workers = engine.DefaultMaxWorkers
+		if config.MaxWorkers > 0 {
+			workers = config.MaxWorkers
+		}
+	}
+
+	var limiter *engine.RateLimiter
+	if config.RateLimit > 0 {
+		limiter = engine.NewRateLimiter(config.RateLimit, config.RateBurst)
+	}
+
+	rowCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var errOnce sync.Once
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(rows); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				variables := NewVariableResolver()
+				run := newChainRun(NewRequestProcessor(executor.factory, executor.client, variables), variables)
+				run.vars = dataset.Variables(rows[index], index)
+				run.suffix = dataset.IterationName("", index)
+				run.limiter = limiter
+				if err := executor.executeRequestsInOrder(rowCtx, run, executionOrder, requestMap, config); err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("row %d: %w", index, err)
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+sendRows:
+	for index := range rows {
+		select {
+		case <-rowCtx.Done():
+			break sendRows
+		case indexes <- index:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
 }
 
 // executeRequestsInOrder executes requests according to the calculated execution order.
@@ -178,6 +380,7 @@ func (executor *ChainExecutor) Execute(ctx context.Context, config *rule.Config)
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control
+//   - run: State of the current pass through the chain
 //   - executionOrder: Ordered list of request names to execute
 //   - requestMap: Map of request names to request objects
 //   - config: Configuration containing global settings
@@ -186,6 +389,7 @@ func (executor *ChainExecutor) Execute(ctx context.Context, config *rule.Config)
 //   - error: Any error encountered during execution
 func (executor *ChainExecutor) executeRequestsInOrder(
 	ctx context.Context,
+	run *chainRun,
 	executionOrder []string,
 	requestMap map[string]*rule.Request,
 	config *rule.Config,
@@ -198,7 +402,7 @@ func (executor *ChainExecutor) executeRequestsInOrder(
 			return ctx.Err()
 		default:
 			// Process current request
-			if err := executor.processRequestByName(ctx, requestName, requestMap, executedRequests, config); err != nil {
+			if err := executor.processRequestByName(ctx, run, requestName, requestMap, executedRequests, config); err != nil {
 				return err
 			}
 		}
@@ -208,10 +412,14 @@ func (executor *ChainExecutor) executeRequestsInOrder(
 }
 
 // processRequestByName processes a single request by name.
-// It executes the request, collects the result, and handles any errors.
+// A request with its own dataset or a for_each array is processed once per row
+// or element, in order, before any request that depends on it.
+// A request whose dependency was skipped is skipped as well, since the variables
+// it would use were never extracted.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control
+//   - run: State of the current pass through the chain
 //   - requestName: Name of the request to process
 //   - requestMap: Map of request names to request objects
 //   - executedRequests: Map tracking which requests have been executed
@@ -221,6 +429,7 @@ func (executor *ChainExecutor) executeRequestsInOrder(
 //   - error: Any error encountered during processing
 func (executor *ChainExecutor) processRequestByName(
 	ctx context.Context,
+	run *chainRun,
 	requestName string,
 	requestMap map[string]*rule.Request,
 	executedRequests map[string]bool,
@@ -233,19 +442,109 @@ func (executor *ChainExecutor) processRequestByName(
 
 	// Get request object
 	requestObj := requestMap[requestName]
+	name := requestObj.Name + run.suffix
+
+	// Skip dependents of a skipped request instead of sending unresolved placeholders
+	if parent := requestObj.DependsOn; parent != "" && run.skipped[parent] {
+		err := fmt.Errorf("%w: depends on skipped request '%s'", se.ErrRequestSkipped, parent)
+		executor.collect(name, requestObj.Method, engine.RequestURL(config, requestObj), 0, err, 0, nil, nil)
+		run.skipped[requestObj.Name] = true
+		executedRequests[requestName] = true
+		return nil
+	}
+
+	switch {
+	case requestObj.ForEach != "":
+		if err := executor.processForEach(ctx, run, requestObj, name, config); err != nil {
+			return err
+		}
+	case requestObj.Data == "":
+		request := requestObj
+		if run.vars != nil {
+			applied := dataset.ApplyToRequest(config, *requestObj, run.vars)
+			request = &applied
+		}
+		if _, err := executor.processRequest(ctx, run, request, name, run.vars, config); err != nil {
+			return err
+		}
+	default:
+		rows, err := dataset.Load(config.ResolvePath(requestObj.Data))
+		if err != nil {
+			return fmt.Errorf("failed to load data for request '%s': %w", requestObj.Name, err)
+		}
+		for index, row := range rows {
+			vars := dataset.MergeVariables(run.vars, dataset.Variables(row, index))
+			applied := dataset.ApplyToRequest(config, *requestObj, vars)
+			applied.Data = ""
+			if _, err := executor.processRequest(ctx, run, &applied, dataset.IterationName(name, index), vars, config); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Mark as executed
+	executedRequests[requestName] = true
+	return nil
+}
+
+// processRequest executes a single request, collects the result, and handles any errors.
+// A request whose when or skip_if condition says so is not sent; it is reported to the
+// collector with an error wrapping se.ErrRequestSkipped and does not count as a failure.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - run: State of the current pass through the chain
+//   - requestObj: Request to execute, with dataset variables applied
+//   - name: Name under which the result is reported
+//   - vars: Dataset row variables of the request, nil for none
+//   - config: Configuration containing global settings
+//
+// Returns:
+//   - *ExecutionResult: Result of the request, nil if it was skipped or failed
+//   - error: Any error encountered during processing, nil if ignored due to ignore_fail
+func (executor *ChainExecutor) processRequest(
+	ctx context.Context,
+	run *chainRun,
+	requestObj *rule.Request,
+	name string,
+	vars map[string]string,
+	config *rule.Config,
+) (*ExecutionResult, error) {
+	// Get full URL
+	url := engine.RequestURL(config, requestObj)
+
+	// Evaluate conditions before anything is sent
+	reason, err := run.skipReason(requestObj, vars)
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate condition: %w", err)
+		executor.collect(name, requestObj.Method, url, 0, err, 0, nil, nil)
+		return nil, executor.handleFailure(name, err, config)
+	}
+	if reason != "" {
+		executor.collect(name, requestObj.Method, url, 0, fmt.Errorf("%w: %s", se.ErrRequestSkipped, reason), 0, nil, nil)
+		if _, seen := run.skipped[requestObj.Name]; !seen {
+			run.skipped[requestObj.Name] = true
+		}
+		return nil, nil
+	}
+	run.skipped[requestObj.Name] = false
+
+	// Wait for the shared rate limiter
+	if run.limiter != nil {
+		if err := run.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	// Start timing
 	startTime := time.Now()
 
 	// Process the request
-	result, err := executor.requestProcessor.ProcessRequest(ctx, requestObj, config)
+	result, err := run.processor.ProcessRequest(ctx, requestObj, config)
 
 	// Calculate duration
 	duration := time.Since(startTime)
 
-	// Get full URL
-	url := config.BaseUrl + requestObj.Path
-
 	// Variable map for collector
 	variables := make(map[string]string)
 	if result != nil {
@@ -256,31 +555,64 @@ func (executor *ChainExecutor) processRequestByName(
 
 	// Collect result if collector is set
 	var statusCode int
+	var timing *http.Timing
 	if result != nil {
 		statusCode = result.StatusCode
+		timing = result.Timing
+		run.statuses[requestObj.Name] = statusCode
 	}
 
-	if executor.resultCollector != nil {
-		executor.resultCollector(
-			requestObj.Name,
-			requestObj.Method,
-			url,
-			statusCode,
-			err,
-			duration,
-			variables,
-		)
-	}
+	executor.collect(name, requestObj.Method, url, statusCode, err, duration, timing, variables)
 
 	if err != nil {
-		if config.IgnoreFail {
-			fmt.Printf("Request '%s' failed: %v, continuing due to ignore_fail=true\n", requestObj.Name, err)
-			return nil
-		}
-		return fmt.Errorf("failed to process request '%s': %w", requestObj.Name, err)
+		return nil, executor.handleFailure(name, err, config)
 	}
 
-	// Mark as executed
-	executedRequests[requestName] = true
-	return nil
+	return result, nil
+}
+
+// collect passes a result to the result collector, if one is set.
+// Calls are serialized so the collector is safe to use when dataset rows run in parallel.
+//
+// Parameters:
+//   - name: Name under which the result is reported
+//   - method: HTTP method of the request
+//   - url: Full URL of the request
+//   - statusCode: HTTP status code, 0 if no response was received
+//   - err: Error encountered, or nil if successful
+//   - duration: Time taken to execute the request
+//   - timing: Timing breakdown, nil if no response was received
+//   - variables: Extracted variables
+func (executor *ChainExecutor) collect(
+	name, method, url string,
+	statusCode int,
+	err error,
+	duration time.Duration,
+	timing *http.Timing,
+	variables map[string]string,
+) {
+	if executor.resultCollector == nil {
+		return
+	}
+
+	executor.collectorMu.Lock()
+	defer executor.collectorMu.Unlock()
+	executor.resultCollector(name, method, url, statusCode, err, duration, timing, variables)
+}
+
+// handleFailure decides whether a failed request stops the chain.
+//
+// Parameters:
+//   - name: Name of the failed request
+//   - err: Error of the request
+//   - config: Configuration containing the ignore_fail setting
+//
+// Returns:
+//   - error: Wrapped error, or nil if ignored due to ignore_fail
+func (executor *ChainExecutor) handleFailure(name string, err error, config *rule.Config) error {
+	if config.IgnoreFail {
+		fmt.Printf("Request '%s' failed: %v, continuing due to ignore_fail=true\n", name, err)
+		return nil
+	}
+	return fmt.Errorf("failed to process request '%s': %w", name, err)
 }
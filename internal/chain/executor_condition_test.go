@@ -0,0 +1,187 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// conditionResult is a result received by the collector in condition tests
+type conditionResult struct {
+	name    string
+	status  int
+	skipped bool
+	err     error
+}
+
+// runConditionChain executes a chain config against a server that knows only the user "alice"
+func runConditionChain(t *testing.T, content string) ([]conditionResult, error) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/alice":
+			fmt.Fprint(w, `{"name": "alice", "role": "admin"}`)
+		case "/users":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"name": "created"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte("user\nalice\nbob\n"), 0644))
+	configPath := filepath.Join(dir, "chain.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(fmt.Sprintf("base_url = %q\n%s", server.URL, content)), 0644))
+
+	config, err := rule.LoadConfig(configPath)
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	var results []conditionResult
+	executor := NewChainExecutor()
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, variables map[string]string) {
+		results = append(results, conditionResult{name: name, status: statusCode, skipped: errors.Is(err, se.ErrRequestSkipped), err: err})
+	})
+
+	err = executor.Execute(context.Background(), config)
+	return results, err
+}
+
+func TestExecute_WhenStatus(t *testing.T) {
+	results, err := runConditionChain(t, `
+data = "users.csv"
+
+[[request]]
+name = "Lookup"
+method = "GET"
+path = "/users/${user}"
+
+[[request]]
+name = "Create"
+method = "POST"
+path = "/users"
+json_body = '{"name": "${user}"}'
+depends_on = "Lookup"
+when = "${Lookup.status} == 404"
+`)
+
+	require.NoError(t, err)
+	assert.Equal(t, []conditionResult{
+		{name: "Lookup[0]", status: 200},
+		{name: "Create[0]", skipped: true, err: results[1].err},
+		{name: "Lookup[1]", status: 404},
+		{name: "Create[1]", status: 201},
+	}, results)
+	assert.ErrorContains(t, results[1].err, `when "${Lookup.status} == 404" is false`)
+}
+
+func TestExecute_SkipIfExtractedVariable(t *testing.T) {
+	results, err := runConditionChain(t, `
+[[request]]
+name = "Lookup"
+method = "GET"
+path = "/users/alice"
+extract = { role = "role" }
+
+[[request]]
+name = "Admin Only"
+method = "GET"
+path = "/users/alice"
+depends_on = "Lookup"
+when = "${role} == 'admin'"
+
+[[request]]
+name = "Non Admin"
+method = "GET"
+path = "/users/alice"
+depends_on = "Lookup"
+skip_if = "${role} == 'admin'"
+`)
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.False(t, results[1].skipped)
+	assert.Equal(t, "Non Admin", results[2].name)
+	assert.True(t, results[2].skipped)
+	assert.ErrorContains(t, results[2].err, `skip_if "${role} == 'admin'" is true`)
+}
+
+func TestExecute_SkippedDependency(t *testing.T) {
+	results, err := runConditionChain(t, `
+data = "users.csv"
+
+[[request]]
+name = "Lookup"
+method = "GET"
+path = "/users/${user}"
+when = "${user} == 'alice'"
+extract = { role = "role" }
+
+[[request]]
+name = "Role"
+method = "GET"
+path = "/roles/${role}"
+depends_on = "Lookup"
+
+[[request]]
+name = "Audit"
+method = "POST"
+path = "/users"
+depends_on = "Role"
+`)
+
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+	assert.Equal(t, "Role[0]", results[1].name)
+	assert.False(t, results[1].skipped)
+
+	// Dependents of the skipped lookup are skipped transitively, not sent with ${role}
+	assert.Equal(t, "Lookup[1]", results[3].name)
+	assert.True(t, results[3].skipped)
+	for _, result := range results[4:] {
+		assert.True(t, result.skipped, result.name)
+		assert.Zero(t, result.status, result.name)
+	}
+	assert.ErrorContains(t, results[4].err, "depends on skipped request 'Lookup'")
+	assert.ErrorContains(t, results[5].err, "depends on skipped request 'Role'")
+}
+
+func TestExecute_ConditionError(t *testing.T) {
+	results, err := runConditionChain(t, `
+[[request]]
+name = "Lookup"
+method = "GET"
+path = "/users/alice"
+extract = { role = "role" }
+
+[[request]]
+name = "Broken"
+method = "GET"
+path = "/users/alice"
+depends_on = "Lookup"
+when = "${role} > 1"
+`)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to evaluate condition")
+	require.Len(t, results, 2)
+	assert.False(t, results[1].skipped)
+	assert.Error(t, results[1].err)
+}
@@ -0,0 +1,158 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// newDataChainServer returns a server issuing a token per user and echoing the profile of the token owner
+func newDataChainServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/login/"):
+			fmt.Fprintf(w, `{"token": "token-%s"}`, strings.TrimPrefix(r.URL.Path, "/login/"))
+		case r.URL.Path == "/profile":
+			user := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer token-")
+			fmt.Fprintf(w, `{"user": %q}`, user)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newDataChainConfig returns a login/profile chain repeated for every row of users.csv
+func newDataChainConfig(t *testing.T, baseURL string, concurrency bool) *rule.Config {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte("user\nalice\nbob\ncarol\n"), 0644))
+	configPath := filepath.Join(dir, "chain.toml")
+	content := fmt.Sprintf(`
+base_url = %q
+concurrency = %t
+data = "users.csv"
+
+[[request]]
+name = "Profile"
+method = "GET"
+path = "/profile?row=${$index}"
+headers = ["Authorization: Bearer ${token}"]
+depends_on = "Login"
+extract = { owner = "user" }
+
+[[request]]
+name = "Login"
+method = "POST"
+path = "/login/${user}"
+extract = { token = "token" }
+`, baseURL, concurrency)
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	config, err := rule.LoadConfig(configPath)
+	require.NoError(t, err)
+	return config
+}
+
+func TestExecute_ConfigData(t *testing.T) {
+	for _, concurrency := range []bool{false, true} {
+		t.Run(fmt.Sprintf("concurrency=%t", concurrency), func(t *testing.T) {
+			server := newDataChainServer(t)
+			config := newDataChainConfig(t, server.URL, concurrency)
+
+			var mu sync.Mutex
+			var names []string
+			owners := make(map[string]string)
+
+			executor := NewChainExecutor()
+			executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, variables map[string]string) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, statusCode)
+				names = append(names, name)
+				if owner, ok := variables["owner"]; ok {
+					owners[name] = owner
+				}
+			})
+
+			err := executor.Execute(context.Background(), config)
+
+			require.NoError(t, err)
+			if !concurrency {
+				// Rows run one after another, dependencies first
+				assert.Equal(t, []string{"Login[0]", "Profile[0]", "Login[1]", "Profile[1]", "Login[2]", "Profile[2]"}, names)
+			}
+			sort.Strings(names)
+			assert.Equal(t, []string{"Login[0]", "Login[1]", "Login[2]", "Profile[0]", "Profile[1]", "Profile[2]"}, names)
+
+			// Each row extracts its own variables
+			assert.Equal(t, map[string]string{"Profile[0]": "alice", "Profile[1]": "bob", "Profile[2]": "carol"}, owners)
+		})
+	}
+}
+
+func TestExecute_RequestData(t *testing.T) {
+	server := newDataChainServer(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.json"), []byte(`["dave", "erin"]`), 0644))
+	configPath := filepath.Join(dir, "chain.toml")
+	content := fmt.Sprintf(`
+base_url = %q
+
+[[request]]
+name = "Login"
+method = "POST"
+path = "/login/${value}"
+data = "users.json"
+`, server.URL)
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	config, err := rule.LoadConfig(configPath)
+	require.NoError(t, err)
+
+	var urls []string
+	executor := NewChainExecutor()
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, variables map[string]string) {
+		urls = append(urls, name+" "+strings.TrimPrefix(url, server.URL))
+	})
+
+	err = executor.Execute(context.Background(), config)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Login[0] /login/dave", "Login[1] /login/erin"}, urls)
+}
+
+func TestExecute_ConfigDataRowError(t *testing.T) {
+	server := newDataChainServer(t)
+	config := newDataChainConfig(t, server.URL, false)
+	config.Request[1].Path = "/missing/${user}"
+
+	executor := NewChainExecutor()
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, variables map[string]string) {
+	})
+
+	err := executor.Execute(context.Background(), config)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 0")
+}
@@ -0,0 +1,66 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+func TestExecute_Poll(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jobs":
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, `{"job_id": "42"}`)
+		case "/jobs/42":
+			if polls.Add(1) < 3 {
+				fmt.Fprint(w, `{"status": "running"}`)
+				return
+			}
+			fmt.Fprint(w, `{"status": "done", "result": {"url": "/reports/7"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := &rule.Config{
+		BaseUrl: server.URL,
+		Request: []rule.Request{
+			{Name: "Start", Method: "POST", Path: "/jobs", Extract: map[string]string{"job_id": "job_id"}},
+			{
+				Name:      "Wait",
+				Method:    "GET",
+				Path:      "/jobs/${job_id}",
+				DependsOn: "Start",
+				Poll:      &rule.Poll{Until: "status == 'done'", Interval: time.Millisecond},
+				Extract:   map[string]string{"report": "result.url"},
+			},
+		},
+	}
+
+	var variables map[string]string
+	executor := NewChainExecutor()
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, vars map[string]string) {
+		if name == "Wait" {
+			assert.NoError(t, err)
+			variables = vars
+		}
+	})
+
+	err := executor.Execute(context.Background(), config)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), polls.Load())
+	assert.Equal(t, map[string]string{"report": "/reports/7"}, variables)
+}
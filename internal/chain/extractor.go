@@ -3,8 +3,10 @@ package chain
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/tidwall/gjson"
 	"github.com/ymatsukawa/jak/internal/http"
@@ -72,6 +74,7 @@ func newVariableExtractor(extractor JSONExtractor) *variableExtractor {
 
 // ExtractVariables extracts variables from an HTTP response based on provided extraction paths.
 // It reads the response body, parses it as JSON, and extracts values using the JSON extractor.
+// Paths starting with "redirects:" are evaluated against the redirect chain instead of the body.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control
@@ -102,6 +105,7 @@ func (ve *variableExtractor) ExtractVariables(
 	}
 
 	jsonString := string(body)
+	redirectsJSON := ""
 	variables := make(map[string]string)
 
 	for variableName, jsonPath := range extractions {
@@ -111,7 +115,19 @@ func (ve *variableExtractor) ExtractVariables(
 		default:
 		}
 
-		value, exists := ve.jsonExtractor.Extract(jsonString, jsonPath)
+		// Paths with the redirects prefix are evaluated against the redirect chain
+		source, path := jsonString, jsonPath
+		if strings.HasPrefix(jsonPath, redirectsPathPrefix) {
+			if redirectsJSON == "" {
+				redirectsJSON, err = redirectsDocument(resp.Redirects)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode redirect chain: %w", err)
+				}
+			}
+			source, path = redirectsJSON, strings.TrimPrefix(jsonPath, redirectsPathPrefix)
+		}
+
+		value, exists := ve.jsonExtractor.Extract(source, path)
 		if !exists {
 			return nil, fmt.Errorf("path '%s' not found for variable '%s': %w", jsonPath, variableName, se.ErrPathNotFound)
 		}
@@ -121,6 +137,47 @@ func (ve *variableExtractor) ExtractVariables(
 	return variables, nil
 }
 
+// redirectsDocument encodes the redirect chain of a response as a JSON array for extraction.
+// Each hop is an object with status, method, url, location and header fields; header
+// values are the first value of each response header.
+//
+// Parameters:
+//   - hops: Redirect hops, oldest first
+//
+// Returns:
+//   - string: JSON array describing the hops
+//   - error: Any error encountered during encoding
+func redirectsDocument(hops []http.RedirectHop) (string, error) {
+	type hopDocument struct {
+		Status   int               `json:"status"`
+		Method   string            `json:"method"`
+		URL      string            `json:"url"`
+		Location string            `json:"location"`
+		Header   map[string]string `json:"header"`
+	}
+
+	documents := make([]hopDocument, 0, len(hops))
+	for _, hop := range hops {
+		header := make(map[string]string, len(hop.Header))
+		for key := range hop.Header {
+			header[key] = hop.Header.Get(key)
+		}
+		documents = append(documents, hopDocument{
+			Status:   hop.StatusCode,
+			Method:   hop.Method,
+			URL:      hop.URL,
+			Location: hop.Location(),
+			Header:   header,
+		})
+	}
+
+	data, err := json.Marshal(documents)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // readResponseBody safely reads the response body with size limits.
 // It also resets the body for further use.
 //
@@ -178,6 +178,40 @@ func TestExtractVariables(t *testing.T) {
 			},
 			expectErr: nil,
 		},
+		{
+			name: "successfully extracts from redirect chain",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`{"id": 7}`)),
+				Redirects: []http.RedirectHop{
+					{Method: "POST", URL: "http://example.com/login", StatusCode: 302,
+						Header: map[string][]string{"Location": {"/callback?code=xyz"}}},
+					{Method: "GET", URL: "http://example.com/callback?code=xyz", StatusCode: 303,
+						Header: map[string][]string{"Location": {"/home"}, "Set-Cookie": {"sid=1"}}},
+				},
+			},
+			extractions: map[string]string{
+				"callback": "redirects:0.location",
+				"cookie":   "redirects:1.header.Set-Cookie",
+				"status":   "redirects:1.status",
+				"id":       "id",
+			},
+			expect: map[string]string{
+				"callback": "/callback?code=xyz",
+				"cookie":   "sid=1",
+				"status":   "303",
+				"id":       "7",
+			},
+		},
+		{
+			name: "missing redirect hop returns error",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`{}`)),
+			},
+			extractions: map[string]string{
+				"callback": "redirects:0.location",
+			},
+			expectErr: se.ErrPathNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,168 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/ymatsukawa/jak/internal/dataset"
+	"github.com/ymatsukawa/jak/internal/engine"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// ItemVariable is the variable holding the current element of a for_each iteration.
+// Fields of object elements are available as ${item.<field>}.
+const ItemVariable = "item"
+
+// singleVariablePattern matches a for_each value that is a single ${name} reference
+var singleVariablePattern = regexp.MustCompile(`^\${([^}]+)}$`)
+
+// processForEach executes a request once per element of its for_each array.
+// Iterations are reported as name[i]; values listed in collect are gathered from
+// the iterations that ran and stored as JSON array variables afterwards.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - run: State of the current pass through the chain
+//   - requestObj: Request with for_each settings
+//   - name: Name under which the results are reported
+//   - config: Configuration containing global settings
+//
+// Returns:
+//   - error: Any error encountered during processing, nil if ignored due to ignore_fail
+func (executor *ChainExecutor) processForEach(
+	ctx context.Context,
+	run *chainRun,
+	requestObj *rule.Request,
+	name string,
+	config *rule.Config,
+) error {
+	items, err := run.forEachItems(requestObj.ForEach)
+	if err != nil {
+		err = fmt.Errorf("invalid for_each: %w", err)
+		executor.collect(name, requestObj.Method, engine.RequestURL(config, requestObj), 0, err, 0, nil, nil)
+		return executor.handleFailure(name, err, config)
+	}
+
+	collected := make(map[string][]string, len(requestObj.Collect))
+	for index, item := range items {
+		vars := dataset.MergeVariables(run.vars, itemVariables(item, index))
+		applied := dataset.ApplyToRequest(config, *requestObj, vars)
+
+		result, err := executor.processRequest(ctx, run, &applied, dataset.IterationName(name, index), vars, config)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			continue
+		}
+		for target, from := range requestObj.Collect {
+			if value, ok := result.Variables[from]; ok {
+				collected[target] = append(collected[target], value)
+			}
+		}
+	}
+
+	for target := range requestObj.Collect {
+		if err := run.variables.Set(target, collectedArray(collected[target])); err != nil {
+			return fmt.Errorf("failed to set variable '%s': %w", target, err)
+		}
+	}
+	return nil
+}
+
+// forEachItems resolves the array a request iterates over.
+// A single ${name} reference is looked up without the length limit applied when
+// variables are substituted, so long extracted arrays are kept intact.
+//
+// Parameters:
+//   - value: for_each setting, e.g. "${ids}" or a literal JSON array
+//
+// Returns:
+//   - []gjson.Result: Elements of the array
+//   - error: Error if the variable is not set or the value is not a JSON array
+func (run *chainRun) forEachItems(value string) ([]gjson.Result, error) {
+	value = strings.TrimSpace(value)
+
+	if match := singleVariablePattern.FindStringSubmatch(value); match != nil {
+		resolved, ok := run.lookup(nil)(match[1])
+		if !ok {
+			return nil, fmt.Errorf("variable '%s' is not set", match[1])
+		}
+		value = resolved
+	} else {
+		value = dataset.Resolve(value, run.vars)
+		if run.variables != nil {
+			value = run.variables.Resolve(value)
+		}
+	}
+
+	result := gjson.Parse(value)
+	if !result.IsArray() {
+		return nil, fmt.Errorf("expected a JSON array, got %q", truncate(value, 50))
+	}
+	return result.Array(), nil
+}
+
+// itemVariables returns the variables of a for_each iteration.
+//
+// Parameters:
+//   - item: Current element
+//   - index: Zero-based index of the element
+//
+// Returns:
+//   - map[string]string: ${item}, ${item.<field>} for object elements and ${$index}
+func itemVariables(item gjson.Result, index int) map[string]string {
+	vars := dataset.Variables(nil, index)
+	vars[ItemVariable] = item.String()
+
+	if item.IsObject() {
+		item.ForEach(func(key, value gjson.Result) bool {
+			vars[ItemVariable+"."+key.String()] = value.String()
+			return true
+		})
+	}
+	return vars
+}
+
+// collectedArray encodes collected values as a JSON array.
+// Values that are JSON objects or arrays are kept as is, everything else becomes a string.
+//
+// Parameters:
+//   - values: Values collected from the iterations
+//
+// Returns:
+//   - string: JSON array
+func collectedArray(values []string) string {
+	elements := make([]json.RawMessage, 0, len(values))
+	for _, value := range values {
+		trimmed := strings.TrimSpace(value)
+		if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+			elements = append(elements, json.RawMessage(trimmed))
+			continue
+		}
+		encoded, _ := json.Marshal(value)
+		elements = append(elements, encoded)
+	}
+
+	data, _ := json.Marshal(elements)
+	return string(data)
+}
+
+// truncate shortens a string for error messages.
+//
+// Parameters:
+//   - value: String to shorten
+//   - max: Maximum length
+//
+// Returns:
+//   - string: Value, cut to max characters with "..." appended if it was longer
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max] + "..."
+}
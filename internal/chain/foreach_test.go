@@ -0,0 +1,163 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// forEachResult is a result received by the collector in for_each tests
+type forEachResult struct {
+	name string
+	url  string
+	err  error
+}
+
+// runForEachChain executes requests against a server listing three items
+func runForEachChain(t *testing.T, requests []rule.Request) ([]forEachResult, *ChainExecutor, error) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/items":
+			fmt.Fprint(w, `{"data": [{"id": 1, "active": true}, {"id": 2, "active": false}, {"id": 3, "active": true}]}`)
+		case strings.HasPrefix(r.URL.Path, "/items/"):
+			id := strings.TrimPrefix(r.URL.Path, "/items/")
+			fmt.Fprintf(w, `{"name": "item-%s", "tags": ["t%s"]}`, id, id)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := &rule.Config{BaseUrl: server.URL, Request: requests}
+	require.NoError(t, config.Validate())
+
+	var results []forEachResult
+	executor := NewChainExecutor()
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *jakhttp.Timing, variables map[string]string) {
+		results = append(results, forEachResult{name: name, url: strings.TrimPrefix(url, server.URL), err: err})
+	})
+
+	err := executor.Execute(context.Background(), config)
+	return results, executor, err
+}
+
+func TestExecute_ForEach(t *testing.T) {
+	results, executor, err := runForEachChain(t, []rule.Request{
+		{Name: "List", Method: "GET", Path: "/items", Extract: map[string]string{"ids": "data.#.id"}},
+		{
+			Name:      "Get",
+			Method:    "GET",
+			Path:      "/items/${item}?index=${$index}",
+			DependsOn: "List",
+			ForEach:   "${ids}",
+			Extract:   map[string]string{"name": "name", "tags": "tags"},
+			Collect:   map[string]string{"names": "name", "all_tags": "tags"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []forEachResult{
+		{name: "List", url: "/items"},
+		{name: "Get[0]", url: "/items/1?index=0"},
+		{name: "Get[1]", url: "/items/2?index=1"},
+		{name: "Get[2]", url: "/items/3?index=2"},
+	}, results)
+
+	names, _ := executor.variableResolver.Get("names")
+	assert.JSONEq(t, `["item-1", "item-2", "item-3"]`, names)
+	tags, _ := executor.variableResolver.Get("all_tags")
+	assert.JSONEq(t, `[["t1"], ["t2"], ["t3"]]`, tags)
+}
+
+func TestExecute_ForEachObjects(t *testing.T) {
+	results, executor, err := runForEachChain(t, []rule.Request{
+		{Name: "List", Method: "GET", Path: "/items", Extract: map[string]string{"items": "data"}},
+		{
+			Name:      "Get",
+			Method:    "GET",
+			Path:      "/items/${item.id}",
+			DependsOn: "List",
+			ForEach:   "${items}",
+			When:      "${item.active} == true",
+			Extract:   map[string]string{"name": "name"},
+			Collect:   map[string]string{"names": "name"},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, "/items/1", results[1].url)
+	assert.ErrorContains(t, results[2].err, "request skipped")
+	assert.Equal(t, "/items/3", results[3].url)
+
+	// Only iterations that ran are collected
+	names, _ := executor.variableResolver.Get("names")
+	assert.JSONEq(t, `["item-1", "item-3"]`, names)
+}
+
+func TestExecute_ForEachInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		forEach string
+		wantErr string
+	}{
+		{name: "unknown variable", forEach: "${missing}", wantErr: "variable 'missing' is not set"},
+		{name: "not an array", forEach: "${first}", wantErr: "expected a JSON array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, _, err := runForEachChain(t, []rule.Request{
+				{Name: "List", Method: "GET", Path: "/items", Extract: map[string]string{"first": "data.0.id"}},
+				{Name: "Get", Method: "GET", Path: "/items/${item}", DependsOn: "List", ForEach: tt.forEach},
+			})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			require.Len(t, results, 2)
+			assert.Equal(t, "Get", results[1].name)
+		})
+	}
+}
+
+func TestExecute_ForEachLiteral(t *testing.T) {
+	results, _, err := runForEachChain(t, []rule.Request{
+		{Name: "Get", Method: "GET", Path: "/items/${item}", ForEach: `["a", "b"]`},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []forEachResult{
+		{name: "Get[0]", url: "/items/a"},
+		{name: "Get[1]", url: "/items/b"},
+	}, results)
+}
+
+func TestItemVariables(t *testing.T) {
+	vars := itemVariables(gjson.Parse(`{"id": 7, "user": {"name": "alice"}}`), 2)
+
+	assert.Equal(t, map[string]string{
+		"item":      `{"id": 7, "user": {"name": "alice"}}`,
+		"item.id":   "7",
+		"item.user": `{"name": "alice"}`,
+		"$index":    "2",
+	}, vars)
+}
+
+func TestCollectedArray(t *testing.T) {
+	assert.Equal(t, `[]`, collectedArray(nil))
+	assert.Equal(t, `["a","1",{"id":1},[1,2]]`, collectedArray([]string{"a", "1", `{"id":1}`, `[1,2]`}))
+	assert.Equal(t, `["{not json"]`, collectedArray([]string{"{not json"}))
+}
@@ -18,6 +18,9 @@ type ExecutionResult struct {
 
 	// Variables is a map of variable names to their extracted values from the response
 	Variables map[string]string
+
+	// Timing is the timing breakdown of the request
+	Timing *http.Timing
 }
 
 // RequestProcessor is responsible for preparing, executing, and extracting variables from requests.
@@ -101,8 +104,13 @@ func (processor *DefaultRequestProcessor) ProcessRequest(
 		return nil, fmt.Errorf("failed to prepare request: %w", err)
 	}
 
+	// Resolve variables in the effective (request or config level) auth settings
+	if auth := config.AuthFor(request); auth != nil {
+		preparedRequest.Auth = processor.resolveAuth(auth)
+	}
+
 	// Execute the request
-	response, err := processor.executeRequest(ctx, config, preparedRequest)
+	response, err := processor.sendRequest(ctx, config, preparedRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +119,7 @@ func (processor *DefaultRequestProcessor) ProcessRequest(
 	result := &ExecutionResult{
 		StatusCode: response.StatusCode,
 		Variables:  make(map[string]string),
+		Timing:     response.Timing,
 	}
 
 	// Extract variables if needed
@@ -169,6 +178,10 @@ func (processor *DefaultRequestProcessor) prepareRequest(ctx context.Context, re
 		modifiedReq.Headers = processor.variableResolver.ResolveHeaders(req.Headers)
 	}
 
+	if len(req.Query) > 0 {
+		modifiedReq.Query = processor.resolveQuery(req.Query)
+	}
+
 	// Process different body types
 	if isNonEmptyStringPtr(req.JsonBody) {
 		body := processor.variableResolver.ResolveBody(req.JsonBody)
@@ -188,8 +201,80 @@ func (processor *DefaultRequestProcessor) prepareRequest(ctx context.Context, re
 	return &modifiedReq, nil
 }
 
+// resolveQuery applies variable substitutions to query parameter values.
+// A new map is returned so the original request configuration is left untouched.
+//
+// Parameters:
+//   - query: Original query parameters
+//
+// Returns:
+//   - rule.QueryParams: Query parameters with variables resolved
+func (processor *DefaultRequestProcessor) resolveQuery(query rule.QueryParams) rule.QueryParams {
+	resolved := make(rule.QueryParams, len(query))
+	for key, values := range query {
+		resolvedValues := make([]string, len(values))
+		for i, value := range values {
+			resolvedValues[i] = processor.variableResolver.Resolve(value)
+		}
+		resolved[key] = resolvedValues
+	}
+	return resolved
+}
+
+// resolveAuth applies variable substitutions to authentication settings.
+// A copy is returned so the original configuration is left untouched.
+//
+// Parameters:
+//   - auth: Original authentication settings
+//
+// Returns:
+//   - *rule.Auth: Authentication settings with variables resolved
+func (processor *DefaultRequestProcessor) resolveAuth(auth *rule.Auth) *rule.Auth {
+	resolved := *auth
+	resolved.Username = processor.variableResolver.Resolve(auth.Username)
+	resolved.Password = processor.variableResolver.Resolve(auth.Password)
+	resolved.Token = processor.variableResolver.Resolve(auth.Token)
+	resolved.Value = processor.variableResolver.Resolve(auth.Value)
+	resolved.ClientID = processor.variableResolver.Resolve(auth.ClientID)
+	resolved.ClientSecret = processor.variableResolver.Resolve(auth.ClientSecret)
+	resolved.RefreshToken = processor.variableResolver.Resolve(auth.RefreshToken)
+	return &resolved
+}
+
+// sendRequest executes a prepared request.
+// Requests with polling settings are re-issued until their condition holds; the
+// condition can refer to variables extracted earlier in the chain.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - config: Global configuration settings
+//   - req: Prepared request configuration
+//
+// Returns:
+//   - *http.Response: HTTP response from the server, the final one when polling
+//   - error: Any error encountered during execution
+func (processor *DefaultRequestProcessor) sendRequest(
+	ctx context.Context,
+	config *rule.Config,
+	req *rule.Request,
+) (*http.Response, error) {
+	if req.Poll == nil {
+		return processor.executeRequest(ctx, config, req)
+	}
+
+	resp, err := engine.Poll(ctx, req.Poll, processor.variableResolver.Get, func() (*http.Response, error) {
+		return processor.executeRequest(ctx, config, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("polling failed: %w", err)
+	}
+	return resp, nil
+}
+
 // executeRequest creates and sends an HTTP request.
 // It creates the request from configuration and executes it with the HTTP client.
+// Requests with pagination settings follow all their pages, and the response body
+// holds the merged items so they are available for extraction.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control
@@ -211,6 +296,14 @@ func (processor *DefaultRequestProcessor) executeRequest(
 	default:
 	}
 
+	if req.Paginate != nil {
+		resp, err := engine.FetchPages(ctx, processor.factory, processor.client, config, req)
+		if err != nil {
+			return nil, fmt.Errorf("request execution failed: %w", err)
+		}
+		return resp, nil
+	}
+
 	// Create HTTP request
 	httpReq, err := processor.factory.CreateFromConfig(config, req)
 	if err != nil {
@@ -223,7 +316,7 @@ func (processor *DefaultRequestProcessor) executeRequest(
 	// Execute request
 	resp, err := processor.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("request execution failed: %w", se.ErrRequestExecution)
+		return nil, fmt.Errorf("%w: %w", se.ErrRequestExecution, err)
 	}
 
 	return resp, nil
@@ -366,3 +366,80 @@ func TestProcessRequest(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to extract variables")
 	})
 }
+
+func TestPrepareRequest_ResolvesQuery(t *testing.T) {
+	resolver := NewVariableResolver()
+	assert.NoError(t, resolver.Set("page", "2"))
+
+	processor := &DefaultRequestProcessor{variableResolver: resolver}
+	request := &rule.Request{
+		Name:   "list",
+		Method: "GET",
+		Path:   "/items",
+		Query:  rule.QueryParams{"page": {"${page}"}, "tags": {"a", "b"}},
+	}
+
+	prepared, err := processor.prepareRequest(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rule.QueryParams{"page": {"2"}, "tags": {"a", "b"}}, prepared.Query)
+	assert.Equal(t, []string{"${page}"}, request.Query["page"], "original request must not be modified")
+}
+
+func TestResolveAuth(t *testing.T) {
+	resolver := NewVariableResolver()
+	assert.NoError(t, resolver.Set("token", "abc"))
+
+	processor := &DefaultRequestProcessor{variableResolver: resolver}
+	auth := &rule.Auth{Type: rule.AuthTypeBearer, Token: "${token}"}
+
+	resolved := processor.resolveAuth(auth)
+
+	assert.Equal(t, "abc", resolved.Token)
+	assert.Equal(t, "${token}", auth.Token, "original auth must not be modified")
+}
+
+func TestProcessRequest_Paginate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := mock_engine.NewMockFactory(ctrl)
+	mockClient := mock_http.NewMockClient(ctrl)
+
+	config := &rule.Config{BaseUrl: "http://example.com"}
+	request := &rule.Request{
+		Name:     "list",
+		Method:   "GET",
+		Path:     "/items",
+		Paginate: &rule.Paginate{Type: rule.PaginateTypeCursor, Items: "data", CursorPath: "next"},
+		Extract:  map[string]string{"count": "#", "last_id": "@reverse.0.id"},
+	}
+
+	pages := []string{
+		`{"data": [{"id": "a"}, {"id": "b"}], "next": "b"}`,
+		`{"data": [{"id": "c"}], "next": null}`,
+	}
+	var urls []string
+
+	mockFactory.EXPECT().
+		CreateFromConfig(config, gomock.Any()).
+		DoAndReturn(func(config *rule.Config, req *rule.Request) (*http.Request, error) {
+			return &http.Request{URL: "http://example.com/items"}, nil
+		}).
+		Times(2)
+	mockClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL)
+			page := pages[len(urls)-1]
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(page))}, nil
+		}).
+		Times(2)
+
+	processor := NewRequestProcessor(mockFactory, mockClient, NewVariableResolver())
+	result, err := processor.ProcessRequest(context.Background(), request, config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://example.com/items", "http://example.com/items?cursor=b"}, urls)
+	assert.Equal(t, map[string]string{"count": "3", "last_id": "c"}, result.Variables)
+}
@@ -0,0 +1,446 @@
+// Package condition provides the expressions used by the when and skip_if request settings.
+//
+// An expression compares operands with ==, !=, <, <=, > and >= and combines the
+// results with &&, || and !; parentheses group sub-expressions. Operands are
+// ${name} variable references, 'single' or "double" quoted strings, numbers and
+// the literals true, false and null. Operands that both look like numbers are
+// compared numerically, other operands as strings. Unknown variables are empty.
+// An operand used on its own is true unless it is empty, "false" or "0".
+//
+// Expressions parsed with ParseFields may also refer to fields of a document by
+// bare path, e.g. status == 'done' or data.items.# > 0.
+package condition
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Lookup returns the value of a variable referenced as ${name}.
+//
+// Parameters:
+//   - name: Variable name
+//
+// Returns:
+//   - string: Variable value
+//   - bool: True if the variable exists
+type Lookup func(name string) (string, bool)
+
+// Env provides the values an expression refers to.
+type Env struct {
+	// Variables resolves ${name} references
+	Variables Lookup
+
+	// Fields resolves bare field paths of expressions parsed with ParseFields
+	Fields Lookup
+}
+
+// Expr is a parsed condition expression.
+type Expr struct {
+	// source is the expression as written in the configuration
+	source string
+
+	// root is the top node of the expression tree
+	root node
+}
+
+// Parse parses a condition expression.
+//
+// Parameters:
+//   - source: Expression, e.g. "${role} == 'admin' && ${Lookup.status} != 404"
+//
+// Returns:
+//   - *Expr: Parsed expression
+//   - error: Syntax error, if any
+func Parse(source string) (*Expr, error) {
+	return parse(source, false)
+}
+
+// ParseFields parses a condition expression in which bare paths refer to fields.
+//
+// Parameters:
+//   - source: Expression, e.g. "status == 'done' || ${$status} == 404"
+//
+// Returns:
+//   - *Expr: Parsed expression
+//   - error: Syntax error, if any
+func ParseFields(source string) (*Expr, error) {
+	return parse(source, true)
+}
+
+// parse parses a condition expression.
+//
+// Parameters:
+//   - source: Expression to parse
+//   - fields: True if bare paths refer to fields
+//
+// Returns:
+//   - *Expr: Parsed expression
+//   - error: Syntax error, if any
+func parse(source string, fields bool) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %s at position %d", token, token.pos+1)
+	}
+
+	return &Expr{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression.
+//
+// Parameters:
+//   - lookup: Resolves variable references
+//
+// Returns:
+//   - bool: Result of the expression
+//   - error: Error if operands cannot be compared
+func (e *Expr) Eval(lookup Lookup) (bool, error) {
+	return e.EvalEnv(Env{Variables: lookup})
+}
+
+// EvalEnv evaluates the expression against variables and fields.
+//
+// Parameters:
+//   - env: Resolves variable references and field paths
+//
+// Returns:
+//   - bool: Result of the expression
+//   - error: Error if operands cannot be compared
+func (e *Expr) EvalEnv(env Env) (bool, error) {
+	value, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+// String returns the expression as written.
+//
+// Returns:
+//   - string: Source of the expression
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Evaluate parses and evaluates an expression in one step.
+//
+// Parameters:
+//   - source: Expression to evaluate
+//   - lookup: Resolves variable references
+//
+// Returns:
+//   - bool: Result of the expression
+//   - error: Syntax or evaluation error, if any
+func Evaluate(source string, lookup Lookup) (bool, error) {
+	expr, err := Parse(source)
+	if err != nil {
+		return false, err
+	}
+	return expr.Eval(lookup)
+}
+
+// truthy reports whether a value counts as true.
+//
+// Parameters:
+//   - value: Operand or comparison result
+//
+// Returns:
+//   - bool: False for empty, "false" and "0", true otherwise
+func truthy(value string) bool {
+	return value != "" && value != "false" && value != "0"
+}
+
+// node is an element of the expression tree.
+type node interface {
+	// eval returns the value of the node; comparisons and logical operators yield "true" or "false"
+	eval(env Env) (string, error)
+}
+
+// literal is a constant operand.
+type literal struct {
+	value string
+}
+
+// eval returns the literal value.
+func (n literal) eval(Env) (string, error) {
+	return n.value, nil
+}
+
+// variable is a ${name} reference.
+type variable struct {
+	name string
+}
+
+// eval returns the variable value, or empty if the variable is unknown.
+func (n variable) eval(env Env) (string, error) {
+	if env.Variables == nil {
+		return "", nil
+	}
+	value, _ := env.Variables(n.name)
+	return value, nil
+}
+
+// field is a bare path referring to a field.
+type field struct {
+	path string
+}
+
+// eval returns the field value, or empty if the field does not exist.
+func (n field) eval(env Env) (string, error) {
+	if env.Fields == nil {
+		return "", nil
+	}
+	value, _ := env.Fields(n.path)
+	return value, nil
+}
+
+// not negates its operand.
+type not struct {
+	operand node
+}
+
+// eval returns the negated truth value of the operand.
+func (n not) eval(env Env) (string, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(!truthy(value)), nil
+}
+
+// logical is a short-circuit && or || operation.
+type logical struct {
+	op          string
+	left, right node
+}
+
+// eval evaluates the right operand only if the left one does not decide the result.
+func (n logical) eval(env Env) (string, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return "", err
+	}
+	if truthy(left) == (n.op == "||") {
+		return strconv.FormatBool(truthy(left)), nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(truthy(right)), nil
+}
+
+// comparison compares two operands.
+type comparison struct {
+	op          string
+	left, right node
+}
+
+// eval compares the values of both operands.
+func (n comparison) eval(env Env) (string, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return "", err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := compare(n.op, left, right)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(result), nil
+}
+
+// compare applies a comparison operator.
+// Numbers are compared numerically; ordering operators require numbers.
+//
+// Parameters:
+//   - op: Comparison operator
+//   - left: Left operand
+//   - right: Right operand
+//
+// Returns:
+//   - bool: Result of the comparison
+//   - error: Error if an ordering operator is applied to non-numbers
+func compare(op, left, right string) (bool, error) {
+	leftNumber, leftErr := strconv.ParseFloat(left, 64)
+	rightNumber, rightErr := strconv.ParseFloat(right, 64)
+	numeric := leftErr == nil && rightErr == nil
+
+	switch op {
+	case "==":
+		if numeric {
+			return leftNumber == rightNumber, nil
+		}
+		return left == right, nil
+	case "!=":
+		if numeric {
+			return leftNumber != rightNumber, nil
+		}
+		return left != right, nil
+	}
+
+	if !numeric {
+		return false, fmt.Errorf("cannot compare %q %s %q: operands must be numbers", left, op, right)
+	}
+	switch op {
+	case "<":
+		return leftNumber < rightNumber, nil
+	case "<=":
+		return leftNumber <= rightNumber, nil
+	case ">":
+		return leftNumber > rightNumber, nil
+	default:
+		return leftNumber >= rightNumber, nil
+	}
+}
+
+// parser is a recursive descent parser over the tokens of an expression.
+type parser struct {
+	tokens []token
+	pos    int
+
+	// fields allows bare paths referring to fields
+	fields bool
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *parser) next() token {
+	token := p.tokens[p.pos]
+	if token.kind != tokenEnd {
+		p.pos++
+	}
+	return token
+}
+
+// parseOr parses operands joined by ||.
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenOperator, "||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses operands joined by &&.
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenOperator, "&&") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseNot parses an optionally negated comparison.
+func (p *parser) parseNot() (node, error) {
+	if p.peek().is(tokenOperator, "!") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses an operand optionally compared with another one.
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	token := p.peek()
+	if token.kind != tokenOperator || !isComparison(token.text) {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return comparison{op: token.text, left: left, right: right}, nil
+}
+
+// parseOperand parses a variable, literal or parenthesized expression.
+func (p *parser) parseOperand() (node, error) {
+	token := p.next()
+
+	switch token.kind {
+	case tokenVariable:
+		return variable{name: token.text}, nil
+	case tokenString, tokenNumber:
+		return literal{value: token.text}, nil
+	case tokenIdent:
+		switch token.text {
+		case "true", "false":
+			return literal{value: token.text}, nil
+		case "null":
+			return literal{value: ""}, nil
+		}
+		if p.fields {
+			return field{path: token.text}, nil
+		}
+		return nil, fmt.Errorf("unknown identifier %q at position %d (quote strings or use ${name} for variables)", token.text, token.pos+1)
+	case tokenOperator:
+		if token.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if closing := p.next(); !closing.is(tokenOperator, ")") {
+				return nil, fmt.Errorf("expected ) at position %d, got %s", closing.pos+1, closing)
+			}
+			return inner, nil
+		}
+	case tokenEnd:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %s at position %d", token, token.pos+1)
+}
+
+// isComparison reports whether an operator is a comparison operator.
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package condition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]string{
+		"role":          "admin",
+		"count":         "3",
+		"zero":          "0",
+		"Lookup.status": "404",
+		"name":          "O'Brien",
+		"enabled":       "true",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{`${role} == 'admin'`, true},
+		{`${role} == "user"`, false},
+		{`${role} != 'user'`, true},
+		{`${Lookup.status} == 404`, true},
+		{`${Lookup.status} >= 400 && ${Lookup.status} < 500`, true},
+		{`${count} > 10`, false},
+		{`${count} == 3.0`, true},
+		{`${count} == '3'`, true},
+		{`${name} == 'O\'Brien'`, true},
+		{`${enabled}`, true},
+		{`${enabled} == true`, true},
+		{`${zero}`, false},
+		{`!${zero}`, true},
+		{`${missing}`, false},
+		{`${missing} == null`, true},
+		{`${missing} == ''`, true},
+		{`${role} == 'user' || ${count} > 1`, true},
+		{`!(${role} == 'admin' && ${count} > 5)`, true},
+		{`${role} == 'admin' && (${count} < 2 || ${Lookup.status} == 404)`, true},
+		{`true && !false`, true},
+		{`-1 < 0`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := Evaluate(tt.expr, lookup)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEvaluate_ShortCircuit(t *testing.T) {
+	// The right operand would fail to compare, but is never evaluated
+	result, err := Evaluate(`false && 'a' < 'b'`, nil)
+	require.NoError(t, err)
+	assert.False(t, result)
+
+	result, err = Evaluate(`true || 'a' < 'b'`, nil)
+	require.NoError(t, err)
+	assert.True(t, result)
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	lookup := func(name string) (string, bool) { return "admin", true }
+
+	_, err := Evaluate(`${role} > 1`, lookup)
+
+	assert.ErrorContains(t, err, "operands must be numbers")
+}
+
+func TestParse_SyntaxErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`${role} ==`,
+		`${role} == admin`,
+		`${role`,
+		`${}`,
+		`'unterminated`,
+		`(${a} == 1`,
+		`${a} == 1)`,
+		`${a} = 1`,
+		`${a} == 1 2`,
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Parse(expr)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExpr_String(t *testing.T) {
+	expr, err := Parse(`${a} == 1`)
+
+	require.NoError(t, err)
+	assert.Equal(t, `${a} == 1`, expr.String())
+}
+
+func TestParseFields(t *testing.T) {
+	fields := map[string]string{
+		"status":        "done",
+		"data.items.#":  "2",
+		"job.progress":  "100",
+		"@this.enabled": "true",
+	}
+	env := Env{
+		Variables: func(name string) (string, bool) {
+			if name == "$status" {
+				return "200", true
+			}
+			return "", false
+		},
+		Fields: func(path string) (string, bool) {
+			value, ok := fields[path]
+			return value, ok
+		},
+	}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{`status == 'done'`, true},
+		{`status == 'done' && ${$status} == 200`, true},
+		{`data.items.# > 1`, true},
+		{`job.progress >= 100 || missing.field`, true},
+		{`missing.field == null`, true},
+		{`@this.enabled`, true},
+		{`status != 'done'`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseFields(tt.expr)
+			require.NoError(t, err)
+
+			result, err := expr.EvalEnv(env)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	// Bare paths are only allowed by ParseFields
+	_, err := Parse(`status == 'done'`)
+	assert.Error(t, err)
+}
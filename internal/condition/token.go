@@ -0,0 +1,186 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind identifies the type of a token.
+type tokenKind int
+
+// Token kinds of the expression language.
+const (
+	// tokenEnd marks the end of the expression
+	tokenEnd tokenKind = iota
+
+	// tokenVariable is a ${name} reference; text is the name
+	tokenVariable
+
+	// tokenString is a quoted string; text is the unquoted value
+	tokenString
+
+	// tokenNumber is a numeric literal
+	tokenNumber
+
+	// tokenIdent is a bare word such as true, false, null or a field path
+	tokenIdent
+
+	// tokenOperator is an operator or parenthesis
+	tokenOperator
+)
+
+// operators lists the operators, two-character operators first so they match greedily
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+// token is a lexical element of an expression.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is reports whether the token has the given kind and text.
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+// String describes the token for error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokenEnd:
+		return "end of expression"
+	case tokenVariable:
+		return fmt.Sprintf("${%s}", t.text)
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// tokenize splits an expression into tokens.
+//
+// Parameters:
+//   - source: Expression to split
+//
+// Returns:
+//   - []token: Tokens, terminated by a tokenEnd token
+//   - error: Error if the expression contains an invalid character or unterminated literal
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(source); {
+		c := source[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case strings.HasPrefix(source[i:], "${"):
+			end := strings.IndexByte(source[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated variable reference at position %d", i+1)
+			}
+			name := strings.TrimSpace(source[i+2 : i+end])
+			if name == "" {
+				return nil, fmt.Errorf("empty variable reference at position %d", i+1)
+			}
+			tokens = append(tokens, token{kind: tokenVariable, text: name, pos: i})
+			i += end + 1
+
+		case c == '\'' || c == '"':
+			value, length, err := readString(source[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w at position %d", err, i+1)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: i})
+			i += length
+
+		case isDigit(c) || (c == '-' && i+1 < len(source) && isDigit(source[i+1])):
+			start := i
+			i++
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], pos: start})
+
+		case isLetter(c) || c == '@':
+			start := i
+			for i < len(source) && isPathChar(source[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start})
+
+		default:
+			op := matchOperator(source[i:])
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i+1)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, pos: len(source)}), nil
+}
+
+// readString reads a quoted string; a backslash escapes the next character.
+//
+// Parameters:
+//   - source: Input starting with the opening quote
+//
+// Returns:
+//   - string: Unquoted value
+//   - int: Number of bytes consumed, including the quotes
+//   - error: Error if the string is not terminated
+func readString(source string) (string, int, error) {
+	quote := source[0]
+	var value strings.Builder
+
+	for i := 1; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			if i+1 < len(source) {
+				i++
+				value.WriteByte(source[i])
+			}
+		case quote:
+			return value.String(), i + 1, nil
+		default:
+			value.WriteByte(source[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// matchOperator returns the operator at the start of the input.
+//
+// Parameters:
+//   - source: Remaining input
+//
+// Returns:
+//   - string: Matched operator, or empty if there is none
+func matchOperator(source string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(source, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isLetter reports whether c can start an identifier.
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isPathChar reports whether c can continue an identifier or field path.
+func isPathChar(c byte) bool {
+	return isLetter(c) || isDigit(c) || strings.IndexByte(".#-@*?", c) >= 0
+}
@@ -0,0 +1,188 @@
+// Package dataset provides data-driven execution of requests from CSV and JSON files.
+package dataset
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// IndexVariable is the variable holding the zero-based index of the current row.
+const IndexVariable = "$index"
+
+// ValueColumn is the column name for rows of a JSON array of scalar values.
+const ValueColumn = "value"
+
+// Row is a single record of a dataset, mapping column names to values.
+type Row map[string]string
+
+// Load reads a dataset file.
+// CSV files need a header row naming the columns; JSON files hold an array of objects
+// whose fields become columns. Non-string JSON values are kept in their JSON form.
+//
+// Parameters:
+//   - path: Path to a .csv or .json file
+//
+// Returns:
+//   - []Row: Rows of the dataset in file order
+//   - error: Any error encountered while reading or parsing the file
+func Load(path string) ([]Row, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", se.ErrInvalidDataset, err)
+	}
+
+	var rows []Row
+	switch strings.ToLower(filepath.Ext(path)) {
+	case rule.DataFormatCSV:
+		rows, err = parseCSV(content)
+	case rule.DataFormatJSON:
+		rows, err = parseJSON(content)
+	default:
+		err = fmt.Errorf("unsupported file type %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", se.ErrInvalidDataset, path, err)
+	}
+
+	return rows, nil
+}
+
+// parseCSV parses CSV content with a header row.
+//
+// Parameters:
+//   - content: CSV file content
+//
+// Returns:
+//   - []Row: Parsed rows
+//   - error: Any error encountered while parsing
+func parseCSV(content []byte) ([]Row, error) {
+	// Excel and other tools prepend a byte order mark
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	header := records[0]
+	for i, column := range header {
+		header[i] = strings.TrimSpace(column)
+		if header[i] == "" {
+			return nil, fmt.Errorf("empty column name at position %d", i+1)
+		}
+	}
+
+	rows := make([]Row, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(Row, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseJSON parses a JSON array of objects or scalar values.
+// Scalars are exposed in the ValueColumn column.
+//
+// Parameters:
+//   - content: JSON file content
+//
+// Returns:
+//   - []Row: Parsed rows
+//   - error: Any error encountered while parsing
+func parseJSON(content []byte) ([]Row, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, fmt.Errorf("expected a JSON array: %w", err)
+	}
+
+	rows := make([]Row, 0, len(items))
+	for i, item := range items {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(item, &fields); err != nil || fields == nil {
+			// Not an object: the element itself is the value
+			rows = append(rows, Row{ValueColumn: jsonValue(item)})
+			continue
+		}
+
+		row := make(Row, len(fields))
+		for name, value := range fields {
+			if name == "" {
+				return nil, fmt.Errorf("empty field name in element %d", i)
+			}
+			row[name] = jsonValue(value)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// jsonValue converts a JSON value into a variable value.
+// Strings are unquoted, null becomes empty and everything else keeps its compact JSON form.
+//
+// Parameters:
+//   - raw: Raw JSON value
+//
+// Returns:
+//   - string: Variable value
+func jsonValue(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	trimmed := bytes.TrimSpace(raw)
+	if bytes.Equal(trimmed, []byte("null")) {
+		return ""
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, trimmed); err != nil {
+		return string(trimmed)
+	}
+	return compacted.String()
+}
+
+// Variables returns the variables of a row, including the row index.
+//
+// Parameters:
+//   - row: Dataset row
+//   - index: Zero-based index of the row
+//
+// Returns:
+//   - map[string]string: Column values and IndexVariable
+func Variables(row Row, index int) map[string]string {
+	vars := make(map[string]string, len(row)+1)
+	for column, value := range row {
+		vars[column] = value
+	}
+	vars[IndexVariable] = fmt.Sprintf("%d", index)
+	return vars
+}
+
+// IterationName returns the name under which one iteration of a request is reported.
+//
+// Parameters:
+//   - name: Request name
+//   - index: Zero-based row index
+//
+// Returns:
+//   - string: Name with the row index appended, e.g. "Get User[2]"
+func IterationName(name string, index int) string {
+	return fmt.Sprintf("%s[%d]", name, index)
+}
@@ -0,0 +1,113 @@
+package dataset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// writeDataset writes a dataset file into a temporary directory and returns its path
+func writeDataset(t *testing.T, name, content string) string {
+	t.Helper()
+
+	return writeDatasetAt(t, filepath.Join(t.TempDir(), name), content)
+}
+
+// writeDatasetAt writes a file at the given path and returns the path
+func writeDatasetAt(t *testing.T, path, content string) string {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected []Row
+		wantErr  bool
+	}{
+		{
+			name:    "csv with header",
+			file:    "users.csv",
+			content: "id, name\n1,alice\n2,\"bob, jr\"\n",
+			expected: []Row{
+				{"id": "1", "name": "alice"},
+				{"id": "2", "name": "bob, jr"},
+			},
+		},
+		{
+			name:     "csv with byte order mark",
+			file:     "users.csv",
+			content:  "\ufeffid\n7\n",
+			expected: []Row{{"id": "7"}},
+		},
+		{
+			name:     "csv header only",
+			file:     "users.csv",
+			content:  "id,name\n",
+			expected: []Row{},
+		},
+		{name: "empty csv", file: "users.csv", content: "", wantErr: true},
+		{name: "csv with ragged row", file: "users.csv", content: "id,name\n1\n", wantErr: true},
+		{name: "csv with empty column name", file: "users.csv", content: "id,\n1,2\n", wantErr: true},
+		{
+			name:    "json objects",
+			file:    "users.json",
+			content: `[{"id": 1, "name": "alice", "admin": true, "tags": ["a", "b"], "note": null}]`,
+			expected: []Row{
+				{"id": "1", "name": "alice", "admin": "true", "tags": `["a","b"]`, "note": ""},
+			},
+		},
+		{
+			name:    "json scalars",
+			file:    "ids.json",
+			content: `["a1", 42]`,
+			expected: []Row{
+				{ValueColumn: "a1"},
+				{ValueColumn: "42"},
+			},
+		},
+		{name: "json object instead of array", file: "users.json", content: `{"id": 1}`, wantErr: true},
+		{name: "unsupported extension", file: "users.txt", content: "id\n1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Load(writeDataset(t, tt.file, tt.content))
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, se.ErrInvalidDataset)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rows)
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.csv"))
+
+	assert.ErrorIs(t, err, se.ErrInvalidDataset)
+}
+
+func TestVariables(t *testing.T) {
+	row := Row{"id": "1"}
+
+	vars := Variables(row, 3)
+
+	assert.Equal(t, map[string]string{"id": "1", IndexVariable: "3"}, vars)
+	assert.NotContains(t, row, IndexVariable)
+}
+
+func TestIterationName(t *testing.T) {
+	assert.Equal(t, "Get User[2]", IterationName("Get User", 2))
+	assert.Equal(t, "[0]", IterationName("", 0))
+}
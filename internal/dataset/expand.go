@@ -0,0 +1,273 @@
+package dataset
+
+import (
+	"regexp"
+
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// variablePattern matches ${name} references
+var variablePattern = regexp.MustCompile(`\${([^}]+)}`)
+
+// Resolve replaces ${name} references with row variables.
+// References to other names are left untouched so they can be resolved later,
+// e.g. by variables extracted in a chain.
+//
+// Parameters:
+//   - input: String containing variable references
+//   - vars: Row variables
+//
+// Returns:
+//   - string: String with row variables substituted
+func Resolve(input string, vars map[string]string) string {
+	if input == "" || len(vars) == 0 {
+		return input
+	}
+
+	return variablePattern.ReplaceAllStringFunc(input, func(match string) string {
+		if value, ok := vars[match[2:len(match)-1]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// ApplyToRequest returns a copy of a request with row variables substituted
+// in its path, query, headers, body and effective authentication settings.
+// The original request is left untouched.
+//
+// Parameters:
+//   - config: Configuration providing config-level authentication
+//   - req: Request to substitute variables in
+//   - vars: Row variables, nil for none
+//
+// Returns:
+//   - rule.Request: Request with row variables substituted
+func ApplyToRequest(config *rule.Config, req rule.Request, vars map[string]string) rule.Request {
+	if len(vars) == 0 {
+		return req
+	}
+
+	req.Path = Resolve(req.Path, vars)
+
+	if len(req.Headers) > 0 {
+		headers := make([]string, len(req.Headers))
+		for i, header := range req.Headers {
+			headers[i] = Resolve(header, vars)
+		}
+		req.Headers = headers
+	}
+
+	if len(req.Query) > 0 {
+		query := make(rule.QueryParams, len(req.Query))
+		for key, values := range req.Query {
+			resolved := make([]string, len(values))
+			for i, value := range values {
+				resolved[i] = Resolve(value, vars)
+			}
+			query[key] = resolved
+		}
+		req.Query = query
+	}
+
+	req.JsonBody = resolveBody(req.JsonBody, vars)
+	req.FormBody = resolveBody(req.FormBody, vars)
+	req.RawBody = resolveBody(req.RawBody, vars)
+
+	// Pin the effective auth to the request so config-level credentials can vary per row
+	if auth := config.AuthFor(&req); auth != nil {
+		resolved := *auth
+		resolved.Username = Resolve(auth.Username, vars)
+		resolved.Password = Resolve(auth.Password, vars)
+		resolved.Token = Resolve(auth.Token, vars)
+		resolved.Value = Resolve(auth.Value, vars)
+		resolved.ClientID = Resolve(auth.ClientID, vars)
+		resolved.ClientSecret = Resolve(auth.ClientSecret, vars)
+		resolved.RefreshToken = Resolve(auth.RefreshToken, vars)
+		req.Auth = &resolved
+	}
+
+	return req
+}
+
+// resolveBody substitutes row variables in a request body.
+//
+// Parameters:
+//   - body: Body content, may be nil
+//   - vars: Row variables
+//
+// Returns:
+//   - *string: New body with variables substituted, or nil if body is nil
+func resolveBody(body *string, vars map[string]string) *string {
+	if body == nil {
+		return nil
+	}
+	resolved := Resolve(*body, vars)
+	return &resolved
+}
+
+// MergeVariables combines two sets of row variables; inner takes precedence.
+//
+// Parameters:
+//   - outer: Variables of the enclosing iteration, may be nil
+//   - inner: Variables of the current iteration
+//
+// Returns:
+//   - map[string]string: Combined variables
+func MergeVariables(outer, inner map[string]string) map[string]string {
+	merged := make(map[string]string, len(outer)+len(inner))
+	for name, value := range outer {
+		merged[name] = value
+	}
+	for name, value := range inner {
+		merged[name] = value
+	}
+	return merged
+}
+
+// Expand returns a configuration in which data-driven requests are replaced by one request per row.
+// With config-level data, all requests are repeated for every row (row by row);
+// request-level data repeats a single request. Iterations are named with IterationName,
+// so each one is executed and reported separately. Without data, config is returned as is.
+//
+// Parameters:
+//   - config: Configuration to expand
+//
+// Returns:
+//   - *rule.Config: Expanded configuration
+//   - error: Any error encountered while loading a dataset
+func Expand(config *rule.Config) (*rule.Config, error) {
+	if !usesData(config) {
+		return config, nil
+	}
+
+	loader := newLoader(config)
+	expanded := *config
+	expanded.Data = ""
+
+	if config.Data == "" {
+		requests, err := expandRequests(config, nil, "", loader)
+		if err != nil {
+			return nil, err
+		}
+		expanded.Request = requests
+		return &expanded, nil
+	}
+
+	rows, err := loader.load(config.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded.Request = nil
+	for i, row := range rows {
+		requests, err := expandRequests(config, Variables(row, i), IterationName("", i), loader)
+		if err != nil {
+			return nil, err
+		}
+		expanded.Request = append(expanded.Request, requests...)
+	}
+
+	return &expanded, nil
+}
+
+// expandRequests applies one set of row variables to all requests of a configuration,
+// repeating requests that have their own dataset once per row.
+//
+// Parameters:
+//   - config: Configuration containing the requests
+//   - vars: Row variables of the whole configuration, nil for none
+//   - suffix: Suffix appended to request names, e.g. "[0]"
+//   - loader: Loader for request-level datasets
+//
+// Returns:
+//   - []rule.Request: Expanded requests
+//   - error: Any error encountered while loading a dataset
+func expandRequests(config *rule.Config, vars map[string]string, suffix string, loader *loader) ([]rule.Request, error) {
+	var requests []rule.Request
+
+	for _, req := range config.Request {
+		name := req.Name + suffix
+
+		if req.Data == "" {
+			iteration := ApplyToRequest(config, req, vars)
+			iteration.Name = name
+			requests = append(requests, iteration)
+			continue
+		}
+
+		rows, err := loader.load(req.Data)
+		if err != nil {
+			return nil, err
+		}
+		for i, row := range rows {
+			iteration := ApplyToRequest(config, req, MergeVariables(vars, Variables(row, i)))
+			iteration.Name = IterationName(name, i)
+			iteration.Data = ""
+			requests = append(requests, iteration)
+		}
+	}
+
+	return requests, nil
+}
+
+// loader loads datasets referenced by a configuration, reading each file only once.
+type loader struct {
+	// config resolves relative dataset paths
+	config *rule.Config
+
+	// rows caches loaded datasets by resolved path
+	rows map[string][]Row
+}
+
+// newLoader creates a dataset loader for a configuration.
+//
+// Parameters:
+//   - config: Configuration the dataset paths belong to
+//
+// Returns:
+//   - *loader: Loader with an empty cache
+func newLoader(config *rule.Config) *loader {
+	return &loader{config: config, rows: make(map[string][]Row)}
+}
+
+// load returns the rows of a dataset, reading the file on first use.
+//
+// Parameters:
+//   - path: Dataset path as written in the configuration
+//
+// Returns:
+//   - []Row: Rows of the dataset
+//   - error: Any error encountered while loading the dataset
+func (l *loader) load(path string) ([]Row, error) {
+	path = l.config.ResolvePath(path)
+	if rows, ok := l.rows[path]; ok {
+		return rows, nil
+	}
+
+	rows, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	l.rows[path] = rows
+	return rows, nil
+}
+
+// usesData reports whether the configuration or any request has a dataset.
+//
+// Parameters:
+//   - config: Configuration to check
+//
+// Returns:
+//   - bool: True if any data setting is present
+func usesData(config *rule.Config) bool {
+	if config.Data != "" {
+		return true
+	}
+	for _, req := range config.Request {
+		if req.Data != "" {
+			return true
+		}
+	}
+	return false
+}
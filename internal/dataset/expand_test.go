@@ -0,0 +1,145 @@
+package dataset
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+func stringPtrTest(s string) *string {
+	return &s
+}
+
+func TestResolve(t *testing.T) {
+	vars := map[string]string{"id": "42", IndexVariable: "0"}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/users/${id}", "/users/42"},
+		{"row ${$index} of ${id}", "row 0 of 42"},
+		{"Bearer ${token}", "Bearer ${token}"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Resolve(tt.input, vars))
+	}
+	assert.Equal(t, "/users/${id}", Resolve("/users/${id}", nil))
+}
+
+func TestApplyToRequest(t *testing.T) {
+	config := &rule.Config{
+		Auth: &rule.Auth{Type: rule.AuthTypeBasic, Username: "${user}", Password: "${password}"},
+	}
+	original := rule.Request{
+		Name:     "Update User",
+		Method:   "PUT",
+		Path:     "/users/${id}",
+		Query:    rule.QueryParams{"trace": {"${$index}"}},
+		Headers:  []string{"X-User: ${id}", "Authorization: Bearer ${token}"},
+		JsonBody: stringPtrTest(`{"name": "${name}"}`),
+	}
+	vars := map[string]string{"id": "7", "name": "alice", "user": "admin", "password": "secret", IndexVariable: "1"}
+
+	applied := ApplyToRequest(config, original, vars)
+
+	assert.Equal(t, "/users/7", applied.Path)
+	assert.Equal(t, []string{"1"}, applied.Query["trace"])
+	assert.Equal(t, []string{"X-User: 7", "Authorization: Bearer ${token}"}, applied.Headers)
+	assert.Equal(t, `{"name": "alice"}`, *applied.JsonBody)
+	require.NotNil(t, applied.Auth)
+	assert.Equal(t, "admin", applied.Auth.Username)
+	assert.Equal(t, "secret", applied.Auth.Password)
+
+	// The original request and config are left untouched
+	assert.Equal(t, "/users/${id}", original.Path)
+	assert.Equal(t, []string{"${$index}"}, original.Query["trace"])
+	assert.Equal(t, "X-User: ${id}", original.Headers[0])
+	assert.Equal(t, `{"name": "${name}"}`, *original.JsonBody)
+	assert.Nil(t, original.Auth)
+	assert.Equal(t, "${user}", config.Auth.Username)
+}
+
+func TestExpand_WithoutData(t *testing.T) {
+	config := &rule.Config{Request: []rule.Request{{Name: "a", Path: "/a"}}}
+
+	expanded, err := Expand(config)
+
+	require.NoError(t, err)
+	assert.Same(t, config, expanded)
+}
+
+func TestExpand_RequestData(t *testing.T) {
+	path := writeDataset(t, "users.csv", "id\n1\n2\n")
+	config := &rule.Config{
+		Request: []rule.Request{
+			{Name: "list", Path: "/users"},
+			{Name: "get", Path: "/users/${id}", Data: path},
+		},
+	}
+
+	expanded, err := Expand(config)
+
+	require.NoError(t, err)
+	require.Len(t, expanded.Request, 3)
+	assert.Equal(t, "list", expanded.Request[0].Name)
+	assert.Equal(t, "get[0]", expanded.Request[1].Name)
+	assert.Equal(t, "/users/1", expanded.Request[1].Path)
+	assert.Equal(t, "get[1]", expanded.Request[2].Name)
+	assert.Equal(t, "/users/2", expanded.Request[2].Path)
+	assert.Empty(t, expanded.Request[2].Data)
+
+	// The original configuration is left untouched
+	assert.Len(t, config.Request, 2)
+}
+
+func TestExpand_ConfigData(t *testing.T) {
+	dir := t.TempDir()
+	writeDatasetAt(t, filepath.Join(dir, "tenants.json"), `[{"tenant": "acme"}, {"tenant": "globex"}]`)
+	writeDatasetAt(t, filepath.Join(dir, "ids.json"), `[10, 20]`)
+
+	config, err := rule.LoadConfig(writeDatasetAt(t, filepath.Join(dir, "config.toml"), `
+base_url = "http://example.com"
+data = "tenants.json"
+
+[[request]]
+name = "info"
+method = "GET"
+path = "/${tenant}/info"
+
+[[request]]
+name = "item"
+method = "GET"
+path = "/${tenant}/items/${value}?row=${$index}"
+data = "ids.json"
+`))
+	require.NoError(t, err)
+
+	expanded, err := Expand(config)
+
+	require.NoError(t, err)
+	var names, paths []string
+	for _, req := range expanded.Request {
+		names = append(names, req.Name)
+		paths = append(paths, req.Path)
+	}
+	assert.Equal(t, []string{"info[0]", "item[0][0]", "item[0][1]", "info[1]", "item[1][0]", "item[1][1]"}, names)
+	assert.Equal(t, []string{
+		"/acme/info", "/acme/items/10?row=0", "/acme/items/20?row=1",
+		"/globex/info", "/globex/items/10?row=0", "/globex/items/20?row=1",
+	}, paths)
+	assert.Empty(t, expanded.Data)
+}
+
+func TestExpand_MissingDataset(t *testing.T) {
+	config := &rule.Config{Data: filepath.Join(t.TempDir(), "missing.csv")}
+
+	_, err := Expand(config)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+// Package diff compares the responses of the same requests sent to two environments.
+package diff
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of a difference between two JSON values.
+type Kind int
+
+// Kinds of differences between JSON values.
+const (
+	// KindChanged means the value differs between both sides
+	KindChanged Kind = iota
+
+	// KindOnlyA means the value only exists on side A
+	KindOnlyA
+
+	// KindOnlyB means the value only exists on side B
+	KindOnlyB
+)
+
+// Difference is a value that differs between two JSON documents.
+type Difference struct {
+	// Path is the dot-separated path of the value, empty for the root
+	Path string
+
+	// Kind tells whether the value changed or exists on one side only
+	Kind Kind
+
+	// A is the value on side A, nil for KindOnlyB
+	A interface{}
+
+	// B is the value on side B, nil for KindOnlyA
+	B interface{}
+}
+
+// CompareJSON compares two decoded JSON values structurally.
+// Object keys are compared regardless of their order; array elements by index.
+//
+// Parameters:
+//   - a: Value of side A, decoded with json.Number for numbers
+//   - b: Value of side B, decoded with json.Number for numbers
+//
+// Returns:
+//   - []Difference: Differences in document order, object keys sorted; empty if the values are equal
+func CompareJSON(a, b interface{}) []Difference {
+	var differences []Difference
+	compareValue(a, b, "", &differences)
+	return differences
+}
+
+// compareValue appends the differences between two values at a path.
+//
+// Parameters:
+//   - a: Value of side A
+//   - b: Value of side B
+//   - path: Path of the values
+//   - differences: Differences found so far
+func compareValue(a, b interface{}, path string, differences *[]Difference) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			compareObject(av, bv, path, differences)
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			compareArray(av, bv, path, differences)
+			return
+		}
+	default:
+		if equalScalar(a, b) {
+			return
+		}
+	}
+	*differences = append(*differences, Difference{Path: path, Kind: KindChanged, A: a, B: b})
+}
+
+// compareObject appends the differences between two objects.
+//
+// Parameters:
+//   - a: Object of side A
+//   - b: Object of side B
+//   - path: Path of the objects
+//   - differences: Differences found so far
+func compareObject(a, b map[string]interface{}, path string, differences *[]Difference) {
+	keys := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		childPath := joinPath(path, strings.ReplaceAll(key, ".", `\.`))
+		av, inA := a[key]
+		bv, inB := b[key]
+		switch {
+		case !inB:
+			*differences = append(*differences, Difference{Path: childPath, Kind: KindOnlyA, A: av})
+		case !inA:
+			*differences = append(*differences, Difference{Path: childPath, Kind: KindOnlyB, B: bv})
+		default:
+			compareValue(av, bv, childPath, differences)
+		}
+	}
+}
+
+// compareArray appends the differences between two arrays.
+//
+// Parameters:
+//   - a: Array of side A
+//   - b: Array of side B
+//   - path: Path of the arrays
+//   - differences: Differences found so far
+func compareArray(a, b []interface{}, path string, differences *[]Difference) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		childPath := joinPath(path, strconv.Itoa(i))
+		switch {
+		case i >= len(b):
+			*differences = append(*differences, Difference{Path: childPath, Kind: KindOnlyA, A: a[i]})
+		case i >= len(a):
+			*differences = append(*differences, Difference{Path: childPath, Kind: KindOnlyB, B: b[i]})
+		default:
+			compareValue(a[i], b[i], childPath, differences)
+		}
+	}
+}
+
+// equalScalar reports whether two scalar JSON values are equal.
+// Numbers are equal if they have the same value, e.g. 1 and 1.0.
+//
+// Parameters:
+//   - a: Value of side A
+//   - b: Value of side B
+//
+// Returns:
+//   - bool: True if the values are equal
+func equalScalar(a, b interface{}) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if av == bv {
+			return true
+		}
+		af, errA := av.Float64()
+		bf, errB := bv.Float64()
+		return errA == nil && errB == nil && af == bf
+	case string, bool, nil:
+		switch b.(type) {
+		case string, bool, nil:
+			return a == b
+		}
+	}
+	return false
+}
+
+// joinPath appends a component to a path.
+//
+// Parameters:
+//   - path: Parent path, empty for the root
+//   - component: Object key or array index
+//
+// Returns:
+//   - string: Joined path
+func joinPath(path, component string) string {
+	if path == "" {
+		return component
+	}
+	return path + "." + component
+}
@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/snapshot"
+)
+
+func decode(t *testing.T, body string) interface{} {
+	t.Helper()
+	value, ok := snapshot.DecodeJSON([]byte(body))
+	require.True(t, ok, "invalid JSON: %s", body)
+	return value
+}
+
+func TestCompareJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []Difference
+	}{
+		{name: "equal with different key order", a: `{"a":1,"b":[true,null]}`, b: `{"b":[true,null],"a":1}`},
+		{name: "equal numbers", a: `{"n":1}`, b: `{"n":1.0}`},
+		{
+			name: "changed value",
+			a:    `{"user":{"name":"alice"}}`,
+			b:    `{"user":{"name":"bob"}}`,
+			want: []Difference{{Path: "user.name", Kind: KindChanged, A: "alice", B: "bob"}},
+		},
+		{
+			name: "changed type",
+			a:    `{"id":1}`,
+			b:    `{"id":"1"}`,
+			want: []Difference{{Path: "id", Kind: KindChanged, A: json.Number("1"), B: "1"}},
+		},
+		{
+			name: "object replaced by array",
+			a:    `{"data":{}}`,
+			b:    `{"data":[]}`,
+			want: []Difference{{Path: "data", Kind: KindChanged, A: map[string]interface{}{}, B: []interface{}{}}},
+		},
+		{
+			name: "keys on one side",
+			a:    `{"a":1,"old":true}`,
+			b:    `{"a":1,"new":false}`,
+			want: []Difference{
+				{Path: "new", Kind: KindOnlyB, B: false},
+				{Path: "old", Kind: KindOnlyA, A: true},
+			},
+		},
+		{
+			name: "array lengths",
+			a:    `[1,2,3]`,
+			b:    `[1,5]`,
+			want: []Difference{
+				{Path: "1", Kind: KindChanged, A: json.Number("2"), B: json.Number("5")},
+				{Path: "2", Kind: KindOnlyA, A: json.Number("3")},
+			},
+		},
+		{
+			name: "dotted key",
+			a:    `{"a.b":1}`,
+			b:    `{"a.b":2}`,
+			want: []Difference{{Path: `a\.b`, Kind: KindChanged, A: json.Number("1"), B: json.Number("2")}},
+		},
+		{
+			name: "root scalar",
+			a:    `"x"`,
+			b:    `"y"`,
+			want: []Difference{{Path: "", Kind: KindChanged, A: "x", B: "y"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareJSON(decode(t, tt.a), decode(t, tt.b)))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package diff
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ymatsukawa/jak/internal/snapshot"
+)
+
+// DefaultHeaders lists the response headers compared when none are configured.
+var DefaultHeaders = []string{"Content-Type"}
+
+// Response is the outcome of a request in one environment.
+type Response struct {
+	// Env is the name of the environment
+	Env string
+
+	// StatusCode is the status code, 0 if the request failed
+	StatusCode int
+
+	// Header contains the response headers
+	Header http.Header
+
+	// Body is the response body
+	Body []byte
+
+	// Err is the error of a failed request
+	Err error
+}
+
+// Options controls which parts of the responses are compared.
+type Options struct {
+	// Headers lists the headers compared
+	Headers []string
+
+	// Ignore lists JSON paths left out of body comparisons
+	Ignore []string
+}
+
+// HeaderDifference is a header whose values differ between both responses.
+type HeaderDifference struct {
+	// Name is the canonical header name
+	Name string
+
+	// A is the value on side A, empty if the header is missing
+	A string
+
+	// B is the value on side B, empty if the header is missing
+	B string
+}
+
+// Result holds the differences between the responses of a request in two environments.
+type Result struct {
+	// A is the response of side A
+	A Response
+
+	// B is the response of side B
+	B Response
+
+	// Headers are the compared headers that differ
+	Headers []HeaderDifference
+
+	// Body are the differences of JSON bodies
+	Body []Difference
+
+	// TextDiff is the unified diff of bodies that are not both JSON, empty if they are equal
+	TextDiff string
+}
+
+// Failed reports whether the request failed in either environment.
+//
+// Returns:
+//   - bool: True if either response has an error
+func (r *Result) Failed() bool {
+	return r.A.Err != nil || r.B.Err != nil
+}
+
+// StatusDiffers reports whether the status codes differ.
+//
+// Returns:
+//   - bool: True if both requests succeeded with different status codes
+func (r *Result) StatusDiffers() bool {
+	return !r.Failed() && r.A.StatusCode != r.B.StatusCode
+}
+
+// Identical reports whether both responses match.
+//
+// Returns:
+//   - bool: True if no request failed and status, headers and body are equal
+func (r *Result) Identical() bool {
+	return !r.Failed() && !r.StatusDiffers() && len(r.Headers) == 0 && len(r.Body) == 0 && r.TextDiff == ""
+}
+
+// Compare compares the responses of a request in two environments.
+// JSON bodies are compared structurally with the ignore paths masked;
+// other bodies are compared as text.
+//
+// Parameters:
+//   - a: Response of side A
+//   - b: Response of side B
+//   - opts: Compared headers and ignored paths
+//
+// Returns:
+//   - *Result: Differences between the responses
+func Compare(a, b Response, opts Options) *Result {
+	result := &Result{A: a, B: b}
+	if result.Failed() {
+		return result
+	}
+
+	for _, name := range opts.Headers {
+		name = http.CanonicalHeaderKey(name)
+		av := strings.Join(a.Header.Values(name), ", ")
+		bv := strings.Join(b.Header.Values(name), ", ")
+		if av != bv {
+			result.Headers = append(result.Headers, HeaderDifference{Name: name, A: av, B: bv})
+		}
+	}
+
+	av, aJSON := snapshot.DecodeJSON(a.Body)
+	bv, bJSON := snapshot.DecodeJSON(b.Body)
+	if aJSON && bJSON {
+		result.Body = CompareJSON(snapshot.Mask(av, opts.Ignore), snapshot.Mask(bv, opts.Ignore))
+		return result
+	}
+
+	result.TextDiff = snapshot.Unified(
+		string(snapshot.Normalize(a.Body, opts.Ignore)),
+		string(snapshot.Normalize(b.Body, opts.Ignore)),
+		a.Env, b.Env)
+	return result
+}
@@ -0,0 +1,73 @@
+package diff
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonResponse(env string, status int, body string) Response {
+	return Response{
+		Env:        env,
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(body),
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	result := Compare(
+		jsonResponse("legacy", 200, `{"id":1,"name":"alice"}`),
+		jsonResponse("new", 200, `{"name":"alice","id":1}`),
+		Options{Headers: DefaultHeaders},
+	)
+
+	assert.True(t, result.Identical())
+	assert.False(t, result.StatusDiffers())
+}
+
+func TestCompare_Differences(t *testing.T) {
+	a := jsonResponse("legacy", 200, `{"id":1,"name":"alice","generated_at":"2024-01-01"}`)
+	b := jsonResponse("new", 201, `{"id":1,"name":"Alice","generated_at":"2024-02-02"}`)
+	b.Header.Set("Content-Type", "application/json; charset=utf-8")
+	b.Header.Set("Cache-Control", "no-store")
+
+	result := Compare(a, b, Options{Headers: []string{"content-type", "cache-control"}, Ignore: []string{"generated_at"}})
+
+	assert.False(t, result.Identical())
+	assert.True(t, result.StatusDiffers())
+	assert.Equal(t, []HeaderDifference{
+		{Name: "Content-Type", A: "application/json", B: "application/json; charset=utf-8"},
+		{Name: "Cache-Control", A: "", B: "no-store"},
+	}, result.Headers)
+	assert.Equal(t, []Difference{{Path: "name", Kind: KindChanged, A: "alice", B: "Alice"}}, result.Body)
+	assert.Empty(t, result.TextDiff)
+}
+
+func TestCompare_Text(t *testing.T) {
+	a := Response{Env: "legacy", StatusCode: 200, Body: []byte("ok\n")}
+	b := Response{Env: "new", StatusCode: 200, Body: []byte("OK\n")}
+
+	result := Compare(a, b, Options{})
+
+	assert.False(t, result.Identical())
+	assert.Equal(t, "--- legacy\n+++ new\n@@ -1 +1 @@\n-ok\n+OK\n", result.TextDiff)
+
+	// A JSON body compared with a text body is a text difference
+	result = Compare(jsonResponse("legacy", 200, `{}`), Response{Env: "new", StatusCode: 200, Body: []byte("<html>")}, Options{})
+	assert.NotEmpty(t, result.TextDiff)
+}
+
+func TestCompare_Failed(t *testing.T) {
+	a := jsonResponse("legacy", 200, `{}`)
+	b := Response{Env: "new", Err: errors.New("connection refused")}
+
+	result := Compare(a, b, Options{Headers: DefaultHeaders})
+
+	assert.True(t, result.Failed())
+	assert.False(t, result.StatusDiffers())
+	assert.False(t, result.Identical())
+	assert.Empty(t, result.Headers)
+}
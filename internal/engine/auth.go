@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// authOptions converts authentication settings into request options.
+// API keys placed in the query string are handled by authQuery instead.
+//
+// Parameters:
+//   - auth: Effective authentication settings (may be nil)
+//
+// Returns:
+//   - []http.RequestOption: Options applying the authentication to a request
+func (factory *DefaultFactory) authOptions(auth *rule.Auth) []http.RequestOption {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Type {
+	case rule.AuthTypeBasic:
+		return []http.RequestOption{http.WithBasicAuth(auth.Username, auth.Password)}
+	case rule.AuthTypeBearer:
+		return []http.RequestOption{http.WithBearerToken(auth.Token)}
+	case rule.AuthTypeDigest:
+		return []http.RequestOption{http.WithDigestAuth(auth.Username, auth.Password)}
+	case rule.AuthTypeAPIKey:
+		if auth.In != rule.APIKeyInQuery {
+			return []http.RequestOption{http.WithHeaderValue(auth.Name, auth.Value)}
+		}
+	case rule.AuthTypeOAuth2:
+		return []http.RequestOption{http.WithTokenSource(factory.tokenSource(auth))}
+	}
+	return nil
+}
+
+// tokenSource returns the shared OAuth2 token source for the given settings.
+// Requests with identical OAuth2 settings share one source, so a token is
+// fetched once and reused until it expires.
+//
+// Parameters:
+//   - auth: OAuth2 authentication settings
+//
+// Returns:
+//   - http.TokenSource: Token source for the settings
+func (factory *DefaultFactory) tokenSource(auth *rule.Auth) http.TokenSource {
+	key := strings.Join([]string{
+		auth.TokenURL, auth.GrantType, auth.ClientID, auth.ClientSecret,
+		auth.Username, auth.Password, auth.RefreshToken, strings.Join(auth.Scopes, " "), auth.TokenCache,
+	}, "\x00")
+
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	if source, ok := factory.tokenSources[key]; ok {
+		return source
+	}
+
+	source := http.NewOAuth2TokenSource(http.OAuth2Config{
+		TokenURL:     auth.TokenURL,
+		ClientID:     auth.ClientID,
+		ClientSecret: auth.ClientSecret,
+		GrantType:    auth.GrantType,
+		Username:     auth.Username,
+		Password:     auth.Password,
+		RefreshToken: auth.RefreshToken,
+		Scopes:       auth.Scopes,
+		CacheFile:    auth.TokenCache,
+	})
+	factory.tokenSources[key] = source
+	return source
+}
+
+// authQuery returns the query parameters of a request with an API key added
+// when the authentication places it in the query string.
+// The original query map is never modified.
+//
+// Parameters:
+//   - auth: Effective authentication settings (may be nil)
+//   - query: Query parameters of the request
+//
+// Returns:
+//   - rule.QueryParams: Query parameters including the API key if applicable
+func authQuery(auth *rule.Auth, query rule.QueryParams) rule.QueryParams {
+	if auth == nil || auth.Type != rule.AuthTypeAPIKey || auth.In != rule.APIKeyInQuery {
+		return query
+	}
+
+	merged := make(rule.QueryParams, len(query)+1)
+	for key, values := range query {
+		merged[key] = values
+	}
+	merged[auth.Name] = append(merged[auth.Name], auth.Value)
+	return merged
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// NewClientFromConfig creates an HTTP client with the transport settings of the configuration.
+// Additional options, such as a cookie jar, are applied after the configured settings.
+//
+// Parameters:
+//   - config: Configuration containing transport settings such as TLS and proxy
+//   - extra: Additional client options
+//
+// Returns:
+//   - http.Client: Configured client ready to execute requests
+//   - error: Any error encountered while applying the settings
+func NewClientFromConfig(config *rule.Config, extra ...http.ClientOption) (http.Client, error) {
+	var opts []http.ClientOption
+
+	if config.TLS != nil {
+		tlsConfig, err := http.BuildTLSConfig(TLSOptions(config.TLS))
+		if err != nil {
+			return nil, se.WrapError(err, "invalid tls settings")
+		}
+		opts = append(opts, http.WithTLSConfig(tlsConfig))
+	}
+
+	if config.Proxy != "" || config.NoProxy != "" {
+		opts = append(opts, http.WithProxy(http.ProxyOptions{
+			URL:     config.Proxy,
+			NoProxy: config.NoProxy,
+		}))
+	}
+
+	opts = append(opts, extra...)
+
+	return http.NewClient(opts...), nil
+}
+
+// TLSOptions converts TLS configuration into client TLS options.
+//
+// Parameters:
+//   - tls: TLS configuration
+//
+// Returns:
+//   - http.TLSOptions: Equivalent client TLS options
+func TLSOptions(tls *rule.TLS) http.TLSOptions {
+	return http.TLSOptions{
+		CACert:             tls.CACert,
+		ClientCert:         tls.ClientCert,
+		ClientKey:          tls.ClientKey,
+		InsecureSkipVerify: tls.InsecureSkipVerify,
+		ServerName:         tls.ServerName,
+		MinVersion:         tls.MinVersion,
+		MaxVersion:         tls.MaxVersion,
+		CipherSuites:       tls.CipherSuites,
+	}
+}
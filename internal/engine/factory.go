@@ -3,6 +3,7 @@ package engine
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/ymatsukawa/jak/internal/http"
 	"github.com/ymatsukawa/jak/internal/rule"
@@ -44,6 +45,15 @@ type Factory interface {
 type DefaultFactory struct {
 	// builder helps construct HTTP request options
 	builder http.RequestBuilder
+
+	// tokenSources caches OAuth2 token sources shared between requests
+	tokenSources map[string]http.TokenSource
+
+	// credentials caches AWS credentials by profile
+	credentials map[string]http.AWSCredentials
+
+	// mu guards tokenSources and credentials for concurrent request creation
+	mu sync.Mutex
 }
 
 // NewFactory creates a new request factory with default components.
@@ -53,7 +63,9 @@ type DefaultFactory struct {
 //   - Factory: Initialized factory ready to create requests
 func NewFactory() Factory {
 	return &DefaultFactory{
-		builder: http.NewRequestBuilder(),
+		builder:      http.NewRequestBuilder(),
+		tokenSources: make(map[string]http.TokenSource),
+		credentials:  make(map[string]http.AWSCredentials),
 	}
 }
 
@@ -80,7 +92,8 @@ func (factory *DefaultFactory) CreateSimple(url, method, header, body string) (*
 }
 
 // CreateFromConfig creates a request from Config and Request objects.
-// It validates the configuration, builds options using the request builder, and creates the request.
+// It validates the configuration, builds options using the request builder, applies
+// authentication, signing, proxy and redirect settings, and creates the request.
 //
 // Parameters:
 //   - config: Base configuration containing global settings like base URL
@@ -97,11 +110,49 @@ func (factory *DefaultFactory) CreateFromConfig(config *rule.Config, request *ru
 		return nil, fmt.Errorf("%w: %s", se.ErrConfigValidation, err)
 	}
 
-	url := config.BaseUrl + request.Path
+	auth := config.AuthFor(request)
+
+	url, err := http.BuildURL(config.BaseUrl, request.Path, authQuery(auth, request.Query))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", se.ErrInvalidURL, err)
+	}
 	method := strings.ToUpper(request.Method)
 
 	options := factory.builder.BuildFromConfig(
 		method, request.Headers, request.JsonBody, request.FormBody, request.RawBody)
+	options = append(options, factory.authOptions(auth)...)
+
+	signer, err := factory.signer(config.SigningFor(request))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", se.ErrRequestPreparation, err)
+	}
+	if signer != nil {
+		options = append(options, http.WithSigner(signer))
+	}
+	if request.Proxy != "" {
+		options = append(options, http.WithRequestProxy(request.Proxy))
+	}
+	if redirect := config.RedirectFor(request); redirect != nil {
+		options = append(options, http.WithRequestRedirectPolicy(redirectPolicy(redirect)))
+	}
 
 	return http.NewRequest(url, method, options...), nil
 }
+
+// RequestURL returns the full URL a configured request is sent to.
+// It is used for reporting; if the URL cannot be built, the base URL and
+// path are concatenated as a best-effort fallback.
+//
+// Parameters:
+//   - config: Base configuration containing the base URL
+//   - request: Request configuration with path and query parameters
+//
+// Returns:
+//   - string: Full request URL
+func RequestURL(config *rule.Config, request *rule.Request) string {
+	url, err := http.BuildURL(config.BaseUrl, request.Path, request.Query)
+	if err != nil {
+		return config.BaseUrl + request.Path
+	}
+	return url
+}
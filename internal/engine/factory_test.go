@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/ymatsukawa/jak/internal/http"
 	"github.com/ymatsukawa/jak/internal/rule"
 )
 
@@ -202,6 +203,37 @@ func TestCreateFromConfig(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name:   "request with query table",
+			config: baseConfig,
+			request: &rule.Request{
+				Name:   "get /search",
+				Path:   "/search",
+				Method: "GET",
+				Query:  rule.QueryParams{"tags": {"a", "b"}, "page": {"1"}},
+			},
+			expect: expect{
+				url:    url + "/search?page=1&tags=a&tags=b",
+				method: "GET",
+			},
+			expectErr: false,
+		},
+		{
+			name: "base URL with path prefix",
+			config: &rule.Config{
+				BaseUrl: url + "/api/v1/",
+			},
+			request: &rule.Request{
+				Name:   "get /users",
+				Path:   "/users",
+				Method: "GET",
+			},
+			expect: expect{
+				url:    url + "/api/v1/users",
+				method: "GET",
+			},
+			expectErr: false,
+		},
 		{
 			name:      "invalid config",
 			config:    &rule.Config{},
@@ -250,3 +282,195 @@ func TestCreateFromConfig(t *testing.T) {
 func strPtrTest(s string) *string {
 	return &s
 }
+
+func TestCreateFromConfig_Auth(t *testing.T) {
+	factory := NewFactory()
+
+	tests := []struct {
+		name         string
+		configAuth   *rule.Auth
+		requestAuth  *rule.Auth
+		expectURL    string
+		expectHeader string
+		expectValue  string
+		expectDigest bool
+	}{
+		{
+			name:         "config level bearer",
+			configAuth:   &rule.Auth{Type: rule.AuthTypeBearer, Token: "abc"},
+			expectURL:    "http://example.com/test",
+			expectHeader: "Authorization",
+			expectValue:  "Bearer abc",
+		},
+		{
+			name:         "request level basic overrides config",
+			configAuth:   &rule.Auth{Type: rule.AuthTypeBearer, Token: "abc"},
+			requestAuth:  &rule.Auth{Type: rule.AuthTypeBasic, Username: "user", Password: "pass"},
+			expectURL:    "http://example.com/test",
+			expectHeader: "Authorization",
+			expectValue:  "Basic dXNlcjpwYXNz",
+		},
+		{
+			name:         "api key in header",
+			requestAuth:  &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "X-API-Key", Value: "secret"},
+			expectURL:    "http://example.com/test",
+			expectHeader: "X-API-Key",
+			expectValue:  "secret",
+		},
+		{
+			name:        "api key in query",
+			requestAuth: &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "api_key", Value: "secret", In: rule.APIKeyInQuery},
+			expectURL:   "http://example.com/test?api_key=secret",
+		},
+		{
+			name:         "digest",
+			requestAuth:  &rule.Auth{Type: rule.AuthTypeDigest, Username: "user", Password: "pass"},
+			expectURL:    "http://example.com/test",
+			expectDigest: true,
+		},
+		{
+			name:        "request opts out of config auth",
+			configAuth:  &rule.Auth{Type: rule.AuthTypeBearer, Token: "abc"},
+			requestAuth: &rule.Auth{Type: rule.AuthTypeNone},
+			expectURL:   "http://example.com/test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := rule.Request{Name: "test", Method: "GET", Path: "/test", Auth: tt.requestAuth}
+			config := &rule.Config{
+				BaseUrl: "http://example.com",
+				Auth:    tt.configAuth,
+				Request: []rule.Request{request},
+			}
+
+			req, err := factory.CreateFromConfig(config, &request)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectURL, req.URL)
+			if tt.expectHeader != "" {
+				assert.Equal(t, tt.expectValue, req.Headers.Get(tt.expectHeader))
+			} else {
+				assert.Nil(t, req.Headers)
+			}
+			assert.Equal(t, tt.expectDigest, req.DigestAuth != nil)
+		})
+	}
+}
+
+func TestCreateFromConfig_OAuth2SharesTokenSource(t *testing.T) {
+	factory := NewFactory()
+	auth := &rule.Auth{Type: rule.AuthTypeOAuth2, TokenURL: "http://auth.example.com/token", ClientID: "jak"}
+	config := &rule.Config{
+		BaseUrl: "http://example.com",
+		Auth:    auth,
+		Request: []rule.Request{
+			{Name: "first", Method: "GET", Path: "/a"},
+			{Name: "second", Method: "GET", Path: "/b"},
+		},
+	}
+
+	first, err := factory.CreateFromConfig(config, &config.Request[0])
+	assert.NoError(t, err)
+	second, err := factory.CreateFromConfig(config, &config.Request[1])
+	assert.NoError(t, err)
+
+	assert.NotNil(t, first.TokenSource)
+	assert.Same(t, first.TokenSource, second.TokenSource)
+}
+
+func TestCreateFromConfig_Signing(t *testing.T) {
+	factory := NewFactory()
+
+	t.Run("hmac signer is attached", func(t *testing.T) {
+		request := rule.Request{Name: "test", Method: "GET", Path: "/test"}
+		config := &rule.Config{
+			BaseUrl: "http://example.com",
+			Signing: &rule.Signing{Type: rule.SigningTypeHMAC, Secret: "key"},
+			Request: []rule.Request{request},
+		}
+
+		req, err := factory.CreateFromConfig(config, &request)
+
+		assert.NoError(t, err)
+		assert.IsType(t, &http.HMACSigner{}, req.Signer)
+	})
+
+	t.Run("aws sigv4 without credentials fails", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent/credentials")
+
+		request := rule.Request{
+			Name:    "test",
+			Method:  "GET",
+			Path:    "/test",
+			Signing: &rule.Signing{Type: rule.SigningTypeAWSSigV4, Region: "us-east-1", Service: "execute-api"},
+		}
+		config := &rule.Config{BaseUrl: "http://example.com", Request: []rule.Request{request}}
+
+		_, err := factory.CreateFromConfig(config, &request)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("aws sigv4 credentials are resolved once", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "first")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+		request := rule.Request{Name: "test", Method: "GET", Path: "/test"}
+		config := &rule.Config{
+			BaseUrl: "http://example.com",
+			Signing: &rule.Signing{Type: rule.SigningTypeAWSSigV4, Region: "us-east-1", Service: "execute-api"},
+			Request: []rule.Request{request},
+		}
+		factory := NewFactory()
+
+		first, err := factory.CreateFromConfig(config, &request)
+		assert.NoError(t, err)
+		t.Setenv("AWS_ACCESS_KEY_ID", "second")
+		second, err := factory.CreateFromConfig(config, &request)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "first", first.Signer.(*http.AWSSigV4Signer).Credentials.AccessKeyID)
+		assert.Equal(t, "first", second.Signer.(*http.AWSSigV4Signer).Credentials.AccessKeyID)
+	})
+}
+
+func TestCreateFromConfig_Proxy(t *testing.T) {
+	factory := NewFactory()
+
+	request := rule.Request{Name: "test", Method: "GET", Path: "/test", Proxy: "socks5://bastion:1080"}
+	config := &rule.Config{BaseUrl: "http://example.com", Request: []rule.Request{request}}
+
+	req, err := factory.CreateFromConfig(config, &request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "socks5://bastion:1080", req.Proxy)
+}
+
+func TestCreateFromConfig_Redirect(t *testing.T) {
+	factory := NewFactory()
+	follow := false
+	maxRedirects := 3
+
+	t.Run("no redirect settings keep the client policy", func(t *testing.T) {
+		request := rule.Request{Name: "test", Method: "GET", Path: "/test"}
+		config := &rule.Config{BaseUrl: "http://example.com", Request: []rule.Request{request}}
+
+		req, err := factory.CreateFromConfig(config, &request)
+
+		assert.NoError(t, err)
+		assert.Nil(t, req.Redirect)
+	})
+
+	t.Run("config and request settings are merged", func(t *testing.T) {
+		request := rule.Request{Name: "test", Method: "GET", Path: "/test", MaxRedirects: &maxRedirects}
+		config := &rule.Config{BaseUrl: "http://example.com", FollowRedirects: &follow, Request: []rule.Request{request}}
+
+		req, err := factory.CreateFromConfig(config, &request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &http.RedirectPolicy{Follow: false, MaxRedirects: 3, KeepMethod: true}, req.Redirect)
+	})
+}
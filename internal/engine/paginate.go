@@ -0,0 +1,351 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// maxBodySize limits the size of a response body read during pagination or polling.
+const maxBodySize = 10 * 1024 * 1024 // 10MB
+
+// FetchPages sends a request with pagination settings and follows its subsequent pages.
+// The items of every page are merged into a single JSON array, which replaces the
+// body of the returned response; status code, headers and timing are those of the
+// last page. A page with a non-2xx status ends pagination and is returned as is.
+// Next links to another origin are not followed, so the credentials of the request
+// are only sent to the configured host.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - factory: Factory creating the request of each page
+//   - client: Client executing the requests
+//   - config: Configuration containing global settings
+//   - req: Request configuration with pagination settings
+//
+// Returns:
+//   - *http.Response: Response with the merged items as body
+//   - error: Any error encountered while requesting or parsing a page
+func FetchPages(
+	ctx context.Context,
+	factory Factory,
+	client http.Client,
+	config *rule.Config,
+	req *rule.Request,
+) (*http.Response, error) {
+	paginate := req.Paginate
+	items := []string{}
+	pageURL := ""
+	cursor := ""
+
+	for page := 0; ; page++ {
+		httpReq, err := factory.CreateFromConfig(config, req)
+		if err != nil {
+			return nil, err
+		}
+		if page == 0 {
+			pageURL = httpReq.URL
+		}
+		httpReq.URL = pageURL
+		httpReq.WithContext(ctx)
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return resp, nil
+		}
+
+		body, err := readBody(resp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: page %d: %s", se.ErrPagination, page+1, err)
+		}
+		pageItems, err := itemsOf(body, paginate.Items)
+		if err != nil {
+			return nil, fmt.Errorf("%w: page %d: %s", se.ErrPagination, page+1, err)
+		}
+		items = append(items, pageItems...)
+
+		next := ""
+		if page+1 < paginate.PageLimit() {
+			next, cursor, err = nextPageURL(paginate, pageURL, resp, body, cursor, page, len(pageItems))
+			if err == nil && next != "" && paginate.Type == rule.PaginateTypeLink {
+				next, err = withAuthQuery(next, config.AuthFor(req))
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: page %d: %s", se.ErrPagination, page+1, err)
+			}
+		}
+		if next == "" {
+			resp.Body = io.NopCloser(strings.NewReader("[" + strings.Join(items, ",") + "]"))
+			return resp, nil
+		}
+		pageURL = next
+	}
+}
+
+// nextPageURL determines the URL of the page following the current one.
+//
+// Parameters:
+//   - paginate: Pagination settings
+//   - pageURL: URL of the current page
+//   - resp: Response of the current page
+//   - body: Body of the current page
+//   - cursor: Cursor that selected the current page, empty for the first page
+//   - page: Zero-based index of the current page
+//   - count: Number of items in the current page
+//
+// Returns:
+//   - string: URL of the next page, or empty if the current page is the last one
+//   - string: Cursor of the next page, for cursor pagination
+//   - error: Any error encountered while building the URL
+func nextPageURL(
+	paginate *rule.Paginate,
+	pageURL string,
+	resp *http.Response,
+	body []byte,
+	cursor string,
+	page int,
+	count int,
+) (string, string, error) {
+	switch paginate.Type {
+	case rule.PaginateTypeLink:
+		link := nextLink(resp.Header.Values("Link"))
+		if link == "" {
+			return "", "", nil
+		}
+		next, err := resolveURL(pageURL, link)
+		if err != nil {
+			return "", "", err
+		}
+		return next, "", checkOrigin(next, pageURL)
+
+	case rule.PaginateTypeCursor:
+		result := gjson.GetBytes(body, paginate.CursorPath)
+		next := result.String()
+		// A missing, empty or repeated cursor means there are no more pages
+		if !result.Exists() || result.Type == gjson.Null || next == "" || next == cursor {
+			return "", "", nil
+		}
+		nextURL, err := setQueryParam(pageURL, paginate.Param(), next)
+		return nextURL, next, err
+
+	case rule.PaginateTypePage:
+		if count == 0 {
+			return "", "", nil
+		}
+		nextURL, err := setQueryParam(pageURL, paginate.Param(), strconv.Itoa(paginate.FirstPage()+page+1))
+		return nextURL, "", err
+
+	default:
+		return "", "", fmt.Errorf("unsupported paginate type: %s", paginate.Type)
+	}
+}
+
+// readBody reads a response body for pagination or polling.
+//
+// Parameters:
+//   - resp: Response to read
+//
+// Returns:
+//   - []byte: Response body
+//   - error: Any error encountered while reading the body
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil {
+		return nil, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", se.ErrResponseReadFailed, err)
+	}
+	if len(body) > maxBodySize {
+		return nil, se.ErrResponseTooLarge
+	}
+	return body, nil
+}
+
+// itemsOf returns the raw JSON items of the array found in a page.
+// A missing array counts as an empty page.
+//
+// Parameters:
+//   - body: Page body
+//   - path: gjson path to the array, empty if the body itself is the array
+//
+// Returns:
+//   - []string: Raw JSON of each item
+//   - error: Error if the value at path is not an array
+func itemsOf(body []byte, path string) ([]string, error) {
+	result := gjson.ParseBytes(body)
+	if path != "" {
+		result = gjson.GetBytes(body, path)
+	}
+	if !result.Exists() {
+		return nil, nil
+	}
+	if !result.IsArray() {
+		return nil, fmt.Errorf("items at '%s' is not a JSON array", path)
+	}
+
+	var items []string
+	for _, item := range result.Array() {
+		items = append(items, item.Raw)
+	}
+	return items, nil
+}
+
+// nextLink returns the target of the rel="next" link in Link header values (RFC 8288).
+//
+// Parameters:
+//   - values: Values of the Link response header
+//
+// Returns:
+//   - string: URL reference of the next page, or empty if there is none
+func nextLink(values []string) string {
+	for _, value := range values {
+		for value != "" {
+			start := strings.IndexByte(value, '<')
+			end := strings.IndexByte(value, '>')
+			if start < 0 || end < start {
+				break
+			}
+			target := value[start+1 : end]
+
+			// Parameters run up to the next link
+			params := value[end+1:]
+			value = ""
+			if next := strings.IndexByte(params, '<'); next >= 0 {
+				params, value = params[:next], params[next:]
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, rels, ok := strings.Cut(param, "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(rels), `",`)) {
+					if strings.EqualFold(rel, "next") {
+						return target
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// resolveURL resolves a URL reference against the URL of the current page.
+//
+// Parameters:
+//   - base: URL of the current page
+//   - ref: Absolute or relative URL reference
+//
+// Returns:
+//   - string: Absolute URL
+//   - error: Any error encountered while parsing the URLs
+func resolveURL(base, ref string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid page URL '%s': %w", base, err)
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link '%s': %w", ref, err)
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+// checkOrigin rejects a next link to another origin than the current page.
+// The request carries its credentials in headers and the query string, which
+// must not be sent to a host that was not configured.
+//
+// Parameters:
+//   - rawURL: URL of the next page
+//   - pageURL: URL of the current page
+//
+// Returns:
+//   - error: Error if the origins differ or a URL cannot be parsed, nil otherwise
+func checkOrigin(rawURL, pageURL string) error {
+	next, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid next link '%s': %w", rawURL, err)
+	}
+	current, err := url.Parse(pageURL)
+	if err != nil {
+		return fmt.Errorf("invalid page URL '%s': %w", pageURL, err)
+	}
+	if next.Scheme != current.Scheme || next.Host != current.Host {
+		return fmt.Errorf("next link '%s' points to another origin than '%s://%s'", rawURL, current.Scheme, current.Host)
+	}
+	return nil
+}
+
+// withAuthQuery adds an API key sent in the query string to the URL of a next link,
+// which servers usually build without it. The key is only added when the link does
+// not carry it already.
+//
+// Parameters:
+//   - rawURL: URL of the next page
+//   - auth: Effective authentication settings of the request (may be nil)
+//
+// Returns:
+//   - string: URL of the next page including the API key
+//   - error: Any error encountered while parsing the URL
+func withAuthQuery(rawURL string, auth *rule.Auth) (string, error) {
+	params := authQuery(auth, nil)
+	if len(params) == 0 {
+		return rawURL, nil
+	}
+
+	next, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link '%s': %w", rawURL, err)
+	}
+
+	query := next.Query()
+	added := url.Values{}
+	for name, values := range params {
+		if !query.Has(name) {
+			added[name] = values
+		}
+	}
+	if len(added) == 0 {
+		return rawURL, nil
+	}
+	if next.RawQuery != "" {
+		next.RawQuery += "&"
+	}
+	next.RawQuery += added.Encode()
+	return next.String(), nil
+}
+
+// setQueryParam returns a URL with a query parameter set to a single value.
+//
+// Parameters:
+//   - rawURL: URL to modify
+//   - name: Query parameter name
+//   - value: Query parameter value
+//
+// Returns:
+//   - string: URL with the parameter set
+//   - error: Any error encountered while parsing the URL
+func setQueryParam(rawURL, name, value string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid page URL '%s': %w", rawURL, err)
+	}
+	query := parsed.Query()
+	query.Set(name, value)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// newPagedServer serves three pages of two items in every supported pagination style
+func newPagedServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var requested []string
+	server := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		requested = append(requested, r.URL.RequestURI())
+
+		page := 1
+		if value := r.URL.Query().Get("page"); value != "" {
+			page, _ = strconv.Atoi(value)
+		}
+		if cursor := r.URL.Query().Get("after"); cursor != "" {
+			page, _ = strconv.Atoi(cursor[1:])
+		}
+
+		items := "[]"
+		if page <= 3 {
+			items = fmt.Sprintf(`[{"id": %d}, {"id": %d}]`, page*2-1, page*2)
+		}
+
+		switch r.URL.Path {
+		case "/link":
+			if page < 3 {
+				w.Header().Add("Link", fmt.Sprintf(`<http://%s/link?page=%d>; rel="next", </link?page=3>; rel="last"`, r.Host, page+1))
+			}
+			fmt.Fprint(w, items)
+		case "/cursor":
+			next := "null"
+			if page < 3 {
+				next = fmt.Sprintf(`"c%d"`, page+1)
+			}
+			fmt.Fprintf(w, `{"data": %s, "meta": {"next_cursor": %s}}`, items, next)
+		case "/page":
+			fmt.Fprintf(w, `{"data": %s}`, items)
+		default:
+			w.WriteHeader(stdhttp.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requested
+}
+
+func fetchPages(t *testing.T, baseURL string, req *rule.Request) (*http.Response, error) {
+	t.Helper()
+
+	config := &rule.Config{BaseUrl: baseURL, Request: []rule.Request{*req}}
+	return FetchPages(context.Background(), NewFactory(), http.NewClient(), config, req)
+}
+
+func TestFetchPages(t *testing.T) {
+	allItems := `[{"id": 1},{"id": 2},{"id": 3},{"id": 4},{"id": 5},{"id": 6}]`
+
+	tests := []struct {
+		name      string
+		path      string
+		paginate  rule.Paginate
+		expected  string
+		requested []string
+	}{
+		{
+			name:      "link header",
+			path:      "/link",
+			paginate:  rule.Paginate{Type: rule.PaginateTypeLink},
+			expected:  allItems,
+			requested: []string{"/link", "/link?page=2", "/link?page=3"},
+		},
+		{
+			name:      "cursor",
+			path:      "/cursor?limit=2",
+			paginate:  rule.Paginate{Type: rule.PaginateTypeCursor, Items: "data", CursorPath: "meta.next_cursor", CursorParam: "after"},
+			expected:  allItems,
+			requested: []string{"/cursor?limit=2", "/cursor?after=c2&limit=2", "/cursor?after=c3&limit=2"},
+		},
+		{
+			name:      "page numbers until an empty page",
+			path:      "/page",
+			paginate:  rule.Paginate{Type: rule.PaginateTypePage, Items: "data"},
+			expected:  allItems,
+			requested: []string{"/page", "/page?page=2", "/page?page=3", "/page?page=4"},
+		},
+		{
+			name:      "max pages",
+			path:      "/link",
+			paginate:  rule.Paginate{Type: rule.PaginateTypeLink, MaxPages: 2},
+			expected:  `[{"id": 1},{"id": 2},{"id": 3},{"id": 4}]`,
+			requested: []string{"/link", "/link?page=2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requested := newPagedServer(t)
+			req := &rule.Request{Name: "list", Method: "GET", Path: tt.path, Paginate: &tt.paginate}
+
+			resp, err := fetchPages(t, server.URL, req)
+
+			require.NoError(t, err)
+			assert.Equal(t, stdhttp.StatusOK, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(body))
+			assert.Equal(t, tt.requested, *requested)
+		})
+	}
+}
+
+func TestFetchPages_LinkQueryAuth(t *testing.T) {
+	server, requested := newPagedServer(t)
+	req := &rule.Request{
+		Name:     "list",
+		Method:   "GET",
+		Path:     "/link",
+		Auth:     &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "api_key", Value: "secret", In: rule.APIKeyInQuery},
+		Paginate: &rule.Paginate{Type: rule.PaginateTypeLink},
+	}
+
+	resp, err := fetchPages(t, server.URL, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, stdhttp.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"/link?api_key=secret", "/link?page=2&api_key=secret", "/link?page=3&api_key=secret"}, *requested)
+}
+
+func TestWithAuthQuery(t *testing.T) {
+	auth := &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "api_key", Value: "secret", In: rule.APIKeyInQuery}
+	tests := []struct {
+		name     string
+		next     string
+		auth     *rule.Auth
+		expected string
+	}{
+		{"added to same origin", "http://api.test/items?page=2", auth, "http://api.test/items?page=2&api_key=secret"},
+		{"kept when present", "http://api.test/items?page=2&api_key=other", auth, "http://api.test/items?page=2&api_key=other"},
+		{"header api key", "http://api.test/items?page=2", &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "X-Key", Value: "secret"}, "http://api.test/items?page=2"},
+		{"no auth", "http://api.test/items?page=2", nil, "http://api.test/items?page=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := withAuthQuery(tt.next, tt.auth)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, next)
+		})
+	}
+}
+
+func TestFetchPages_CrossOriginLink(t *testing.T) {
+	var received []stdhttp.Header
+	other := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		received = append(received, r.Header.Clone())
+		fmt.Fprint(w, `[]`)
+	}))
+	t.Cleanup(other.Close)
+
+	server := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next"`, other.URL))
+		fmt.Fprint(w, `[{"id": 1}]`)
+	}))
+	t.Cleanup(server.Close)
+
+	req := &rule.Request{
+		Name:     "list",
+		Method:   "GET",
+		Path:     "/items",
+		Headers:  []string{"Authorization: Bearer token"},
+		Auth:     &rule.Auth{Type: rule.AuthTypeAPIKey, Name: "X-Api-Key", Value: "secret"},
+		Paginate: &rule.Paginate{Type: rule.PaginateTypeLink},
+	}
+
+	_, err := fetchPages(t, server.URL, req)
+
+	require.ErrorIs(t, err, se.ErrPagination)
+	assert.ErrorContains(t, err, "another origin")
+	assert.Empty(t, received, "no credentials are sent to the other origin")
+}
+
+func TestCheckOrigin(t *testing.T) {
+	pageURL := "http://api.test/items?page=1"
+
+	assert.NoError(t, checkOrigin("http://api.test/items?page=2", pageURL))
+	assert.Error(t, checkOrigin("http://cdn.test/items?page=2", pageURL))
+	assert.Error(t, checkOrigin("https://api.test/items?page=2", pageURL))
+	assert.Error(t, checkOrigin("http://api.test:8080/items?page=2", pageURL))
+}
+
+func TestFetchPages_ErrorStatus(t *testing.T) {
+	server, requested := newPagedServer(t)
+	req := &rule.Request{Name: "list", Method: "GET", Path: "/missing", Paginate: &rule.Paginate{Type: rule.PaginateTypePage}}
+
+	resp, err := fetchPages(t, server.URL, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, stdhttp.StatusNotFound, resp.StatusCode)
+	assert.Len(t, *requested, 1)
+}
+
+func TestFetchPages_ItemsNotArray(t *testing.T) {
+	server, _ := newPagedServer(t)
+	req := &rule.Request{Name: "list", Method: "GET", Path: "/cursor", Paginate: &rule.Paginate{Type: rule.PaginateTypeCursor, Items: "meta", CursorPath: "meta.next_cursor"}}
+
+	_, err := fetchPages(t, server.URL, req)
+
+	assert.ErrorIs(t, err, se.ErrPagination)
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected string
+	}{
+		{name: "no header", values: nil, expected: ""},
+		{name: "next only", values: []string{`<https://api.example.com/items?page=2>; rel="next"`}, expected: "https://api.example.com/items?page=2"},
+		{
+			name:     "next after other relations",
+			values:   []string{`<https://api.example.com/items?page=1>; rel="prev", <https://api.example.com/items?page=3>; rel="next"`},
+			expected: "https://api.example.com/items?page=3",
+		},
+		{name: "multiple relation types", values: []string{`</items?page=2>; title="more"; rel="next last"`}, expected: "/items?page=2"},
+		{name: "unquoted relation", values: []string{`</items?page=2>; rel=next`}, expected: "/items?page=2"},
+		{name: "separate header values", values: []string{`</items?page=9>; rel="last"`, `</items?page=2>; rel="next"`}, expected: "/items?page=2"},
+		{name: "no next relation", values: []string{`</items?page=1>; rel="first"`}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextLink(tt.values))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/ymatsukawa/jak/internal/condition"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// StatusVariable is the variable holding the status code of the response in a poll condition.
+const StatusVariable = "$status"
+
+// Poll re-issues a request until the until condition of its poll settings holds.
+// The condition is evaluated against each response: bare paths refer to fields of the
+// JSON body and ${$status} is the status code; other ${name} references are resolved
+// with variables. The first response meeting the condition is returned with its body intact.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - poll: Polling settings
+//   - variables: Resolves ${name} references in the condition, may be nil
+//   - send: Sends one attempt of the request
+//
+// Returns:
+//   - *http.Response: First response meeting the condition
+//   - error: Any error from sending or evaluating, or se.ErrPollTimeout if the condition
+//     is not met within the poll timeout
+func Poll(
+	ctx context.Context,
+	poll *rule.Poll,
+	variables condition.Lookup,
+	send func() (*http.Response, error),
+) (*http.Response, error) {
+	until, err := condition.ParseFields(poll.Until)
+	if err != nil {
+		return nil, fmt.Errorf("invalid until condition: %w", err)
+	}
+
+	deadline := time.Now().Add(poll.PollTimeout())
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := readBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		done, err := until.EvalEnv(pollEnv(resp.StatusCode, body, variables))
+		if err != nil {
+			return nil, fmt.Errorf("invalid until condition: %w", err)
+		}
+		if done {
+			return resp, nil
+		}
+
+		interval := poll.PollInterval()
+		if time.Now().Add(interval).After(deadline) {
+			return nil, fmt.Errorf("%w after %d attempts: until %q not met (last status %d)",
+				se.ErrPollTimeout, attempt, poll.Until, resp.StatusCode)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pollEnv builds the values a poll condition is evaluated against.
+//
+// Parameters:
+//   - statusCode: Status code of the response
+//   - body: Body of the response
+//   - variables: Resolves other ${name} references, may be nil
+//
+// Returns:
+//   - condition.Env: Response fields and variables
+func pollEnv(statusCode int, body []byte, variables condition.Lookup) condition.Env {
+	return condition.Env{
+		Variables: func(name string) (string, bool) {
+			if name == StatusVariable {
+				return strconv.Itoa(statusCode), true
+			}
+			if variables == nil {
+				return "", false
+			}
+			return variables(name)
+		},
+		Fields: func(path string) (string, bool) {
+			result := gjson.GetBytes(body, path)
+			return result.String(), result.Exists()
+		},
+	}
+}
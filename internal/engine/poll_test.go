@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// newJobServer returns a server whose job is running for the first doneAfter-1 polls
+func newJobServer(t *testing.T, doneAfter int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		attempt := attempts.Add(1)
+		if attempt < doneAfter {
+			w.WriteHeader(stdhttp.StatusAccepted)
+			fmt.Fprintf(w, `{"status": "running", "attempt": %d}`, attempt)
+			return
+		}
+		fmt.Fprintf(w, `{"status": "done", "attempt": %d, "result": {"id": "job-1"}}`, attempt)
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+// pollJob polls the job server with the given settings
+func pollJob(ctx context.Context, t *testing.T, server *httptest.Server, poll *rule.Poll) (*http.Response, error) {
+	t.Helper()
+
+	client := http.NewClient()
+	return Poll(ctx, poll, nil, func() (*http.Response, error) {
+		return client.Do(http.NewRequest(server.URL+"/jobs/1", "GET").WithContext(ctx))
+	})
+}
+
+func TestPoll(t *testing.T) {
+	tests := []struct {
+		name  string
+		until string
+	}{
+		{name: "body field", until: "status == 'done'"},
+		{name: "status code", until: "${$status} == 200"},
+		{name: "nested field and status", until: "result.id != null && ${$status} < 300"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, attempts := newJobServer(t, 3)
+
+			resp, err := pollJob(context.Background(), t, server, &rule.Poll{Until: tt.until, Interval: time.Millisecond})
+
+			require.NoError(t, err)
+			assert.Equal(t, stdhttp.StatusOK, resp.StatusCode)
+			assert.Equal(t, int32(3), attempts.Load())
+
+			// The final body is still readable for extraction
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), `"attempt": 3`)
+		})
+	}
+}
+
+func TestPoll_Variables(t *testing.T) {
+	server, attempts := newJobServer(t, 2)
+	variables := func(name string) (string, bool) {
+		if name == "expected" {
+			return "done", true
+		}
+		return "", false
+	}
+
+	client := http.NewClient()
+	_, err := Poll(context.Background(), &rule.Poll{Until: "status == ${expected}", Interval: time.Millisecond}, variables,
+		func() (*http.Response, error) {
+			return client.Do(http.NewRequest(server.URL, "GET"))
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestPoll_Timeout(t *testing.T) {
+	server, attempts := newJobServer(t, 100)
+
+	_, err := pollJob(context.Background(), t, server, &rule.Poll{
+		Until:    "status == 'done'",
+		Interval: 20 * time.Millisecond,
+		Timeout:  50 * time.Millisecond,
+	})
+
+	assert.ErrorIs(t, err, se.ErrPollTimeout)
+	assert.Contains(t, err.Error(), "last status 202")
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestPoll_Canceled(t *testing.T) {
+	server, _ := newJobServer(t, 100)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, err := pollJob(ctx, t, server, &rule.Poll{Until: "status == 'done'", Interval: 100 * time.Millisecond, Timeout: time.Hour})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPoll_EvaluationError(t *testing.T) {
+	server, _ := newJobServer(t, 1)
+
+	_, err := pollJob(context.Background(), t, server, &rule.Poll{Until: "status > 1"})
+
+	assert.ErrorContains(t, err, "invalid until condition")
+}
+
+func TestExecuteConfigRequest_Poll(t *testing.T) {
+	server, attempts := newJobServer(t, 2)
+	config := &rule.Config{
+		BaseUrl: server.URL,
+		Request: []rule.Request{{
+			Name:   "job",
+			Method: "GET",
+			Path:   "/jobs/1",
+			Poll:   &rule.Poll{Until: "status == 'done'", Interval: time.Millisecond},
+		}},
+	}
+
+	resp, err := NewExecutor(context.Background()).executeConfigRequest(config, &config.Request[0])
+
+	require.NoError(t, err)
+	assert.Equal(t, stdhttp.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), attempts.Load())
+}
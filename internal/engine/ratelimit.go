@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket that paces requests.
+// Tokens are added at a fixed rate up to the burst size and every request takes one.
+// A single limiter can be shared by any number of goroutines.
+type RateLimiter struct {
+	// rate is the number of tokens added per second
+	rate float64
+
+	// burst is the maximum number of tokens in the bucket
+	burst float64
+
+	// tokens is the number of available tokens; negative values are reserved by waiting callers
+	tokens float64
+
+	// last is when tokens were last added
+	last time.Time
+
+	// mu guards the bucket state
+	mu sync.Mutex
+}
+
+// NewRateLimiter creates a token bucket that starts full.
+//
+// Parameters:
+//   - rate: Requests per second
+//   - burst: Number of requests that may be sent at once; values below 1 mean 1
+//
+// Returns:
+//   - *RateLimiter: Initialized rate limiter
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+// A token is reserved before waiting, so callers are served in the order they arrive.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//
+// Returns:
+//   - error: Context error if ctx is done before a token is available
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	delay := limiter.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		limiter.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve refills the bucket and takes a token.
+//
+// Returns:
+//   - time.Duration: How long the caller has to wait until its token is available
+func (limiter *RateLimiter) reserve() time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	limiter.tokens += now.Sub(limiter.last).Seconds() * limiter.rate
+	if limiter.tokens > limiter.burst {
+		limiter.tokens = limiter.burst
+	}
+	limiter.last = now
+
+	limiter.tokens--
+	if limiter.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used.
+func (limiter *RateLimiter) cancel() {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.tokens++
+}
+
+// newRateLimiter creates the shared rate limiter for a batch run.
+//
+// Parameters:
+//   - rate: Requests per second, 0 for no limit
+//   - burst: Burst size
+//
+// Returns:
+//   - *RateLimiter: Rate limiter, or nil if rate is 0
+func newRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return NewRateLimiter(rate, burst)
+}
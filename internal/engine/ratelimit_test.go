@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := NewRateLimiter(10, 3)
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// The burst is served immediately
+	assert.Less(t, time.Since(start), 20*time.Millisecond)
+
+	// The next token takes 1/rate
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiter_SharedByGoroutines(t *testing.T) {
+	limiter := NewRateLimiter(100, 1)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	// One immediate token plus nine at 10ms each
+	assert.GreaterOrEqual(t, time.Since(start), 85*time.Millisecond)
+}
+
+func TestRateLimiter_Cancel(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Wait(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// The reserved token is returned, so the bucket is not pushed further into debt
+	assert.InDelta(t, 0, limiter.tokens, 0.1)
+}
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, newRateLimiter(0, 5))
+	assert.NotNil(t, newRateLimiter(1, 0))
+}
@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// redirectPolicy converts redirect settings into a client redirect policy.
+// Settings that are not configured keep their default values.
+//
+// Parameters:
+//   - redirect: Effective redirect settings
+//
+// Returns:
+//   - http.RedirectPolicy: Redirect policy for the request
+func redirectPolicy(redirect *rule.Redirect) http.RedirectPolicy {
+	policy := http.DefaultRedirectPolicy()
+	if redirect.Follow != nil {
+		policy.Follow = *redirect.Follow
+	}
+	if redirect.MaxRedirects != nil {
+		policy.MaxRedirects = *redirect.MaxRedirects
+	}
+	if redirect.KeepMethod != nil {
+		policy.KeepMethod = *redirect.KeepMethod
+	}
+	return policy
+}
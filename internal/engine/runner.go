@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ymatsukawa/jak/internal/dataset"
 	"github.com/ymatsukawa/jak/internal/http"
 	"github.com/ymatsukawa/jak/internal/rule"
 	"github.com/ymatsukawa/jak/internal/sys_error"
@@ -13,7 +14,7 @@ import (
 
 // DefaultMaxWorkers defines the maximum number of concurrent workers
 // used for executing batch requests in parallel. This limits resource usage
-// while still providing parallelism benefits.
+// while still providing parallelism benefits. The max_workers setting overrides it.
 const (
 	DefaultMaxWorkers = 5
 )
@@ -28,7 +29,19 @@ const (
 //   - statusCode: HTTP status code of the response (0 if request failed)
 //   - err: Error encountered during execution, or nil if successful
 //   - duration: Time taken to execute the request
-type ResultCollector func(requestName, method, url string, statusCode int, err error, duration time.Duration)
+//   - timing: Timing breakdown of the request (nil if no response was received)
+type ResultCollector func(requestName, method, url string, statusCode int, err error, duration time.Duration, timing *http.Timing)
+
+// ResponseChecker is a function type that verifies the response of a configured request.
+// It is called after each successful batch request; a returned error marks the request as failed.
+//
+// Parameters:
+//   - req: Executed request configuration
+//   - resp: HTTP response of the request
+//
+// Returns:
+//   - error: Error if the response does not pass the check
+type ResponseChecker func(req *rule.Request, resp *http.Response) error
 
 // Executor handles HTTP request execution with various modes (simple, batch, concurrent).
 // It manages the lifecycle of HTTP requests, including context management, execution, and result collection.
@@ -44,6 +57,9 @@ type Executor struct {
 
 	// resultCollector is called after each request execution to collect results
 	resultCollector ResultCollector
+
+	// responseChecker verifies the response of each batch request
+	responseChecker ResponseChecker
 }
 
 // NewExecutor creates a new executor with the given context.
@@ -97,6 +113,15 @@ func (executor *Executor) SetResultCollector(collector ResultCollector) {
 	executor.resultCollector = collector
 }
 
+// SetResponseChecker sets a function to verify the response of each batch request.
+// A check failure is reported to the result collector like a request error.
+//
+// Parameters:
+//   - checker: Function verifying responses
+func (executor *Executor) SetResponseChecker(checker ResponseChecker) {
+	executor.responseChecker = checker
+}
+
 // ExecuteSimple executes a simple HTTP request with the given parameters.
 // It creates and executes a single request, measuring execution time and collecting results.
 //
@@ -127,11 +152,8 @@ func (executor *Executor) ExecuteSimple(url, method, header, body string) (*http
 
 	// Collect result if collector is set
 	if executor.resultCollector != nil {
-		var statusCode int
-		if resp != nil {
-			statusCode = resp.StatusCode
-		}
-		executor.resultCollector("", method, url, statusCode, err, duration)
+		statusCode, timing := responseDetails(resp)
+		executor.resultCollector("", method, url, statusCode, err, duration, timing)
 	}
 
 	return resp, err
@@ -139,6 +161,8 @@ func (executor *Executor) ExecuteSimple(url, method, header, body string) (*http
 
 // ExecuteBatchSequential executes requests in sequence from the configuration.
 // It processes each request one after another, respecting the order defined in the configuration.
+// Requests are separated by the configured delay_between and paced by rate_limit.
+// Data-driven requests are executed once per dataset row.
 //
 // Parameters:
 //   - config: Configuration containing requests and global settings
@@ -146,7 +170,21 @@ func (executor *Executor) ExecuteSimple(url, method, header, body string) (*http
 // Returns:
 //   - error: Any error encountered during batch execution
 func (executor *Executor) ExecuteBatchSequential(config *rule.Config) error {
-	for _, req := range config.Request {
+	config, err := dataset.Expand(config)
+	if err != nil {
+		return sys_error.WrapError(err, "failed to load data")
+	}
+
+	limiter := newRateLimiter(config.RateLimit, config.RateBurst)
+
+	for i, req := range config.Request {
+		// Pause before every request but the first; cancellation is checked below
+		delay := config.DelayBetween
+		if i == 0 {
+			delay = 0
+		}
+		executor.pace(delay, limiter)
+
 		select {
 		case <-executor.ctx.Done():
 			if config.IgnoreFail {
@@ -164,16 +202,18 @@ func (executor *Executor) ExecuteBatchSequential(config *rule.Config) error {
 			// End timing
 			duration := time.Since(startTime)
 
+			// Verify the response
+			if err == nil {
+				err = executor.checkResponse(&req, resp)
+			}
+
 			// Get full URL
-			url := config.BaseUrl + req.Path
+			url := RequestURL(config, &req)
 
 			// Collect result if collector is set
 			if executor.resultCollector != nil {
-				var statusCode int
-				if resp != nil {
-					statusCode = resp.StatusCode
-				}
-				executor.resultCollector(req.Name, req.Method, url, statusCode, err, duration)
+				statusCode, timing := responseDetails(resp)
+				executor.resultCollector(req.Name, req.Method, url, statusCode, err, duration, timing)
 			} else if err != nil {
 				fmt.Printf("Request '%s' failed: %v\n", req.Name, err)
 				if config.IgnoreFail {
@@ -190,7 +230,9 @@ func (executor *Executor) ExecuteBatchSequential(config *rule.Config) error {
 }
 
 // ExecuteBatchConcurrent executes requests concurrently using a worker pool.
-// It limits concurrency to control resource usage while maximizing throughput.
+// It limits concurrency to max_workers (DefaultMaxWorkers if not configured)
+// and paces all workers with a single token bucket when rate_limit is set.
+// Every row of a data-driven request is a separate job for the worker pool.
 //
 // Parameters:
 //   - config: Configuration containing requests and global settings
@@ -198,8 +240,16 @@ func (executor *Executor) ExecuteBatchSequential(config *rule.Config) error {
 // Returns:
 //   - error: Any error encountered during batch execution
 func (executor *Executor) ExecuteBatchConcurrent(config *rule.Config) error {
+	config, err := dataset.Expand(config)
+	if err != nil {
+		return sys_error.WrapError(err, "failed to load data")
+	}
+
 	requestCount := len(config.Request)
 	maxWorkers := DefaultMaxWorkers
+	if config.MaxWorkers > 0 {
+		maxWorkers = config.MaxWorkers
+	}
 
 	if requestCount < maxWorkers {
 		maxWorkers = requestCount
@@ -212,11 +262,14 @@ func (executor *Executor) ExecuteBatchConcurrent(config *rule.Config) error {
 	workerCtx, cancel := context.WithCancel(executor.ctx)
 	defer cancel()
 
+	// Rate limiter shared by all workers
+	limiter := newRateLimiter(config.RateLimit, config.RateBurst)
+
 	// Start worker pool
 	var wg sync.WaitGroup
 	for w := 0; w < maxWorkers; w++ {
 		wg.Add(1)
-		go executor.worker(workerCtx, &wg, jobs, errCh, config)
+		go executor.worker(workerCtx, &wg, jobs, errCh, config, limiter)
 	}
 
 	executor.sendJobs(workerCtx, jobs, config.Request)
@@ -268,12 +321,14 @@ func (e *Executor) sendJobs(ctx context.Context, jobs chan<- rule.Request, reque
 //   - jobs: Channel to receive jobs from
 //   - errCh: Channel to send errors to
 //   - config: Configuration containing global settings
+//   - limiter: Rate limiter shared by all workers, or nil for no limit
 func (executor *Executor) worker(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	jobs <-chan rule.Request,
 	errCh chan<- error,
 	config *rule.Config,
+	limiter *RateLimiter,
 ) {
 	defer wg.Done()
 
@@ -286,6 +341,11 @@ func (executor *Executor) worker(
 				return
 			}
 
+			// Wait for a token before sending
+			if limiter != nil && limiter.Wait(ctx) != nil {
+				return
+			}
+
 			// Start timing
 			startTime := time.Now()
 
@@ -295,16 +355,18 @@ func (executor *Executor) worker(
 			// End timing
 			duration := time.Since(startTime)
 
+			// Verify the response
+			if err == nil {
+				err = executor.checkResponse(&req, resp)
+			}
+
 			// Get full URL
-			url := config.BaseUrl + req.Path
+			url := RequestURL(config, &req)
 
 			// Collect result if collector is set
 			if executor.resultCollector != nil {
-				var statusCode int
-				if resp != nil {
-					statusCode = resp.StatusCode
-				}
-				executor.resultCollector(req.Name, req.Method, url, statusCode, err, duration)
+				statusCode, timing := responseDetails(resp)
+				executor.resultCollector(req.Name, req.Method, url, statusCode, err, duration, timing)
 			}
 
 			if err != nil {
@@ -321,8 +383,62 @@ func (executor *Executor) worker(
 	}
 }
 
+// pace waits for the given delay and then for a token of the rate limiter.
+// It returns early if the executor context is done; callers check the context afterwards.
+//
+// Parameters:
+//   - delay: Time to wait, 0 for none
+//   - limiter: Rate limiter, or nil for no limit
+func (executor *Executor) pace(delay time.Duration, limiter *RateLimiter) {
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-executor.ctx.Done():
+			return
+		case <-timer.C:
+		}
+	}
+
+	if limiter != nil {
+		limiter.Wait(executor.ctx)
+	}
+}
+
+// checkResponse verifies a response with the response checker, if one is set.
+//
+// Parameters:
+//   - req: Executed request configuration
+//   - resp: HTTP response of the request
+//
+// Returns:
+//   - error: Error returned by the checker, or nil without a checker
+func (executor *Executor) checkResponse(req *rule.Request, resp *http.Response) error {
+	if executor.responseChecker == nil || resp == nil {
+		return nil
+	}
+	return executor.responseChecker(req, resp)
+}
+
+// responseDetails returns the status code and timing breakdown reported to the result collector.
+//
+// Parameters:
+//   - resp: HTTP response, may be nil if the request failed
+//
+// Returns:
+//   - int: HTTP status code, or 0 without a response
+//   - *http.Timing: Timing breakdown, or nil without a response
+func responseDetails(resp *http.Response) (int, *http.Timing) {
+	if resp == nil {
+		return 0, nil
+	}
+	return resp.StatusCode, resp.Timing
+}
+
 // executeConfigRequest creates and executes a request from configuration.
 // It handles the process of creating the request from config and executing it.
+// Requests with polling settings are re-issued until their condition holds.
 //
 // Parameters:
 //   - config: Configuration containing global settings
@@ -332,6 +448,38 @@ func (executor *Executor) worker(
 //   - *http.Response: HTTP response from the server
 //   - error: Any error encountered during execution
 func (executor *Executor) executeConfigRequest(config *rule.Config, req *rule.Request) (*http.Response, error) {
+	if req.Poll == nil {
+		return executor.sendConfigRequest(config, req)
+	}
+
+	resp, err := Poll(executor.ctx, req.Poll, nil, func() (*http.Response, error) {
+		return executor.sendConfigRequest(config, req)
+	})
+	if err != nil {
+		return nil, sys_error.WrapError(err, "polling failed")
+	}
+	return resp, nil
+}
+
+// sendConfigRequest creates and sends a request from configuration once.
+// Requests with pagination settings follow all their pages.
+//
+// Parameters:
+//   - config: Configuration containing global settings
+//   - req: Specific request configuration to execute
+//
+// Returns:
+//   - *http.Response: HTTP response from the server
+//   - error: Any error encountered during execution
+func (executor *Executor) sendConfigRequest(config *rule.Config, req *rule.Request) (*http.Response, error) {
+	if req.Paginate != nil {
+		resp, err := FetchPages(executor.ctx, executor.factory, executor.client, config, req)
+		if err != nil {
+			return nil, sys_error.WrapError(err, "request execution failed")
+		}
+		return resp, nil
+	}
+
 	httpReq, err := executor.factory.CreateFromConfig(config, req)
 	if err != nil {
 		return nil, sys_error.WrapError(err, "failed to prepare request")
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/ymatsukawa/jak/internal/http"
@@ -361,3 +363,130 @@ func TestExecuteBatchConcurrent_IgnoreFail(t *testing.T) {
 	// Assert results
 	assert.NoError(t, err)
 }
+
+// newThrottleTestConfig creates a config with the given number of identical requests
+func newThrottleTestConfig(count int) *rule.Config {
+	config := &rule.Config{BaseUrl: "http://example.com"}
+	for i := 0; i < count; i++ {
+		config.Request = append(config.Request, rule.Request{
+			Name:   fmt.Sprintf("req%d", i),
+			Method: "GET",
+			Path:   "/api",
+		})
+	}
+	return config
+}
+
+// Tests that max_workers limits the number of requests in flight
+func TestExecuteBatchConcurrent_MaxWorkers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := mock_engine.NewMockFactory(ctrl)
+	mockClient := mock_http.NewMockClient(ctrl)
+
+	config := newThrottleTestConfig(8)
+	config.MaxWorkers = 2
+
+	var inFlight, peak atomic.Int32
+	mockFactory.EXPECT().CreateFromConfig(gomock.Any(), gomock.Any()).Return(&http.Request{}, nil).Times(8)
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := peak.Load()
+			if current <= highest || peak.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return &http.Response{StatusCode: 200}, nil
+	}).Times(8)
+
+	executor := NewExecutor(context.Background()).WithFactory(mockFactory).WithClient(mockClient)
+
+	err := executor.ExecuteBatchConcurrent(config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+// Tests that rate_limit paces all workers together
+func TestExecuteBatchConcurrent_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := mock_engine.NewMockFactory(ctrl)
+	mockClient := mock_http.NewMockClient(ctrl)
+
+	config := newThrottleTestConfig(5)
+	config.MaxWorkers = 5
+	config.RateLimit = 50
+	config.RateBurst = 2
+
+	mockFactory.EXPECT().CreateFromConfig(gomock.Any(), gomock.Any()).Return(&http.Request{}, nil).Times(5)
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 200}, nil).Times(5)
+
+	executor := NewExecutor(context.Background()).WithFactory(mockFactory).WithClient(mockClient)
+	start := time.Now()
+
+	err := executor.ExecuteBatchConcurrent(config)
+
+	// Two requests from the burst, three more at 20ms each
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 55*time.Millisecond)
+}
+
+// Tests that delay_between separates sequential requests
+func TestExecuteBatchSequential_DelayBetween(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := mock_engine.NewMockFactory(ctrl)
+	mockClient := mock_http.NewMockClient(ctrl)
+
+	config := newThrottleTestConfig(3)
+	config.DelayBetween = 30 * time.Millisecond
+
+	var sent []time.Time
+	mockFactory.EXPECT().CreateFromConfig(gomock.Any(), gomock.Any()).Return(&http.Request{}, nil).Times(3)
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, time.Now())
+		return &http.Response{StatusCode: 200}, nil
+	}).Times(3)
+
+	executor := NewExecutor(context.Background()).WithFactory(mockFactory).WithClient(mockClient)
+
+	err := executor.ExecuteBatchSequential(config)
+
+	assert.NoError(t, err)
+	assert.Len(t, sent, 3)
+	for i := 1; i < len(sent); i++ {
+		assert.GreaterOrEqual(t, sent[i].Sub(sent[i-1]), 30*time.Millisecond)
+	}
+}
+
+// Tests that a canceled context interrupts the delay between sequential requests
+func TestExecuteBatchSequential_DelayBetweenCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := mock_engine.NewMockFactory(ctrl)
+	mockClient := mock_http.NewMockClient(ctrl)
+
+	config := newThrottleTestConfig(2)
+	config.DelayBetween = time.Minute
+
+	mockFactory.EXPECT().CreateFromConfig(gomock.Any(), gomock.Any()).Return(&http.Request{}, nil).Times(1)
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 200}, nil).Times(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	executor := NewExecutor(ctx).WithFactory(mockFactory).WithClient(mockClient)
+	start := time.Now()
+
+	err := executor.ExecuteBatchSequential(config)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -3,6 +3,8 @@ package engine
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -440,3 +442,101 @@ func TestContextTimeout(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, context.DeadlineExceeded, err)
 }
+
+func TestExecuteBatchSequential_RequestData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataPath := filepath.Join(t.TempDir(), "ids.csv")
+	assert.NoError(t, os.WriteFile(dataPath, []byte("id\n1\n2\n"), 0644))
+
+	mockFactory := mock_engine.NewMockFactory(ctrl)
+	mockClient := mock_http.NewMockClient(ctrl)
+
+	config := &rule.Config{
+		BaseUrl: "http://example.com",
+		Request: []rule.Request{
+			{Name: "get", Method: "GET", Path: "/items/${id}", Data: dataPath},
+		},
+	}
+
+	var paths []string
+	mockFactory.EXPECT().
+		CreateFromConfig(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(config *rule.Config, reqConfig *rule.Request) (*http.Request, error) {
+			paths = append(paths, reqConfig.Path)
+			return &http.Request{}, nil
+		}).
+		Times(2)
+	mockClient.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{StatusCode: 200}, nil).
+		Times(2)
+
+	var names []string
+	executor := NewExecutor(context.Background()).WithFactory(mockFactory).WithClient(mockClient)
+	executor.SetResultCollector(func(requestName, method, url string, statusCode int, err error, duration time.Duration, timing *http.Timing) {
+		names = append(names, requestName)
+	})
+
+	err := executor.ExecuteBatchSequential(config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/items/1", "/items/2"}, paths)
+	assert.Equal(t, []string{"get[0]", "get[1]"}, names)
+}
+
+func TestExecuteBatchSequential_MissingData(t *testing.T) {
+	config := &rule.Config{
+		BaseUrl: "http://example.com",
+		Request: []rule.Request{
+			{Name: "get", Method: "GET", Path: "/items", Data: filepath.Join(t.TempDir(), "missing.csv")},
+		},
+	}
+
+	err := NewExecutor(context.Background()).ExecuteBatchSequential(config)
+
+	assert.Error(t, err)
+}
+
+func TestExecuteBatchSequential_ResponseChecker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := mock_engine.NewMockFactory(ctrl)
+	mockClient := mock_http.NewMockClient(ctrl)
+
+	config := &rule.Config{
+		BaseUrl: "http://example.com",
+		Request: []rule.Request{
+			{Name: "req1", Method: "GET", Path: "/api1"},
+			{Name: "req2", Method: "GET", Path: "/api2"},
+		},
+	}
+
+	mockFactory.EXPECT().CreateFromConfig(gomock.Any(), gomock.Any()).Return(&http.Request{}, nil).Times(2)
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 200}, nil).Times(2)
+
+	checkErr := errors.New("unexpected body")
+	var checked []string
+	results := make(map[string]error)
+
+	executor := NewExecutor(context.Background()).WithFactory(mockFactory).WithClient(mockClient)
+	executor.SetResponseChecker(func(req *rule.Request, resp *http.Response) error {
+		checked = append(checked, req.Name)
+		if req.Name == "req2" {
+			return checkErr
+		}
+		return nil
+	})
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *http.Timing) {
+		results[name] = err
+	})
+
+	err := executor.ExecuteBatchSequential(config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"req1", "req2"}, checked)
+	assert.NoError(t, results["req1"])
+	assert.ErrorIs(t, results["req2"], checkErr)
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// signer converts signing settings into a request signer.
+//
+// Parameters:
+//   - signing: Effective signing settings (may be nil)
+//
+// Returns:
+//   - http.Signer: Signer for the settings, or nil if requests are not signed
+//   - error: Error if AWS credentials cannot be resolved or the type is unknown
+func (factory *DefaultFactory) signer(signing *rule.Signing) (http.Signer, error) {
+	if signing == nil {
+		return nil, nil
+	}
+
+	switch signing.Type {
+	case rule.SigningTypeAWSSigV4:
+		creds, err := factory.awsCredentials(signing.Profile)
+		if err != nil {
+			return nil, err
+		}
+		return &http.AWSSigV4Signer{
+			Region:      signing.Region,
+			Service:     signing.Service,
+			Credentials: creds,
+		}, nil
+	case rule.SigningTypeHMAC:
+		return &http.HMACSigner{
+			Secret:          signing.Secret,
+			Algorithm:       signing.Algorithm,
+			Encoding:        signing.Encoding,
+			SignatureHeader: signing.Header,
+			TimestampHeader: signing.TimestampHeader,
+			Prefix:          signing.Prefix,
+			Template:        signing.Template,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing type: %s", signing.Type)
+	}
+}
+
+// awsCredentials returns the AWS credentials of a profile.
+// Credentials are resolved on first use and shared by all requests of the factory,
+// so the environment and credentials file are not read for every request.
+//
+// Parameters:
+//   - profile: Profile name; empty for the environment or default profile
+//
+// Returns:
+//   - http.AWSCredentials: Resolved credentials
+//   - error: Error if no credentials could be found
+func (factory *DefaultFactory) awsCredentials(profile string) (http.AWSCredentials, error) {
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	if creds, ok := factory.credentials[profile]; ok {
+		return creds, nil
+	}
+
+	creds, err := http.LoadAWSCredentials(profile)
+	if err != nil {
+		return http.AWSCredentials{}, err
+	}
+	factory.credentials[profile] = creds
+	return creds, nil
+}
@@ -8,7 +8,9 @@ const (
 	// Style codes
 	Reset     = "\033[0m"
 	Bold      = "\033[1m"
+	Dim       = "\033[2m"
 	Underline = "\033[4m"
+	Reverse   = "\033[7m"
 
 	// Foreground color codes
 	Black   = "\033[30m"
@@ -89,16 +89,51 @@ func FormatCommandError(cmdName string, err error) string {
 		buffer.WriteString("  jak bat [config_file]\n\n")
 		buffer.WriteString("Examples:\n")
 		buffer.WriteString("  jak bat config.toml\n")
+		buffer.WriteString("  jak bat config.toml --snapshot-dir snapshots/\n")
 	case "chain":
 		buffer.WriteString("  jak chain [config_file]\n\n")
 		buffer.WriteString("Examples:\n")
 		buffer.WriteString("  jak chain config.toml\n")
+	case "load":
+		buffer.WriteString("  jak load [config_file] [flags]\n\n")
+		buffer.WriteString("Examples:\n")
+		buffer.WriteString("  jak load config.toml --rps 200 --duration 60s --users 50\n")
+		buffer.WriteString("  jak load config.toml --users 20 --stages 10s:20,1m:20,10s:0\n")
+	case "diff":
+		buffer.WriteString("  jak diff [config_file] --env-a <env> --env-b <env>\n\n")
+		buffer.WriteString("Examples:\n")
+		buffer.WriteString("  jak diff config.toml --env-a legacy --env-b new\n")
+	case "serve":
+		buffer.WriteString("  jak serve [stub_file] [flags]\n\n")
+		buffer.WriteString("Examples:\n")
+		buffer.WriteString("  jak serve mocks.toml --port 8080\n")
+	case "record":
+		buffer.WriteString("  jak record [flags]\n\n")
+		buffer.WriteString("Examples:\n")
+		buffer.WriteString("  jak record --listen :8888 --target https://api.example.com --out captured.toml\n")
+		buffer.WriteString("  jak record --listen :8888 --out captured.toml --extract\n")
+	case "shell":
+		buffer.WriteString("  jak shell [flags]\n\n")
+		buffer.WriteString("Examples:\n")
+		buffer.WriteString("  jak shell --base-url https://api.example.com\n")
+		buffer.WriteString("  jak shell --base-url https://api.example.com -H \"Authorization: Bearer abc\"\n")
+	case "tui":
+		buffer.WriteString("  jak tui [config_file] [flags]\n\n")
+		buffer.WriteString("Examples:\n")
+		buffer.WriteString("  jak tui config.toml\n")
+		buffer.WriteString("  jak tui config.toml --replay session.cassette.json\n")
 	default:
 		buffer.WriteString("  jak [command] [args] [flags]\n\n")
 		buffer.WriteString("Available Commands:\n")
 		buffer.WriteString("  req     Execute a simple HTTP request\n")
 		buffer.WriteString("  bat     Execute batch requests from a config file\n")
 		buffer.WriteString("  chain   Execute chain requests with dependencies\n")
+		buffer.WriteString("  load    Load test the requests of a config file\n")
+		buffer.WriteString("  diff    Compare responses between two environments\n")
+		buffer.WriteString("  serve   Run a mock server from stub routes\n")
+		buffer.WriteString("  record  Record proxied traffic into a config file\n")
+		buffer.WriteString("  shell   Explore an API interactively\n")
+		buffer.WriteString("  tui     Browse and run a config in a terminal interface\n")
 	}
 
 	buffer.WriteString("\nRun 'jak --help' or 'jak [command] --help' for more information.\n")
@@ -3,10 +3,13 @@ package format
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/ymatsukawa/jak/internal/diff"
 	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/load"
 )
 
 // ReqResult holds structured information about a request execution.
@@ -20,6 +23,28 @@ type ReqResult struct {
 	Duration   time.Duration // Time taken to execute
 	Success    bool          // Whether the request was successful
 	Error      error         // Error if any
+	Timing     *http.Timing  // Timing breakdown (nil if no response was received)
+	Skipped    bool          // Whether the request was skipped by its condition
+}
+
+// DiffResult holds the comparison of a request between two environments.
+type DiffResult struct {
+	Name   string       // Name of the request
+	Method string       // HTTP method used
+	Path   string       // Request path
+	Result *diff.Result // Differences between the responses
+}
+
+// verbose enables detailed output such as the timing waterfall
+var verbose bool
+
+// SetVerbose enables or disables detailed output.
+// In verbose mode PrintRequestResult also prints the timing waterfall of each request.
+//
+// Parameters:
+//   - enabled: True to enable verbose output
+func SetVerbose(enabled bool) {
+	verbose = enabled
 }
 
 // PrintResponse prints a formatted HTTP response to standard output.
@@ -32,6 +57,41 @@ func PrintResponse(resp *http.Response) {
 	fmt.Fprint(os.Stdout, FormatResponse(resp))
 }
 
+// PrintTLSInfo prints TLS handshake details to standard output.
+// It uses the FormatTLSInfo function to create a human-readable
+// representation of the negotiated connection.
+//
+// Parameters:
+//   - info: TLS handshake details (may be nil for plain HTTP)
+func PrintTLSInfo(info *http.TLSInfo) {
+	fmt.Fprint(os.Stdout, FormatTLSInfo(info))
+}
+
+// PrintCookies prints stored cookies to standard output.
+// It uses the FormatCookies function to create a human-readable list.
+//
+// Parameters:
+//   - cookies: Stored cookies to print
+func PrintCookies(cookies []http.StoredCookie) {
+	fmt.Fprint(os.Stdout, FormatCookies(cookies))
+}
+
+// PrintDiff prints a colored unified diff to standard output.
+//
+// Parameters:
+//   - diff: Unified diff, printed only if not empty
+func PrintDiff(diff string) {
+	if diff == "" {
+		return
+	}
+	fmt.Fprint(os.Stdout, FormatDiff(diff))
+}
+
+// ClearScreen clears the terminal and moves the cursor to the top left corner.
+func ClearScreen() {
+	fmt.Fprint(os.Stdout, "\033[H\033[2J")
+}
+
 // PrintError prints a formatted error message to standard error.
 // It uses the FormatError function to create a human-readable
 // representation of the error with context and help messages.
@@ -71,7 +131,8 @@ func PrintCommandError(cmdName string, err error) {
 //   - HTTP method and URL
 //   - Status code (color-coded based on status)
 //   - Duration
-//   - Error message (if any)
+//   - Error message (if any), or the reason a skipped request was not sent
+//   - Timing waterfall (verbose mode only)
 func PrintRequestResult(result ReqResult) {
 	var statusText string
 	if result.StatusCode > 0 {
@@ -97,7 +158,12 @@ func PrintRequestResult(result ReqResult) {
 
 	// Status and duration
 	var statusPart string
-	if result.Success {
+	if result.Skipped {
+		statusPart = " " + ColorizeWarning("[SKIPPED]")
+		if result.Error != nil {
+			statusPart += " " + ColorizeWarning(result.Error.Error())
+		}
+	} else if result.Success {
 		if result.StatusCode >= 200 && result.StatusCode < 300 {
 			statusPart = ColorizeSuccess(statusText) + durationText
 		} else {
@@ -112,6 +178,10 @@ func PrintRequestResult(result ReqResult) {
 
 	// Print the formatted line
 	fmt.Fprintf(os.Stdout, "%s%s%s\n", nameText, methodURLText, statusPart)
+
+	if verbose && result.Timing != nil {
+		fmt.Fprint(os.Stdout, FormatTiming(result.Timing))
+	}
 }
 
 // PrintBatchSummary prints a summary of batch request execution to standard output.
@@ -124,19 +194,23 @@ func PrintRequestResult(result ReqResult) {
 //   - Total number of requests
 //   - Number of successful requests
 //   - Number of failed requests
+//   - Number of skipped requests (only if any were skipped)
 //   - Total execution time
 func PrintBatchSummary(results []ReqResult) {
 	if len(results) == 0 {
 		return
 	}
 
-	// Count successful and failed requests
+	// Count successful, failed and skipped requests
 	successCount := 0
 	failCount := 0
+	skipCount := 0
 	var totalDuration time.Duration
 
 	for _, result := range results {
-		if result.Success {
+		if result.Skipped {
+			skipCount++
+		} else if result.Success {
 			successCount++
 		} else {
 			failCount++
@@ -167,6 +241,14 @@ func PrintBatchSummary(results []ReqResult) {
 		ColorizeError(fmt.Sprintf("%d", failCount)),
 		ColorizeHeader(""))
 
+	if skipCount > 0 {
+		fmt.Printf("%s│ %s %-43s│%s\n",
+			ColorizeHeader(""),
+			"Skipped:",
+			ColorizeWarning(fmt.Sprintf("%d", skipCount)),
+			ColorizeHeader(""))
+	}
+
 	fmt.Printf("%s│ %s %-43s│%s\n",
 		ColorizeHeader(""),
 		"Total Time:",
@@ -176,3 +258,191 @@ func PrintBatchSummary(results []ReqResult) {
 	fmt.Println(ColorizeHeader("└─" + strings.Repeat("─", 46) + "┘"))
 	fmt.Println()
 }
+
+// PrintComparison prints the comparison of a request between two environments to standard output.
+// It prints a result line followed by the differences formatted by FormatComparison.
+//
+// Parameters:
+//   - result: Comparison of the request
+//
+// The printed line includes:
+//   - Request name, HTTP method and path
+//   - Status codes of both environments
+//   - Whether the responses are identical, differ, or a request failed
+func PrintComparison(result DiffResult) {
+	statusText := func(resp diff.Response) string {
+		if resp.Err != nil {
+			return ColorizeError("ERR")
+		}
+		return ColorizeByStatus(resp.StatusCode, fmt.Sprintf("%d", resp.StatusCode))
+	}
+
+	outcome := ColorizeSuccess("identical")
+	if result.Result.Failed() {
+		outcome = ColorizeError("failed")
+	} else if !result.Result.Identical() {
+		outcome = ColorizeError("differs")
+	}
+
+	fmt.Fprintf(os.Stdout, "%s | %s %s [%s | %s] %s\n",
+		ColorizeName(result.Name),
+		ColorizeMethod(result.Method),
+		result.Path,
+		statusText(result.Result.A),
+		statusText(result.Result.B),
+		outcome)
+	fmt.Fprint(os.Stdout, FormatComparison(result.Result))
+}
+
+// PrintDiffSummary prints a summary of a comparison between two environments to standard output.
+// It creates a visually formatted box like PrintBatchSummary, followed by the divergent endpoints.
+//
+// Parameters:
+//   - envA: Name of environment A
+//   - envB: Name of environment B
+//   - results: Comparisons of all requests
+func PrintDiffSummary(envA, envB string, results []DiffResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	const width = 46
+
+	row := func(label, value string) {
+		line := fmt.Sprintf("%-13s %s", label, value)
+		if len(line) > width {
+			line = line[:width-3] + "..."
+		}
+		fmt.Printf("%s│ %-*s│%s\n", ColorizeHeader(""), width, line, ColorizeHeader(""))
+	}
+
+	identical, failed := 0, 0
+	var divergent []DiffResult
+	for _, result := range results {
+		switch {
+		case result.Result.Failed():
+			failed++
+			divergent = append(divergent, result)
+		case result.Result.Identical():
+			identical++
+		default:
+			divergent = append(divergent, result)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(ColorizeHeader("┌─" + strings.Repeat("─", width) + "┐"))
+	row("DIFF", envA+" vs "+envB)
+	fmt.Println(ColorizeHeader("├─" + strings.Repeat("─", width) + "┤"))
+	row("Requests:", fmt.Sprintf("%d", len(results)))
+	row("Identical:", fmt.Sprintf("%d", identical))
+	row("Divergent:", fmt.Sprintf("%d", len(divergent)-failed))
+	if failed > 0 {
+		row("Failed:", fmt.Sprintf("%d", failed))
+	}
+	fmt.Println(ColorizeHeader("└─" + strings.Repeat("─", width) + "┘"))
+
+	if len(divergent) > 0 {
+		fmt.Println(ColorizeWarning("Divergent endpoints:"))
+		for _, result := range divergent {
+			fmt.Printf("  - %s (%s %s)\n", result.Name, result.Method, result.Path)
+		}
+	}
+	fmt.Println()
+}
+
+// PrintLoadProgress prints a single line with the running totals of a load test.
+//
+// Parameters:
+//   - elapsed: Time since the start of the test
+//   - requests: Number of completed requests so far
+//   - errors: Number of failed requests so far
+func PrintLoadProgress(elapsed time.Duration, requests, errors int64) {
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(requests) / elapsed.Seconds()
+	}
+
+	errorText := fmt.Sprintf("%d errors", errors)
+	if errors > 0 {
+		errorText = ColorizeError(errorText)
+	}
+
+	fmt.Fprintf(os.Stdout, "%s %d requests, %s, %.1f req/s\n",
+		ColorizeInfo(fmt.Sprintf("[%6s]", elapsed.Round(time.Second))),
+		requests,
+		errorText,
+		rate)
+}
+
+// PrintLoadReport prints the results of a load test to standard output.
+// It creates a visually formatted box like PrintBatchSummary.
+//
+// Parameters:
+//   - report: Results of the load test
+//
+// The report includes:
+//   - Load model, duration, request count and throughput
+//   - Error count and rate
+//   - Latency percentiles (p50/p90/p95/p99) with min, mean and max
+//   - Response counts per status code and transport errors by message
+func PrintLoadReport(report *load.Report) {
+	const width = 46
+
+	row := func(label, value string) {
+		line := fmt.Sprintf("%-13s %s", label, value)
+		if len(line) > width {
+			line = line[:width-3] + "..."
+		}
+		fmt.Printf("%s│ %-*s│%s\n", ColorizeHeader(""), width, line, ColorizeHeader(""))
+	}
+	separator := func() {
+		fmt.Println(ColorizeHeader("├─" + strings.Repeat("─", width) + "┤"))
+	}
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+	}
+
+	fmt.Println()
+	fmt.Println(ColorizeHeader("┌─" + strings.Repeat("─", width) + "┐"))
+	row("LOAD TEST", "")
+	separator()
+
+	row("Model:", string(report.Model))
+	row("Duration:", report.Elapsed.Round(time.Millisecond).String())
+	requests := fmt.Sprintf("%d", report.Requests)
+	if report.Dropped > 0 {
+		requests += fmt.Sprintf(" (%d dropped)", report.Dropped)
+	}
+	row("Requests:", requests)
+	row("Throughput:", fmt.Sprintf("%.1f req/s", report.Throughput()))
+	row("Errors:", fmt.Sprintf("%d (%.2f%%)", report.Errors, report.ErrorRate()*100))
+	separator()
+
+	latency := report.Latency
+	row("Latency:", "")
+	row("  min:", ms(latency.Min()))
+	row("  mean:", ms(latency.Mean()))
+	for _, percentile := range []float64{50, 90, 95, 99} {
+		row(fmt.Sprintf("  p%g:", percentile), ms(latency.Percentile(percentile)))
+	}
+	row("  max:", ms(latency.Max()))
+
+	if len(report.StatusCodes) > 0 || len(report.ErrorMessages) > 0 {
+		separator()
+	}
+	for _, code := range report.SortedStatusCodes() {
+		row(fmt.Sprintf("Status %d:", code), fmt.Sprintf("%d", report.StatusCodes[code]))
+	}
+	messages := make([]string, 0, len(report.ErrorMessages))
+	for message := range report.ErrorMessages {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	for _, message := range messages {
+		row(fmt.Sprintf("Error (%d):", report.ErrorMessages[message]), message)
+	}
+
+	fmt.Println(ColorizeHeader("└─" + strings.Repeat("─", width) + "┘"))
+	fmt.Println()
+}
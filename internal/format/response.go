@@ -9,7 +9,9 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/ymatsukawa/jak/internal/diff"
 	"github.com/ymatsukawa/jak/internal/http"
 )
 
@@ -25,6 +27,7 @@ import (
 //
 // The formatted response includes:
 //   - Divider lines at top and bottom
+//   - Redirect chain (if redirects were followed)
 //   - Status line with color based on status code
 //   - Headers section (if headers present)
 //   - Body section (if body present) with content-type specific formatting
@@ -39,6 +42,11 @@ func FormatResponse(resp *http.Response) string {
 	divider := strings.Repeat("─", 50)
 	buffer.WriteString(ColorizeInfo(divider) + "\n")
 
+	// Redirect chain
+	if len(resp.Redirects) > 0 {
+		buffer.WriteString(FormatRedirects(resp.Redirects) + "\n")
+	}
+
 	// Status line
 	statusText := getStatusText(resp.StatusCode)
 	statusLine := fmt.Sprintf("Status: %d %s", resp.StatusCode, statusText)
@@ -80,6 +88,285 @@ func FormatResponse(resp *http.Response) string {
 	return buffer.String()
 }
 
+// FormatRedirects formats the redirect chain of a response for display.
+// Each hop shows the status, the method and URL that were redirected, and the target.
+//
+// Parameters:
+//   - hops: Redirect hops, oldest first
+//
+// Returns:
+//   - string: Formatted redirect chain
+func FormatRedirects(hops []http.RedirectHop) string {
+	var buffer bytes.Buffer
+	buffer.WriteString(ColorizeHeader("Redirects:") + "\n")
+	for i, hop := range hops {
+		status := ColorizeByStatus(hop.StatusCode, fmt.Sprintf("%d", hop.StatusCode))
+		buffer.WriteString(fmt.Sprintf("  %d. %s %s %s -> %s\n", i+1, status, hop.Method, hop.URL, hop.Location()))
+	}
+	return buffer.String()
+}
+
+// FormatTLSInfo formats TLS handshake details for display.
+//
+// Parameters:
+//   - info: TLS handshake details (may be nil for plain HTTP)
+//
+// Returns:
+//   - string: Formatted connection details
+func FormatTLSInfo(info *http.TLSInfo) string {
+	if info == nil {
+		return ColorizeInfo("TLS:") + " none (plain HTTP)\n"
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(ColorizeHeader("TLS:") + "\n")
+	buffer.WriteString(fmt.Sprintf("  Version: %s\n", info.Version))
+	buffer.WriteString(fmt.Sprintf("  Cipher: %s\n", info.CipherSuite))
+	if info.ServerName != "" {
+		buffer.WriteString(fmt.Sprintf("  Server Name: %s\n", info.ServerName))
+	}
+	if info.PeerSubject != "" {
+		buffer.WriteString(fmt.Sprintf("  Peer Subject: %s\n", info.PeerSubject))
+		buffer.WriteString(fmt.Sprintf("  Peer Issuer: %s\n", info.PeerIssuer))
+
+		expiry := info.PeerNotAfter.Format(time.RFC3339)
+		if time.Until(info.PeerNotAfter) < 30*24*time.Hour {
+			expiry = ColorizeWarning(expiry)
+		}
+		buffer.WriteString(fmt.Sprintf("  Peer Expires: %s\n", expiry))
+	}
+
+	return buffer.String()
+}
+
+// timingBarWidth is the width in characters of the timing waterfall bars
+const timingBarWidth = 30
+
+// FormatTiming formats a timing breakdown as a waterfall.
+// Phases are drawn in the order they happen, each bar starting where the previous one ended.
+//
+// Parameters:
+//   - timing: Timing breakdown of a request
+//
+// Returns:
+//   - string: Formatted waterfall
+func FormatTiming(timing *http.Timing) string {
+	if timing == nil {
+		return ""
+	}
+
+	phases := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"DNS Lookup", timing.DNSLookup},
+		{"TCP Connect", timing.TCPConnect},
+		{"TLS Handshake", timing.TLSHandshake},
+		{"Server Processing", timing.ServerProcessing},
+		{"Content Transfer", timing.ContentTransfer},
+	}
+
+	var total time.Duration
+	for _, phase := range phases {
+		total += phase.duration
+	}
+
+	var buffer bytes.Buffer
+	var offset time.Duration
+	for _, phase := range phases {
+		start, width := 0, 0
+		if total > 0 {
+			start = int(offset * timingBarWidth / total)
+			width = int((offset+phase.duration)*timingBarWidth/total) - start
+		}
+		if phase.duration > 0 && width == 0 {
+			width = 1
+		}
+		if start+width > timingBarWidth {
+			start = timingBarWidth - width
+		}
+		offset += phase.duration
+
+		bar := strings.Repeat(" ", start) + strings.Repeat("█", width) + strings.Repeat(" ", timingBarWidth-start-width)
+		buffer.WriteString(fmt.Sprintf("    %-18s %s %8s\n",
+			phase.name, ColorizeInfo("|"+bar+"|"), phase.duration.Round(time.Microsecond)))
+	}
+
+	connection := "new connection"
+	if timing.ConnectionReused {
+		connection = "connection reused"
+	}
+	buffer.WriteString(fmt.Sprintf("    %-18s %s %8s\n", "Time to First Byte", strings.Repeat(" ", timingBarWidth+2), timing.TimeToFirstByte.Round(time.Microsecond)))
+	buffer.WriteString(fmt.Sprintf("    %-18s %s %8s %s\n", "Total", strings.Repeat(" ", timingBarWidth+2), timing.Total.Round(time.Microsecond), ColorizeInfo("("+connection+")")))
+
+	return buffer.String()
+}
+
+// FormatCookies formats stored cookies for display, grouped by domain.
+//
+// Parameters:
+//   - cookies: Stored cookies sorted by domain
+//
+// Returns:
+//   - string: Formatted cookie list
+func FormatCookies(cookies []http.StoredCookie) string {
+	if len(cookies) == 0 {
+		return ColorizeWarning("No cookies stored") + "\n"
+	}
+
+	var buffer bytes.Buffer
+	domain := ""
+	for _, cookie := range cookies {
+		if cookie.Domain != domain {
+			domain = cookie.Domain
+			buffer.WriteString(ColorizeHeader(domain) + "\n")
+		}
+
+		var attrs []string
+		attrs = append(attrs, "path="+cookie.Path)
+		if cookie.Expires.IsZero() {
+			attrs = append(attrs, "session")
+		} else {
+			attrs = append(attrs, "expires="+cookie.Expires.Format(time.RFC3339))
+		}
+		if cookie.Secure {
+			attrs = append(attrs, "secure")
+		}
+		if cookie.HttpOnly {
+			attrs = append(attrs, "httponly")
+		}
+		if !cookie.HostOnly {
+			attrs = append(attrs, "subdomains")
+		}
+
+		buffer.WriteString(fmt.Sprintf("  %s = %s %s\n",
+			ColorizeName(cookie.Name), cookie.Value, ColorizeInfo("("+strings.Join(attrs, ", ")+")")))
+	}
+
+	return buffer.String()
+}
+
+// FormatDiff colors a unified diff for display.
+// Removed lines are red, added lines green and hunk headers cyan.
+//
+// Parameters:
+//   - diff: Unified diff
+//
+// Returns:
+//   - string: Colored diff, indented to appear below a request result line
+func FormatDiff(diff string) string {
+	var buffer bytes.Buffer
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++"):
+			line = ColorizeHeader(line)
+		case strings.HasPrefix(line, "@@"):
+			line = ColorizeInfo(line)
+		case strings.HasPrefix(line, "-"):
+			line = ColorizeError(line)
+		case strings.HasPrefix(line, "+"):
+			line = ColorizeSuccess(line)
+		}
+		buffer.WriteString("    " + line + "\n")
+	}
+	return buffer.String()
+}
+
+// maxDiffValueLength is the maximum length of a JSON value shown in a comparison
+const maxDiffValueLength = 80
+
+// FormatComparison formats the differences between the responses of a request in two environments.
+//
+// Parameters:
+//   - result: Differences between the responses
+//
+// Returns:
+//   - string: One indented line per difference, followed by the diff of text bodies;
+//     empty if the responses are identical
+//
+// The formatted differences include:
+//   - Errors of failed requests
+//   - Status codes, if they differ
+//   - Compared headers that differ
+//   - JSON values that changed or exist in one environment only
+func FormatComparison(result *diff.Result) string {
+	var buffer bytes.Buffer
+
+	for _, resp := range []diff.Response{result.A, result.B} {
+		if resp.Err != nil {
+			buffer.WriteString(fmt.Sprintf("    %s %s\n", ColorizeError(resp.Env+":"), resp.Err))
+		}
+	}
+
+	if result.StatusDiffers() {
+		buffer.WriteString(fmt.Sprintf("    status: %s → %s\n",
+			ColorizeByStatus(result.A.StatusCode, fmt.Sprintf("%d", result.A.StatusCode)),
+			ColorizeByStatus(result.B.StatusCode, fmt.Sprintf("%d", result.B.StatusCode))))
+	}
+
+	for _, header := range result.Headers {
+		buffer.WriteString(fmt.Sprintf("    header %s: %s → %s\n",
+			header.Name, formatHeaderValue(header.A), formatHeaderValue(header.B)))
+	}
+
+	for _, difference := range result.Body {
+		path := "body"
+		if difference.Path != "" {
+			path = "body " + difference.Path
+		}
+		switch difference.Kind {
+		case diff.KindOnlyA:
+			buffer.WriteString(fmt.Sprintf("    %s: %s %s\n",
+				path, ColorizeError(formatDiffValue(difference.A)), ColorizeWarning("(only in "+result.A.Env+")")))
+		case diff.KindOnlyB:
+			buffer.WriteString(fmt.Sprintf("    %s: %s %s\n",
+				path, ColorizeSuccess(formatDiffValue(difference.B)), ColorizeWarning("(only in "+result.B.Env+")")))
+		default:
+			buffer.WriteString(fmt.Sprintf("    %s: %s → %s\n",
+				path, ColorizeError(formatDiffValue(difference.A)), ColorizeSuccess(formatDiffValue(difference.B))))
+		}
+	}
+
+	if result.TextDiff != "" {
+		buffer.WriteString(FormatDiff(result.TextDiff))
+	}
+
+	return buffer.String()
+}
+
+// formatHeaderValue quotes a header value for a comparison.
+//
+// Parameters:
+//   - value: Header value, empty if the header is missing
+//
+// Returns:
+//   - string: Quoted value, or "<missing>"
+func formatHeaderValue(value string) string {
+	if value == "" {
+		return ColorizeWarning("<missing>")
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// formatDiffValue formats a decoded JSON value as compact JSON for a comparison.
+//
+// Parameters:
+//   - value: Decoded JSON value
+//
+// Returns:
+//   - string: Compact JSON, shortened to maxDiffValueLength characters
+func formatDiffValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	text := string(data)
+	if len(text) > maxDiffValueLength {
+		text = text[:maxDiffValueLength-3] + "..."
+	}
+	return text
+}
+
 // formatBody formats the response body based on content type.
 // It applies different formatting strategies based on the content type:
 // - JSON: Pretty-printed with indentation
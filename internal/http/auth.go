@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/base64"
+)
+
+// Credentials holds a username and password pair used for authentication.
+type Credentials struct {
+	// Username is the user name
+	Username string
+
+	// Password is the password
+	Password string
+}
+
+// WithBasicAuth sets the Authorization header for HTTP Basic authentication.
+//
+// Parameters:
+//   - username: User name
+//   - password: Password
+//
+// Returns:
+//   - RequestOption: Option function that sets the Authorization header
+func WithBasicAuth(username, password string) RequestOption {
+	return func(req *Request) {
+		encoded := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		setRequestHeader(req, "Authorization", "Basic "+encoded)
+	}
+}
+
+// WithBearerToken sets the Authorization header with a bearer token.
+// If the token is empty, no action is taken.
+//
+// Parameters:
+//   - token: Bearer token
+//
+// Returns:
+//   - RequestOption: Option function that sets the Authorization header
+func WithBearerToken(token string) RequestOption {
+	return func(req *Request) {
+		if token == "" {
+			return
+		}
+		setRequestHeader(req, "Authorization", "Bearer "+token)
+	}
+}
+
+// WithHeaderValue sets a single header value, keeping other headers intact.
+// If the key is empty, no action is taken.
+//
+// Parameters:
+//   - key: Header key
+//   - value: Header value
+//
+// Returns:
+//   - RequestOption: Option function that sets the header
+func WithHeaderValue(key, value string) RequestOption {
+	return func(req *Request) {
+		if key == "" {
+			return
+		}
+		setRequestHeader(req, key, value)
+	}
+}
+
+// WithDigestAuth enables HTTP Digest authentication for the request.
+// The client answers a 401 Digest challenge using these credentials.
+//
+// Parameters:
+//   - username: User name
+//   - password: Password
+//
+// Returns:
+//   - RequestOption: Option function that sets the digest credentials
+func WithDigestAuth(username, password string) RequestOption {
+	return func(req *Request) {
+		req.DigestAuth = &Credentials{
+			Username: username,
+			Password: password,
+		}
+	}
+}
+
+// setRequestHeader sets a header on the request, creating the header container if needed.
+//
+// Parameters:
+//   - req: Request to modify
+//   - key: Header key
+//   - value: Header value
+func setRequestHeader(req *Request, key, value string) {
+	if req.Headers == nil {
+		req.Headers = NewBaseHeaders()
+	}
+	_ = req.Headers.Set(key, value)
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthOptions(t *testing.T) {
+	t.Run("basic auth", func(t *testing.T) {
+		req := NewRequest("http://example.com", MethodGet, WithBasicAuth("user", "pass"))
+		assert.Equal(t, "Basic dXNlcjpwYXNz", req.Headers.Get("Authorization"))
+	})
+
+	t.Run("bearer token keeps existing headers", func(t *testing.T) {
+		req := NewRequest("http://example.com", MethodGet,
+			WithHeaders([]string{"Accept: application/json"}),
+			WithBearerToken("abc"))
+		assert.Equal(t, "Bearer abc", req.Headers.Get("Authorization"))
+		assert.Equal(t, "application/json", req.Headers.Get("Accept"))
+	})
+
+	t.Run("empty bearer token is ignored", func(t *testing.T) {
+		req := NewRequest("http://example.com", MethodGet, WithBearerToken(""))
+		assert.Nil(t, req.Headers)
+	})
+
+	t.Run("header value", func(t *testing.T) {
+		req := NewRequest("http://example.com", MethodGet, WithHeaderValue("X-API-Key", "secret"))
+		assert.Equal(t, "secret", req.Headers.Get("X-API-Key"))
+	})
+
+	t.Run("digest auth", func(t *testing.T) {
+		req := NewRequest("http://example.com", MethodGet, WithDigestAuth("user", "pass"))
+		assert.Equal(t, &Credentials{Username: "user", Password: "pass"}, req.DigestAuth)
+	})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -46,20 +47,25 @@ type DefaultClient struct {
 
 	// timeout is the configured request timeout
 	timeout time.Duration
+
+	// redirect is the default redirect policy for requests
+	redirect RedirectPolicy
 }
 
+// ClientOption defines a function type that configures a client.
+// It mirrors RequestOption and allows transport-level customization.
+type ClientOption func(*DefaultClient)
+
 // NewClient creates a new HTTP client with default timeout.
 // The client is configured to cancel requests after DefaultTimeout (30 seconds).
 //
+// Parameters:
+//   - opts: Variable number of option functions to configure the client
+//
 // Returns:
 //   - Client: Initialized client ready to execute requests
-func NewClient() Client {
-	return &DefaultClient{
-		client: &http.Client{
-			Timeout: DefaultTimeout,
-		},
-		timeout: DefaultTimeout,
-	}
+func NewClient(opts ...ClientOption) Client {
+	return NewClientWithTimeout(DefaultTimeout, opts...)
 }
 
 // NewClientWithTimeout creates a new HTTP client with custom timeout.
@@ -67,16 +73,44 @@ func NewClient() Client {
 //
 // Parameters:
 //   - timeout: Custom timeout duration
+//   - opts: Variable number of option functions to configure the client
 //
 // Returns:
 //   - Client: Initialized client with custom timeout
-func NewClientWithTimeout(timeout time.Duration) Client {
-	return &DefaultClient{
+func NewClientWithTimeout(timeout time.Duration, opts ...ClientOption) Client {
+	client := &DefaultClient{
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:       timeout,
+			CheckRedirect: checkRedirect,
 		},
-		timeout: timeout,
+		timeout:  timeout,
+		redirect: DefaultRedirectPolicy(),
+	}
+
+	// Install a proxy selector so per-request proxy overrides always apply
+	client.transport().Proxy = newProxySelector(ProxyOptions{}).proxyFor
+
+	// Apply all options
+	for _, opt := range opts {
+		opt(client)
 	}
+
+	return client
+}
+
+// transport returns the client's own transport, creating it from
+// http.DefaultTransport on first use so that options never modify the shared default.
+//
+// Returns:
+//   - *http.Transport: Transport owned by this client
+func (client *DefaultClient) transport() *http.Transport {
+	if transport, ok := client.client.Transport.(*http.Transport); ok {
+		return transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	client.client.Transport = transport
+	return transport
 }
 
 // SetTimeout updates the client timeout.
@@ -89,6 +123,24 @@ func (client *DefaultClient) SetTimeout(timeout time.Duration) {
 	client.client.Timeout = timeout
 }
 
+// WithMaxIdleConnsPerHost sets how many idle connections per host the transport keeps.
+// Raising it above the default of 2 lets concurrent requests reuse connections.
+//
+// Parameters:
+//   - n: Maximum number of idle connections per host
+//
+// Returns:
+//   - ClientOption: Option function that sizes the connection pool
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(client *DefaultClient) {
+		transport := client.transport()
+		transport.MaxIdleConnsPerHost = n
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < n {
+			transport.MaxIdleConns = n
+		}
+	}
+}
+
 // Do executes the request and returns a response.
 // It creates a standard Go http.Request from the Request object,
 // sets appropriate headers and context, executes the request,
@@ -101,6 +153,44 @@ func (client *DefaultClient) SetTimeout(timeout time.Duration) {
 //   - *Response: Response from the server
 //   - error: Any error encountered during execution
 func (client *DefaultClient) Do(req *Request) (*Response, error) {
+	httpReq, err := client.newHTTPRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute request
+	resp, err := client.doRequest(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retry once with a fresh token if the cached one was rejected
+	if resp.StatusCode == StatusUnauthorized && req.TokenSource != nil {
+		req.TokenSource.Invalidate()
+		if httpReq, err = client.newHTTPRequest(req); err != nil {
+			return nil, err
+		}
+		return client.doRequest(httpReq)
+	}
+
+	// Answer a Digest challenge if credentials are available
+	if resp.StatusCode == StatusUnauthorized && req.DigestAuth != nil {
+		return client.retryWithDigest(req, resp)
+	}
+
+	return resp, nil
+}
+
+// newHTTPRequest creates a standard Go http.Request from the Request object.
+// A fresh body reader is created on every call, so the result can be used for retries.
+//
+// Parameters:
+//   - req: Request to convert
+//
+// Returns:
+//   - *http.Request: Standard Go request with context and headers applied
+//   - error: Any error encountered during creation
+func (client *DefaultClient) newHTTPRequest(req *Request) (*http.Request, error) {
 	// Normalize method
 	method, err := NormalizeMethod(req.GetMethod())
 	if err != nil {
@@ -125,12 +215,80 @@ func (client *DefaultClient) Do(req *Request) (*Response, error) {
 		httpReq = httpReq.WithContext(ctx)
 	}
 
+	// Carry a per-request proxy override to the transport
+	if req.Proxy != "" {
+		httpReq = httpReq.WithContext(withProxyOverride(httpReq.Context(), req.Proxy))
+	}
+
+	// Track redirects followed for this request
+	policy := client.redirect
+	if req.Redirect != nil {
+		policy = *req.Redirect
+	}
+	httpReq = httpReq.WithContext(withRedirectState(httpReq.Context(), policy))
+
 	// Set headers
 	if err := client.setHeaders(httpReq, req); err != nil {
 		return nil, err
 	}
 
-	// Execute request
+	// Authorize with a token from the token source
+	if req.TokenSource != nil {
+		token, err := req.TokenSource.Token(withTokenTransport(httpReq.Context(), client.client.Transport))
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain access token: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// Sign last so the signature covers the final headers and body
+	if req.Signer != nil {
+		var body []byte
+		if req.Body != nil {
+			body = []byte(req.Body.Content())
+		}
+		if err := req.Signer.Sign(httpReq, body); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	return httpReq, nil
+}
+
+// retryWithDigest re-sends a request with a Digest Authorization header
+// computed from the challenge in a 401 response.
+// If the response carries no Digest challenge, it is returned unchanged.
+//
+// Parameters:
+//   - req: Original request with digest credentials
+//   - unauthorized: 401 response containing the WWW-Authenticate challenge
+//
+// Returns:
+//   - *Response: Response to the authenticated request
+//   - error: Any error encountered during execution
+func (client *DefaultClient) retryWithDigest(req *Request, unauthorized *Response) (*Response, error) {
+	var challenge *digestChallenge
+	for _, header := range unauthorized.Header.Values("WWW-Authenticate") {
+		if parsed, ok := parseDigestChallenge(header); ok {
+			challenge = parsed
+			break
+		}
+	}
+	if challenge == nil {
+		return unauthorized, nil
+	}
+
+	httpReq, err := client.newHTTPRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization, err := challenge.authorization(req.DigestAuth, httpReq.Method, httpReq.URL.RequestURI())
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", authorization)
+
 	return client.doRequest(httpReq)
 }
 
@@ -162,7 +320,7 @@ func (client *DefaultClient) setHeaders(httpReq *http.Request, req *Request) err
 
 // doRequest executes the HTTP request and processes the response.
 // It sends the request, reads the response body, and creates a
-// reusable response object.
+// reusable response object with the redirect chain and timing breakdown.
 //
 // Parameters:
 //   - req: Standard Go http.Request to execute
@@ -171,6 +329,10 @@ func (client *DefaultClient) setHeaders(httpReq *http.Request, req *Request) err
 //   - *Response: Response from the server
 //   - error: Any error encountered during execution
 func (client *DefaultClient) doRequest(req *http.Request) (*Response, error) {
+	// Trace connection phases for the timing breakdown
+	recorder := newTimingRecorder()
+	req = req.WithContext(recorder.withTrace(req.Context()))
+
 	// Send request
 	resp, err := client.client.Do(req)
 	if err != nil {
@@ -183,11 +345,21 @@ func (client *DefaultClient) doRequest(req *http.Request) (*Response, error) {
 	if err != nil {
 		return nil, se.ErrResponseReadFailed
 	}
+	timing := recorder.timing(time.Now())
+
+	// Collect the redirect hops recorded while following redirects
+	var redirects []RedirectHop
+	if state := redirectStateFrom(req.Context()); state != nil {
+		redirects = state.hops
+	}
 
 	// Create reusable body reader
 	return &Response{
 		Header:     resp.Header,
 		Body:       io.NopCloser(bytes.NewReader(bodyBytes)),
 		StatusCode: resp.StatusCode,
+		TLS:        newTLSInfo(resp.TLS),
+		Redirects:  redirects,
+		Timing:     timing,
 	}, nil
 }
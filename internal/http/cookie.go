@@ -0,0 +1,363 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StoredCookie is a cookie held by a CookieJar, with the scope it was stored for.
+// It is also the on-disk representation of a persisted cookie.
+type StoredCookie struct {
+	// Name is the cookie name
+	Name string `json:"name"`
+
+	// Value is the cookie value
+	Value string `json:"value"`
+
+	// Domain is the host (host-only cookies) or domain the cookie is sent to
+	Domain string `json:"domain"`
+
+	// HostOnly restricts the cookie to exactly Domain, without subdomains
+	HostOnly bool `json:"host_only"`
+
+	// Path is the path prefix the cookie is sent for
+	Path string `json:"path"`
+
+	// Expires is the expiry time; zero for session cookies
+	Expires time.Time `json:"expires,omitempty"`
+
+	// Secure limits the cookie to HTTPS requests
+	Secure bool `json:"secure"`
+
+	// HttpOnly mirrors the HttpOnly attribute of the cookie
+	HttpOnly bool `json:"http_only"`
+}
+
+// expired reports whether the cookie has expired at the given time.
+//
+// Parameters:
+//   - now: Reference time
+//
+// Returns:
+//   - bool: True if the cookie has an expiry in the past
+func (c StoredCookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// key returns the identity of the cookie within a jar.
+//
+// Returns:
+//   - string: Key made of domain, path and name
+func (c StoredCookie) key() string {
+	return c.Domain + ";" + c.Path + ";" + c.Name
+}
+
+// CookieJar is a thread-safe cookie jar that can be persisted to a JSON file.
+// Cookie matching is delegated to net/http/cookiejar; the jar additionally keeps
+// a copy of every stored cookie so the contents can be listed, cleared and saved.
+type CookieJar struct {
+	// jar performs cookie matching for outgoing requests
+	jar *cookiejar.Jar
+
+	// entries holds the stored cookies keyed by domain, path and name
+	entries map[string]StoredCookie
+
+	// file is the path the jar is persisted to, empty for in-memory jars
+	file string
+
+	// mu guards entries and jar replacement
+	mu sync.Mutex
+}
+
+// NewCookieJar creates an empty in-memory cookie jar.
+//
+// Returns:
+//   - *CookieJar: Initialized cookie jar
+func NewCookieJar() *CookieJar {
+	jar, _ := cookiejar.New(nil)
+	return &CookieJar{
+		jar:     jar,
+		entries: make(map[string]StoredCookie),
+	}
+}
+
+// LoadCookieJar creates a cookie jar persisted to the given file.
+// Cookies stored in the file are loaded; a missing file yields an empty jar.
+// Expired cookies are dropped while loading.
+//
+// Parameters:
+//   - file: Path of the JSON cookie file
+//
+// Returns:
+//   - *CookieJar: Cookie jar bound to the file
+//   - error: Error if the file cannot be read or parsed
+func LoadCookieJar(file string) (*CookieJar, error) {
+	jar := NewCookieJar()
+	jar.file = file
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return jar, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cookie jar: %w", err)
+	}
+
+	var cookies []StoredCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, fmt.Errorf("failed to parse cookie jar %s: %w", file, err)
+	}
+
+	now := time.Now()
+	for _, cookie := range cookies {
+		if !cookie.expired(now) {
+			jar.restore(cookie)
+		}
+	}
+	return jar, nil
+}
+
+// WithCookieJar sets the cookie jar used by the client.
+// Cookies received in responses are stored in the jar and sent with later requests.
+//
+// Parameters:
+//   - jar: Cookie jar to use
+//
+// Returns:
+//   - ClientOption: Option function that sets the cookie jar
+func WithCookieJar(jar http.CookieJar) ClientOption {
+	return func(client *DefaultClient) {
+		client.client.Jar = jar
+	}
+}
+
+// SetCookies stores the cookies received in a response from the given URL.
+// It implements http.CookieJar.
+//
+// Parameters:
+//   - u: URL of the response
+//   - cookies: Cookies from the Set-Cookie headers
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.jar.SetCookies(u, cookies)
+
+	now := time.Now()
+	host := strings.ToLower(u.Hostname())
+	for _, cookie := range cookies {
+		stored := newStoredCookie(u, cookie, now)
+		// Skip cookies the jar rejects for setting a foreign domain
+		if !stored.HostOnly && host != stored.Domain && !strings.HasSuffix(host, "."+stored.Domain) {
+			continue
+		}
+		if stored.expired(now) {
+			delete(j.entries, stored.key())
+			continue
+		}
+		j.entries[stored.key()] = stored
+	}
+}
+
+// Cookies returns the cookies to send in a request for the given URL.
+// It implements http.CookieJar.
+//
+// Parameters:
+//   - u: URL of the request
+//
+// Returns:
+//   - []*http.Cookie: Cookies to send
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.jar.Cookies(u)
+}
+
+// All returns the unexpired cookies in the jar, sorted by domain, path and name.
+//
+// Returns:
+//   - []StoredCookie: Stored cookies
+func (j *CookieJar) All() []StoredCookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	cookies := make([]StoredCookie, 0, len(j.entries))
+	for _, cookie := range j.entries {
+		if !cookie.expired(now) {
+			cookies = append(cookies, cookie)
+		}
+	}
+
+	sort.Slice(cookies, func(a, b int) bool {
+		return cookies[a].key() < cookies[b].key()
+	})
+	return cookies
+}
+
+// Clear removes cookies from the jar.
+// If a domain is given, only cookies for that domain and its subdomains are removed.
+//
+// Parameters:
+//   - domain: Domain to clear, or empty to clear all cookies
+//
+// Returns:
+//   - int: Number of cookies removed
+func (j *CookieJar) Clear(domain string) int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	removed := 0
+	for key, cookie := range j.entries {
+		if domain == "" || cookie.Domain == domain || strings.HasSuffix(cookie.Domain, "."+domain) {
+			delete(j.entries, key)
+			removed++
+		}
+	}
+
+	// Rebuild the matching jar from the remaining cookies
+	j.jar, _ = cookiejar.New(nil)
+	for _, cookie := range j.entries {
+		j.jar.SetCookies(cookie.url(), []*http.Cookie{cookie.httpCookie()})
+	}
+
+	return removed
+}
+
+// Save writes the unexpired cookies to the jar's file.
+// In-memory jars are not saved.
+//
+// Returns:
+//   - error: Error if the file cannot be written
+func (j *CookieJar) Save() error {
+	if j.file == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(j.All(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cookie jar: %w", err)
+	}
+	if err := os.WriteFile(j.file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cookie jar: %w", err)
+	}
+	return nil
+}
+
+// restore adds a persisted cookie back into the jar.
+//
+// Parameters:
+//   - cookie: Persisted cookie
+func (j *CookieJar) restore(cookie StoredCookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.jar.SetCookies(cookie.url(), []*http.Cookie{cookie.httpCookie()})
+	j.entries[cookie.key()] = cookie
+}
+
+// newStoredCookie resolves the scope of a cookie received from the given URL
+// the same way net/http/cookiejar does.
+//
+// Parameters:
+//   - u: URL of the response that set the cookie
+//   - cookie: Received cookie
+//   - now: Time of receipt, used to resolve Max-Age
+//
+// Returns:
+//   - StoredCookie: Cookie with its effective domain, path and expiry
+func newStoredCookie(u *url.URL, cookie *http.Cookie, now time.Time) StoredCookie {
+	stored := StoredCookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Domain:   strings.ToLower(u.Hostname()),
+		HostOnly: true,
+		Path:     cookie.Path,
+		Secure:   cookie.Secure,
+		HttpOnly: cookie.HttpOnly,
+	}
+
+	if domain := strings.ToLower(strings.TrimPrefix(cookie.Domain, ".")); domain != "" {
+		stored.Domain = domain
+		stored.HostOnly = false
+	}
+	if stored.Path == "" || !strings.HasPrefix(stored.Path, "/") {
+		stored.Path = defaultCookiePath(u.Path)
+	}
+
+	switch {
+	case cookie.MaxAge < 0:
+		stored.Expires = now.Add(-time.Second)
+	case cookie.MaxAge > 0:
+		stored.Expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+	case !cookie.Expires.IsZero():
+		stored.Expires = cookie.Expires
+	}
+	if !stored.Expires.IsZero() {
+		// Cookie expiry has second precision; normalize so persisted values compare equal
+		stored.Expires = stored.Expires.UTC().Truncate(time.Second)
+	}
+
+	return stored
+}
+
+// defaultCookiePath returns the default cookie path for a request path (RFC 6265 section 5.1.4).
+//
+// Parameters:
+//   - requestPath: Path of the request URL
+//
+// Returns:
+//   - string: Default cookie path
+func defaultCookiePath(requestPath string) string {
+	if requestPath == "" || requestPath[0] != '/' {
+		return "/"
+	}
+	dir := path.Dir(requestPath)
+	if dir == "." {
+		return "/"
+	}
+	return dir
+}
+
+// url returns a URL within the cookie's scope, used to restore it into a jar.
+//
+// Returns:
+//   - *url.URL: URL matching the cookie domain and path
+func (c StoredCookie) url() *url.URL {
+	scheme := "http"
+	if c.Secure {
+		scheme = "https"
+	}
+	return &url.URL{Scheme: scheme, Host: c.Domain, Path: c.Path}
+}
+
+// httpCookie converts the stored cookie into an http.Cookie for a jar.
+//
+// Returns:
+//   - *http.Cookie: Equivalent cookie
+func (c StoredCookie) httpCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Expires:  c.Expires,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+	}
+	if !c.HostOnly {
+		cookie.Domain = c.Domain
+	}
+	return cookie
+}
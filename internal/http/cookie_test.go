@@ -0,0 +1,139 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSessionServer starts a server that sets a session cookie on /login,
+// deletes it on /logout and reports the received cookie on /me.
+func newSessionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", Path: "/", MaxAge: 3600})
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Path: "/", MaxAge: -1})
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err == nil {
+			w.Header().Set("X-Session", cookie.Value)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientCookieJar(t *testing.T) {
+	server := newSessionServer(t)
+	jar := NewCookieJar()
+	client := NewClient(WithCookieJar(jar))
+
+	_, err := client.Do(NewRequest(server.URL+"/login", MethodPost))
+	require.NoError(t, err)
+
+	resp, err := client.Do(NewRequest(server.URL+"/me", MethodGet))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", resp.Header.Get("X-Session"))
+
+	cookies := jar.All()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "session", cookies[0].Name)
+	assert.Equal(t, "127.0.0.1", cookies[0].Domain)
+	assert.True(t, cookies[0].HostOnly)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Expires.IsZero())
+	assert.Equal(t, "theme", cookies[1].Name)
+	assert.False(t, cookies[1].Expires.IsZero())
+
+	_, err = client.Do(NewRequest(server.URL+"/logout", MethodPost))
+	require.NoError(t, err)
+
+	resp, err = client.Do(NewRequest(server.URL+"/me", MethodGet))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get("X-Session"))
+	assert.Len(t, jar.All(), 1)
+}
+
+func TestCookieJar_Persistence(t *testing.T) {
+	server := newSessionServer(t)
+	file := filepath.Join(t.TempDir(), "session.json")
+
+	jar, err := LoadCookieJar(file)
+	require.NoError(t, err)
+	assert.Empty(t, jar.All())
+
+	_, err = NewClient(WithCookieJar(jar)).Do(NewRequest(server.URL+"/login", MethodPost))
+	require.NoError(t, err)
+	require.NoError(t, jar.Save())
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reloaded, err := LoadCookieJar(file)
+	require.NoError(t, err)
+	assert.Equal(t, jar.All(), reloaded.All())
+
+	resp, err := NewClient(WithCookieJar(reloaded)).Do(NewRequest(server.URL+"/me", MethodGet))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", resp.Header.Get("X-Session"))
+}
+
+func TestCookieJar_InvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(file, []byte("not json"), 0600))
+
+	_, err := LoadCookieJar(file)
+
+	assert.Error(t, err)
+}
+
+func TestCookieJar_DomainScope(t *testing.T) {
+	jar := NewCookieJar()
+	origin, _ := url.Parse("https://api.example.com/v1/login")
+
+	jar.SetCookies(origin, []*http.Cookie{
+		{Name: "shared", Value: "1", Domain: ".example.com", Path: "/", Secure: true},
+		{Name: "local", Value: "2"},
+		{Name: "foreign", Value: "3", Domain: "other.com"},
+		{Name: "old", Value: "4", Expires: time.Now().Add(-time.Hour)},
+	})
+
+	cookies := jar.All()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, StoredCookie{Name: "local", Value: "2", Domain: "api.example.com", HostOnly: true, Path: "/v1"}, cookies[0])
+	assert.Equal(t, "example.com", cookies[1].Domain)
+	assert.False(t, cookies[1].HostOnly)
+
+	other, _ := url.Parse("https://www.example.com/")
+	sent := jar.Cookies(other)
+	require.Len(t, sent, 1)
+	assert.Equal(t, "shared", sent[0].Name)
+
+	t.Run("clear by domain", func(t *testing.T) {
+		assert.Equal(t, 1, jar.Clear("api.example.com"))
+		assert.Len(t, jar.All(), 1)
+		assert.Len(t, jar.Cookies(other), 1)
+
+		assert.Equal(t, 1, jar.Clear(""))
+		assert.Empty(t, jar.All())
+		assert.Empty(t, jar.Cookies(other))
+	})
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// digestChallenge holds the parameters of a WWW-Authenticate Digest challenge.
+type digestChallenge struct {
+	// realm is the protection space
+	realm string
+
+	// nonce is the server-specified nonce
+	nonce string
+
+	// opaque is echoed back unchanged
+	opaque string
+
+	// algorithm is the hash algorithm (MD5, MD5-sess, SHA-256, SHA-256-sess)
+	algorithm string
+
+	// qop is the selected quality of protection ("auth" or empty)
+	qop string
+}
+
+// parseDigestChallenge parses a WWW-Authenticate header value with the Digest scheme.
+//
+// Parameters:
+//   - header: WWW-Authenticate header value
+//
+// Returns:
+//   - *digestChallenge: Parsed challenge
+//   - bool: False if the header is not a Digest challenge
+func parseDigestChallenge(header string) (*digestChallenge, bool) {
+	const prefix = "digest "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return nil, false
+	}
+
+	params := parseAuthParams(header[len(prefix):])
+	challenge := &digestChallenge{
+		realm:     params["realm"],
+		nonce:     params["nonce"],
+		opaque:    params["opaque"],
+		algorithm: params["algorithm"],
+	}
+	if challenge.algorithm == "" {
+		challenge.algorithm = "MD5"
+	}
+
+	// Prefer "auth" when the server offers a list of qop values
+	for _, qop := range strings.Split(params["qop"], ",") {
+		if strings.TrimSpace(qop) == "auth" {
+			challenge.qop = "auth"
+			break
+		}
+	}
+
+	return challenge, challenge.nonce != ""
+}
+
+// parseAuthParams parses comma-separated key=value pairs, honouring quoted values.
+//
+// Parameters:
+//   - input: Parameter list from an authentication header
+//
+// Returns:
+//   - map[string]string: Map of lowercased keys to values
+func parseAuthParams(input string) map[string]string {
+	params := make(map[string]string)
+	for len(input) > 0 {
+		input = strings.TrimLeft(input, " ,")
+		eq := strings.IndexByte(input, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(input[:eq]))
+		input = strings.TrimLeft(input[eq+1:], " ")
+
+		var value string
+		if strings.HasPrefix(input, `"`) {
+			var buffer strings.Builder
+			i := 1
+			for ; i < len(input) && input[i] != '"'; i++ {
+				if input[i] == '\\' && i+1 < len(input) {
+					i++
+				}
+				buffer.WriteByte(input[i])
+			}
+			if i < len(input) {
+				i++ // skip closing quote
+			}
+			value = buffer.String()
+			input = input[i:]
+		} else {
+			end := strings.IndexByte(input, ',')
+			if end < 0 {
+				end = len(input)
+			}
+			value = strings.TrimSpace(input[:end])
+			input = input[end:]
+		}
+		params[key] = value
+	}
+	return params
+}
+
+// authorization computes the Authorization header value answering the challenge.
+//
+// Parameters:
+//   - creds: Credentials to authenticate with
+//   - method: HTTP method of the request
+//   - uri: Request URI (path and query)
+//
+// Returns:
+//   - string: Authorization header value
+//   - error: Error if the algorithm is unsupported
+func (c *digestChallenge) authorization(creds *Credentials, method, uri string) (string, error) {
+	newHash, err := digestHashFunc(c.algorithm)
+	if err != nil {
+		return "", err
+	}
+	h := func(data string) string {
+		hasher := newHash()
+		hasher.Write([]byte(data))
+		return hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	cnonce, err := newClientNonce()
+	if err != nil {
+		return "", err
+	}
+	const nc = "00000001"
+
+	ha1 := h(creds.Username + ":" + c.realm + ":" + creds.Password)
+	if strings.HasSuffix(strings.ToLower(c.algorithm), "-sess") {
+		ha1 = h(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+
+	var response string
+	if c.qop != "" {
+		response = h(strings.Join([]string{ha1, c.nonce, nc, cnonce, c.qop, ha2}, ":"))
+	} else {
+		response = h(ha1 + ":" + c.nonce + ":" + ha2)
+	}
+
+	var buffer strings.Builder
+	fmt.Fprintf(&buffer, `Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=%s, response="%s"`,
+		creds.Username, c.realm, c.nonce, uri, c.algorithm, response)
+	if c.opaque != "" {
+		fmt.Fprintf(&buffer, `, opaque="%s"`, c.opaque)
+	}
+	if c.qop != "" {
+		fmt.Fprintf(&buffer, `, qop=%s, nc=%s, cnonce="%s"`, c.qop, nc, cnonce)
+	}
+
+	return buffer.String(), nil
+}
+
+// digestHashFunc returns the hash constructor for a digest algorithm name.
+//
+// Parameters:
+//   - algorithm: Algorithm name from the challenge
+//
+// Returns:
+//   - func() hash.Hash: Hash constructor
+//   - error: Error if the algorithm is unsupported
+func digestHashFunc(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(strings.TrimSuffix(strings.ToLower(algorithm), "-sess")) {
+	case "MD5":
+		return md5.New, nil
+	case "SHA-256":
+		return sha256.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported digest algorithm: %s", algorithm)
+	}
+}
+
+// newClientNonce generates a random client nonce.
+//
+// Returns:
+//   - string: Hex-encoded random nonce
+//   - error: Error if random bytes could not be read
+func newClientNonce() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDigestChallenge(t *testing.T) {
+	challenge, ok := parseDigestChallenge(`Digest realm="test@example.com", qop="auth,auth-int", nonce="abc123", opaque="xyz"`)
+
+	assert.True(t, ok)
+	assert.Equal(t, "test@example.com", challenge.realm)
+	assert.Equal(t, "abc123", challenge.nonce)
+	assert.Equal(t, "xyz", challenge.opaque)
+	assert.Equal(t, "auth", challenge.qop)
+	assert.Equal(t, "MD5", challenge.algorithm)
+
+	_, ok = parseDigestChallenge(`Basic realm="test"`)
+	assert.False(t, ok)
+}
+
+func TestDigestAuthRoundTrip(t *testing.T) {
+	const (
+		realm    = "jak"
+		nonce    = "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+		username = "user"
+		password = "secret"
+	)
+	md5hex := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Digest ") {
+			w.Header().Set("WWW-Authenticate", `Digest realm="`+realm+`", qop="auth", nonce="`+nonce+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := parseAuthParams(strings.TrimPrefix(header, "Digest "))
+		ha1 := md5hex(username + ":" + realm + ":" + password)
+		ha2 := md5hex(r.Method + ":" + params["uri"])
+		expected := md5hex(strings.Join([]string{ha1, nonce, params["nc"], params["cnonce"], params["qop"], ha2}, ":"))
+		if params["response"] != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("valid credentials", func(t *testing.T) {
+		attempts = 0
+		req := NewRequest(server.URL+"/protected?x=1", MethodGet, WithDigestAuth(username, password))
+
+		resp, err := NewClient().Do(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		req := NewRequest(server.URL+"/protected", MethodGet, WithDigestAuth(username, "wrong"))
+
+		resp, err := NewClient().Do(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("without credentials", func(t *testing.T) {
+		attempts = 0
+		req := NewRequest(server.URL+"/protected", MethodGet)
+
+		resp, err := NewClient().Do(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -0,0 +1,369 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2 grant type constants define the supported token grants.
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// tokenTransportKey is the context key carrying the transport used for token requests.
+type tokenTransportKey struct{}
+
+// tokenExpiryDelta is subtracted from the token lifetime so that tokens are
+// refreshed slightly before they actually expire.
+const tokenExpiryDelta = 10 * time.Second
+
+// TokenSource defines an interface for supplying access tokens.
+// Implementations cache tokens and obtain new ones when needed.
+type TokenSource interface {
+	// Token returns a valid access token, fetching a new one if necessary.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control
+	//
+	// Returns:
+	//   - string: Access token
+	//   - error: Any error encountered while obtaining the token
+	Token(ctx context.Context) (string, error)
+
+	// Invalidate discards the cached access token, e.g. after a 401 response.
+	// A refresh token, if any, is kept so it can be used for the next request.
+	Invalidate()
+}
+
+// OAuth2Config holds the settings for obtaining OAuth2 access tokens.
+type OAuth2Config struct {
+	// TokenURL is the token endpoint of the authorization server
+	TokenURL string
+
+	// ClientID is the OAuth2 client identifier
+	ClientID string
+
+	// ClientSecret is the OAuth2 client secret
+	ClientSecret string
+
+	// GrantType is the grant used to obtain tokens (client_credentials, password, refresh_token)
+	GrantType string
+
+	// Username is the resource owner name for the password grant
+	Username string
+
+	// Password is the resource owner password for the password grant
+	Password string
+
+	// RefreshToken is the initial refresh token for the refresh_token grant
+	RefreshToken string
+
+	// Scopes is the list of requested scopes
+	Scopes []string
+
+	// CacheFile is an optional path where tokens are persisted between runs;
+	// tokens of different clients and scopes are stored under separate keys
+	CacheFile string
+}
+
+// oauth2Token represents a token obtained from the token endpoint.
+type oauth2Token struct {
+	// AccessToken is the bearer token sent with requests
+	AccessToken string `json:"access_token"`
+
+	// RefreshToken is used to obtain a new access token
+	RefreshToken string `json:"refresh_token,omitempty"`
+
+	// Expiry is the time the access token expires (zero means no expiry)
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// valid reports whether the token can still be used.
+//
+// Returns:
+//   - bool: True if the access token is present and not about to expire
+func (t *oauth2Token) valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(t.Expiry)
+}
+
+// OAuth2TokenSource implements TokenSource using an OAuth2 token endpoint.
+// Tokens are cached in memory and optionally on disk; it is safe for concurrent use.
+type OAuth2TokenSource struct {
+	// config holds the OAuth2 settings
+	config OAuth2Config
+
+	// client performs token endpoint requests without a transport from the context
+	client *http.Client
+
+	// mu guards token and loaded
+	mu sync.Mutex
+
+	// token is the cached token
+	token *oauth2Token
+
+	// loaded records whether the disk cache has been read
+	loaded bool
+}
+
+// NewOAuth2TokenSource creates a new token source for the given settings.
+// If no grant type is configured, client_credentials is used.
+//
+// Parameters:
+//   - config: OAuth2 settings
+//
+// Returns:
+//   - *OAuth2TokenSource: Initialized token source
+func NewOAuth2TokenSource(config OAuth2Config) *OAuth2TokenSource {
+	if config.GrantType == "" {
+		config.GrantType = GrantClientCredentials
+	}
+	return &OAuth2TokenSource{
+		config: config,
+		client: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// Token returns a cached access token or obtains a new one.
+// An expired token is refreshed with its refresh token when available,
+// falling back to the configured grant if refreshing fails.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//
+// Returns:
+//   - string: Access token
+//   - error: Any error encountered while obtaining the token
+func (ts *OAuth2TokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if !ts.loaded {
+		ts.token = ts.loadCache()
+		ts.loaded = true
+	}
+	if ts.token.valid() {
+		return ts.token.AccessToken, nil
+	}
+
+	token, err := ts.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	ts.token = token
+	ts.saveCache()
+	return token.AccessToken, nil
+}
+
+// Invalidate discards the cached access token but keeps the refresh token.
+func (ts *OAuth2TokenSource) Invalidate() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != nil {
+		ts.token.AccessToken = ""
+	}
+}
+
+// fetch obtains a new token, preferring a refresh token when one is available.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//
+// Returns:
+//   - *oauth2Token: Newly obtained token
+//   - error: Any error encountered while obtaining the token
+func (ts *OAuth2TokenSource) fetch(ctx context.Context) (*oauth2Token, error) {
+	refreshToken := ts.config.RefreshToken
+	if ts.token != nil && ts.token.RefreshToken != "" {
+		refreshToken = ts.token.RefreshToken
+	}
+
+	if refreshToken != "" {
+		form := url.Values{"grant_type": {GrantRefreshToken}, "refresh_token": {refreshToken}}
+		token, err := ts.requestToken(ctx, form)
+		if err == nil || ts.config.GrantType == GrantRefreshToken {
+			return token, err
+		}
+	}
+
+	form := url.Values{"grant_type": {ts.config.GrantType}}
+	switch ts.config.GrantType {
+	case GrantClientCredentials:
+	case GrantPassword:
+		form.Set("username", ts.config.Username)
+		form.Set("password", ts.config.Password)
+	case GrantRefreshToken:
+		return nil, fmt.Errorf("refresh_token grant requires a refresh token")
+	default:
+		return nil, fmt.Errorf("unsupported OAuth2 grant type: %s", ts.config.GrantType)
+	}
+
+	return ts.requestToken(ctx, form)
+}
+
+// requestToken posts a grant request to the token endpoint and parses the response.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - form: Grant-specific form parameters
+//
+// Returns:
+//   - *oauth2Token: Token from the response
+//   - error: Any error encountered during the request or parsing
+func (ts *OAuth2TokenSource) requestToken(ctx context.Context, form url.Values) (*oauth2Token, error) {
+	if ts.config.ClientID != "" {
+		form.Set("client_id", ts.config.ClientID)
+	}
+	if ts.config.ClientSecret != "" {
+		form.Set("client_secret", ts.config.ClientSecret)
+	}
+	if len(ts.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(ts.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeFormURLEncoded)
+	req.Header.Set("Accept", ContentTypeJSON)
+
+	// Use the transport of the client sending the request, so TLS and proxy settings apply
+	client := ts.client
+	if transport, ok := ctx.Value(tokenTransportKey{}).(http.RoundTripper); ok && transport != nil {
+		client = &http.Client{Timeout: ts.client.Timeout, Transport: transport}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+
+	token := &oauth2Token{
+		AccessToken:  payload.AccessToken,
+		RefreshToken: payload.RefreshToken,
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = form.Get("refresh_token")
+	}
+	if payload.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// cacheKey identifies the tokens of this source in the cache file.
+//
+// Returns:
+//   - string: Token URL, client ID, username and scopes of the source
+func (ts *OAuth2TokenSource) cacheKey() string {
+	return strings.Join([]string{
+		ts.config.TokenURL, ts.config.ClientID, ts.config.Username, strings.Join(ts.config.Scopes, " "),
+	}, "|")
+}
+
+// readCache reads all tokens stored in the cache file.
+// Missing or unreadable cache files are treated as empty.
+//
+// Returns:
+//   - map[string]*oauth2Token: Cached tokens by cache key
+func (ts *OAuth2TokenSource) readCache() map[string]*oauth2Token {
+	tokens := make(map[string]*oauth2Token)
+
+	data, err := os.ReadFile(ts.config.CacheFile)
+	if err != nil {
+		return tokens
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return make(map[string]*oauth2Token)
+	}
+	return tokens
+}
+
+// loadCache reads a previously stored token from the cache file.
+//
+// Returns:
+//   - *oauth2Token: Cached token for the settings of this source, or nil if none is available
+func (ts *OAuth2TokenSource) loadCache() *oauth2Token {
+	if ts.config.CacheFile == "" {
+		return nil
+	}
+	return ts.readCache()[ts.cacheKey()]
+}
+
+// saveCache writes the current token to the cache file, keeping the tokens of other settings.
+// Failures are ignored because the cache is only an optimization.
+func (ts *OAuth2TokenSource) saveCache() {
+	if ts.config.CacheFile == "" || ts.token == nil {
+		return
+	}
+
+	tokens := ts.readCache()
+	tokens[ts.cacheKey()] = ts.token
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(ts.config.CacheFile, data, 0600)
+}
+
+// withTokenTransport stores the transport used for token requests in the context.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - transport: Transport of the client sending the request
+//
+// Returns:
+//   - context.Context: Context carrying the transport
+func withTokenTransport(ctx context.Context, transport http.RoundTripper) context.Context {
+	return context.WithValue(ctx, tokenTransportKey{}, transport)
+}
+
+// WithTokenSource sets a token source used to authorize the request with a bearer token.
+//
+// Parameters:
+//   - source: Token source supplying access tokens
+//
+// Returns:
+//   - RequestOption: Option function that sets the token source
+func WithTokenSource(source TokenSource) RequestOption {
+	return func(req *Request) {
+		req.TokenSource = source
+	}
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenServer is a stand-in OAuth2 authorization and resource server.
+type tokenServer struct {
+	mu          sync.Mutex
+	issued      int
+	grants      []string
+	validTokens map[string]bool
+}
+
+func newTokenServer() (*tokenServer, *httptest.Server) {
+	ts := &tokenServer{validTokens: make(map[string]bool)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+
+		_ = r.ParseForm()
+		grant := r.PostForm.Get("grant_type")
+		ts.grants = append(ts.grants, grant)
+		if grant == GrantPassword && r.PostForm.Get("password") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if grant == GrantClientCredentials && r.PostForm.Get("client_secret") != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		ts.issued++
+		token := fmt.Sprintf("token-%d", ts.issued)
+		ts.validTokens[token] = true
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  token,
+			"refresh_token": "refresh-" + token,
+			"expires_in":    3600,
+		})
+	})
+	mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+
+		auth := r.Header.Get("Authorization")
+		if len(auth) < 7 || !ts.validTokens[auth[7:]] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return ts, httptest.NewServer(mux)
+}
+
+func (ts *tokenServer) revokeAll() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.validTokens = make(map[string]bool)
+}
+
+func TestOAuth2TokenSource_ClientCredentials(t *testing.T) {
+	state, server := newTokenServer()
+	defer server.Close()
+
+	source := NewOAuth2TokenSource(OAuth2Config{
+		TokenURL:     server.URL + "/token",
+		ClientID:     "jak",
+		ClientSecret: "s3cr3t",
+	})
+	client := NewClient()
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Do(NewRequest(server.URL+"/resource", MethodGet, WithTokenSource(source)))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, 1, state.issued, "token should be cached between requests")
+
+	// A rejected token is refreshed once with the refresh token
+	state.revokeAll()
+	resp, err := client.Do(NewRequest(server.URL+"/resource", MethodGet, WithTokenSource(source)))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{GrantClientCredentials, GrantRefreshToken}, state.grants)
+}
+
+func TestOAuth2TokenSource_PasswordGrant(t *testing.T) {
+	_, server := newTokenServer()
+	defer server.Close()
+
+	source := NewOAuth2TokenSource(OAuth2Config{
+		TokenURL:  server.URL + "/token",
+		GrantType: GrantPassword,
+		Username:  "user",
+		Password:  "secret",
+	})
+	token, err := source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	badSource := NewOAuth2TokenSource(OAuth2Config{
+		TokenURL:  server.URL + "/token",
+		GrantType: GrantPassword,
+		Username:  "user",
+		Password:  "wrong",
+	})
+	_, err = badSource.Token(context.Background())
+	assert.Error(t, err)
+}
+
+func TestOAuth2TokenSource_DiskCache(t *testing.T) {
+	state, server := newTokenServer()
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "token.json")
+	config := OAuth2Config{
+		TokenURL:     server.URL + "/token",
+		ClientID:     "jak",
+		ClientSecret: "s3cr3t",
+		CacheFile:    cacheFile,
+	}
+
+	token, err := NewOAuth2TokenSource(config).Token(context.Background())
+	assert.NoError(t, err)
+	assert.FileExists(t, cacheFile)
+
+	// A new source (e.g. the next run) reuses the cached token
+	cached, err := NewOAuth2TokenSource(config).Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, token, cached)
+	assert.Equal(t, 1, state.issued)
+
+	// An expired cached token is refreshed
+	source := NewOAuth2TokenSource(config)
+	expired, _ := json.Marshal(map[string]oauth2Token{
+		source.cacheKey(): {AccessToken: "old", RefreshToken: "r", Expiry: time.Now().Add(-time.Minute)},
+	})
+	assert.NoError(t, os.WriteFile(cacheFile, expired, 0600))
+	refreshed, err := source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old", refreshed)
+	assert.Equal(t, GrantRefreshToken, state.grants[len(state.grants)-1])
+}
+
+func TestOAuth2TokenSource_DiskCacheKey(t *testing.T) {
+	state, server := newTokenServer()
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "token.json")
+	config := OAuth2Config{
+		TokenURL:     server.URL + "/token",
+		ClientID:     "jak",
+		ClientSecret: "s3cr3t",
+		Scopes:       []string{"read"},
+		CacheFile:    cacheFile,
+	}
+	readToken, err := NewOAuth2TokenSource(config).Token(context.Background())
+	assert.NoError(t, err)
+
+	// Another client or scope sharing the cache file gets its own token
+	config.Scopes = []string{"read", "write"}
+	writeToken, err := NewOAuth2TokenSource(config).Token(context.Background())
+	assert.NoError(t, err)
+	assert.NotEqual(t, readToken, writeToken)
+
+	config.ClientID = "other"
+	otherToken, err := NewOAuth2TokenSource(config).Token(context.Background())
+	assert.NoError(t, err)
+	assert.NotEqual(t, writeToken, otherToken)
+	assert.Equal(t, 3, state.issued)
+
+	// Each of them is cached
+	config.ClientID, config.Scopes = "jak", []string{"read"}
+	cached, err := NewOAuth2TokenSource(config).Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, readToken, cached)
+	assert.Equal(t, 3, state.issued)
+}
+
+func TestOAuth2TokenSource_ClientTransport(t *testing.T) {
+	state, plain := newTokenServer()
+	plain.Close()
+	server := httptest.NewTLSServer(plain.Config.Handler)
+	defer server.Close()
+
+	// The token endpoint is only trusted by the transport of the client
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	client := NewClient(WithTLSConfig(&tls.Config{RootCAs: roots}))
+
+	source := NewOAuth2TokenSource(OAuth2Config{
+		TokenURL:     server.URL + "/token",
+		ClientID:     "jak",
+		ClientSecret: "s3cr3t",
+	})
+	resp, err := client.Do(NewRequest(server.URL+"/resource", MethodGet, WithTokenSource(source)))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, state.issued)
+}
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ProxyDirect is the proxy value that disables proxying for a request.
+const ProxyDirect = "direct"
+
+// proxyContextKey is the context key carrying a per-request proxy override.
+type proxyContextKey struct{}
+
+// ProxyOptions holds the proxy settings of a client.
+type ProxyOptions struct {
+	// URL is the proxy URL (http, https, socks5 or socks5h scheme); credentials
+	// may be given as user info. ProxyDirect disables proxying and an empty
+	// value falls back to the environment variables.
+	URL string
+
+	// NoProxy is a comma-separated list of hosts, domains, IPs or CIDR ranges that bypass
+	// the proxy. Empty falls back to the NO_PROXY environment variable.
+	NoProxy string
+}
+
+// ParseProxyURL parses and validates a proxy URL.
+// A URL without scheme is treated as an HTTP proxy.
+//
+// Parameters:
+//   - raw: Proxy URL string
+//
+// Returns:
+//   - *url.URL: Parsed proxy URL
+//   - error: Error if the URL is invalid or uses an unsupported scheme
+func ParseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme: %s", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL must include a host: %s", raw)
+	}
+
+	return proxyURL, nil
+}
+
+// WithProxy configures the proxy used by the client.
+// The proxy URL must have been validated with ParseProxyURL.
+//
+// Parameters:
+//   - opts: Proxy settings
+//
+// Returns:
+//   - ClientOption: Option function that sets the proxy
+func WithProxy(opts ProxyOptions) ClientOption {
+	return func(client *DefaultClient) {
+		client.transport().Proxy = newProxySelector(opts).proxyFor
+	}
+}
+
+// WithRequestProxy overrides the client proxy for a single request.
+// Use ProxyDirect to bypass any proxy.
+//
+// Parameters:
+//   - proxy: Proxy URL or ProxyDirect
+//
+// Returns:
+//   - RequestOption: Option function that sets the proxy override
+func WithRequestProxy(proxy string) RequestOption {
+	return func(req *Request) {
+		req.Proxy = proxy
+	}
+}
+
+// withProxyOverride stores a per-request proxy override in the context.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - proxy: Proxy URL or ProxyDirect
+//
+// Returns:
+//   - context.Context: Context carrying the override
+func withProxyOverride(ctx context.Context, proxy string) context.Context {
+	return context.WithValue(ctx, proxyContextKey{}, proxy)
+}
+
+// proxySelector decides which proxy, if any, a request is sent through.
+type proxySelector struct {
+	// proxyURL is the configured proxy, nil to use the environment
+	proxyURL *url.URL
+
+	// direct disables proxying unless a request overrides it
+	direct bool
+
+	// noProxy lists the hosts bypassing the configured proxy
+	noProxy []string
+}
+
+// newProxySelector creates a proxy selector from the given options.
+// An invalid proxy URL is ignored, so callers should validate it beforehand.
+//
+// Parameters:
+//   - opts: Proxy settings
+//
+// Returns:
+//   - *proxySelector: Initialized selector
+func newProxySelector(opts ProxyOptions) *proxySelector {
+	selector := &proxySelector{direct: strings.EqualFold(opts.URL, ProxyDirect)}
+	if opts.URL != "" && !selector.direct {
+		selector.proxyURL, _ = ParseProxyURL(opts.URL)
+	}
+
+	noProxy := opts.NoProxy
+	if noProxy == "" {
+		noProxy = os.Getenv("NO_PROXY")
+		if noProxy == "" {
+			noProxy = os.Getenv("no_proxy")
+		}
+	}
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			selector.noProxy = append(selector.noProxy, entry)
+		}
+	}
+
+	return selector
+}
+
+// proxyFor returns the proxy URL for a request; it is used as http.Transport.Proxy.
+// A per-request override wins, then the configured proxy, and finally the standard
+// proxy environment variables; the latter two honour the no-proxy list.
+//
+// Parameters:
+//   - req: Outgoing request
+//
+// Returns:
+//   - *url.URL: Proxy URL, or nil for a direct connection
+//   - error: Error if the per-request override is invalid
+func (s *proxySelector) proxyFor(req *http.Request) (*url.URL, error) {
+	if override, ok := req.Context().Value(proxyContextKey{}).(string); ok && override != "" {
+		if strings.EqualFold(override, ProxyDirect) {
+			return nil, nil
+		}
+		return ParseProxyURL(override)
+	}
+
+	if s.direct {
+		return nil, nil
+	}
+
+	proxyURL := s.proxyURL
+	if proxyURL == nil {
+		envURL, err := http.ProxyFromEnvironment(req)
+		if err != nil || envURL == nil {
+			return envURL, err
+		}
+		proxyURL = envURL
+	}
+	if s.bypass(req.URL) {
+		return nil, nil
+	}
+	return proxyURL, nil
+}
+
+// bypass reports whether the target URL matches the no-proxy list.
+// Entries may be "*", a host name (matching the host and its subdomains),
+// a ".domain" suffix, an IP address, a CIDR range, or any of these with a port.
+//
+// Parameters:
+//   - target: Request URL
+//
+// Returns:
+//   - bool: True if the request should not use the proxy
+func (s *proxySelector) bypass(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	ip := net.ParseIP(host)
+
+	for _, entry := range s.noProxy {
+		if entry == "*" {
+			return true
+		}
+
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		if _, network, err := net.ParseCIDR(entryHost); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		domain := strings.TrimPrefix(entryHost, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
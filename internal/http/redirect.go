@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxRedirects is the number of redirects followed before a request fails.
+const DefaultMaxRedirects = 10
+
+// RedirectPolicy controls how redirect responses are handled.
+type RedirectPolicy struct {
+	// Follow enables following redirects; when false the 3xx response is returned as-is
+	Follow bool
+
+	// MaxRedirects is the maximum number of redirects to follow; 0 returns the first redirect response
+	MaxRedirects int
+
+	// KeepMethod re-sends the original method and body on 307 and 308 redirects;
+	// when false those redirects are followed with a GET like 303
+	KeepMethod bool
+}
+
+// DefaultRedirectPolicy returns the policy used when none is configured.
+// It follows up to DefaultMaxRedirects redirects and keeps the method on 307/308.
+//
+// Returns:
+//   - RedirectPolicy: Default redirect policy
+func DefaultRedirectPolicy() RedirectPolicy {
+	return RedirectPolicy{
+		Follow:       true,
+		MaxRedirects: DefaultMaxRedirects,
+		KeepMethod:   true,
+	}
+}
+
+// RedirectHop describes a single redirect response received while following redirects.
+type RedirectHop struct {
+	// Method is the method of the request that was redirected
+	Method string
+
+	// URL is the URL of the request that was redirected
+	URL string
+
+	// StatusCode is the status code of the redirect response
+	StatusCode int
+
+	// Header contains the headers of the redirect response
+	Header http.Header
+}
+
+// Location returns the target of the redirect.
+//
+// Returns:
+//   - string: Value of the Location header
+func (hop RedirectHop) Location() string {
+	return hop.Header.Get("Location")
+}
+
+// WithRedirectPolicy sets the default redirect policy of the client.
+//
+// Parameters:
+//   - policy: Redirect policy
+//
+// Returns:
+//   - ClientOption: Option function that sets the redirect policy
+func WithRedirectPolicy(policy RedirectPolicy) ClientOption {
+	return func(client *DefaultClient) {
+		client.redirect = policy
+	}
+}
+
+// WithRequestRedirectPolicy overrides the client redirect policy for a single request.
+//
+// Parameters:
+//   - policy: Redirect policy
+//
+// Returns:
+//   - RequestOption: Option function that sets the redirect policy
+func WithRequestRedirectPolicy(policy RedirectPolicy) RequestOption {
+	return func(req *Request) {
+		req.Redirect = &policy
+	}
+}
+
+// redirectContextKey is the context key carrying the redirect state of a request.
+type redirectContextKey struct{}
+
+// redirectState holds the policy and recorded hops for a single request execution.
+type redirectState struct {
+	// policy is the redirect policy applied to the request
+	policy RedirectPolicy
+
+	// hops records every redirect response in order
+	hops []RedirectHop
+}
+
+// withRedirectState attaches a new redirect state to the context.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - policy: Redirect policy for the request
+//
+// Returns:
+//   - context.Context: Context carrying the state
+func withRedirectState(ctx context.Context, policy RedirectPolicy) context.Context {
+	return context.WithValue(ctx, redirectContextKey{}, &redirectState{policy: policy})
+}
+
+// redirectStateFrom returns the redirect state stored in the context, if any.
+//
+// Parameters:
+//   - ctx: Request context
+//
+// Returns:
+//   - *redirectState: Redirect state, or nil if none is attached
+func redirectStateFrom(ctx context.Context) *redirectState {
+	state, _ := ctx.Value(redirectContextKey{}).(*redirectState)
+	return state
+}
+
+// checkRedirect is used as http.Client.CheckRedirect.
+// It records the redirect response as a hop and enforces the request's redirect policy.
+//
+// Parameters:
+//   - req: Upcoming request for the redirect target
+//   - via: Requests made so far, oldest first
+//
+// Returns:
+//   - error: http.ErrUseLastResponse to stop following, or an error if too many redirects occurred
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	state := redirectStateFrom(req.Context())
+	if state == nil {
+		state = &redirectState{policy: DefaultRedirectPolicy()}
+	}
+
+	// A limit of 0 follows no redirect, so the first redirect response is returned like without follow
+	if !state.policy.Follow || state.policy.MaxRedirects == 0 {
+		return http.ErrUseLastResponse
+	}
+
+	if req.Response != nil {
+		previous := via[len(via)-1]
+		state.hops = append(state.hops, RedirectHop{
+			Method:     previous.Method,
+			URL:        previous.URL.String(),
+			StatusCode: req.Response.StatusCode,
+			Header:     req.Response.Header.Clone(),
+		})
+	}
+
+	if len(via) > state.policy.MaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", state.policy.MaxRedirects)
+	}
+
+	if !state.policy.KeepMethod && req.Response != nil && isMethodPreservingRedirect(req.Response.StatusCode) {
+		downgradeToGet(req)
+	}
+
+	return nil
+}
+
+// isMethodPreservingRedirect reports whether the status code requires the
+// method and body to be kept when following the redirect.
+//
+// Parameters:
+//   - statusCode: Redirect status code
+//
+// Returns:
+//   - bool: True for 307 and 308
+func isMethodPreservingRedirect(statusCode int) bool {
+	return statusCode == http.StatusTemporaryRedirect || statusCode == http.StatusPermanentRedirect
+}
+
+// downgradeToGet turns a redirected request into a bodiless GET, as done for 303.
+// HEAD requests are left unchanged.
+//
+// Parameters:
+//   - req: Redirected request to modify
+func downgradeToGet(req *http.Request) {
+	if strings.EqualFold(req.Method, http.MethodHead) {
+		return
+	}
+	req.Method = http.MethodGet
+	req.Body = nil
+	req.GetBody = nil
+	req.ContentLength = 0
+	req.Header.Del("Content-Type")
+	req.Header.Del("Content-Length")
+}
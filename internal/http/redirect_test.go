@@ -0,0 +1,127 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedirectServer starts a server redirecting /start -> /middle (302) -> /end (307).
+// The /end handler echoes the method and body it received.
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		http.Redirect(w, r, "/middle", http.StatusFound)
+	})
+	mux.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/end", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/end", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Write(body)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/post-307", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/end", http.StatusTemporaryRedirect)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientRecordsRedirects(t *testing.T) {
+	server := newRedirectServer(t)
+
+	resp, err := NewClient().Do(NewRequest(server.URL+"/start", MethodGet))
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, resp.Redirects, 2)
+
+	assert.Equal(t, http.StatusFound, resp.Redirects[0].StatusCode)
+	assert.Equal(t, MethodGet, resp.Redirects[0].Method)
+	assert.Equal(t, server.URL+"/start", resp.Redirects[0].URL)
+	assert.Equal(t, "/middle", resp.Redirects[0].Location())
+	assert.Equal(t, "session=abc", resp.Redirects[0].Header.Get("Set-Cookie"))
+
+	assert.Equal(t, http.StatusTemporaryRedirect, resp.Redirects[1].StatusCode)
+	assert.Equal(t, server.URL+"/middle", resp.Redirects[1].URL)
+	assert.Equal(t, "/end", resp.Redirects[1].Location())
+}
+
+func TestClientRedirectPolicy(t *testing.T) {
+	server := newRedirectServer(t)
+
+	t.Run("no follow returns the redirect response", func(t *testing.T) {
+		client := NewClient(WithRedirectPolicy(RedirectPolicy{Follow: false}))
+
+		resp, err := client.Do(NewRequest(server.URL+"/start", MethodGet))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Equal(t, "/middle", resp.Header.Get("Location"))
+		assert.Empty(t, resp.Redirects)
+	})
+
+	t.Run("max redirects 0 returns the redirect response", func(t *testing.T) {
+		client := NewClient(WithRedirectPolicy(RedirectPolicy{Follow: true, MaxRedirects: 0, KeepMethod: true}))
+
+		resp, err := client.Do(NewRequest(server.URL+"/start", MethodGet))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Equal(t, "/middle", resp.Header.Get("Location"))
+		assert.Empty(t, resp.Redirects)
+	})
+
+	t.Run("too many redirects", func(t *testing.T) {
+		client := NewClient(WithRedirectPolicy(RedirectPolicy{Follow: true, MaxRedirects: 3, KeepMethod: true}))
+
+		_, err := client.Do(NewRequest(server.URL+"/loop", MethodGet))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stopped after 3 redirects")
+	})
+
+	t.Run("307 keeps method and body", func(t *testing.T) {
+		resp, err := NewClient().Do(NewRequest(server.URL+"/post-307", MethodPost, WithJsonBody(`{"id":1}`)))
+
+		require.NoError(t, err)
+		assert.Equal(t, MethodPost, resp.Header.Get("X-Method"))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"id":1}`, string(body))
+	})
+
+	t.Run("307 downgraded to GET when keep method is disabled", func(t *testing.T) {
+		client := NewClient(WithRedirectPolicy(RedirectPolicy{Follow: true, MaxRedirects: 10, KeepMethod: false}))
+
+		resp, err := client.Do(NewRequest(server.URL+"/post-307", MethodPost, WithJsonBody(`{"id":1}`)))
+
+		require.NoError(t, err)
+		assert.Equal(t, MethodGet, resp.Header.Get("X-Method"))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Empty(t, body)
+	})
+
+	t.Run("request policy overrides client policy", func(t *testing.T) {
+		client := NewClient(WithRedirectPolicy(RedirectPolicy{Follow: false}))
+
+		resp, err := client.Do(NewRequest(server.URL+"/start", MethodGet,
+			WithRequestRedirectPolicy(DefaultRedirectPolicy())))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, resp.Redirects, 2)
+	})
+}
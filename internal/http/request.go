@@ -25,6 +25,21 @@ type Request struct {
 
 	// Context provides cancellation and timeout control
 	Context context.Context
+
+	// DigestAuth holds credentials used to answer a Digest authentication challenge
+	DigestAuth *Credentials
+
+	// TokenSource supplies bearer tokens; the token is refreshed once on a 401 response
+	TokenSource TokenSource
+
+	// Signer signs the request after its headers and body are final
+	Signer Signer
+
+	// Proxy overrides the client proxy for this request (URL or ProxyDirect)
+	Proxy string
+
+	// Redirect overrides the client redirect policy for this request
+	Redirect *RedirectPolicy
 }
 
 // NewRequest creates a new HTTP request with the given options.
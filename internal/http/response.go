@@ -18,4 +18,13 @@ type Response struct {
 
 	// StatusCode is the HTTP status code of the response
 	StatusCode int
+
+	// TLS contains details of the TLS handshake, or nil for plain HTTP
+	TLS *TLSInfo
+
+	// Redirects lists the redirect responses followed before this response, oldest first
+	Redirects []RedirectHop
+
+	// Timing is the breakdown of the time spent on the request
+	Timing *Timing
 }
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+)
+
+// Signer defines an interface for signing outgoing HTTP requests.
+// Signers are applied by the client after all headers and the body are final,
+// so the signature covers exactly what is sent over the wire.
+type Signer interface {
+	// Sign adds signature information (usually headers) to the request.
+	//
+	// Parameters:
+	//   - req: Standard Go http.Request to sign
+	//   - body: Request body bytes (may be empty)
+	//
+	// Returns:
+	//   - error: Any error encountered during signing
+	Sign(req *http.Request, body []byte) error
+}
+
+// SignerFunc adapts an ordinary function to the Signer interface.
+type SignerFunc func(req *http.Request, body []byte) error
+
+// Sign calls the underlying function.
+//
+// Parameters:
+//   - req: Standard Go http.Request to sign
+//   - body: Request body bytes (may be empty)
+//
+// Returns:
+//   - error: Any error returned by the function
+func (f SignerFunc) Sign(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// WithSigner sets a signer that is applied to the request right before it is sent.
+//
+// Parameters:
+//   - signer: Signer implementation
+//
+// Returns:
+//   - RequestOption: Option function that sets the signer
+func WithSigner(signer Signer) RequestOption {
+	return func(req *Request) {
+		req.Signer = signer
+	}
+}
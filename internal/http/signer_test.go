@@ -0,0 +1,189 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Vectors from the AWS Signature Version 4 test suite.
+var sigV4TestCredentials = AWSCredentials{
+	AccessKeyID:     "AKIDEXAMPLE",
+	SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+}
+
+func sigV4TestTime() time.Time {
+	return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+}
+
+func TestAWSSigV4Signer_TestSuite(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		url       string
+		signature string
+	}{
+		{
+			name:      "get-vanilla",
+			method:    "GET",
+			url:       "https://example.amazonaws.com/",
+			signature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:      "post-vanilla",
+			method:    "POST",
+			url:       "https://example.amazonaws.com/",
+			signature: "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			assert.NoError(t, err)
+
+			signer := &AWSSigV4Signer{
+				Region:      "us-east-1",
+				Service:     "service",
+				Credentials: sigV4TestCredentials,
+				Now:         sigV4TestTime,
+			}
+			assert.NoError(t, signer.Sign(req, nil))
+
+			assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+			assert.Equal(t,
+				"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+					"SignedHeaders=host;x-amz-date, Signature="+tt.signature,
+				req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestAWSSigV4Signer_SessionToken(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	creds := sigV4TestCredentials
+	creds.SessionToken = "session"
+	signer := &AWSSigV4Signer{Region: "us-east-1", Service: "service", Credentials: creds, Now: sigV4TestTime}
+
+	assert.NoError(t, signer.Sign(req, nil))
+	assert.Equal(t, "session", req.Header.Get("X-Amz-Security-Token"))
+	assert.Contains(t, req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token")
+}
+
+func TestAWSSigV4Signer_MissingCredentials(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	signer := &AWSSigV4Signer{Region: "us-east-1", Service: "service"}
+
+	assert.Error(t, signer.Sign(req, nil))
+}
+
+func TestLoadAWSCredentials(t *testing.T) {
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	content := strings.Join([]string{
+		"[default]",
+		"aws_access_key_id = DEFAULTKEY",
+		"aws_secret_access_key = defaultsecret",
+		"",
+		"[staging]",
+		"aws_access_key_id = STAGINGKEY",
+		"aws_secret_access_key = stagingsecret",
+		"aws_session_token = stagingtoken",
+	}, "\n")
+	assert.NoError(t, os.WriteFile(credentialsFile, []byte(content), 0600))
+
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	t.Run("default profile", func(t *testing.T) {
+		creds, err := LoadAWSCredentials("")
+		assert.NoError(t, err)
+		assert.Equal(t, "DEFAULTKEY", creds.AccessKeyID)
+	})
+
+	t.Run("named profile", func(t *testing.T) {
+		creds, err := LoadAWSCredentials("staging")
+		assert.NoError(t, err)
+		assert.Equal(t, AWSCredentials{"STAGINGKEY", "stagingsecret", "stagingtoken"}, creds)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := LoadAWSCredentials("missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("environment takes precedence", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "ENVKEY")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+		creds, err := LoadAWSCredentials("")
+		assert.NoError(t, err)
+		assert.Equal(t, "ENVKEY", creds.AccessKeyID)
+	})
+}
+
+func TestHMACSigner(t *testing.T) {
+	fixedTime := func() time.Time { return time.Unix(1700000000, 0) }
+
+	t.Run("default template with hex sha256", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "http://example.com/orders?id=1", nil)
+		signer := &HMACSigner{Secret: "topsecret", Now: fixedTime}
+
+		assert.NoError(t, signer.Sign(req, []byte(`{"a":1}`)))
+		assert.Equal(t, "8d07bd09956aa8d278670e4a086e474824e01c6d449ff8cd06eb1efd33714612", req.Header.Get("X-Signature"))
+		assert.Equal(t, "1700000000", req.Header.Get("X-Timestamp"))
+	})
+
+	t.Run("custom headers with base64 sha512", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "http://example.com/status", nil)
+		signer := &HMACSigner{
+			Secret:          "topsecret",
+			Algorithm:       "sha512",
+			Encoding:        "base64",
+			SignatureHeader: "X-Hub-Signature",
+			TimestampHeader: "-",
+			Prefix:          "sha512=",
+			Now:             fixedTime,
+		}
+
+		assert.NoError(t, signer.Sign(req, nil))
+		assert.Equal(t,
+			"sha512=ARpV6XAGYxokHTRPX81G0EaWHbzIjGce5KDj/oYxmNCd8j++LPXHdcOHiSj6Z5IWCK0SagfPNRfPgGcdLHC8Mw==",
+			req.Header.Get("X-Hub-Signature"))
+		assert.Empty(t, req.Header.Get("X-Timestamp"))
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "http://example.com/", nil)
+		signer := &HMACSigner{Secret: "s", Algorithm: "md4"}
+		assert.Error(t, signer.Sign(req, nil))
+	})
+}
+
+func TestClientAppliesSigner(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var signedBody string
+	signer := SignerFunc(func(req *http.Request, body []byte) error {
+		signedBody = string(body)
+		req.Header.Set("X-Signed-Type", req.Header.Get("Content-Type"))
+		return nil
+	})
+	req := NewRequest(server.URL, MethodPost, WithJsonBody(`{"a":1}`), WithSigner(signer))
+
+	_, err := NewClient().Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, signedBody)
+	assert.Equal(t, ContentTypeJSON, received.Get("X-Signed-Type"), "signer must see final headers")
+}
@@ -0,0 +1,323 @@
+package http
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWS Signature Version 4 constants.
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+// AWSCredentials holds the credentials used for AWS Signature Version 4.
+type AWSCredentials struct {
+	// AccessKeyID is the AWS access key ID
+	AccessKeyID string
+
+	// SecretAccessKey is the AWS secret access key
+	SecretAccessKey string
+
+	// SessionToken is the optional session token for temporary credentials
+	SessionToken string
+}
+
+// AWSSigV4Signer signs requests with AWS Signature Version 4.
+type AWSSigV4Signer struct {
+	// Region is the AWS region (e.g., us-east-1)
+	Region string
+
+	// Service is the AWS service name (e.g., execute-api)
+	Service string
+
+	// Credentials are the AWS credentials used for signing
+	Credentials AWSCredentials
+
+	// Now returns the signing time; time.Now is used when nil
+	Now func() time.Time
+}
+
+// Sign adds the X-Amz-Date, X-Amz-Security-Token and Authorization headers to the request.
+//
+// Parameters:
+//   - req: Standard Go http.Request to sign
+//   - body: Request body bytes (may be empty)
+//
+// Returns:
+//   - error: Error if credentials are missing
+func (s *AWSSigV4Signer) Sign(req *http.Request, body []byte) error {
+	if s.Credentials.AccessKeyID == "" || s.Credentials.SecretAccessKey == "" {
+		return fmt.Errorf("AWS credentials are not configured")
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	signingTime := now().UTC()
+	amzDate := signingTime.Format(sigV4TimeFormat)
+	date := signingTime.Format(sigV4DateFormat)
+
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.Credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.Credentials.SessionToken)
+	}
+	if s.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := s.canonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s.canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.Credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.Credentials.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// canonicalHeaders builds the signed header list and the canonical header block.
+// The host header, content type and all x-amz-* headers are signed.
+//
+// Parameters:
+//   - req: Request being signed
+//
+// Returns:
+//   - string: Semicolon-separated list of signed header names
+//   - string: Canonical header block (one "name:value" line per header)
+func (s *AWSSigV4Signer) canonicalHeaders(req *http.Request) (string, string) {
+	headers := map[string]string{"host": req.Host}
+	if headers["host"] == "" {
+		headers["host"] = req.URL.Host
+	}
+	for key, values := range req.Header {
+		name := strings.ToLower(key)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			trimmed := make([]string, len(values))
+			for i, value := range values {
+				trimmed[i] = strings.Join(strings.Fields(value), " ")
+			}
+			headers[name] = strings.Join(trimmed, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return strings.Join(names, ";"), block.String()
+}
+
+// canonicalURI returns the URI-encoded path. Services other than S3 expect
+// each path segment to be encoded twice.
+//
+// Parameters:
+//   - u: Request URL
+//
+// Returns:
+//   - string: Canonical URI
+func (s *AWSSigV4Signer) canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	if s.Service == "s3" {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery returns the query string sorted by key and value with strict encoding.
+//
+// Parameters:
+//   - u: Request URL
+//
+// Returns:
+//   - string: Canonical query string
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(key)+"="+awsURIEncode(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes every byte except RFC 3986 unreserved characters.
+//
+// Parameters:
+//   - value: String to encode
+//
+// Returns:
+//   - string: Encoded string
+func awsURIEncode(value string) string {
+	var buffer strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			buffer.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buffer, "%%%02X", c)
+		}
+	}
+	return buffer.String()
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data using key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// LoadAWSCredentials resolves AWS credentials from the environment or the shared credentials file.
+// Environment variables (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN) take
+// precedence; otherwise the profile is read from AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials.
+//
+// Parameters:
+//   - profile: Profile name; AWS_PROFILE or "default" is used when empty
+//
+// Returns:
+//   - AWSCredentials: Resolved credentials
+//   - error: Error if no credentials could be found
+func LoadAWSCredentials(profile string) (AWSCredentials, error) {
+	if profile == "" {
+		if accessKey := os.Getenv("AWS_ACCESS_KEY_ID"); accessKey != "" {
+			return AWSCredentials{
+				AccessKeyID:     accessKey,
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			}, nil
+		}
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return AWSCredentials{}, fmt.Errorf("failed to locate AWS credentials file: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+
+	return loadAWSProfile(path, profile)
+}
+
+// loadAWSProfile reads a profile from an INI-style AWS credentials file.
+//
+// Parameters:
+//   - path: Path to the credentials file
+//   - profile: Profile section to read
+//
+// Returns:
+//   - AWSCredentials: Credentials from the profile
+//   - error: Error if the file or profile cannot be read
+func loadAWSProfile(path, profile string) (AWSCredentials, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to open AWS credentials file: %w", err)
+	}
+	defer file.Close()
+
+	var creds AWSCredentials
+	inProfile := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inProfile = strings.TrimSpace(line[1:len(line)-1]) == profile
+			continue
+		}
+		if !inProfile {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to read AWS credentials file: %w", err)
+	}
+
+	if creds.AccessKeyID == "" {
+		return AWSCredentials{}, fmt.Errorf("AWS profile '%s' not found in %s", profile, path)
+	}
+	return creds, nil
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing is the breakdown of the time spent on a request, captured with net/http/httptrace.
+// Phases that did not happen, such as DNS and connect on a reused connection, are zero.
+// When redirects are followed, the phases describe the final request.
+type Timing struct {
+	// DNSLookup is the time spent resolving the host name
+	DNSLookup time.Duration `json:"dns_lookup"`
+
+	// TCPConnect is the time spent establishing the TCP connection
+	TCPConnect time.Duration `json:"tcp_connect"`
+
+	// TLSHandshake is the time spent on the TLS handshake
+	TLSHandshake time.Duration `json:"tls_handshake"`
+
+	// ServerProcessing is the time from sending the request to the first response byte
+	ServerProcessing time.Duration `json:"server_processing"`
+
+	// TimeToFirstByte is the time from the start of the request to the first response byte
+	TimeToFirstByte time.Duration `json:"time_to_first_byte"`
+
+	// ContentTransfer is the time spent reading the response body
+	ContentTransfer time.Duration `json:"content_transfer"`
+
+	// Total is the time from the start of the request until the body was read
+	Total time.Duration `json:"total"`
+
+	// ConnectionReused reports whether an idle keep-alive connection was used
+	ConnectionReused bool `json:"connection_reused"`
+}
+
+// timingRecorder collects httptrace events for a single request execution.
+// Callbacks may run on different goroutines, so all fields are guarded by mu.
+type timingRecorder struct {
+	mu sync.Mutex
+
+	// start is the time the request execution started
+	start time.Time
+
+	// hopStart is the time the final request (after redirects) asked for a connection
+	hopStart time.Time
+
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest, firstByte   time.Time
+
+	// reused reports whether the connection was reused
+	reused bool
+}
+
+// newTimingRecorder creates a recorder whose clock starts now.
+//
+// Returns:
+//   - *timingRecorder: Initialized recorder
+func newTimingRecorder() *timingRecorder {
+	now := time.Now()
+	return &timingRecorder{start: now, hopStart: now}
+}
+
+// withTrace attaches the recorder's client trace to the context.
+//
+// Parameters:
+//   - ctx: Request context
+//
+// Returns:
+//   - context.Context: Context carrying the client trace
+func (r *timingRecorder) withTrace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) {
+			// A new hop (e.g. after a redirect) starts a fresh breakdown
+			r.record(r.reset)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.record(func() { r.reused = info.Reused })
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			r.record(func() { r.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			r.record(func() { r.dnsDone = time.Now() })
+		},
+		ConnectStart: func(string, string) {
+			r.record(func() {
+				if r.connectStart.IsZero() {
+					r.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(string, string, error) {
+			r.record(func() { r.connectDone = time.Now() })
+		},
+		TLSHandshakeStart: func() {
+			r.record(func() { r.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			r.record(func() { r.tlsDone = time.Now() })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			r.record(func() { r.wroteRequest = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			r.record(func() { r.firstByte = time.Now() })
+		},
+	})
+}
+
+// reset clears the recorded phases for a new hop, keeping the overall start time.
+// It must be called with the lock held.
+func (r *timingRecorder) reset() {
+	r.hopStart = time.Now()
+	r.dnsStart, r.dnsDone = time.Time{}, time.Time{}
+	r.connectStart, r.connectDone = time.Time{}, time.Time{}
+	r.tlsStart, r.tlsDone = time.Time{}, time.Time{}
+	r.wroteRequest, r.firstByte = time.Time{}, time.Time{}
+	r.reused = false
+}
+
+// record runs fn while holding the recorder lock.
+//
+// Parameters:
+//   - fn: Function updating the recorder fields
+func (r *timingRecorder) record(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn()
+}
+
+// timing builds the breakdown once the response body has been read.
+//
+// Parameters:
+//   - done: Time the response body was fully read
+//
+// Returns:
+//   - *Timing: Timing breakdown of the request
+func (r *timingRecorder) timing(done time.Time) *Timing {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Timing{
+		DNSLookup:        between(r.dnsStart, r.dnsDone),
+		TCPConnect:       between(r.connectStart, r.connectDone),
+		TLSHandshake:     between(r.tlsStart, r.tlsDone),
+		ServerProcessing: between(r.wroteRequest, r.firstByte),
+		TimeToFirstByte:  between(r.hopStart, r.firstByte),
+		ContentTransfer:  between(r.firstByte, done),
+		Total:            done.Sub(r.start),
+		ConnectionReused: r.reused,
+	}
+}
+
+// between returns the duration between two recorded events,
+// or zero if either event did not happen.
+//
+// Parameters:
+//   - from: Start event time
+//   - to: End event time
+//
+// Returns:
+//   - time.Duration: Elapsed time, never negative
+func between(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
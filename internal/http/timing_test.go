@@ -0,0 +1,74 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+
+	first, err := client.Do(NewRequest(server.URL, MethodGet))
+	require.NoError(t, err)
+	require.NotNil(t, first.Timing)
+	assert.False(t, first.Timing.ConnectionReused)
+	assert.Greater(t, first.Timing.TCPConnect, time.Duration(0))
+	assert.Zero(t, first.Timing.TLSHandshake)
+	assert.GreaterOrEqual(t, first.Timing.ServerProcessing, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, first.Timing.TimeToFirstByte, first.Timing.ServerProcessing)
+	assert.GreaterOrEqual(t, first.Timing.Total, first.Timing.TimeToFirstByte)
+
+	second, err := client.Do(NewRequest(server.URL, MethodGet))
+	require.NoError(t, err)
+	require.NotNil(t, second.Timing)
+	assert.True(t, second.Timing.ConnectionReused)
+	assert.Zero(t, second.Timing.TCPConnect)
+}
+
+func TestClientTiming_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithTLSConfig(&tls.Config{InsecureSkipVerify: true}))
+
+	resp, err := client.Do(NewRequest(server.URL, MethodGet))
+
+	require.NoError(t, err)
+	require.NotNil(t, resp.Timing)
+	assert.Greater(t, resp.Timing.TLSHandshake, time.Duration(0))
+}
+
+func TestClientTiming_Redirect(t *testing.T) {
+	server := newRedirectServer(t)
+
+	resp, err := NewClient().Do(NewRequest(server.URL+"/start", MethodGet))
+
+	require.NoError(t, err)
+	require.NotNil(t, resp.Timing)
+	// The final hop reuses the connection opened for the first request
+	assert.True(t, resp.Timing.ConnectionReused)
+	assert.GreaterOrEqual(t, resp.Timing.Total, resp.Timing.TimeToFirstByte)
+}
+
+func TestBetween(t *testing.T) {
+	now := time.Now()
+
+	assert.Equal(t, time.Second, between(now, now.Add(time.Second)))
+	assert.Zero(t, between(time.Time{}, now))
+	assert.Zero(t, between(now, time.Time{}))
+	assert.Zero(t, between(now, now.Add(-time.Second)))
+}
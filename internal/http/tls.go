@@ -0,0 +1,216 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// TLSOptions holds the TLS settings used to build a client TLS configuration.
+type TLSOptions struct {
+	// CACert is the path to a PEM file with additional trusted CA certificates
+	CACert string
+
+	// ClientCert is the path to a PEM client certificate for mutual TLS
+	ClientCert string
+
+	// ClientKey is the path to the PEM private key of the client certificate
+	ClientKey string
+
+	// InsecureSkipVerify disables server certificate verification
+	InsecureSkipVerify bool
+
+	// ServerName overrides the server name used for SNI and verification
+	ServerName string
+
+	// MinVersion is the minimum TLS version ("1.0", "1.1", "1.2", "1.3")
+	MinVersion string
+
+	// MaxVersion is the maximum TLS version ("1.0", "1.1", "1.2", "1.3")
+	MaxVersion string
+
+	// CipherSuites restricts the cipher suites by their standard names (TLS 1.0-1.2 only)
+	CipherSuites []string
+}
+
+// IsEmpty checks if no TLS setting is specified.
+//
+// Returns:
+//   - bool: True if all settings have their zero value
+func (o TLSOptions) IsEmpty() bool {
+	return o.CACert == "" && o.ClientCert == "" && o.ClientKey == "" && !o.InsecureSkipVerify &&
+		o.ServerName == "" && o.MinVersion == "" && o.MaxVersion == "" && len(o.CipherSuites) == 0
+}
+
+// BuildTLSConfig creates a tls.Config from the given options.
+//
+// Parameters:
+//   - opts: TLS settings
+//
+// Returns:
+//   - *tls.Config: TLS configuration for the client transport
+//   - error: Error if certificates cannot be loaded or settings are invalid
+func BuildTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+		ServerName:         opts.ServerName,
+	}
+
+	if opts.CACert != "" {
+		pem, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates found in %s", opts.CACert)
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, fmt.Errorf("both client certificate and client key are required")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	var err error
+	if config.MinVersion, err = parseTLSVersion(opts.MinVersion); err != nil {
+		return nil, err
+	}
+	if config.MaxVersion, err = parseTLSVersion(opts.MaxVersion); err != nil {
+		return nil, err
+	}
+	if config.MinVersion != 0 && config.MaxVersion != 0 && config.MinVersion > config.MaxVersion {
+		return nil, fmt.Errorf("TLS min version %s is greater than max version %s", opts.MinVersion, opts.MaxVersion)
+	}
+
+	if len(opts.CipherSuites) > 0 {
+		if config.CipherSuites, err = parseCipherSuites(opts.CipherSuites); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// parseTLSVersion converts a version string to its crypto/tls constant.
+//
+// Parameters:
+//   - version: Version string ("1.0", "1.1", "1.2", "1.3"), empty for default
+//
+// Returns:
+//   - uint16: TLS version constant, 0 for default
+//   - error: Error if the version is unknown
+func parseTLSVersion(version string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(version), "tls") {
+	case "":
+		return 0, nil
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version: %s", version)
+	}
+}
+
+// parseCipherSuites converts cipher suite names to their IDs.
+//
+// Parameters:
+//   - names: Standard cipher suite names (e.g., TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+//
+// Returns:
+//   - []uint16: Cipher suite IDs
+//   - error: Error if a name is unknown
+func parseCipherSuites(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported cipher suite: %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// WithTLSConfig sets the TLS configuration used by the client transport.
+//
+// Parameters:
+//   - config: TLS configuration
+//
+// Returns:
+//   - ClientOption: Option function that sets the TLS configuration
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(client *DefaultClient) {
+		client.transport().TLSClientConfig = config
+	}
+}
+
+// TLSInfo holds details of a negotiated TLS connection.
+type TLSInfo struct {
+	// Version is the negotiated TLS version (e.g., "TLS 1.3")
+	Version string
+
+	// CipherSuite is the negotiated cipher suite name
+	CipherSuite string
+
+	// ServerName is the server name sent via SNI
+	ServerName string
+
+	// PeerSubject is the subject of the server leaf certificate
+	PeerSubject string
+
+	// PeerIssuer is the issuer of the server leaf certificate
+	PeerIssuer string
+
+	// PeerNotAfter is the expiry of the server leaf certificate
+	PeerNotAfter time.Time
+}
+
+// newTLSInfo extracts handshake details from a connection state.
+//
+// Parameters:
+//   - state: TLS connection state (may be nil)
+//
+// Returns:
+//   - *TLSInfo: Handshake details, or nil if the connection was not TLS
+func newTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	if len(state.PeerCertificates) > 0 {
+		leaf := state.PeerCertificates[0]
+		info.PeerSubject = leaf.Subject.String()
+		info.PeerIssuer = leaf.Issuer.String()
+		info.PeerNotAfter = leaf.NotAfter
+	}
+	return info
+}
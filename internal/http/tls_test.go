@@ -0,0 +1,158 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate creates a self-signed certificate and key pair as PEM files.
+func writeTestCertificate(t *testing.T, dir, name string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		DNSNames:              []string{name},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile, cert
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeTestCertificate(t, dir, "client.test")
+
+	tests := []struct {
+		name  string
+		opts  TLSOptions
+		check func(t *testing.T, config *tls.Config)
+		isErr bool
+	}{
+		{
+			name: "versions and server name",
+			opts: TLSOptions{MinVersion: "1.2", MaxVersion: "1.3", ServerName: "api.internal", InsecureSkipVerify: true},
+			check: func(t *testing.T, config *tls.Config) {
+				assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+				assert.Equal(t, uint16(tls.VersionTLS13), config.MaxVersion)
+				assert.Equal(t, "api.internal", config.ServerName)
+				assert.True(t, config.InsecureSkipVerify)
+			},
+		},
+		{
+			name: "cipher suites",
+			opts: TLSOptions{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+			check: func(t *testing.T, config *tls.Config) {
+				assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, config.CipherSuites)
+			},
+		},
+		{
+			name: "client certificate and CA",
+			opts: TLSOptions{CACert: certFile, ClientCert: certFile, ClientKey: keyFile},
+			check: func(t *testing.T, config *tls.Config) {
+				assert.Len(t, config.Certificates, 1)
+				assert.NotNil(t, config.RootCAs)
+			},
+		},
+		{name: "unknown version", opts: TLSOptions{MinVersion: "2.0"}, isErr: true},
+		{name: "min greater than max", opts: TLSOptions{MinVersion: "1.3", MaxVersion: "1.2"}, isErr: true},
+		{name: "unknown cipher", opts: TLSOptions{CipherSuites: []string{"TLS_FAKE"}}, isErr: true},
+		{name: "client cert without key", opts: TLSOptions{ClientCert: certFile}, isErr: true},
+		{name: "missing CA file", opts: TLSOptions{CACert: filepath.Join(dir, "missing.pem")}, isErr: true},
+		{name: "CA file without certificates", opts: TLSOptions{CACert: keyFile}, isErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := BuildTLSConfig(tt.opts)
+			if tt.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			tt.check(t, config)
+		})
+	}
+}
+
+func TestClientMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, clientKey, clientCA := writeTestCertificate(t, dir, "client.test")
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	clientPool := x509.NewCertPool()
+	clientPool.AddCert(clientCA)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientPool}
+	server.StartTLS()
+	defer server.Close()
+
+	serverCAFile := filepath.Join(dir, "server-ca.pem")
+	require.NoError(t, os.WriteFile(serverCAFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	t.Run("with client certificate", func(t *testing.T) {
+		config, err := BuildTLSConfig(TLSOptions{CACert: serverCAFile, ClientCert: clientCert, ClientKey: clientKey, MinVersion: "1.2"})
+		require.NoError(t, err)
+
+		resp, err := NewClient(WithTLSConfig(config)).Do(NewRequest(server.URL, MethodGet))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotNil(t, resp.TLS)
+		assert.Equal(t, "TLS 1.3", resp.TLS.Version)
+		assert.NotEmpty(t, resp.TLS.CipherSuite)
+		assert.NotEmpty(t, resp.TLS.PeerSubject)
+		assert.False(t, resp.TLS.PeerNotAfter.IsZero())
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		config, err := BuildTLSConfig(TLSOptions{CACert: serverCAFile})
+		require.NoError(t, err)
+
+		_, err = NewClient(WithTLSConfig(config)).Do(NewRequest(server.URL, MethodGet))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("untrusted server", func(t *testing.T) {
+		_, err := NewClient().Do(NewRequest(server.URL, MethodGet))
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// BuildURL combines a base URL, a request path and query parameters into a full URL.
+// Unlike plain string concatenation, it keeps any path prefix of the base URL,
+// avoids duplicated or missing slashes, and encodes query parameters properly.
+//
+// Parameters:
+//   - baseURL: Base URL, optionally with a path prefix (e.g., "https://host/api/v1/")
+//   - path: Request path, may contain its own query string; absolute URLs are used as-is
+//   - query: Additional query parameters to merge into the URL
+//
+// Returns:
+//   - string: Full request URL
+//   - error: Error if the base URL or path cannot be parsed
+func BuildURL(baseURL, path string, query map[string][]string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL '%s': %w", baseURL, err)
+	}
+
+	// Only URLs with a host are absolute; "/projects:list" or "v1/a:b" are paths
+	target, err := url.Parse(path)
+	if err != nil || !target.IsAbs() || target.Host == "" {
+		// Treat the base path as a directory and the request path as relative to it,
+		// so that "https://host/api" + "/users" resolves to "https://host/api/users"
+		if !strings.HasSuffix(base.Path, "/") {
+			base.Path += "/"
+			if base.RawPath != "" {
+				base.RawPath += "/"
+			}
+		}
+		// The "./" prefix keeps a colon in the first segment from being read as a scheme
+		relative, err := url.Parse("./" + strings.TrimLeft(path, "/"))
+		if err != nil {
+			return "", fmt.Errorf("invalid path '%s': %w", path, err)
+		}
+		target = base.ResolveReference(relative)
+	}
+
+	if len(query) > 0 {
+		target.RawQuery = mergeQuery(target.RawQuery, query)
+	}
+
+	return target.String(), nil
+}
+
+// mergeQuery appends query parameters to an existing raw query string.
+// Existing parameters are kept in their original order; new parameters are
+// appended sorted by key for deterministic output.
+//
+// Parameters:
+//   - rawQuery: Existing raw query string (may be empty)
+//   - query: Parameters to append
+//
+// Returns:
+//   - string: Combined raw query string
+func mergeQuery(rawQuery string, query map[string][]string) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buffer strings.Builder
+	buffer.WriteString(rawQuery)
+	for _, key := range keys {
+		for _, value := range query[key] {
+			if buffer.Len() > 0 {
+				buffer.WriteByte('&')
+			}
+			buffer.WriteString(url.QueryEscape(key))
+			buffer.WriteByte('=')
+			buffer.WriteString(url.QueryEscape(value))
+		}
+	}
+
+	return buffer.String()
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		path     string
+		query    map[string][]string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "simple join",
+			baseURL:  "http://example.com",
+			path:     "/users",
+			expected: "http://example.com/users",
+		},
+		{
+			name:     "base with trailing slash",
+			baseURL:  "http://example.com/",
+			path:     "/users",
+			expected: "http://example.com/users",
+		},
+		{
+			name:     "base with path prefix",
+			baseURL:  "http://example.com/api/v1",
+			path:     "/users",
+			expected: "http://example.com/api/v1/users",
+		},
+		{
+			name:     "base with path prefix and trailing slash",
+			baseURL:  "http://example.com/api/v1/",
+			path:     "users",
+			expected: "http://example.com/api/v1/users",
+		},
+		{
+			name:     "path with query string",
+			baseURL:  "http://example.com",
+			path:     "/search?q=test",
+			expected: "http://example.com/search?q=test",
+		},
+		{
+			name:     "query parameters are encoded",
+			baseURL:  "http://example.com",
+			path:     "/search",
+			query:    map[string][]string{"q": {"a b&c"}, "page": {"1"}},
+			expected: "http://example.com/search?page=1&q=a+b%26c",
+		},
+		{
+			name:     "query parameters merged with path query",
+			baseURL:  "http://example.com",
+			path:     "/search?q=test",
+			query:    map[string][]string{"tags": {"a", "b"}},
+			expected: "http://example.com/search?q=test&tags=a&tags=b",
+		},
+		{
+			name:     "absolute path URL is used as-is",
+			baseURL:  "http://example.com/api",
+			path:     "https://other.example.com/health",
+			expected: "https://other.example.com/health",
+		},
+		{
+			name:     "path with colon",
+			baseURL:  "http://example.com/api",
+			path:     "/projects:list",
+			expected: "http://example.com/api/projects:list",
+		},
+		{
+			name:     "relative path with colon",
+			baseURL:  "http://example.com",
+			path:     "v1/a:b",
+			query:    map[string][]string{"page": {"2"}},
+			expected: "http://example.com/v1/a:b?page=2",
+		},
+		{
+			name:     "colon in first segment is not a scheme",
+			baseURL:  "http://example.com/",
+			path:     "projects:list?sort=name",
+			expected: "http://example.com/projects:list?sort=name",
+		},
+		{
+			name:     "absolute path URL with query parameters",
+			baseURL:  "http://example.com/api",
+			path:     "https://other.example.com/search?q=a",
+			query:    map[string][]string{"page": {"2"}},
+			expected: "https://other.example.com/search?q=a&page=2",
+		},
+		{
+			name:    "invalid path",
+			baseURL: "http://example.com",
+			path:    "/users/%zz",
+			isErr:   true,
+		},
+		{
+			name:    "invalid base URL",
+			baseURL: "http://exa mple.com:port",
+			path:    "/users",
+			isErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildURL(tt.baseURL, tt.path, tt.query)
+			if tt.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,267 @@
+// Package load provides load testing of configured requests at a target rate or concurrency.
+package load
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// Histogram records latencies in HDR-style log-linear buckets.
+// Values are stored in microseconds with a fixed number of significant digits,
+// so memory use is constant regardless of how many values are recorded and
+// percentiles are accurate to the configured precision.
+// A Histogram is not safe for concurrent use.
+type Histogram struct {
+	// highest is the largest trackable value in microseconds; larger values are clamped
+	highest int64
+
+	// subBucketHalfCountMagnitude is log2 of half the number of sub-buckets per bucket
+	subBucketHalfCountMagnitude uint
+
+	// subBucketHalfCount is half the number of sub-buckets per bucket
+	subBucketHalfCount int
+
+	// subBucketMask masks the values that fall into the first bucket
+	subBucketMask int64
+
+	// counts holds the number of values recorded for each bucket slot
+	counts []int64
+
+	// total is the number of recorded values
+	total int64
+
+	// sum is the sum of all recorded values in microseconds
+	sum int64
+
+	// min is the smallest recorded value in microseconds
+	min int64
+
+	// max is the largest recorded value in microseconds
+	max int64
+}
+
+// NewHistogram creates a histogram that tracks latencies up to highest
+// with the given number of significant digits.
+//
+// Parameters:
+//   - highest: Largest trackable latency; larger values are recorded as highest
+//   - significantDigits: Precision of recorded values, between 1 and 5
+//
+// Returns:
+//   - *Histogram: Empty histogram
+func NewHistogram(highest time.Duration, significantDigits int) *Histogram {
+	if significantDigits < 1 {
+		significantDigits = 1
+	}
+	if significantDigits > 5 {
+		significantDigits = 5
+	}
+
+	highestMicros := highest.Microseconds()
+	if highestMicros < 2 {
+		highestMicros = 2
+	}
+
+	// Enough sub-buckets to distinguish values at the requested precision
+	largestSingleUnitResolution := 2 * int64(math.Pow10(significantDigits))
+	subBucketCountMagnitude := uint(math.Ceil(math.Log2(float64(largestSingleUnitResolution))))
+	subBucketHalfCountMagnitude := subBucketCountMagnitude - 1
+	subBucketCount := int64(1) << subBucketCountMagnitude
+
+	// Each further bucket doubles the covered range
+	bucketCount := 1
+	for smallestUntrackable := subBucketCount; smallestUntrackable <= highestMicros; smallestUntrackable <<= 1 {
+		bucketCount++
+	}
+
+	halfCount := int(subBucketCount / 2)
+	return &Histogram{
+		highest:                     highestMicros,
+		subBucketHalfCountMagnitude: subBucketHalfCountMagnitude,
+		subBucketHalfCount:          halfCount,
+		subBucketMask:               subBucketCount - 1,
+		counts:                      make([]int64, (bucketCount+1)*halfCount),
+		min:                         math.MaxInt64,
+	}
+}
+
+// Record adds a latency to the histogram.
+// Negative values are recorded as zero and values above the trackable range as the highest value.
+//
+// Parameters:
+//   - d: Latency to record
+func (h *Histogram) Record(d time.Duration) {
+	value := d.Microseconds()
+	if value < 0 {
+		value = 0
+	}
+	if value > h.highest {
+		value = h.highest
+	}
+
+	h.counts[h.countsIndex(value)]++
+	h.total++
+	h.sum += value
+	if value < h.min {
+		h.min = value
+	}
+	if value > h.max {
+		h.max = value
+	}
+}
+
+// Merge adds all values recorded in another histogram with the same layout.
+//
+// Parameters:
+//   - other: Histogram created with the same range and precision
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.total == 0 {
+		return
+	}
+
+	for i, count := range other.counts {
+		if i < len(h.counts) {
+			h.counts[i] += count
+		}
+	}
+	h.total += other.total
+	h.sum += other.sum
+	if other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+}
+
+// Count returns the number of recorded values.
+//
+// Returns:
+//   - int64: Number of recorded values
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Min returns the smallest recorded latency.
+//
+// Returns:
+//   - time.Duration: Smallest latency, or 0 if nothing was recorded
+func (h *Histogram) Min() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.min) * time.Microsecond
+}
+
+// Max returns the largest recorded latency.
+//
+// Returns:
+//   - time.Duration: Largest latency, or 0 if nothing was recorded
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max) * time.Microsecond
+}
+
+// Mean returns the average recorded latency.
+//
+// Returns:
+//   - time.Duration: Average latency, or 0 if nothing was recorded
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.sum/h.total) * time.Microsecond
+}
+
+// Percentile returns the latency at or below which the given percentage of values fall.
+// The result is the highest value equivalent to the bucket slot at the requested precision,
+// capped at the largest recorded value.
+//
+// Parameters:
+//   - percentile: Percentage between 0 and 100, e.g. 99 for p99
+//
+// Returns:
+//   - time.Duration: Latency at the percentile, or 0 if nothing was recorded
+func (h *Histogram) Percentile(percentile float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	if percentile > 100 {
+		percentile = 100
+	}
+
+	target := int64(math.Ceil(percentile / 100 * float64(h.total)))
+	if target < 1 {
+		target = 1
+	}
+
+	var seen int64
+	for i, count := range h.counts {
+		seen += count
+		if seen >= target {
+			value := h.highestEquivalentValue(h.valueFromIndex(i))
+			if value > h.max {
+				value = h.max
+			}
+			return time.Duration(value) * time.Microsecond
+		}
+	}
+
+	return h.Max()
+}
+
+// countsIndex returns the slot in counts that holds a value.
+//
+// Parameters:
+//   - value: Value in microseconds
+//
+// Returns:
+//   - int: Index into counts
+func (h *Histogram) countsIndex(value int64) int {
+	bucket := h.bucketIndex(value)
+	subBucket := int(value >> uint(bucket))
+
+	// The lower half of every bucket but the first overlaps the previous bucket
+	return (bucket+1)<<h.subBucketHalfCountMagnitude + (subBucket - h.subBucketHalfCount)
+}
+
+// bucketIndex returns the power-of-two bucket a value falls into.
+//
+// Parameters:
+//   - value: Value in microseconds
+//
+// Returns:
+//   - int: Bucket index, 0 for values within the first sub-bucket range
+func (h *Histogram) bucketIndex(value int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(value|h.subBucketMask))
+	return pow2Ceiling - int(h.subBucketHalfCountMagnitude+1)
+}
+
+// valueFromIndex returns the lowest value stored in a counts slot.
+//
+// Parameters:
+//   - index: Index into counts
+//
+// Returns:
+//   - int64: Lowest value of the slot in microseconds
+func (h *Histogram) valueFromIndex(index int) int64 {
+	bucket := (index >> h.subBucketHalfCountMagnitude) - 1
+	subBucket := (index & (h.subBucketHalfCount - 1)) + h.subBucketHalfCount
+	if bucket < 0 {
+		subBucket -= h.subBucketHalfCount
+		bucket = 0
+	}
+	return int64(subBucket) << uint(bucket)
+}
+
+// highestEquivalentValue returns the largest value that shares a slot with the given value.
+//
+// Parameters:
+//   - value: Value in microseconds
+//
+// Returns:
+//   - int64: Largest value in the same slot
+func (h *Histogram) highestEquivalentValue(value int64) int64 {
+	bucket := h.bucketIndex(value)
+	return value + (int64(1) << uint(bucket)) - 1
+}
@@ -0,0 +1,94 @@
+package load
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Empty(t *testing.T) {
+	h := NewHistogram(time.Minute, 3)
+
+	assert.Zero(t, h.Count())
+	assert.Zero(t, h.Min())
+	assert.Zero(t, h.Max())
+	assert.Zero(t, h.Mean())
+	assert.Zero(t, h.Percentile(99))
+}
+
+func TestHistogram_Percentiles(t *testing.T) {
+	h := NewHistogram(time.Minute, 3)
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		percentile float64
+		expected   time.Duration
+	}{
+		{50, 500 * time.Millisecond},
+		{90, 900 * time.Millisecond},
+		{95, 950 * time.Millisecond},
+		{99, 990 * time.Millisecond},
+		{100, 1000 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		got := h.Percentile(tt.percentile)
+		// Three significant digits allow an error of 0.1%
+		assert.InDelta(t, float64(tt.expected), float64(got), float64(tt.expected)/1000, "p%v", tt.percentile)
+	}
+
+	assert.Equal(t, int64(1000), h.Count())
+	assert.Equal(t, time.Millisecond, h.Min())
+	assert.Equal(t, time.Second, h.Max())
+	assert.Equal(t, 500500*time.Microsecond, h.Mean())
+}
+
+func TestHistogram_Precision(t *testing.T) {
+	h := NewHistogram(time.Hour, 3)
+	random := rand.New(rand.NewSource(1))
+
+	values := make([]time.Duration, 10000)
+	for i := range values {
+		// Spread values over several orders of magnitude
+		values[i] = time.Duration(random.ExpFloat64()*50) * time.Millisecond
+		h.Record(values[i])
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	for _, percentile := range []float64{50, 90, 99, 99.9} {
+		exact := values[int(math.Ceil(percentile/100*float64(len(values))))-1]
+		got := h.Percentile(percentile)
+		assert.InDelta(t, float64(exact), float64(got), float64(exact)/1000+float64(time.Microsecond), "p%v", percentile)
+	}
+}
+
+func TestHistogram_Clamp(t *testing.T) {
+	h := NewHistogram(time.Second, 2)
+
+	h.Record(-time.Millisecond)
+	h.Record(time.Minute)
+
+	assert.Equal(t, time.Duration(0), h.Min())
+	assert.Equal(t, time.Second, h.Max())
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a := NewHistogram(time.Minute, 3)
+	b := NewHistogram(time.Minute, 3)
+	a.Record(10 * time.Millisecond)
+	b.Record(30 * time.Millisecond)
+	b.Record(50 * time.Millisecond)
+
+	a.Merge(b)
+
+	assert.Equal(t, int64(3), a.Count())
+	assert.Equal(t, 10*time.Millisecond, a.Min())
+	assert.Equal(t, 50*time.Millisecond, a.Max())
+	assert.InDelta(t, float64(30*time.Millisecond), float64(a.Percentile(50)), float64(30*time.Microsecond))
+}
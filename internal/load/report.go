@@ -0,0 +1,145 @@
+package load
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Report holds the results of a load test.
+// Requests that failed to send or received a status code of 400 or above count as errors.
+type Report struct {
+	// mu guards the report while the test is running
+	mu sync.Mutex
+
+	// Model is the load model the test ran with
+	Model Model
+
+	// Requests is the number of completed requests, including errors
+	Requests int64
+
+	// Errors is the number of failed requests
+	Errors int64
+
+	// Dropped is the number of arrivals skipped because all users were busy (open model only)
+	Dropped int64
+
+	// StatusCodes counts responses by status code
+	StatusCodes map[int]int64
+
+	// ErrorMessages counts transport errors by message
+	ErrorMessages map[string]int64
+
+	// Elapsed is the wall-clock duration of the test
+	Elapsed time.Duration
+
+	// Latency holds the latency distribution of completed requests
+	Latency *Histogram
+}
+
+// newReport creates an empty report.
+//
+// Parameters:
+//   - model: Load model of the test
+//
+// Returns:
+//   - *Report: Empty report
+func newReport(model Model) *Report {
+	return &Report{
+		Model:         model,
+		StatusCodes:   make(map[int]int64),
+		ErrorMessages: make(map[string]int64),
+		Latency:       NewHistogram(defaultHighestLatency, defaultSignificantDigits),
+	}
+}
+
+// record adds the outcome of one request.
+//
+// Parameters:
+//   - statusCode: HTTP status code, or 0 if no response was received
+//   - latency: Time taken by the request
+//   - err: Error encountered while sending the request, or nil
+func (report *Report) record(statusCode int, latency time.Duration, err error) {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	report.Requests++
+	report.Latency.Record(latency)
+
+	if err != nil {
+		report.Errors++
+		report.ErrorMessages[err.Error()]++
+		return
+	}
+
+	report.StatusCodes[statusCode]++
+	if statusCode >= 400 {
+		report.Errors++
+	}
+}
+
+// drop counts an arrival that could not be served.
+func (report *Report) drop() {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	report.Dropped++
+}
+
+// progress returns the running totals while the test is in progress.
+//
+// Returns:
+//   - int64: Number of completed requests
+//   - int64: Number of failed requests
+func (report *Report) progress() (int64, int64) {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	return report.Requests, report.Errors
+}
+
+// finish records the duration of the test.
+//
+// Parameters:
+//   - elapsed: Wall-clock duration of the test
+func (report *Report) finish(elapsed time.Duration) {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	report.Elapsed = elapsed
+}
+
+// Throughput returns the number of completed requests per second.
+//
+// Returns:
+//   - float64: Requests per second, or 0 if no time has elapsed
+func (report *Report) Throughput() float64 {
+	if report.Elapsed <= 0 {
+		return 0
+	}
+	return float64(report.Requests) / report.Elapsed.Seconds()
+}
+
+// ErrorRate returns the fraction of requests that failed.
+//
+// Returns:
+//   - float64: Error rate between 0 and 1
+func (report *Report) ErrorRate() float64 {
+	if report.Requests == 0 {
+		return 0
+	}
+	return float64(report.Errors) / float64(report.Requests)
+}
+
+// SortedStatusCodes returns the observed status codes in ascending order.
+//
+// Returns:
+//   - []int: Status codes
+func (report *Report) SortedStatusCodes() []int {
+	codes := make([]int, 0, len(report.StatusCodes))
+	for code := range report.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
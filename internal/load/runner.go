@@ -0,0 +1,511 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ymatsukawa/jak/internal/engine"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// Model selects how load is generated.
+type Model string
+
+const (
+	// ModelOpen starts requests at a target rate, independent of how fast the server responds
+	ModelOpen Model = "open"
+
+	// ModelClosed runs a fixed number of users that each send the next request after the previous one completes
+	ModelClosed Model = "closed"
+)
+
+// Constants for default values and internal limits of load tests
+const (
+	// DefaultUsers is the number of virtual users when none is given.
+	// In the open model it caps the number of requests in flight.
+	DefaultUsers = 10
+
+	// DefaultDuration is the test duration when neither a duration nor stages are given
+	DefaultDuration = 10 * time.Second
+
+	// defaultHighestLatency is the largest latency tracked by the histogram
+	defaultHighestLatency = time.Hour
+
+	// defaultSignificantDigits is the precision of the latency histogram
+	defaultSignificantDigits = 3
+
+	// schedulerTick is how often the scheduler releases due arrivals
+	schedulerTick = 5 * time.Millisecond
+
+	// idlePollInterval is how often an inactive user checks whether it became active
+	idlePollInterval = 10 * time.Millisecond
+
+	// progressInterval is how often the progress collector is called
+	progressInterval = time.Second
+)
+
+// ProgressCollector is a function type that receives running totals during a load test.
+//
+// Parameters:
+//   - elapsed: Time since the start of the test
+//   - requests: Number of completed requests so far
+//   - errors: Number of failed requests so far
+type ProgressCollector func(elapsed time.Duration, requests, errors int64)
+
+// Options configures a load test.
+type Options struct {
+	// Model is the load model; empty selects open when RPS is set and closed otherwise
+	Model Model
+
+	// RPS is the target request rate; in the closed model it caps the rate of all users together
+	RPS int
+
+	// Users is the number of virtual users (closed) or the maximum requests in flight (open)
+	Users int
+
+	// Duration is how long the test runs; stages extend it if they last longer
+	Duration time.Duration
+
+	// Stages ramp the target over time: the rate in the open model, the active users in the closed model
+	Stages []Stage
+
+	// RequestTimeout limits each individual request
+	RequestTimeout time.Duration
+}
+
+// normalize resolves defaults and validates the options.
+//
+// Returns:
+//   - Options: Options with defaults applied
+//   - error: Any invalid combination of options
+func (opts Options) normalize() (Options, error) {
+	if opts.RPS < 0 {
+		return opts, fmt.Errorf("rps must not be negative: %d", opts.RPS)
+	}
+	if opts.Users < 0 {
+		return opts, fmt.Errorf("users must not be negative: %d", opts.Users)
+	}
+	if opts.Duration < 0 {
+		return opts, fmt.Errorf("duration must not be negative: %s", opts.Duration)
+	}
+
+	if opts.Model == "" {
+		opts.Model = ModelClosed
+		if opts.RPS > 0 {
+			opts.Model = ModelOpen
+		}
+	}
+
+	switch opts.Model {
+	case ModelOpen:
+		if opts.RPS == 0 && len(opts.Stages) == 0 {
+			return opts, errors.New("open model requires a request rate or stages")
+		}
+	case ModelClosed:
+	default:
+		return opts, fmt.Errorf("unknown load model %q: expected %q or %q", opts.Model, ModelOpen, ModelClosed)
+	}
+
+	if opts.Users == 0 {
+		opts.Users = DefaultUsers
+	}
+	if opts.Duration == 0 && len(opts.Stages) == 0 {
+		opts.Duration = DefaultDuration
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = http.DefaultTimeout
+	}
+
+	return opts, nil
+}
+
+// TotalDuration returns how long the test runs: the longer of the duration and the stages.
+//
+// Returns:
+//   - time.Duration: Total test duration
+func (opts Options) TotalDuration() time.Duration {
+	if total := stagesDuration(opts.Stages); total > opts.Duration {
+		return total
+	}
+	return opts.Duration
+}
+
+// rate returns the target request rate at a point in time.
+//
+// Parameters:
+//   - elapsed: Time since the start of the test
+//
+// Returns:
+//   - float64: Requests per second
+func (opts Options) rate(elapsed time.Duration) float64 {
+	if opts.Model == ModelOpen && len(opts.Stages) > 0 {
+		return stageTarget(opts.Stages, elapsed)
+	}
+	return float64(opts.RPS)
+}
+
+// activeUsers returns the number of users that send requests at a point in time.
+//
+// Parameters:
+//   - elapsed: Time since the start of the test
+//
+// Returns:
+//   - float64: Number of active users
+func (opts Options) activeUsers(elapsed time.Duration) float64 {
+	if len(opts.Stages) > 0 {
+		return stageTarget(opts.Stages, elapsed)
+	}
+	return float64(opts.Users)
+}
+
+// poolSize returns the number of user goroutines needed in the closed model.
+//
+// Returns:
+//   - int: Largest number of users active at any time
+func (opts Options) poolSize() int {
+	size := opts.Users
+	if len(opts.Stages) > 0 {
+		size = 0
+		for _, stage := range opts.Stages {
+			if stage.Target > size {
+				size = stage.Target
+			}
+		}
+	}
+	return size
+}
+
+// Runner replays the requests of a configuration under load.
+// Requests are sent round-robin in the order they are defined.
+type Runner struct {
+	// factory creates HTTP requests from configuration
+	factory engine.Factory
+
+	// client executes HTTP requests
+	client http.Client
+
+	// config holds the requests to replay
+	config *rule.Config
+
+	// opts configures the load
+	opts Options
+
+	// progressCollector receives running totals, if set
+	progressCollector ProgressCollector
+
+	// sequence selects the next request round-robin
+	sequence atomic.Uint64
+}
+
+// NewRunner creates a load test runner with default dependencies.
+//
+// Parameters:
+//   - config: Configuration containing the requests to replay
+//   - opts: Load options
+//
+// Returns:
+//   - *Runner: Initialized runner
+func NewRunner(config *rule.Config, opts Options) *Runner {
+	return &Runner{
+		factory: engine.NewFactory(),
+		client:  http.NewClient(),
+		config:  config,
+		opts:    opts,
+	}
+}
+
+// WithClient sets a custom HTTP client for the runner.
+//
+// Parameters:
+//   - client: Custom HTTP client implementation
+//
+// Returns:
+//   - *Runner: The runner instance for method chaining
+func (runner *Runner) WithClient(client http.Client) *Runner {
+	runner.client = client
+	return runner
+}
+
+// WithFactory sets a custom request factory for the runner.
+//
+// Parameters:
+//   - factory: Custom request factory implementation
+//
+// Returns:
+//   - *Runner: The runner instance for method chaining
+func (runner *Runner) WithFactory(factory engine.Factory) *Runner {
+	runner.factory = factory
+	return runner
+}
+
+// SetProgressCollector sets a function to receive running totals about once per second.
+//
+// Parameters:
+//   - collector: Function to receive progress
+func (runner *Runner) SetProgressCollector(collector ProgressCollector) {
+	runner.progressCollector = collector
+}
+
+// Run executes the load test until its duration has passed or ctx is canceled.
+// Requests in flight when the test ends are allowed to complete.
+//
+// Parameters:
+//   - ctx: Context for cancellation; canceling it stops the test early
+//
+// Returns:
+//   - *Report: Results of the test, also returned when the test was stopped early
+//   - error: Any error in the options or configuration
+func (runner *Runner) Run(ctx context.Context) (*Report, error) {
+	opts, err := runner.opts.normalize()
+	if err != nil {
+		return nil, err
+	}
+	if len(runner.config.Request) == 0 {
+		return nil, errors.New("no requests to run")
+	}
+
+	report := newReport(opts.Model)
+	start := time.Now()
+
+	// runCtx ends the generation of new requests; ctx still governs those in flight
+	runCtx, cancel := context.WithTimeout(ctx, opts.TotalDuration())
+	defer cancel()
+
+	stopProgress := runner.startProgress(runCtx, start, report)
+
+	if opts.Model == ModelOpen {
+		runner.runOpen(ctx, runCtx, start, opts, report)
+	} else {
+		runner.runClosed(ctx, runCtx, start, opts, report)
+	}
+
+	stopProgress()
+	report.finish(time.Since(start))
+
+	return report, nil
+}
+
+// runOpen starts requests at the target rate until runCtx ends.
+// Arrivals that find all users busy are dropped rather than delayed,
+// so a slow server does not lower the offered load.
+//
+// Parameters:
+//   - ctx: Context for requests in flight
+//   - runCtx: Context that ends the test
+//   - start: Start time of the test
+//   - opts: Normalized load options
+//   - report: Report to record results in
+func (runner *Runner) runOpen(ctx, runCtx context.Context, start time.Time, opts Options, report *Report) {
+	slots := make(chan struct{}, opts.Users)
+	var wg sync.WaitGroup
+
+	schedule(runCtx, start, opts.rate, func() {
+		select {
+		case slots <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				runner.execute(ctx, opts, report)
+			}()
+		default:
+			report.drop()
+		}
+	})
+
+	wg.Wait()
+}
+
+// runClosed runs the virtual users until runCtx ends.
+// With stages, only the users below the current target are active.
+// With a request rate, all users share one schedule that caps the combined rate.
+//
+// Parameters:
+//   - ctx: Context for requests in flight
+//   - runCtx: Context that ends the test
+//   - start: Start time of the test
+//   - opts: Normalized load options
+//   - report: Report to record results in
+func (runner *Runner) runClosed(ctx, runCtx context.Context, start time.Time, opts Options, report *Report) {
+	var tokens chan struct{}
+	var wg sync.WaitGroup
+
+	if opts.RPS > 0 {
+		tokens = make(chan struct{}, opts.poolSize())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			schedule(runCtx, start, opts.rate, func() {
+				select {
+				case tokens <- struct{}{}:
+				default:
+					// All users are busy; the rate is a cap, not a target
+				}
+			})
+		}()
+	}
+
+	for user := 0; user < opts.poolSize(); user++ {
+		wg.Add(1)
+		go func(user int) {
+			defer wg.Done()
+			runner.user(ctx, runCtx, start, user, opts, tokens, report)
+		}(user)
+	}
+
+	wg.Wait()
+}
+
+// user is the loop of one virtual user in the closed model.
+//
+// Parameters:
+//   - ctx: Context for requests in flight
+//   - runCtx: Context that ends the test
+//   - start: Start time of the test
+//   - user: Index of the user, compared against the number of active users
+//   - opts: Normalized load options
+//   - tokens: Channel that paces requests, or nil to send as fast as possible
+//   - report: Report to record results in
+func (runner *Runner) user(
+	ctx, runCtx context.Context,
+	start time.Time,
+	user int,
+	opts Options,
+	tokens <-chan struct{},
+	report *Report,
+) {
+	for runCtx.Err() == nil {
+		if float64(user) >= opts.activeUsers(time.Since(start)) {
+			select {
+			case <-runCtx.Done():
+			case <-time.After(idlePollInterval):
+			}
+			continue
+		}
+
+		if tokens != nil {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-tokens:
+			}
+		}
+
+		runner.execute(ctx, opts, report)
+	}
+}
+
+// execute sends the next request and records its outcome.
+//
+// Parameters:
+//   - ctx: Context for the request
+//   - opts: Normalized load options
+//   - report: Report to record the result in
+func (runner *Runner) execute(ctx context.Context, opts Options, report *Report) {
+	index := (runner.sequence.Add(1) - 1) % uint64(len(runner.config.Request))
+	req := runner.config.Request[index]
+
+	httpReq, err := runner.factory.CreateFromConfig(runner.config, &req)
+	if err != nil {
+		report.record(0, 0, fmt.Errorf("failed to prepare request '%s': %w", req.Name, err))
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, opts.RequestTimeout)
+	defer cancel()
+	httpReq.WithContext(reqCtx)
+
+	startTime := time.Now()
+	resp, err := runner.client.Do(httpReq)
+	latency := time.Since(startTime)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	report.record(statusCode, latency, err)
+}
+
+// startProgress calls the progress collector periodically until the returned function is called.
+//
+// Parameters:
+//   - runCtx: Context that ends the test
+//   - start: Start time of the test
+//   - report: Report to read running totals from
+//
+// Returns:
+//   - func(): Function that stops reporting progress and waits for it to finish
+func (runner *Runner) startProgress(runCtx context.Context, start time.Time, report *Report) func() {
+	if runner.progressCollector == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+				requests, errs := report.progress()
+				runner.progressCollector(time.Since(start), requests, errs)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// schedule calls arrive at the rate returned by rate until ctx ends.
+// The number of due arrivals is the integral of the rate over time,
+// so ramps are followed smoothly and a late tick catches up on missed arrivals.
+//
+// Parameters:
+//   - ctx: Context that ends the schedule
+//   - start: Start time of the test
+//   - rate: Function returning the target rate at a time since start
+//   - arrive: Function called for every arrival
+func schedule(ctx context.Context, start time.Time, rate func(time.Duration) float64, arrive func()) {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+
+	due := 0.0
+	sent := 0
+	last := start
+
+	for {
+		// Round up so that an arrival is released as soon as it becomes due
+		for sent < int(math.Ceil(due)) {
+			arrive()
+			sent++
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Integrate with the midpoint rate to follow linear ramps exactly
+			from := last.Sub(start)
+			to := now.Sub(start)
+			due += rate((from+to)/2) * (to - from).Seconds()
+			last = now
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package load
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// newLoadServer starts a server that answers /fail with 500 and everything else with 200
+// after the given delay, tracking the highest number of concurrent requests.
+func newLoadServer(t *testing.T, delay time.Duration) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var inFlight, peak atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := peak.Load()
+			if current <= highest || peak.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+
+		time.Sleep(delay)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &peak
+}
+
+func loadConfig(baseURL string, paths ...string) *rule.Config {
+	config := &rule.Config{BaseUrl: baseURL}
+	for _, path := range paths {
+		config.Request = append(config.Request, rule.Request{Name: path, Method: "GET", Path: path})
+	}
+	return config
+}
+
+func TestRunner_OpenModel(t *testing.T) {
+	server, _ := newLoadServer(t, 0)
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		RPS:      100,
+		Duration: 500 * time.Millisecond,
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ModelOpen, report.Model)
+	assert.InDelta(t, 50, report.Requests, 10)
+	assert.Zero(t, report.Errors)
+	assert.Zero(t, report.Dropped)
+	assert.Equal(t, report.Requests, report.StatusCodes[http.StatusOK])
+	assert.Equal(t, report.Requests, report.Latency.Count())
+	assert.InDelta(t, 100, report.Throughput(), 25)
+}
+
+func TestRunner_OpenModel_DropsWhenUsersBusy(t *testing.T) {
+	server, peak := newLoadServer(t, 100*time.Millisecond)
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		Model:    ModelOpen,
+		RPS:      100,
+		Users:    2,
+		Duration: 300 * time.Millisecond,
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, peak.Load(), int64(2))
+	assert.Greater(t, report.Dropped, int64(0))
+	assert.GreaterOrEqual(t, report.Latency.Percentile(50), 100*time.Millisecond)
+}
+
+func TestRunner_ClosedModel(t *testing.T) {
+	server, peak := newLoadServer(t, 10*time.Millisecond)
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		Users:    3,
+		Duration: 300 * time.Millisecond,
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ModelClosed, report.Model)
+	assert.Equal(t, int64(3), peak.Load())
+	assert.Greater(t, report.Requests, int64(30))
+	assert.GreaterOrEqual(t, report.Latency.Min(), 10*time.Millisecond)
+}
+
+func TestRunner_ClosedModel_RateCap(t *testing.T) {
+	server, _ := newLoadServer(t, 0)
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		Model:    ModelClosed,
+		RPS:      50,
+		Users:    5,
+		Duration: 400 * time.Millisecond,
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.InDelta(t, 20, report.Requests, 5)
+}
+
+func TestRunner_ClosedModel_Stages(t *testing.T) {
+	server, peak := newLoadServer(t, 5*time.Millisecond)
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		Stages: []Stage{
+			{Duration: 200 * time.Millisecond, Target: 4},
+			{Duration: 200 * time.Millisecond, Target: 4},
+		},
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), peak.Load())
+	assert.GreaterOrEqual(t, report.Elapsed, 400*time.Millisecond)
+}
+
+func TestRunner_ErrorRate(t *testing.T) {
+	server, _ := newLoadServer(t, 0)
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok", "/fail"), Options{
+		Users:    1,
+		Duration: 200 * time.Millisecond,
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	// A single user alternates between both requests
+	assert.InDelta(t, report.StatusCodes[http.StatusOK], report.StatusCodes[http.StatusInternalServerError], 1)
+	assert.Equal(t, report.StatusCodes[http.StatusInternalServerError], report.Errors)
+	assert.InDelta(t, 0.5, report.ErrorRate(), 0.05)
+	assert.Equal(t, []int{http.StatusOK, http.StatusInternalServerError}, report.SortedStatusCodes())
+}
+
+func TestRunner_TransportErrors(t *testing.T) {
+	server, _ := newLoadServer(t, 0)
+	server.Close()
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		Users:    1,
+		Duration: 100 * time.Millisecond,
+	}).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Greater(t, report.Requests, int64(0))
+	assert.Equal(t, report.Requests, report.Errors)
+	assert.NotEmpty(t, report.ErrorMessages)
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	server, _ := newLoadServer(t, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	report, err := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		RPS:      50,
+		Duration: time.Minute,
+	}).Run(ctx)
+
+	require.NoError(t, err)
+	assert.Less(t, report.Elapsed, time.Second)
+}
+
+func TestRunner_Progress(t *testing.T) {
+	server, _ := newLoadServer(t, 0)
+	runner := NewRunner(loadConfig(server.URL, "/ok"), Options{
+		RPS:      20,
+		Duration: 1100 * time.Millisecond,
+	})
+
+	var calls atomic.Int64
+	runner.SetProgressCollector(func(elapsed time.Duration, requests, errors int64) {
+		calls.Add(1)
+		assert.Greater(t, requests, int64(0))
+	})
+
+	_, err := runner.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), calls.Load())
+}
+
+func TestRunner_InvalidOptions(t *testing.T) {
+	config := loadConfig("http://localhost", "/ok")
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "negative rps", opts: Options{RPS: -1}},
+		{name: "negative users", opts: Options{Users: -1}},
+		{name: "negative duration", opts: Options{Duration: -time.Second}},
+		{name: "open model without rate", opts: Options{Model: ModelOpen}},
+		{name: "unknown model", opts: Options{Model: "burst"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := NewRunner(config, tt.opts).Run(context.Background())
+
+			assert.Error(t, err)
+			assert.Nil(t, report)
+		})
+	}
+
+	_, err := NewRunner(&rule.Config{BaseUrl: "http://localhost"}, Options{}).Run(context.Background())
+	assert.Error(t, err)
+}
+
+func TestOptions_TotalDuration(t *testing.T) {
+	opts := Options{Duration: time.Second, Stages: []Stage{{Duration: 2 * time.Second, Target: 1}}}
+	assert.Equal(t, 2*time.Second, opts.TotalDuration())
+
+	opts.Duration = 5 * time.Second
+	assert.Equal(t, 5*time.Second, opts.TotalDuration())
+}
@@ -0,0 +1,95 @@
+package load
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stage describes one ramp step of a load test.
+// During a stage the target moves linearly from the previous target to Target.
+// The target is the request rate in the open model and the number of users in the closed model.
+type Stage struct {
+	// Duration is how long the stage lasts
+	Duration time.Duration
+
+	// Target is the rate or number of users reached at the end of the stage
+	Target int
+}
+
+// ParseStages parses a comma separated list of "duration:target" stages,
+// e.g. "10s:50,1m:50,10s:0" to ramp up to 50, hold for a minute and ramp down.
+//
+// Parameters:
+//   - spec: Stage list to parse
+//
+// Returns:
+//   - []Stage: Parsed stages, empty if spec is empty
+//   - error: Any error encountered while parsing
+func ParseStages(spec string) ([]Stage, error) {
+	var stages []Stage
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		durationText, targetText, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid stage %q: expected duration:target", part)
+		}
+
+		duration, err := time.ParseDuration(strings.TrimSpace(durationText))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid stage duration %q", durationText)
+		}
+
+		target, err := strconv.Atoi(strings.TrimSpace(targetText))
+		if err != nil || target < 0 {
+			return nil, fmt.Errorf("invalid stage target %q", targetText)
+		}
+
+		stages = append(stages, Stage{Duration: duration, Target: target})
+	}
+
+	return stages, nil
+}
+
+// stagesDuration returns the combined duration of all stages.
+//
+// Parameters:
+//   - stages: Ramp stages
+//
+// Returns:
+//   - time.Duration: Sum of the stage durations
+func stagesDuration(stages []Stage) time.Duration {
+	var total time.Duration
+	for _, stage := range stages {
+		total += stage.Duration
+	}
+	return total
+}
+
+// stageTarget returns the interpolated target at a point in time.
+// Before the first stage the ramp starts at zero; after the last stage its target is held.
+//
+// Parameters:
+//   - stages: Ramp stages
+//   - elapsed: Time since the start of the test
+//
+// Returns:
+//   - float64: Target rate or number of users at that time
+func stageTarget(stages []Stage, elapsed time.Duration) float64 {
+	from := 0.0
+	for _, stage := range stages {
+		if elapsed < stage.Duration {
+			progress := float64(elapsed) / float64(stage.Duration)
+			return from + (float64(stage.Target)-from)*progress
+		}
+		elapsed -= stage.Duration
+		from = float64(stage.Target)
+	}
+	return from
+}
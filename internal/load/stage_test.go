@@ -0,0 +1,71 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStages(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected []Stage
+		wantErr  bool
+	}{
+		{
+			name: "ramp up, hold and down",
+			spec: "10s:50, 1m:50,10s:0",
+			expected: []Stage{
+				{Duration: 10 * time.Second, Target: 50},
+				{Duration: time.Minute, Target: 50},
+				{Duration: 10 * time.Second, Target: 0},
+			},
+		},
+		{name: "empty", spec: "", expected: nil},
+		{name: "missing target", spec: "10s", wantErr: true},
+		{name: "invalid duration", spec: "ten:5", wantErr: true},
+		{name: "zero duration", spec: "0s:5", wantErr: true},
+		{name: "negative target", spec: "10s:-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := ParseStages(tt.spec)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stages)
+		})
+	}
+}
+
+func TestStageTarget(t *testing.T) {
+	stages := []Stage{
+		{Duration: 10 * time.Second, Target: 100},
+		{Duration: 10 * time.Second, Target: 100},
+		{Duration: 10 * time.Second, Target: 0},
+	}
+
+	tests := []struct {
+		elapsed  time.Duration
+		expected float64
+	}{
+		{0, 0},
+		{5 * time.Second, 50},
+		{10 * time.Second, 100},
+		{15 * time.Second, 100},
+		{25 * time.Second, 50},
+		{time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, stageTarget(stages, tt.elapsed), 0.001, "at %s", tt.elapsed)
+	}
+	assert.Equal(t, 30*time.Second, stagesDuration(stages))
+}
@@ -0,0 +1,167 @@
+package rule
+
+import (
+	"fmt"
+)
+
+// Authentication type constants define the supported auth schemes.
+const (
+	// AuthTypeNone disables authentication, e.g. to opt a request out of config-level auth
+	AuthTypeNone = "none"
+
+	// AuthTypeBasic sends HTTP Basic credentials
+	AuthTypeBasic = "basic"
+
+	// AuthTypeBearer sends a bearer token in the Authorization header
+	AuthTypeBearer = "bearer"
+
+	// AuthTypeDigest performs HTTP Digest authentication after a 401 challenge
+	AuthTypeDigest = "digest"
+
+	// AuthTypeAPIKey sends an API key in a header or query parameter
+	AuthTypeAPIKey = "api_key"
+
+	// AuthTypeOAuth2 obtains a bearer token from an OAuth2 token endpoint
+	AuthTypeOAuth2 = "oauth2"
+)
+
+// API key placement constants define where an API key is sent.
+const (
+	// APIKeyInHeader sends the API key as a request header
+	APIKeyInHeader = "header"
+
+	// APIKeyInQuery sends the API key as a query parameter
+	APIKeyInQuery = "query"
+)
+
+// Auth represents the authentication settings for requests.
+// It can be declared at config level (applied to every request)
+// or at request level (overriding the config-level settings).
+type Auth struct {
+	// Type is the authentication scheme (none, basic, bearer, digest, api_key)
+	Type string `toml:"type"`
+
+	// Username is the user name for basic and digest authentication
+	Username string `toml:"username"`
+
+	// Password is the password for basic and digest authentication
+	Password string `toml:"password"`
+
+	// Token is the bearer token, may contain ${var} references
+	Token string `toml:"token"`
+
+	// Name is the header or query parameter name for API key authentication
+	Name string `toml:"name"`
+
+	// Value is the API key value, may contain ${var} references
+	Value string `toml:"value"`
+
+	// In specifies where the API key is sent ("header" or "query", defaults to header)
+	In string `toml:"in"`
+
+	// TokenURL is the OAuth2 token endpoint
+	TokenURL string `toml:"token_url"`
+
+	// ClientID is the OAuth2 client identifier
+	ClientID string `toml:"client_id"`
+
+	// ClientSecret is the OAuth2 client secret
+	ClientSecret string `toml:"client_secret"`
+
+	// GrantType is the OAuth2 grant (client_credentials, password, refresh_token)
+	// Defaults to client_credentials; the password grant uses Username and Password
+	GrantType string `toml:"grant_type"`
+
+	// RefreshToken is the initial OAuth2 refresh token for the refresh_token grant
+	RefreshToken string `toml:"refresh_token"`
+
+	// Scopes is the list of OAuth2 scopes to request
+	Scopes []string `toml:"scopes"`
+
+	// TokenCache is an optional file path where OAuth2 tokens are cached between runs
+	TokenCache string `toml:"token_cache"`
+}
+
+// AuthFor returns the effective authentication settings for a request.
+// Request-level auth takes precedence over config-level auth, and an
+// explicit "none" type disables authentication for the request.
+//
+// Parameters:
+//   - req: Request configuration to look up auth for
+//
+// Returns:
+//   - *Auth: Effective auth settings, or nil if no authentication applies
+func (c *Config) AuthFor(req *Request) *Auth {
+	if c == nil {
+		return nil
+	}
+
+	auth := c.Auth
+	if req != nil && req.Auth != nil {
+		auth = req.Auth
+	}
+	if auth == nil || auth.Type == AuthTypeNone {
+		return nil
+	}
+	return auth
+}
+
+// Validate checks if the authentication settings are complete for their type.
+//
+// Returns:
+//   - error: Validation error or nil if settings are valid
+func (a *Auth) Validate() error {
+	switch a.Type {
+	case AuthTypeNone:
+		return nil
+	case AuthTypeBasic, AuthTypeDigest:
+		if a.Username == "" {
+			return fmt.Errorf("username is required for %s auth", a.Type)
+		}
+	case AuthTypeBearer:
+		if a.Token == "" {
+			return fmt.Errorf("token is required for bearer auth")
+		}
+	case AuthTypeAPIKey:
+		if a.Name == "" || a.Value == "" {
+			return fmt.Errorf("name and value are required for api_key auth")
+		}
+		if a.In != "" && a.In != APIKeyInHeader && a.In != APIKeyInQuery {
+			return fmt.Errorf("api_key auth 'in' must be 'header' or 'query', got '%s'", a.In)
+		}
+	case AuthTypeOAuth2:
+		return a.validateOAuth2()
+	case "":
+		return fmt.Errorf("auth type is required")
+	default:
+		return fmt.Errorf("unsupported auth type: %s", a.Type)
+	}
+	return nil
+}
+
+// validateOAuth2 checks the OAuth2 specific settings.
+//
+// Returns:
+//   - error: Validation error or nil if settings are valid
+func (a *Auth) validateOAuth2() error {
+	if a.TokenURL == "" {
+		return fmt.Errorf("token_url is required for oauth2 auth")
+	}
+	switch a.GrantType {
+	case "", "client_credentials":
+		if a.ClientID == "" {
+			return fmt.Errorf("client_id is required for client_credentials grant")
+		}
+	case "password":
+		if a.Username == "" {
+			return fmt.Errorf("username is required for password grant")
+		}
+	case "refresh_token":
+		if a.RefreshToken == "" {
+			return fmt.Errorf("refresh_token is required for refresh_token grant")
+		}
+	default:
+		return fmt.Errorf("unsupported oauth2 grant_type: %s", a.GrantType)
+	}
+	return nil
+}
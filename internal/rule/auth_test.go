@@ -0,0 +1,68 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		auth  Auth
+		isErr bool
+	}{
+		{"none", Auth{Type: AuthTypeNone}, false},
+		{"basic", Auth{Type: AuthTypeBasic, Username: "user", Password: "pass"}, false},
+		{"basic without username", Auth{Type: AuthTypeBasic, Password: "pass"}, true},
+		{"digest", Auth{Type: AuthTypeDigest, Username: "user", Password: "pass"}, false},
+		{"bearer", Auth{Type: AuthTypeBearer, Token: "${token}"}, false},
+		{"bearer without token", Auth{Type: AuthTypeBearer}, true},
+		{"api key header", Auth{Type: AuthTypeAPIKey, Name: "X-API-Key", Value: "secret"}, false},
+		{"api key query", Auth{Type: AuthTypeAPIKey, Name: "api_key", Value: "secret", In: APIKeyInQuery}, false},
+		{"api key invalid placement", Auth{Type: AuthTypeAPIKey, Name: "k", Value: "v", In: "cookie"}, true},
+		{"api key without name", Auth{Type: AuthTypeAPIKey, Value: "secret"}, true},
+		{"oauth2 client credentials", Auth{Type: AuthTypeOAuth2, TokenURL: "http://auth/token", ClientID: "id"}, false},
+		{"oauth2 without token url", Auth{Type: AuthTypeOAuth2, ClientID: "id"}, true},
+		{"oauth2 password grant", Auth{Type: AuthTypeOAuth2, TokenURL: "http://auth/token", GrantType: "password", Username: "u"}, false},
+		{"oauth2 refresh without token", Auth{Type: AuthTypeOAuth2, TokenURL: "http://auth/token", GrantType: "refresh_token"}, true},
+		{"oauth2 unknown grant", Auth{Type: AuthTypeOAuth2, TokenURL: "http://auth/token", GrantType: "implicit"}, true},
+		{"missing type", Auth{}, true},
+		{"unknown type", Auth{Type: "kerberos"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.Validate()
+			if tt.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_AuthFor(t *testing.T) {
+	configAuth := &Auth{Type: AuthTypeBearer, Token: "config-token"}
+	requestAuth := &Auth{Type: AuthTypeBasic, Username: "user"}
+
+	tests := []struct {
+		name     string
+		config   *Config
+		request  *Request
+		expected *Auth
+	}{
+		{"no auth", &Config{}, &Request{}, nil},
+		{"config level", &Config{Auth: configAuth}, &Request{}, configAuth},
+		{"request overrides config", &Config{Auth: configAuth}, &Request{Auth: requestAuth}, requestAuth},
+		{"request opts out", &Config{Auth: configAuth}, &Request{Auth: &Auth{Type: AuthTypeNone}}, nil},
+		{"nil config", nil, &Request{Auth: requestAuth}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.config.AuthFor(tt.request))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package rule
+
+import (
+	"fmt"
+
+	"github.com/ymatsukawa/jak/internal/condition"
+)
+
+// validateConditions checks the syntax of the when and skip_if expressions of a request.
+//
+// Parameters:
+//   - req: Request configuration to validate
+//
+// Returns:
+//   - error: Syntax error or nil if the expressions are valid
+func validateConditions(req Request) error {
+	if req.When != "" {
+		if _, err := condition.Parse(req.When); err != nil {
+			return fmt.Errorf("when: %w", err)
+		}
+	}
+	if req.SkipIf != "" {
+		if _, err := condition.Parse(req.SkipIf); err != nil {
+			return fmt.Errorf("skip_if: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     Request
+		wantErr string
+	}{
+		{name: "no conditions", req: Request{}},
+		{name: "valid when", req: Request{When: "${role} == 'admin'"}},
+		{name: "valid skip_if", req: Request{SkipIf: "${Lookup.status} != 404"}},
+		{name: "invalid when", req: Request{When: "${role} == admin"}, wantErr: "when:"},
+		{name: "invalid skip_if", req: Request{SkipIf: "${status} >"}, wantErr: "skip_if:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConditions(tt.req)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_ValidateConditions(t *testing.T) {
+	config := &Config{
+		BaseUrl: "http://example.com",
+		Request: []Request{{Name: "test", Method: "GET", Path: "/test", When: "${a} =="}},
+	}
+
+	err := config.Validate()
+
+	assert.ErrorContains(t, err, "invalid condition for request 'test'")
+}
@@ -2,8 +2,9 @@ package rule
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/ymatsukawa/jak/internal/file"
 )
 
@@ -24,12 +25,19 @@ type Request struct {
 	// Name is a unique identifier for the request
 	Name string `toml:"name"`
 
+	// Extends is the name of a template whose settings the request inherits
+	Extends string `toml:"extends"`
+
 	// Method is the HTTP method (e.g., GET, POST)
 	Method string `toml:"method"`
 
 	// Path is the endpoint path (will be combined with BaseUrl)
 	Path string `toml:"path"`
 
+	// Query contains query string parameters appended to the URL
+	// Values are encoded automatically; arrays become repeated parameters
+	Query QueryParams `toml:"query"`
+
 	// Headers is a list of header strings in format "Key: Value"
 	Headers []string `toml:"headers"`
 
@@ -49,11 +57,59 @@ type Request struct {
 	// DependsOn specifies the name of the request this one depends on
 	// For chain requests, this request will only be executed after its dependency
 	DependsOn string `toml:"depends_on"`
+
+	// Auth overrides the config-level authentication for this request
+	Auth *Auth `toml:"auth"`
+
+	// Signing overrides the config-level request signing for this request
+	Signing *Signing `toml:"signing"`
+
+	// Proxy overrides the config-level proxy for this request ("direct" disables it)
+	Proxy string `toml:"proxy"`
+
+	// FollowRedirects overrides the config-level redirect following for this request
+	FollowRedirects *bool `toml:"follow_redirects"`
+
+	// MaxRedirects overrides the config-level redirect limit for this request
+	MaxRedirects *int `toml:"max_redirects"`
+
+	// KeepMethodOnRedirect overrides the config-level 307/308 method handling for this request
+	KeepMethodOnRedirect *bool `toml:"keep_method_on_redirect"`
+
+	// Data is a CSV or JSON dataset file; the request is executed once per row
+	// with the columns available as ${column} and the row index as ${$index}
+	Data string `toml:"data"`
+
+	// Paginate follows subsequent pages and merges their items into one JSON array
+	Paginate *Paginate `toml:"paginate"`
+
+	// When is a condition that must hold for the request to run in a chain
+	When string `toml:"when"`
+
+	// SkipIf is a condition that skips the request in a chain when it holds
+	SkipIf string `toml:"skip_if"`
+
+	// Poll re-issues the request until a condition on the response holds
+	Poll *Poll `toml:"poll"`
+
+	// ForEach is a JSON array, usually an extracted ${variable}; the request is executed
+	// in a chain once per element with the element available as ${item} and its index as ${$index}
+	ForEach string `toml:"for_each"`
+
+	// Collect maps array variables to variables extracted in each for_each iteration
+	Collect map[string]string `toml:"collect"`
+
+	// SnapshotIgnore lists JSON paths masked in the response snapshot of this request
+	SnapshotIgnore []string `toml:"snapshot_ignore"`
 }
 
 // Config represents the entire configuration for execution.
 // It contains global settings and a list of request configurations.
 type Config struct {
+	// Include lists configuration files whose settings and requests are merged into this one
+	// Paths are relative to this file; settings of this file take precedence
+	Include []string `toml:"include"`
+
 	// BaseUrl is the base URL prefix for all requests
 	BaseUrl string `toml:"base_url"`
 
@@ -66,13 +122,76 @@ type Config struct {
 	// IgnoreFail continues execution even if requests fail
 	IgnoreFail bool `toml:"ignore_fail"`
 
+	// MaxWorkers is the number of concurrent workers for batch requests (default 5)
+	MaxWorkers int `toml:"max_workers"`
+
+	// RateLimit caps batch requests per second across all workers (0 disables the limit)
+	RateLimit float64 `toml:"rate_limit"`
+
+	// RateBurst is the number of requests that may be sent at once before rate_limit applies (default 1)
+	RateBurst int `toml:"rate_burst"`
+
+	// DelayBetween is the pause between sequential batch requests, e.g. "500ms"
+	DelayBetween time.Duration `toml:"delay_between"`
+
+	// Auth is the default authentication applied to all requests
+	Auth *Auth `toml:"auth"`
+
+	// Signing is the default request signing applied to all requests
+	Signing *Signing `toml:"signing"`
+
+	// TLS configures certificates and protocol settings for HTTPS connections
+	TLS *TLS `toml:"tls"`
+
+	// Proxy is the proxy URL for all requests (http, https or socks5 scheme)
+	// Credentials for proxy authentication may be given as user info
+	Proxy string `toml:"proxy"`
+
+	// NoProxy is a comma-separated list of hosts, domains or CIDR ranges that bypass the proxy
+	NoProxy string `toml:"no_proxy"`
+
+	// FollowRedirects enables following redirects (default true)
+	FollowRedirects *bool `toml:"follow_redirects"`
+
+	// MaxRedirects is the maximum number of redirects to follow (default 10); 0 follows none
+	MaxRedirects *int `toml:"max_redirects"`
+
+	// KeepMethodOnRedirect keeps the method and body on 307/308 redirects (default true)
+	// When false, those redirects are followed with a GET
+	KeepMethodOnRedirect *bool `toml:"keep_method_on_redirect"`
+
+	// Cookies enables a cookie jar shared by all requests of a run
+	// Defaults to enabled for chain and disabled for batch execution
+	Cookies *bool `toml:"cookies"`
+
+	// Data is a CSV or JSON dataset file; all requests (or the whole chain) are executed once per row
+	Data string `toml:"data"`
+
+	// SnapshotIgnore lists JSON paths masked in all response snapshots, e.g. "data.#.created_at"
+	SnapshotIgnore []string `toml:"snapshot_ignore"`
+
+	// Env maps environment names to their settings, e.g. [env.staging]
+	Env map[string]Environment `toml:"env"`
+
+	// Diff configures the comparison of responses between two environments
+	Diff *Diff `toml:"diff"`
+
+	// Templates maps template names to request defaults, e.g. [templates.json_api]
+	Templates map[string]Request `toml:"templates"`
+
 	// Request is a list of request configurations to execute
 	Request []Request `toml:"request"`
+
+	// dir is the directory of the configuration file, used to resolve relative paths
+	dir string
+
+	// includes are the resolved paths of all included files, including nested ones
+	includes []string
 }
 
 // LoadConfig loads a configuration from the given file path.
-// It resolves the absolute path, decodes the TOML file, and
-// sets default values for unspecified fields.
+// It resolves the absolute path, decodes the TOML file with the files it
+// includes, applies request templates and sets default values for unspecified fields.
 //
 // Parameters:
 //   - path: Path to the configuration file
@@ -87,11 +206,15 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to resolve config path: %w", err)
 	}
 
-	// Decode TOML file
-	var config Config
-	_, err = toml.DecodeFile(configPath, &config)
+	// Decode the TOML file and the files it includes
+	config, err := loadConfigFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode TOML: %w", err)
+		return nil, err
+	}
+
+	// Apply the templates requests extend
+	if err := config.applyTemplates(); err != nil {
+		return nil, err
 	}
 
 	// Set default timeout if not specified
@@ -99,7 +222,37 @@ func LoadConfig(path string) (*Config, error) {
 		config.Timeout = DefaultTimeout
 	}
 
-	return &config, nil
+	return config, nil
+}
+
+// ResolvePath resolves a path given in the configuration, such as a dataset file.
+// Relative paths are resolved against the directory of the configuration file,
+// or the working directory if the configuration was not loaded from a file.
+//
+// Parameters:
+//   - path: Path as written in the configuration
+//
+// Returns:
+//   - string: Resolved path
+func (c *Config) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || c == nil || c.dir == "" {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
+
+// CookiesEnabled reports whether a cookie jar should be used for the run.
+//
+// Parameters:
+//   - defaultValue: Value used when the cookies setting is not configured
+//
+// Returns:
+//   - bool: True if cookies should be stored and sent
+func (c *Config) CookiesEnabled(defaultValue bool) bool {
+	if c == nil || c.Cookies == nil {
+		return defaultValue
+	}
+	return *c.Cookies
 }
 
 // Validate checks if the configuration is valid.
@@ -127,6 +280,39 @@ func (c *Config) validateBaseConfig() error {
 	if len(c.Request) == 0 {
 		return fmt.Errorf("at least one request must be defined")
 	}
+	if c.Auth != nil {
+		if err := c.Auth.Validate(); err != nil {
+			return fmt.Errorf("invalid auth in config: %w", err)
+		}
+	}
+	if c.Signing != nil {
+		if err := c.Signing.Validate(); err != nil {
+			return fmt.Errorf("invalid signing in config: %w", err)
+		}
+	}
+	if err := validateProxy(c.Proxy); err != nil {
+		return fmt.Errorf("invalid proxy in config: %w", err)
+	}
+	if err := validateMaxRedirects(c.MaxRedirects); err != nil {
+		return fmt.Errorf("invalid redirect settings in config: %w", err)
+	}
+	if err := c.validateThrottle(); err != nil {
+		return fmt.Errorf("invalid batch settings in config: %w", err)
+	}
+	if err := validateData(c.Data); err != nil {
+		return fmt.Errorf("invalid data in config: %w", err)
+	}
+	if err := validateIgnorePaths(c.SnapshotIgnore); err != nil {
+		return fmt.Errorf("invalid snapshot_ignore in config: %w", err)
+	}
+	if err := c.validateEnvironments(); err != nil {
+		return fmt.Errorf("invalid env in config: %w", err)
+	}
+	if c.Diff != nil {
+		if err := c.Diff.Validate(); err != nil {
+			return fmt.Errorf("invalid diff in config: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -141,6 +327,9 @@ func (c *Config) validateRequests() error {
 		if err := validateRequest(req, i, nameSet); err != nil {
 			return err
 		}
+		if err := c.validateAuthSigning(&c.Request[i]); err != nil {
+			return fmt.Errorf("invalid signing for request '%s': %w", req.Name, err)
+		}
 	}
 	return nil
 }
@@ -162,6 +351,44 @@ func validateRequest(req Request, index int, nameSet map[string]bool) error {
 	if err := validateRequestBasics(req); err != nil {
 		return err
 	}
+	if req.Auth != nil {
+		if err := req.Auth.Validate(); err != nil {
+			return fmt.Errorf("invalid auth for request '%s': %w", req.Name, err)
+		}
+	}
+	if req.Signing != nil {
+		if err := req.Signing.Validate(); err != nil {
+			return fmt.Errorf("invalid signing for request '%s': %w", req.Name, err)
+		}
+	}
+	if err := validateProxy(req.Proxy); err != nil {
+		return fmt.Errorf("invalid proxy for request '%s': %w", req.Name, err)
+	}
+	if err := validateMaxRedirects(req.MaxRedirects); err != nil {
+		return fmt.Errorf("invalid redirect settings for request '%s': %w", req.Name, err)
+	}
+	if err := validateData(req.Data); err != nil {
+		return fmt.Errorf("invalid data for request '%s': %w", req.Name, err)
+	}
+	if req.Paginate != nil {
+		if err := req.Paginate.Validate(); err != nil {
+			return fmt.Errorf("invalid paginate for request '%s': %w", req.Name, err)
+		}
+	}
+	if err := validateConditions(req); err != nil {
+		return fmt.Errorf("invalid condition for request '%s': %w", req.Name, err)
+	}
+	if req.Poll != nil {
+		if err := req.Poll.Validate(); err != nil {
+			return fmt.Errorf("invalid poll for request '%s': %w", req.Name, err)
+		}
+	}
+	if err := validateForEach(req); err != nil {
+		return fmt.Errorf("invalid for_each for request '%s': %w", req.Name, err)
+	}
+	if err := validateIgnorePaths(req.SnapshotIgnore); err != nil {
+		return fmt.Errorf("invalid snapshot_ignore for request '%s': %w", req.Name, err)
+	}
 	return validateRequestBody(req)
 }
 
@@ -220,3 +220,12 @@ func TestConfig_Validate(t *testing.T) {
 func strPtrTest(s string) *string {
 	return &s
 }
+
+func TestConfig_CookiesEnabled(t *testing.T) {
+	enabled, disabled := true, false
+
+	assert.True(t, (&Config{}).CookiesEnabled(true))
+	assert.False(t, (&Config{}).CookiesEnabled(false))
+	assert.True(t, (&Config{Cookies: &enabled}).CookiesEnabled(false))
+	assert.False(t, (&Config{Cookies: &disabled}).CookiesEnabled(true))
+}
@@ -0,0 +1,36 @@
+package rule
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Dataset format constants define the supported data file extensions.
+const (
+	// DataFormatCSV is a CSV file with a header row naming the columns
+	DataFormatCSV = ".csv"
+
+	// DataFormatJSON is a JSON array of objects
+	DataFormatJSON = ".json"
+)
+
+// validateData checks the data setting.
+//
+// Parameters:
+//   - data: Configured dataset file, may be empty
+//
+// Returns:
+//   - error: Validation error or nil if the setting is valid
+func validateData(data string) error {
+	if data == "" {
+		return nil
+	}
+
+	switch strings.ToLower(filepath.Ext(data)) {
+	case DataFormatCSV, DataFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported data file %q: expected %s or %s", data, DataFormatCSV, DataFormatJSON)
+	}
+}
@@ -0,0 +1,74 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateData(t *testing.T) {
+	tests := []struct {
+		data  string
+		isErr bool
+	}{
+		{data: "", isErr: false},
+		{data: "users.csv", isErr: false},
+		{data: "data/users.JSON", isErr: false},
+		{data: "users.txt", isErr: true},
+		{data: "users", isErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			err := validateData(tt.data)
+
+			if tt.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateData(t *testing.T) {
+	config := &Config{
+		BaseUrl: "http://example.com",
+		Request: []Request{{Name: "test", Method: "GET", Path: "/test", Data: "users.xml"}},
+	}
+
+	err := config.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid data for request 'test'")
+
+	config.Request[0].Data = ""
+	config.Data = "users.xml"
+	assert.ErrorContains(t, config.Validate(), "invalid data in config")
+}
+
+func TestConfig_ResolvePath(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.toml")
+	content := `
+base_url = "http://example.com"
+data = "users.csv"
+
+[[request]]
+name = "test"
+method = "GET"
+path = "/test"
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+	config, err := LoadConfig(configPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "users.csv"), config.ResolvePath(config.Data))
+	assert.Equal(t, "/abs/users.csv", config.ResolvePath("/abs/users.csv"))
+	assert.Equal(t, "", config.ResolvePath(""))
+	assert.Equal(t, "users.csv", (&Config{}).ResolvePath("users.csv"))
+}
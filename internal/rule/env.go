@@ -0,0 +1,78 @@
+package rule
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// Environment is a named deployment the requests of a configuration can be sent to.
+type Environment struct {
+	// BaseUrl replaces the base_url of the configuration
+	BaseUrl string `toml:"base_url"`
+}
+
+// Diff holds the settings of comparing responses between two environments.
+type Diff struct {
+	// Headers lists the response headers compared in addition to status and body (default Content-Type)
+	Headers []string `toml:"headers"`
+
+	// Ignore lists JSON paths left out of body comparisons, e.g. "meta.generated_at"
+	Ignore []string `toml:"ignore"`
+}
+
+// EnvironmentNames returns the names of the configured environments.
+//
+// Returns:
+//   - []string: Sorted environment names
+func (c *Config) EnvironmentNames() []string {
+	names := make([]string, 0, len(c.Env))
+	for name := range c.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithBaseUrl returns a copy of the configuration sending its requests to another base URL.
+//
+// Parameters:
+//   - baseUrl: Base URL replacing base_url
+//
+// Returns:
+//   - *Config: Copy of the configuration with the new base URL
+func (c *Config) WithBaseUrl(baseUrl string) *Config {
+	copied := *c
+	copied.BaseUrl = baseUrl
+	return &copied
+}
+
+// validateEnvironments checks the [env.<name>] tables.
+//
+// Returns:
+//   - error: Validation error or nil if all environments are valid
+func (c *Config) validateEnvironments() error {
+	for _, name := range c.EnvironmentNames() {
+		env := c.Env[name]
+		if env.BaseUrl == "" {
+			return fmt.Errorf("env '%s' requires base_url", name)
+		}
+		if parsed, err := url.Parse(env.BaseUrl); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("env '%s' has invalid base_url '%s'", name, env.BaseUrl)
+		}
+	}
+	return nil
+}
+
+// Validate checks the diff settings.
+//
+// Returns:
+//   - error: Validation error or nil if the settings are valid
+func (d *Diff) Validate() error {
+	for _, header := range d.Headers {
+		if header == "" {
+			return fmt.Errorf("headers must not contain empty names")
+		}
+	}
+	return validateIgnorePaths(d.Ignore)
+}
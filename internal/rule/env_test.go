@@ -0,0 +1,84 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEnvironments(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]Environment
+		wantErr string
+	}{
+		{name: "no environments"},
+		{name: "environments", env: map[string]Environment{"legacy": {BaseUrl: "https://old.example.com"}, "new": {BaseUrl: "http://localhost:8080/v2"}}},
+		{name: "missing base_url", env: map[string]Environment{"legacy": {}}, wantErr: "env 'legacy' requires base_url"},
+		{name: "relative base_url", env: map[string]Environment{"new": {BaseUrl: "/v2"}}, wantErr: "invalid base_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{Env: tt.env}).validateEnvironments()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDiff_Validate(t *testing.T) {
+	assert.NoError(t, (&Diff{Headers: []string{"Content-Type"}, Ignore: []string{"meta.generated_at"}}).Validate())
+	assert.ErrorContains(t, (&Diff{Headers: []string{""}}).Validate(), "empty names")
+	assert.ErrorContains(t, (&Diff{Ignore: []string{"a..b"}}).Validate(), "empty component")
+}
+
+func TestConfig_WithBaseUrl(t *testing.T) {
+	config := &Config{BaseUrl: "https://old.example.com", Timeout: 10}
+
+	copied := config.WithBaseUrl("https://new.example.com")
+
+	assert.Equal(t, "https://new.example.com", copied.BaseUrl)
+	assert.Equal(t, uint8(10), copied.Timeout)
+	assert.Equal(t, "https://old.example.com", config.BaseUrl)
+}
+
+func TestLoadConfig_Environments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "env.toml")
+	content := `
+base_url = "http://localhost:8080"
+
+[env.legacy]
+base_url = "https://old.example.com"
+
+[env.new]
+base_url = "https://new.example.com"
+
+[diff]
+headers = ["Content-Type", "Cache-Control"]
+ignore = ["meta.generated_at"]
+
+[[request]]
+name = "Get Users"
+method = "GET"
+path = "/users"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, []string{"legacy", "new"}, config.EnvironmentNames())
+	assert.Equal(t, "https://old.example.com", config.Env["legacy"].BaseUrl)
+	require.NotNil(t, config.Diff)
+	assert.Equal(t, []string{"Content-Type", "Cache-Control"}, config.Diff.Headers)
+	assert.Equal(t, []string{"meta.generated_at"}, config.Diff.Ignore)
+}
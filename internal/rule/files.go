@@ -0,0 +1,33 @@
+package rule
+
+// ReferencedFiles returns the files the configuration reads besides itself:
+// included configuration files, dataset files of the config and its requests,
+// and TLS certificates and keys.
+//
+// Returns:
+//   - []string: Resolved file paths without duplicates, in configuration order
+func (c *Config) ReferencedFiles() []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+
+	for _, include := range c.includes {
+		add(include)
+	}
+	add(c.ResolvePath(c.Data))
+	for _, req := range c.Request {
+		add(c.ResolvePath(req.Data))
+	}
+	if c.TLS != nil {
+		add(c.TLS.CACert)
+		add(c.TLS.ClientCert)
+		add(c.TLS.ClientKey)
+	}
+	return files
+}
@@ -0,0 +1,50 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferencedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "files.toml")
+	content := `
+base_url = "http://example.com"
+data = "users.csv"
+
+[tls]
+ca_cert = "/etc/ssl/ca.pem"
+
+[[request]]
+name = "Login"
+method = "POST"
+path = "/login"
+data = "logins.json"
+
+[[request]]
+name = "Again"
+method = "POST"
+path = "/login"
+data = "logins.json"
+
+[[request]]
+name = "Plain"
+method = "GET"
+path = "/"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "users.csv"),
+		filepath.Join(dir, "logins.json"),
+		"/etc/ssl/ca.pem",
+	}, config.ReferencedFiles())
+	assert.Empty(t, (&Config{}).ReferencedFiles())
+}
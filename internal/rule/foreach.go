@@ -0,0 +1,34 @@
+package rule
+
+import (
+	"fmt"
+)
+
+// validateForEach checks the for_each and collect settings of a request.
+//
+// Parameters:
+//   - req: Request configuration to validate
+//
+// Returns:
+//   - error: Validation error or nil if the settings are valid
+func validateForEach(req Request) error {
+	if req.ForEach == "" {
+		if len(req.Collect) > 0 {
+			return fmt.Errorf("collect requires for_each")
+		}
+		return nil
+	}
+
+	if req.Data != "" {
+		return fmt.Errorf("for_each cannot be combined with data")
+	}
+	for name, from := range req.Collect {
+		if name == "" || from == "" {
+			return fmt.Errorf("collect entries need a variable name and an extracted variable")
+		}
+		if _, ok := req.Extract[from]; !ok {
+			return fmt.Errorf("collect %s: %s is not extracted by the request", name, from)
+		}
+	}
+	return nil
+}
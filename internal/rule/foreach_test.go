@@ -0,0 +1,37 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateForEach(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     Request
+		wantErr string
+	}{
+		{name: "no for_each", req: Request{}},
+		{name: "for_each", req: Request{ForEach: "${ids}"}},
+		{
+			name: "for_each with collect",
+			req:  Request{ForEach: "${ids}", Extract: map[string]string{"name": "name"}, Collect: map[string]string{"names": "name"}},
+		},
+		{name: "collect without for_each", req: Request{Collect: map[string]string{"names": "name"}}, wantErr: "collect requires for_each"},
+		{name: "collect of variable not extracted", req: Request{ForEach: "${ids}", Collect: map[string]string{"names": "name"}}, wantErr: "not extracted"},
+		{name: "for_each with data", req: Request{ForEach: "${ids}", Data: "ids.csv"}, wantErr: "cannot be combined with data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateForEach(tt.req)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
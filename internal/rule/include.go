@@ -0,0 +1,145 @@
+package rule
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configLoader loads a configuration file together with the files it includes.
+type configLoader struct {
+	// stack holds the files being loaded, outermost first, to detect include cycles
+	stack []string
+
+	// loaded holds the files already merged, so a file included twice is merged once
+	loaded map[string]bool
+}
+
+// loadConfigFile decodes a configuration file and merges the files it includes.
+//
+// Parameters:
+//   - path: Absolute path to the configuration file
+//
+// Returns:
+//   - *Config: Configuration with the settings and requests of the included files
+//   - error: Any error encountered while decoding, or an include cycle
+func loadConfigFile(path string) (*Config, error) {
+	loader := &configLoader{loaded: make(map[string]bool)}
+	config, _, err := loader.load(path)
+	return config, err
+}
+
+// load decodes a configuration file and merges the files it includes.
+//
+// Parameters:
+//   - path: Absolute path to the configuration file
+//
+// Returns:
+//   - *Config: Configuration with the settings and requests of the included files,
+//     nil if the file was already merged
+//   - map[string]bool: Top-level keys defined by the file or the files it includes
+//   - error: Any error encountered while decoding, or an include cycle
+func (l *configLoader) load(path string) (*Config, map[string]bool, error) {
+	for _, loading := range l.stack {
+		if loading == path {
+			return nil, nil, fmt.Errorf("include cycle: %s", strings.Join(append(l.stack, path), " -> "))
+		}
+	}
+	if l.loaded[path] {
+		return nil, nil, nil
+	}
+	l.loaded[path] = true
+
+	var config Config
+	md, err := toml.DecodeFile(path, &config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode TOML: %w", err)
+	}
+	config.dir = filepath.Dir(path)
+
+	defined := make(map[string]bool)
+	for _, key := range md.Keys() {
+		defined[key[0]] = true
+	}
+	if len(config.Include) == 0 {
+		return &config, defined, nil
+	}
+
+	// Merge the included files in order, then this file on top of them
+	merged := &Config{}
+	mergedDefined := make(map[string]bool)
+	l.stack = append(l.stack, path)
+	for _, include := range config.Include {
+		includePath := config.ResolvePath(include)
+		included, includedDefined, err := l.load(includePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to include '%s': %w", include, err)
+		}
+		if included == nil {
+			continue
+		}
+		included.resolveDataPaths()
+
+		merged.merge(included, includedDefined)
+		merged.includes = append(append(merged.includes, includePath), included.includes...)
+		for key := range includedDefined {
+			mergedDefined[key] = true
+		}
+	}
+	l.stack = l.stack[:len(l.stack)-1]
+
+	merged.merge(&config, defined)
+	merged.Include = config.Include
+	merged.dir = config.dir
+	for key := range defined {
+		mergedDefined[key] = true
+	}
+	return merged, mergedDefined, nil
+}
+
+// merge copies the settings defined by another configuration into this one.
+// Requests are appended; environments and templates are merged by name,
+// and every other defined setting replaces the current one.
+//
+// Parameters:
+//   - other: Configuration whose settings take precedence
+//   - defined: Top-level keys defined by the other configuration
+func (c *Config) merge(other *Config, defined map[string]bool) {
+	target := reflect.ValueOf(c).Elem()
+	source := reflect.ValueOf(other).Elem()
+
+	for i := 0; i < target.NumField(); i++ {
+		key, _, _ := strings.Cut(target.Type().Field(i).Tag.Get("toml"), ",")
+		if key == "" || key == "include" || !defined[key] {
+			continue
+		}
+
+		field, value := target.Field(i), source.Field(i)
+		switch {
+		case key == "request":
+			field.Set(reflect.AppendSlice(field, value))
+		case field.Kind() == reflect.Map && !field.IsNil():
+			for _, name := range value.MapKeys() {
+				field.SetMapIndex(name, value.MapIndex(name))
+			}
+		default:
+			field.Set(value)
+		}
+	}
+}
+
+// resolveDataPaths makes the relative dataset paths of an included configuration
+// absolute, so they stay relative to the included file once merged.
+func (c *Config) resolveDataPaths() {
+	c.Data = c.ResolvePath(c.Data)
+	for i := range c.Request {
+		c.Request[i].Data = c.ResolvePath(c.Request[i].Data)
+	}
+	for name, template := range c.Templates {
+		template.Data = c.ResolvePath(template.Data)
+		c.Templates[name] = template
+	}
+}
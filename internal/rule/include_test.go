@@ -0,0 +1,182 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFiles writes configuration files into a temporary directory and returns it
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLoadConfig_Include(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"common/auth.toml": `
+base_url = "http://example.com"
+timeout = 10
+ignore_fail = true
+
+[auth]
+type = "bearer"
+token = "${token}"
+
+[env.staging]
+base_url = "http://staging.example.com"
+
+[[request]]
+name = "Login"
+method = "POST"
+path = "/login"
+data = "users.csv"
+extract = { token = "token" }
+`,
+		"main.toml": `
+include = ["common/auth.toml"]
+timeout = 3
+ignore_fail = false
+
+[env.prod]
+base_url = "http://prod.example.com"
+
+[[request]]
+name = "Profile"
+method = "GET"
+path = "/me"
+depends_on = "Login"
+`,
+	})
+
+	config, err := LoadConfig(filepath.Join(dir, "main.toml"))
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	// Settings of the including file take precedence, even when set to false
+	assert.Equal(t, "http://example.com", config.BaseUrl)
+	assert.Equal(t, uint8(3), config.Timeout)
+	assert.False(t, config.IgnoreFail)
+	require.NotNil(t, config.Auth)
+	assert.Equal(t, "bearer", config.Auth.Type)
+	assert.Equal(t, []string{"prod", "staging"}, config.EnvironmentNames())
+
+	// Included requests come first; their datasets stay relative to the included file
+	require.Len(t, config.Request, 2)
+	assert.Equal(t, "Login", config.Request[0].Name)
+	assert.Equal(t, filepath.Join(dir, "common", "users.csv"), config.Request[0].Data)
+	assert.Equal(t, "Profile", config.Request[1].Name)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "common", "auth.toml"),
+		filepath.Join(dir, "common", "users.csv"),
+	}, config.ReferencedFiles())
+}
+
+func TestLoadConfig_NestedIncludes(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"common.toml": `
+base_url = "http://example.com"
+
+[[request]]
+name = "Health"
+method = "GET"
+path = "/health"
+`,
+		"users/users.toml": `
+include = ["../common.toml"]
+
+[[request]]
+name = "Users"
+method = "GET"
+path = "/users"
+`,
+		"orders.toml": `
+include = ["common.toml"]
+
+[[request]]
+name = "Orders"
+method = "GET"
+path = "/orders"
+`,
+		"main.toml": `include = ["users/users.toml", "orders.toml"]`,
+	})
+
+	config, err := LoadConfig(filepath.Join(dir, "main.toml"))
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	// A file included twice is merged once
+	var names []string
+	for _, req := range config.Request {
+		names = append(names, req.Name)
+	}
+	assert.Equal(t, []string{"Health", "Users", "Orders"}, names)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "users", "users.toml"),
+		filepath.Join(dir, "common.toml"),
+		filepath.Join(dir, "orders.toml"),
+	}, config.ReferencedFiles())
+}
+
+func TestLoadConfig_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr []string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"main.toml":  `include = ["a.toml"]`,
+				"a.toml":     `include = ["sub/b.toml"]`,
+				"sub/b.toml": `include = ["../a.toml"]`,
+			},
+			wantErr: []string{"failed to include 'a.toml'", "include cycle:", "a.toml -> ", "b.toml -> ", "a.toml"},
+		},
+		{
+			name: "self",
+			files: map[string]string{
+				"main.toml": `include = ["main.toml"]`,
+			},
+			wantErr: []string{"include cycle:", "main.toml -> "},
+		},
+		{
+			name: "missing file",
+			files: map[string]string{
+				"main.toml": `include = ["missing.toml"]`,
+			},
+			wantErr: []string{"failed to include 'missing.toml'"},
+		},
+		{
+			name: "invalid included file",
+			files: map[string]string{
+				"main.toml":   `include = ["broken.toml"]`,
+				"broken.toml": `base_url = `,
+			},
+			wantErr: []string{"failed to include 'broken.toml'", "failed to decode TOML"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeConfigFiles(t, tt.files)
+
+			_, err := LoadConfig(filepath.Join(dir, "main.toml"))
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package rule
+
+import (
+	"fmt"
+)
+
+// Paginate type constants define the supported pagination styles.
+const (
+	// PaginateTypeLink follows the rel="next" URL of the Link response header
+	PaginateTypeLink = "link"
+
+	// PaginateTypeCursor sends the cursor found in each page as a query parameter
+	PaginateTypeCursor = "cursor"
+
+	// PaginateTypePage increments a page number query parameter
+	PaginateTypePage = "page"
+)
+
+// Default pagination settings.
+const (
+	// DefaultMaxPages is the page cap when max_pages is not configured
+	DefaultMaxPages = 10
+
+	// DefaultCursorParam is the query parameter receiving the cursor
+	DefaultCursorParam = "cursor"
+
+	// DefaultPageParam is the query parameter receiving the page number
+	DefaultPageParam = "page"
+
+	// DefaultStartPage is the number of the first page
+	DefaultStartPage = 1
+)
+
+// Paginate represents the pagination settings of a request.
+// The request is repeated for subsequent pages and the JSON arrays found in
+// every page are merged into a single array, which becomes the response body
+// used for extraction.
+type Paginate struct {
+	// Type is the pagination style (link, cursor, page)
+	Type string `toml:"type"`
+
+	// Items is the gjson path to the array in each page; empty if the body itself is the array
+	Items string `toml:"items"`
+
+	// MaxPages is the maximum number of pages to request (default 10)
+	MaxPages int `toml:"max_pages"`
+
+	// CursorPath is the gjson path to the next cursor in each page, for cursor pagination
+	CursorPath string `toml:"cursor_path"`
+
+	// CursorParam is the query parameter receiving the cursor (default "cursor")
+	CursorParam string `toml:"cursor_param"`
+
+	// PageParam is the query parameter receiving the page number (default "page")
+	PageParam string `toml:"page_param"`
+
+	// StartPage is the number of the first page, which is sent as configured (default 1)
+	StartPage *int `toml:"start_page"`
+}
+
+// PageLimit returns the maximum number of pages to request.
+//
+// Returns:
+//   - int: Configured max_pages, or DefaultMaxPages if not set
+func (p *Paginate) PageLimit() int {
+	if p.MaxPages > 0 {
+		return p.MaxPages
+	}
+	return DefaultMaxPages
+}
+
+// Param returns the query parameter that selects the next page.
+//
+// Returns:
+//   - string: Cursor or page parameter name, or empty for link pagination
+func (p *Paginate) Param() string {
+	switch p.Type {
+	case PaginateTypeCursor:
+		if p.CursorParam != "" {
+			return p.CursorParam
+		}
+		return DefaultCursorParam
+	case PaginateTypePage:
+		if p.PageParam != "" {
+			return p.PageParam
+		}
+		return DefaultPageParam
+	default:
+		return ""
+	}
+}
+
+// FirstPage returns the number of the first page for page pagination.
+//
+// Returns:
+//   - int: Configured start_page, or DefaultStartPage if not set
+func (p *Paginate) FirstPage() int {
+	if p.StartPage != nil {
+		return *p.StartPage
+	}
+	return DefaultStartPage
+}
+
+// Validate checks if the pagination settings are complete for their type.
+//
+// Returns:
+//   - error: Validation error or nil if settings are valid
+func (p *Paginate) Validate() error {
+	if p.MaxPages < 0 {
+		return fmt.Errorf("max_pages must not be negative: %d", p.MaxPages)
+	}
+
+	switch p.Type {
+	case PaginateTypeLink, PaginateTypePage:
+		return nil
+	case PaginateTypeCursor:
+		if p.CursorPath == "" {
+			return fmt.Errorf("cursor_path is required for cursor pagination")
+		}
+		return nil
+	case "":
+		return fmt.Errorf("paginate type is required")
+	default:
+		return fmt.Errorf("unsupported paginate type: %s", p.Type)
+	}
+}
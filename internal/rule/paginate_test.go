@@ -0,0 +1,104 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		paginate Paginate
+		isErr    bool
+	}{
+		{name: "link", paginate: Paginate{Type: PaginateTypeLink}},
+		{name: "page", paginate: Paginate{Type: PaginateTypePage, Items: "data", MaxPages: 5}},
+		{name: "cursor", paginate: Paginate{Type: PaginateTypeCursor, CursorPath: "next_cursor"}},
+		{name: "cursor without cursor_path", paginate: Paginate{Type: PaginateTypeCursor}, isErr: true},
+		{name: "missing type", paginate: Paginate{}, isErr: true},
+		{name: "unsupported type", paginate: Paginate{Type: "offset"}, isErr: true},
+		{name: "negative max_pages", paginate: Paginate{Type: PaginateTypeLink, MaxPages: -1}, isErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.paginate.Validate()
+
+			if tt.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPaginate_Defaults(t *testing.T) {
+	startPage := 0
+
+	tests := []struct {
+		name      string
+		paginate  Paginate
+		limit     int
+		param     string
+		firstPage int
+	}{
+		{name: "link", paginate: Paginate{Type: PaginateTypeLink}, limit: DefaultMaxPages, param: "", firstPage: DefaultStartPage},
+		{name: "cursor", paginate: Paginate{Type: PaginateTypeCursor}, limit: DefaultMaxPages, param: DefaultCursorParam, firstPage: DefaultStartPage},
+		{name: "page", paginate: Paginate{Type: PaginateTypePage}, limit: DefaultMaxPages, param: DefaultPageParam, firstPage: DefaultStartPage},
+		{
+			name:      "custom",
+			paginate:  Paginate{Type: PaginateTypePage, MaxPages: 3, PageParam: "p", StartPage: &startPage},
+			limit:     3,
+			param:     "p",
+			firstPage: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.limit, tt.paginate.PageLimit())
+			assert.Equal(t, tt.param, tt.paginate.Param())
+			assert.Equal(t, tt.firstPage, tt.paginate.FirstPage())
+		})
+	}
+}
+
+func TestLoadConfig_Paginate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "paginate.toml")
+	content := `
+base_url = "http://example.com"
+
+[[request]]
+name = "List Users"
+method = "GET"
+path = "/users"
+paginate = { type = "cursor", items = "data", cursor_path = "meta.next_cursor", max_pages = 5 }
+
+[[request]]
+name = "List Orders"
+method = "GET"
+path = "/orders"
+paginate = { type = "unknown" }
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	paginate := config.Request[0].Paginate
+	require.NotNil(t, paginate)
+	assert.Equal(t, PaginateTypeCursor, paginate.Type)
+	assert.Equal(t, "data", paginate.Items)
+	assert.Equal(t, "meta.next_cursor", paginate.CursorPath)
+	assert.Equal(t, 5, paginate.MaxPages)
+
+	err = config.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid paginate for request 'List Orders'")
+}
@@ -0,0 +1,93 @@
+package rule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ymatsukawa/jak/internal/condition"
+)
+
+// Default polling settings.
+const (
+	// DefaultPollInterval is the pause between attempts when interval is not configured
+	DefaultPollInterval = time.Second
+
+	// DefaultPollTimeout is how long to poll when timeout is not configured
+	DefaultPollTimeout = time.Minute
+)
+
+// Poll represents the polling settings of a request.
+// The request is re-issued until the until condition holds for the response,
+// e.g. to wait for an asynchronous job to finish.
+type Poll struct {
+	// Until is the condition ending the polling; bare paths refer to fields of the
+	// JSON response body and ${$status} is the status code
+	Until string `toml:"until"`
+
+	// Interval is the pause between attempts (default 1s)
+	Interval time.Duration `toml:"interval"`
+
+	// Timeout is how long to keep polling before giving up (default 1m)
+	Timeout time.Duration `toml:"timeout"`
+}
+
+// PollInterval returns the pause between attempts.
+//
+// Returns:
+//   - time.Duration: Configured interval, or DefaultPollInterval if not set
+func (p *Poll) PollInterval() time.Duration {
+	if p.Interval > 0 {
+		return p.Interval
+	}
+	return DefaultPollInterval
+}
+
+// PollTimeout returns how long to keep polling.
+//
+// Returns:
+//   - time.Duration: Configured timeout, or DefaultPollTimeout if not set
+func (p *Poll) PollTimeout() time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+	return DefaultPollTimeout
+}
+
+// Validate checks if the polling settings are valid.
+//
+// Returns:
+//   - error: Validation error or nil if settings are valid
+func (p *Poll) Validate() error {
+	if p.Until == "" {
+		return fmt.Errorf("until is required for polling")
+	}
+	if _, err := condition.ParseFields(p.Until); err != nil {
+		return fmt.Errorf("until: %w", err)
+	}
+	if p.Interval < 0 {
+		return fmt.Errorf("interval must not be negative: %s", p.Interval)
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative: %s", p.Timeout)
+	}
+	return nil
+}
+
+// PollTimeout returns the total time the requests of the configuration may spend polling.
+// It extends the execution deadline so that polling is not cut short by the request timeout.
+//
+// Returns:
+//   - time.Duration: Sum of the poll timeouts of all polling requests
+func (c *Config) PollTimeout() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	var total time.Duration
+	for _, req := range c.Request {
+		if req.Poll != nil {
+			total += req.Poll.PollTimeout()
+		}
+	}
+	return total
+}
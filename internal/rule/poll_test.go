@@ -0,0 +1,85 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoll_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		poll    Poll
+		wantErr string
+	}{
+		{name: "field condition", poll: Poll{Until: "status == 'done'"}},
+		{name: "status condition", poll: Poll{Until: "${$status} == 200", Interval: time.Second, Timeout: time.Minute}},
+		{name: "missing until", poll: Poll{}, wantErr: "until is required"},
+		{name: "invalid until", poll: Poll{Until: "status =="}, wantErr: "until:"},
+		{name: "negative interval", poll: Poll{Until: "done", Interval: -time.Second}, wantErr: "interval"},
+		{name: "negative timeout", poll: Poll{Until: "done", Timeout: -time.Second}, wantErr: "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.poll.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPoll_Defaults(t *testing.T) {
+	poll := &Poll{Until: "done"}
+	assert.Equal(t, DefaultPollInterval, poll.PollInterval())
+	assert.Equal(t, DefaultPollTimeout, poll.PollTimeout())
+
+	poll = &Poll{Until: "done", Interval: 2 * time.Second, Timeout: 2 * time.Minute}
+	assert.Equal(t, 2*time.Second, poll.PollInterval())
+	assert.Equal(t, 2*time.Minute, poll.PollTimeout())
+}
+
+func TestConfig_PollTimeout(t *testing.T) {
+	config := &Config{
+		Request: []Request{
+			{Name: "a"},
+			{Name: "b", Poll: &Poll{Until: "done"}},
+			{Name: "c", Poll: &Poll{Until: "done", Timeout: 30 * time.Second}},
+		},
+	}
+
+	assert.Equal(t, DefaultPollTimeout+30*time.Second, config.PollTimeout())
+	assert.Zero(t, (*Config)(nil).PollTimeout())
+}
+
+func TestLoadConfig_Poll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "poll.toml")
+	content := `
+base_url = "http://example.com"
+
+[[request]]
+name = "Job"
+method = "GET"
+path = "/jobs/1"
+poll = { until = "status == 'done'", interval = "2s", timeout = "2m" }
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	poll := config.Request[0].Poll
+	require.NotNil(t, poll)
+	assert.Equal(t, "status == 'done'", poll.Until)
+	assert.Equal(t, 2*time.Second, poll.Interval)
+	assert.Equal(t, 2*time.Minute, poll.Timeout)
+}
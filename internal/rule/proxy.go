@@ -0,0 +1,45 @@
+package rule
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ProxyDirect is the proxy value that sends a request without any proxy.
+const ProxyDirect = "direct"
+
+// validateProxy checks a proxy setting.
+// Accepted values are ProxyDirect or a URL with an http, https, socks5 or socks5h scheme;
+// a URL without scheme is treated as an HTTP proxy.
+//
+// Parameters:
+//   - proxy: Proxy setting to validate
+//
+// Returns:
+//   - error: Validation error or nil if the setting is valid
+func validateProxy(proxy string) error {
+	if proxy == "" || strings.EqualFold(proxy, ProxyDirect) {
+		return nil
+	}
+
+	raw := proxy
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL '%s': %w", proxy, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("unsupported proxy scheme '%s'", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return fmt.Errorf("proxy URL '%s' has no host", proxy)
+	}
+	return nil
+}
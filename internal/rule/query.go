@@ -0,0 +1,88 @@
+package rule
+
+import (
+	"fmt"
+)
+
+// QueryParams represents the query string parameters of a request.
+// Each key maps to one or more values, so both scalar entries
+// (page = "1") and array entries (tags = ["a", "b"]) are supported.
+type QueryParams map[string][]string
+
+// UnmarshalTOML decodes a TOML inline table into query parameters.
+// Scalar values (strings, numbers, booleans) become single-element lists,
+// while arrays are expanded into repeated parameters.
+//
+// Parameters:
+//   - data: Raw decoded TOML value
+//
+// Returns:
+//   - error: Error if the value is not a table or contains unsupported types
+func (q *QueryParams) UnmarshalTOML(data interface{}) error {
+	table, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("query must be a table, got %T", data)
+	}
+
+	params := make(QueryParams, len(table))
+	for key, raw := range table {
+		values, err := queryValues(key, raw)
+		if err != nil {
+			return err
+		}
+		params[key] = values
+	}
+
+	*q = params
+	return nil
+}
+
+// queryValues converts a single decoded TOML value into a list of strings.
+//
+// Parameters:
+//   - key: Query parameter name, used for error messages
+//   - raw: Raw decoded TOML value
+//
+// Returns:
+//   - []string: Parameter values
+//   - error: Error if the value type is not supported
+func queryValues(key string, raw interface{}) ([]string, error) {
+	switch value := raw.(type) {
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			str, err := queryScalar(key, item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, str)
+		}
+		return values, nil
+	default:
+		str, err := queryScalar(key, value)
+		if err != nil {
+			return nil, err
+		}
+		return []string{str}, nil
+	}
+}
+
+// queryScalar converts a scalar TOML value into its string form.
+//
+// Parameters:
+//   - key: Query parameter name, used for error messages
+//   - raw: Raw decoded TOML value
+//
+// Returns:
+//   - string: String representation of the value
+//   - error: Error if the value is not a scalar
+func queryScalar(key string, raw interface{}) (string, error) {
+	switch value := raw.(type) {
+	case string:
+		return value, nil
+	case int64, float64, bool:
+		return fmt.Sprintf("%v", value), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T for query parameter '%s'", raw, key)
+	}
+}
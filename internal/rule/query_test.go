@@ -0,0 +1,51 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryParams_UnmarshalTOML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected QueryParams
+		isErr    bool
+	}{
+		{
+			name:     "string values",
+			input:    `query = { page = "1", sort = "name" }`,
+			expected: QueryParams{"page": {"1"}, "sort": {"name"}},
+		},
+		{
+			name:     "array values",
+			input:    `query = { tags = ["a", "b"] }`,
+			expected: QueryParams{"tags": {"a", "b"}},
+		},
+		{
+			name:     "number and boolean values",
+			input:    `query = { limit = 10, ratio = 0.5, active = true }`,
+			expected: QueryParams{"limit": {"10"}, "ratio": {"0.5"}, "active": {"true"}},
+		},
+		{
+			name:  "nested table is rejected",
+			input: `query = { filter = { name = "x" } }`,
+			isErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req Request
+			_, err := toml.Decode(tt.input, &req)
+			if tt.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, req.Query)
+		})
+	}
+}
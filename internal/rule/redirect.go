@@ -0,0 +1,68 @@
+package rule
+
+import (
+	"fmt"
+)
+
+// Redirect represents the effective redirect settings of a request.
+// Nil fields are not configured and keep the client defaults.
+type Redirect struct {
+	// Follow enables following redirects
+	Follow *bool
+
+	// MaxRedirects is the maximum number of redirects to follow
+	MaxRedirects *int
+
+	// KeepMethod keeps the method and body on 307 and 308 redirects
+	KeepMethod *bool
+}
+
+// RedirectFor returns the effective redirect settings for a request.
+// Each request-level setting takes precedence over the config-level setting.
+//
+// Parameters:
+//   - req: Request configuration to look up redirect settings for
+//
+// Returns:
+//   - *Redirect: Effective redirect settings, or nil if none are configured
+func (c *Config) RedirectFor(req *Request) *Redirect {
+	if c == nil {
+		return nil
+	}
+
+	redirect := Redirect{
+		Follow:       c.FollowRedirects,
+		MaxRedirects: c.MaxRedirects,
+		KeepMethod:   c.KeepMethodOnRedirect,
+	}
+	if req != nil {
+		if req.FollowRedirects != nil {
+			redirect.Follow = req.FollowRedirects
+		}
+		if req.MaxRedirects != nil {
+			redirect.MaxRedirects = req.MaxRedirects
+		}
+		if req.KeepMethodOnRedirect != nil {
+			redirect.KeepMethod = req.KeepMethodOnRedirect
+		}
+	}
+
+	if redirect.Follow == nil && redirect.MaxRedirects == nil && redirect.KeepMethod == nil {
+		return nil
+	}
+	return &redirect
+}
+
+// validateMaxRedirects checks the max_redirects setting.
+//
+// Parameters:
+//   - maxRedirects: Configured maximum, may be nil
+//
+// Returns:
+//   - error: Validation error or nil if the setting is valid
+func validateMaxRedirects(maxRedirects *int) error {
+	if maxRedirects != nil && *maxRedirects < 0 {
+		return fmt.Errorf("max_redirects must not be negative: %d", *maxRedirects)
+	}
+	return nil
+}
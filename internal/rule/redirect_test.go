@@ -0,0 +1,56 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPtrTest(b bool) *bool {
+	return &b
+}
+
+func intPtrTest(i int) *int {
+	return &i
+}
+
+func TestConfig_RedirectFor(t *testing.T) {
+	t.Run("nothing configured", func(t *testing.T) {
+		config := &Config{}
+		assert.Nil(t, config.RedirectFor(&Request{}))
+	})
+
+	t.Run("request settings override config settings individually", func(t *testing.T) {
+		config := &Config{FollowRedirects: boolPtrTest(true), MaxRedirects: intPtrTest(5)}
+		request := &Request{MaxRedirects: intPtrTest(1), KeepMethodOnRedirect: boolPtrTest(false)}
+
+		redirect := config.RedirectFor(request)
+
+		require.NotNil(t, redirect)
+		assert.True(t, *redirect.Follow)
+		assert.Equal(t, 1, *redirect.MaxRedirects)
+		assert.False(t, *redirect.KeepMethod)
+	})
+
+	t.Run("nil config", func(t *testing.T) {
+		var config *Config
+		assert.Nil(t, config.RedirectFor(&Request{FollowRedirects: boolPtrTest(false)}))
+	})
+}
+
+func TestConfig_ValidateMaxRedirects(t *testing.T) {
+	config := &Config{
+		BaseUrl:      "http://example.com",
+		MaxRedirects: intPtrTest(-1),
+		Request:      []Request{{Name: "test", Method: "GET", Path: "/test"}},
+	}
+	assert.Error(t, config.Validate())
+
+	config.MaxRedirects = intPtrTest(0)
+	config.Request[0].MaxRedirects = intPtrTest(-2)
+	assert.Error(t, config.Validate())
+
+	config.Request[0].MaxRedirects = intPtrTest(3)
+	assert.NoError(t, config.Validate())
+}
@@ -0,0 +1,121 @@
+package rule
+
+import (
+	"fmt"
+)
+
+// Signing type constants define the supported request signers.
+const (
+	// SigningTypeNone disables signing, e.g. to opt a request out of config-level signing
+	SigningTypeNone = "none"
+
+	// SigningTypeAWSSigV4 signs requests with AWS Signature Version 4
+	SigningTypeAWSSigV4 = "aws_sigv4"
+
+	// SigningTypeHMAC signs requests with an HMAC over method, path, timestamp and body
+	SigningTypeHMAC = "hmac"
+)
+
+// Signing represents request signing settings.
+// It can be declared at config level (applied to every request)
+// or at request level (overriding the config-level settings).
+type Signing struct {
+	// Type is the signer type (none, aws_sigv4, hmac)
+	Type string `toml:"type"`
+
+	// Region is the AWS region for aws_sigv4
+	Region string `toml:"region"`
+
+	// Service is the AWS service name for aws_sigv4 (e.g., execute-api)
+	Service string `toml:"service"`
+
+	// Profile is the AWS shared credentials profile for aws_sigv4
+	// If empty, credentials are taken from the environment or the default profile
+	Profile string `toml:"profile"`
+
+	// Secret is the shared key for hmac
+	Secret string `toml:"secret"`
+
+	// Algorithm is the hash algorithm for hmac (sha1, sha256, sha512)
+	Algorithm string `toml:"algorithm"`
+
+	// Encoding is the signature encoding for hmac (hex, base64)
+	Encoding string `toml:"encoding"`
+
+	// Header is the header receiving the hmac signature
+	Header string `toml:"header"`
+
+	// TimestampHeader is the header receiving the hmac timestamp ("-" to omit)
+	TimestampHeader string `toml:"timestamp_header"`
+
+	// Prefix is prepended to the hmac signature value
+	Prefix string `toml:"prefix"`
+
+	// Template is the hmac string-to-sign using {method}, {path}, {host}, {timestamp}, {body}, {body_sha256}
+	Template string `toml:"template"`
+}
+
+// SigningFor returns the effective signing settings for a request.
+// Request-level settings take precedence over config-level settings, and an
+// explicit "none" type disables signing for the request.
+//
+// Parameters:
+//   - req: Request configuration to look up signing for
+//
+// Returns:
+//   - *Signing: Effective signing settings, or nil if the request is not signed
+func (c *Config) SigningFor(req *Request) *Signing {
+	if c == nil {
+		return nil
+	}
+
+	signing := c.Signing
+	if req != nil && req.Signing != nil {
+		signing = req.Signing
+	}
+	if signing == nil || signing.Type == SigningTypeNone {
+		return nil
+	}
+	return signing
+}
+
+// Validate checks if the signing settings are complete for their type.
+//
+// Returns:
+//   - error: Validation error or nil if settings are valid
+func (s *Signing) Validate() error {
+	switch s.Type {
+	case SigningTypeNone:
+		return nil
+	case SigningTypeAWSSigV4:
+		if s.Region == "" || s.Service == "" {
+			return fmt.Errorf("region and service are required for aws_sigv4 signing")
+		}
+	case SigningTypeHMAC:
+		if s.Secret == "" {
+			return fmt.Errorf("secret is required for hmac signing")
+		}
+	case "":
+		return fmt.Errorf("signing type is required")
+	default:
+		return fmt.Errorf("unsupported signing type: %s", s.Type)
+	}
+	return nil
+}
+
+// validateAuthSigning checks that the effective auth of a request can be combined with its signing.
+// Digest auth sets the Authorization header after the server's challenge, which would
+// invalidate a signature computed over the original request.
+//
+// Parameters:
+//   - req: Request to check
+//
+// Returns:
+//   - error: Validation error or nil if the settings can be combined
+func (c *Config) validateAuthSigning(req *Request) error {
+	auth := c.AuthFor(req)
+	if auth != nil && auth.Type == AuthTypeDigest && c.SigningFor(req) != nil {
+		return fmt.Errorf("digest auth cannot be combined with signing")
+	}
+	return nil
+}
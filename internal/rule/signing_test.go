@@ -0,0 +1,80 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigning_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		signing Signing
+		isErr   bool
+	}{
+		{"none", Signing{Type: SigningTypeNone}, false},
+		{"aws sigv4", Signing{Type: SigningTypeAWSSigV4, Region: "us-east-1", Service: "execute-api"}, false},
+		{"aws sigv4 without region", Signing{Type: SigningTypeAWSSigV4, Service: "execute-api"}, true},
+		{"hmac", Signing{Type: SigningTypeHMAC, Secret: "key"}, false},
+		{"hmac without secret", Signing{Type: SigningTypeHMAC}, true},
+		{"missing type", Signing{}, true},
+		{"unknown type", Signing{Type: "rsa"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.signing.Validate()
+			if tt.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_SigningFor(t *testing.T) {
+	configSigning := &Signing{Type: SigningTypeHMAC, Secret: "config"}
+	requestSigning := &Signing{Type: SigningTypeHMAC, Secret: "request"}
+
+	config := &Config{Signing: configSigning}
+
+	assert.Equal(t, configSigning, config.SigningFor(&Request{}))
+	assert.Equal(t, requestSigning, config.SigningFor(&Request{Signing: requestSigning}))
+	assert.Nil(t, config.SigningFor(&Request{Signing: &Signing{Type: SigningTypeNone}}))
+	assert.Nil(t, (&Config{}).SigningFor(&Request{}))
+}
+
+func TestConfig_ValidateAuthSigning(t *testing.T) {
+	digest := &Auth{Type: AuthTypeDigest, Username: "user", Password: "pass"}
+	hmac := &Signing{Type: SigningTypeHMAC, Secret: "key"}
+
+	tests := []struct {
+		name    string
+		config  Config
+		request Request
+		isErr   bool
+	}{
+		{"digest with config signing", Config{Auth: digest, Signing: hmac}, Request{}, true},
+		{"digest with request signing", Config{Auth: digest}, Request{Signing: hmac}, true},
+		{"request auth with config signing", Config{Signing: hmac}, Request{Auth: digest}, true},
+		{"signing disabled for request", Config{Auth: digest, Signing: hmac}, Request{Signing: &Signing{Type: SigningTypeNone}}, false},
+		{"basic with signing", Config{Auth: &Auth{Type: AuthTypeBasic, Username: "user"}, Signing: hmac}, Request{}, false},
+		{"digest without signing", Config{Auth: digest}, Request{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.BaseUrl = "http://example.com"
+			tt.request.Name, tt.request.Method, tt.request.Path = "test", "GET", "/test"
+			tt.config.Request = []Request{tt.request}
+
+			err := tt.config.Validate()
+			if tt.isErr {
+				assert.EqualError(t, err, "invalid signing for request 'test': digest auth cannot be combined with signing")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SnapshotIgnorePaths returns the paths masked in response snapshots of a request:
+// the config-level paths followed by the paths of the request.
+//
+// Parameters:
+//   - req: Request configuration
+//
+// Returns:
+//   - []string: Paths to mask, empty if none are configured
+func (c *Config) SnapshotIgnorePaths(req *Request) []string {
+	paths := append([]string(nil), c.SnapshotIgnore...)
+	if req != nil {
+		paths = append(paths, req.SnapshotIgnore...)
+	}
+	return paths
+}
+
+// validateIgnorePaths checks paths of JSON values left out of comparisons,
+// such as snapshot_ignore. Paths are dot-separated, e.g. "data.#.created_at".
+//
+// Parameters:
+//   - paths: Paths to validate
+//
+// Returns:
+//   - error: Validation error or nil if all paths are valid
+func validateIgnorePaths(paths []string) error {
+	for _, path := range paths {
+		if path == "" {
+			return fmt.Errorf("path must not be empty")
+		}
+		for _, part := range strings.Split(strings.ReplaceAll(path, `\.`, "_"), ".") {
+			if part == "" {
+				return fmt.Errorf("path '%s' has an empty component", path)
+			}
+		}
+	}
+	return nil
+}
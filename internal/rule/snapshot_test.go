@@ -0,0 +1,67 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIgnorePaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr string
+	}{
+		{name: "no paths"},
+		{name: "paths", paths: []string{"id", "data.#.created_at", "meta.*.etag", `headers.x\.request\.id`}},
+		{name: "empty path", paths: []string{""}, wantErr: "must not be empty"},
+		{name: "empty component", paths: []string{"data..id"}, wantErr: "empty component"},
+		{name: "trailing dot", paths: []string{"data."}, wantErr: "empty component"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIgnorePaths(tt.paths)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSnapshotIgnorePaths(t *testing.T) {
+	config := &Config{SnapshotIgnore: []string{"updated_at"}}
+	req := &Request{SnapshotIgnore: []string{"id"}}
+
+	assert.Equal(t, []string{"updated_at", "id"}, config.SnapshotIgnorePaths(req))
+	assert.Equal(t, []string{"updated_at"}, config.SnapshotIgnorePaths(&Request{}))
+	assert.Equal(t, []string{"id"}, (&Config{}).SnapshotIgnorePaths(req))
+	assert.Equal(t, []string{"updated_at"}, config.SnapshotIgnorePaths(nil))
+}
+
+func TestLoadConfig_SnapshotIgnore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.toml")
+	content := `
+base_url = "http://example.com"
+snapshot_ignore = ["meta.generated_at"]
+
+[[request]]
+name = "Get Users"
+method = "GET"
+path = "/users"
+snapshot_ignore = ["#.id"]
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, []string{"meta.generated_at", "#.id"}, config.SnapshotIgnorePaths(&config.Request[0]))
+}
@@ -0,0 +1,236 @@
+package rule
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ymatsukawa/jak/internal/file"
+)
+
+// StubResponse represents a response returned by a stub route.
+// Body, body_file and header values may contain ${...} references to the request,
+// e.g. ${params.id}, ${query.page}, ${headers.X-Request-Id} or ${body.user.name}.
+type StubResponse struct {
+	// Status is the HTTP status code (default 200)
+	Status int `toml:"status"`
+
+	// Headers is a list of header strings in format "Key: Value"
+	Headers []string `toml:"headers"`
+
+	// Body is the response body (mutually exclusive with BodyFile)
+	Body string `toml:"body"`
+
+	// BodyFile is a file whose content is the response body,
+	// relative to the directory of the stub file
+	BodyFile string `toml:"body_file"`
+
+	// Delay is how long to wait before responding, e.g. "500ms"
+	Delay time.Duration `toml:"delay"`
+}
+
+// Route represents a stub route of the mock server.
+// A route answers with its own response, or steps through Responses on
+// repeated calls, repeating the last one once all have been returned.
+type Route struct {
+	// Name identifies the route in the log (optional)
+	Name string `toml:"name"`
+
+	// Method is the HTTP method to match; empty matches any method
+	Method string `toml:"method"`
+
+	// Path is the path pattern to match, e.g. "/users/{id}";
+	// a trailing "*" segment matches any remainder
+	Path string `toml:"path"`
+
+	// StubResponse is the response of a route without a sequence
+	StubResponse
+
+	// Responses is a sequence of responses for repeated calls
+	Responses []StubResponse `toml:"responses"`
+}
+
+// StubConfig represents the route definitions of the mock server.
+type StubConfig struct {
+	// Route is the list of routes, matched in order
+	Route []Route `toml:"route"`
+
+	// dir is the directory of the stub file, used to resolve body files
+	dir string
+}
+
+// LoadStubConfig loads route definitions from a TOML file.
+//
+// Parameters:
+//   - path: Path to the stub file
+//
+// Returns:
+//   - *StubConfig: Loaded route definitions
+//   - error: Any error encountered during loading
+func LoadStubConfig(path string) (*StubConfig, error) {
+	configPath, err := file.AbsPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve stub path: %w", err)
+	}
+
+	var config StubConfig
+	if _, err := toml.DecodeFile(configPath, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode TOML: %w", err)
+	}
+
+	config.dir = filepath.Dir(configPath)
+
+	return &config, nil
+}
+
+// ResolvePath resolves a body file path against the directory of the stub file.
+//
+// Parameters:
+//   - path: Path as written in the stub file
+//
+// Returns:
+//   - string: Resolved path
+func (c *StubConfig) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || c == nil || c.dir == "" {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
+
+// Validate checks if the route definitions are valid.
+//
+// Returns:
+//   - error: Validation error or nil if all routes are valid
+func (c *StubConfig) Validate() error {
+	if len(c.Route) == 0 {
+		return fmt.Errorf("at least one route must be defined")
+	}
+	for i, route := range c.Route {
+		if err := route.Validate(); err != nil {
+			return fmt.Errorf("invalid route '%s': %w", route.Label(i), err)
+		}
+	}
+	return nil
+}
+
+// Label returns the name of the route, or its method and path if it has no name.
+//
+// Parameters:
+//   - index: Index of the route in the configuration
+//
+// Returns:
+//   - string: Label identifying the route
+func (r *Route) Label(index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	if r.Path == "" {
+		return fmt.Sprintf("#%d", index+1)
+	}
+	if r.Method == "" {
+		return r.Path
+	}
+	return strings.ToUpper(r.Method) + " " + r.Path
+}
+
+// Validate checks if the route is valid.
+//
+// Returns:
+//   - error: Validation error or nil if the route is valid
+func (r *Route) Validate() error {
+	if err := validateRoutePath(r.Path); err != nil {
+		return err
+	}
+	if strings.ContainsAny(r.Method, " \t/") {
+		return fmt.Errorf("invalid method: %s", r.Method)
+	}
+
+	if len(r.Responses) == 0 {
+		return r.StubResponse.Validate()
+	}
+	if r.Status != 0 || len(r.Headers) > 0 || r.Body != "" || r.BodyFile != "" || r.Delay != 0 {
+		return fmt.Errorf("responses cannot be combined with status, headers, body, body_file or delay")
+	}
+	for i, resp := range r.Responses {
+		if err := resp.Validate(); err != nil {
+			return fmt.Errorf("response %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks if the response is valid.
+//
+// Returns:
+//   - error: Validation error or nil if the response is valid
+func (r *StubResponse) Validate() error {
+	if r.Status != 0 && (r.Status < 100 || r.Status > 599) {
+		return fmt.Errorf("status must be between 100 and 599: %d", r.Status)
+	}
+	for _, header := range r.Headers {
+		key, _, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid header format: %s", header)
+		}
+	}
+	if r.Body != "" && r.BodyFile != "" {
+		return fmt.Errorf("body and body_file are mutually exclusive")
+	}
+	if r.Delay < 0 {
+		return fmt.Errorf("delay must not be negative: %s", r.Delay)
+	}
+	return nil
+}
+
+// StatusCode returns the status code of the response.
+//
+// Returns:
+//   - int: Configured status, or 200 if not set
+func (r *StubResponse) StatusCode() int {
+	if r.Status == 0 {
+		return 200
+	}
+	return r.Status
+}
+
+// validateRoutePath checks a route path pattern.
+// Segments are literals, {name} parameters or a trailing "*".
+//
+// Parameters:
+//   - path: Path pattern to validate
+//
+// Returns:
+//   - error: Validation error or nil if the pattern is valid
+func validateRoutePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path must start with '/': %s", path)
+	}
+
+	params := make(map[string]bool)
+	segments := strings.Split(path[1:], "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "*":
+			if i != len(segments)-1 {
+				return fmt.Errorf("'*' must be the last segment of path: %s", path)
+			}
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			name := segment[1 : len(segment)-1]
+			if name == "" || strings.ContainsAny(name, "{}") {
+				return fmt.Errorf("invalid parameter '%s' in path: %s", segment, path)
+			}
+			if params[name] {
+				return fmt.Errorf("duplicate parameter '%s' in path: %s", name, path)
+			}
+			params[name] = true
+		case strings.ContainsAny(segment, "{}"):
+			return fmt.Errorf("parameters must span a whole segment: %s", path)
+		}
+	}
+	return nil
+}
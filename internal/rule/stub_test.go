@@ -0,0 +1,115 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStubConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mocks.toml")
+	content := `
+[[route]]
+name = "Get User"
+method = "GET"
+path = "/users/{id}"
+status = 200
+headers = ["Content-Type: application/json"]
+body = '{"id": ${params.id}}'
+delay = "150ms"
+
+[[route]]
+path = "/jobs/{id}"
+responses = [
+  { status = 202, body = "pending" },
+  { body_file = "done.json" },
+]
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadStubConfig(path)
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	require.Len(t, config.Route, 2)
+
+	route := config.Route[0]
+	assert.Equal(t, "GET", route.Method)
+	assert.Equal(t, "/users/{id}", route.Path)
+	assert.Equal(t, 200, route.Status)
+	assert.Equal(t, []string{"Content-Type: application/json"}, route.Headers)
+	assert.Equal(t, `{"id": ${params.id}}`, route.Body)
+	assert.Equal(t, 150*time.Millisecond, route.Delay)
+
+	sequence := config.Route[1]
+	require.Len(t, sequence.Responses, 2)
+	assert.Equal(t, 202, sequence.Responses[0].StatusCode())
+	assert.Equal(t, 200, sequence.Responses[1].StatusCode())
+	assert.Equal(t, filepath.Join(dir, "done.json"), config.ResolvePath(sequence.Responses[1].BodyFile))
+}
+
+func TestLoadStubConfig_Fixture(t *testing.T) {
+	config, err := LoadStubConfig("../../test/fixtures/mocks.toml")
+	require.NoError(t, err)
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoadStubConfig_NotFound(t *testing.T) {
+	_, err := LoadStubConfig("missing.toml")
+	assert.ErrorContains(t, err, "failed to resolve stub path")
+}
+
+func TestStubConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   Route
+		wantErr string
+	}{
+		{name: "minimal", route: Route{Path: "/"}},
+		{name: "parameters and wildcard", route: Route{Method: "get", Path: "/users/{id}/files/*"}},
+		{name: "sequence", route: Route{Path: "/jobs", Responses: []StubResponse{{Status: 202}, {Status: 200}}}},
+		{name: "missing path", route: Route{Name: "R"}, wantErr: "invalid route 'R': path is required"},
+		{name: "relative path", route: Route{Path: "users"}, wantErr: "must start with '/'"},
+		{name: "wildcard not last", route: Route{Path: "/*/users"}, wantErr: "'*' must be the last segment"},
+		{name: "empty parameter", route: Route{Path: "/users/{}"}, wantErr: "invalid parameter"},
+		{name: "partial parameter", route: Route{Path: "/users/id-{id}"}, wantErr: "must span a whole segment"},
+		{name: "duplicate parameter", route: Route{Path: "/{id}/{id}"}, wantErr: "duplicate parameter 'id'"},
+		{name: "invalid method", route: Route{Method: "GET /x", Path: "/"}, wantErr: "invalid method"},
+		{name: "invalid status", route: Route{Path: "/", StubResponse: StubResponse{Status: 600}}, wantErr: "status must be between 100 and 599"},
+		{name: "invalid header", route: Route{Path: "/", StubResponse: StubResponse{Headers: []string{"nocolon"}}}, wantErr: "invalid header format"},
+		{name: "body and body_file", route: Route{Path: "/", StubResponse: StubResponse{Body: "x", BodyFile: "x.json"}}, wantErr: "mutually exclusive"},
+		{name: "negative delay", route: Route{Path: "/", StubResponse: StubResponse{Delay: -time.Second}}, wantErr: "delay must not be negative"},
+		{
+			name:    "sequence with response fields",
+			route:   Route{Path: "/", StubResponse: StubResponse{Body: "x"}, Responses: []StubResponse{{}}},
+			wantErr: "responses cannot be combined",
+		},
+		{
+			name:    "invalid response in sequence",
+			route:   Route{Method: "GET", Path: "/jobs", Responses: []StubResponse{{}, {Status: 42}}},
+			wantErr: "invalid route 'GET /jobs': response 2: status must be between",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &StubConfig{Route: []Route{tt.route}}
+
+			err := config.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStubConfig_ValidateEmpty(t *testing.T) {
+	assert.ErrorContains(t, (&StubConfig{}).Validate(), "at least one route")
+}
@@ -0,0 +1,142 @@
+package rule
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// applyTemplates replaces each request that extends a template with the
+// request inheriting the settings of the template.
+//
+// Returns:
+//   - error: Error if a request extends an unknown template or templates extend each other in a cycle
+func (c *Config) applyTemplates() error {
+	for i, req := range c.Request {
+		if req.Extends == "" {
+			continue
+		}
+
+		template, err := c.resolveTemplate(req.Extends, nil)
+		if err != nil {
+			return fmt.Errorf("invalid extends for request '%s': %w", req.Name, err)
+		}
+		c.Request[i] = req.inherit(template)
+	}
+	return nil
+}
+
+// resolveTemplate returns a template with the settings of the templates it extends.
+//
+// Parameters:
+//   - name: Template name
+//   - extending: Names of the templates extending this one, to detect cycles
+//
+// Returns:
+//   - Request: Template with inherited settings
+//   - error: Error if the template is unknown or templates extend each other in a cycle
+func (c *Config) resolveTemplate(name string, extending []string) (Request, error) {
+	for _, extended := range extending {
+		if extended == name {
+			return Request{}, fmt.Errorf("template cycle: %s", strings.Join(append(extending, name), " -> "))
+		}
+	}
+
+	template, ok := c.Templates[name]
+	if !ok {
+		return Request{}, fmt.Errorf("unknown template '%s'", name)
+	}
+	if template.Extends == "" {
+		return template, nil
+	}
+
+	parent, err := c.resolveTemplate(template.Extends, append(extending, name))
+	if err != nil {
+		return Request{}, err
+	}
+	return template.inherit(parent), nil
+}
+
+// inherit returns the request with the settings of a template it does not set itself.
+// Headers are combined, with the request replacing template headers of the same name;
+// extract, query and collect entries are combined the same way. The template body is
+// only used when the request has none.
+//
+// Parameters:
+//   - template: Template providing default settings
+//
+// Returns:
+//   - Request: Request with inherited settings
+func (r Request) inherit(template Request) Request {
+	merged := r
+	target := reflect.ValueOf(&merged).Elem()
+	source := reflect.ValueOf(template)
+	for i := 0; i < target.NumField(); i++ {
+		if target.Field(i).IsZero() {
+			target.Field(i).Set(source.Field(i))
+		}
+	}
+
+	merged.Name = r.Name
+	merged.Headers = mergeHeaders(template.Headers, r.Headers)
+	merged.Extract = mergeMaps(template.Extract, r.Extract)
+	merged.Query = mergeMaps(template.Query, r.Query)
+	merged.Collect = mergeMaps(template.Collect, r.Collect)
+	if r.RawBody != nil || r.FormBody != nil || r.JsonBody != nil {
+		merged.RawBody, merged.FormBody, merged.JsonBody = r.RawBody, r.FormBody, r.JsonBody
+	}
+	return merged
+}
+
+// mergeHeaders combines template headers with request headers.
+// Header names are compared case-insensitively.
+//
+// Parameters:
+//   - inherited: Headers of the template in "Key: Value" format
+//   - own: Headers of the request, replacing inherited headers of the same name
+//
+// Returns:
+//   - []string: Inherited headers not replaced, followed by the request headers
+func mergeHeaders(inherited, own []string) []string {
+	if len(inherited) == 0 {
+		return own
+	}
+
+	replaced := make(map[string]bool, len(own))
+	for _, header := range own {
+		name, _, _ := strings.Cut(header, ":")
+		replaced[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var merged []string
+	for _, header := range inherited {
+		name, _, _ := strings.Cut(header, ":")
+		if !replaced[strings.ToLower(strings.TrimSpace(name))] {
+			merged = append(merged, header)
+		}
+	}
+	return append(merged, own...)
+}
+
+// mergeMaps combines template entries with request entries.
+//
+// Parameters:
+//   - inherited: Entries of the template
+//   - own: Entries of the request, replacing inherited entries of the same key
+//
+// Returns:
+//   - M: New map with both entries, nil if both are empty
+func mergeMaps[M ~map[string]V, V any](inherited, own M) M {
+	if len(inherited) == 0 && len(own) == 0 {
+		return own
+	}
+
+	merged := make(M, len(inherited)+len(own))
+	for key, value := range inherited {
+		merged[key] = value
+	}
+	for key, value := range own {
+		merged[key] = value
+	}
+	return merged
+}
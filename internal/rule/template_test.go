@@ -0,0 +1,127 @@
+package rule
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_Templates(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"common/templates.toml": `
+[templates.json_api]
+method = "POST"
+headers = ["Content-Type: application/json", "Accept: application/json"]
+json_body = '{}'
+extract = { id = "id" }
+
+[templates.authorized]
+extends = "json_api"
+headers = ["Authorization: Bearer ${token}"]
+`,
+		"main.toml": `
+include = ["common/templates.toml"]
+base_url = "http://example.com"
+
+[[request]]
+name = "Create"
+extends = "json_api"
+path = "/users"
+json_body = '{"name": "alice"}'
+
+[[request]]
+name = "Upload"
+extends = "authorized"
+path = "/files"
+headers = ["content-type: text/plain"]
+raw_body = "hello"
+extract = { url = "url" }
+
+[[request]]
+name = "Ping"
+extends = "json_api"
+method = "GET"
+path = "/ping"
+`,
+	})
+
+	config, err := LoadConfig(filepath.Join(dir, "main.toml"))
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	create := config.Request[0]
+	assert.Equal(t, "Create", create.Name)
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, []string{"Content-Type: application/json", "Accept: application/json"}, create.Headers)
+	require.NotNil(t, create.JsonBody)
+	assert.Equal(t, `{"name": "alice"}`, *create.JsonBody)
+	assert.Equal(t, map[string]string{"id": "id"}, create.Extract)
+
+	// Templates extending templates combine their settings; the request body replaces the template body
+	upload := config.Request[1]
+	assert.Equal(t, "POST", upload.Method)
+	assert.Equal(t, []string{"Accept: application/json", "Authorization: Bearer ${token}", "content-type: text/plain"}, upload.Headers)
+	assert.Nil(t, upload.JsonBody)
+	require.NotNil(t, upload.RawBody)
+	assert.Equal(t, "hello", *upload.RawBody)
+	assert.Equal(t, map[string]string{"id": "id", "url": "url"}, upload.Extract)
+
+	ping := config.Request[2]
+	assert.Equal(t, "GET", ping.Method)
+	require.NotNil(t, ping.JsonBody)
+	assert.Equal(t, "{}", *ping.JsonBody)
+
+	// Templates are not changed by the requests extending them
+	assert.Equal(t, []string{"Authorization: Bearer ${token}"}, config.Templates["authorized"].Headers)
+}
+
+func TestLoadConfig_TemplateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "unknown template",
+			config: `
+base_url = "http://example.com"
+
+[[request]]
+name = "Create"
+extends = "missing"
+path = "/users"
+`,
+			wantErr: "invalid extends for request 'Create': unknown template 'missing'",
+		},
+		{
+			name: "cycle",
+			config: `
+base_url = "http://example.com"
+
+[templates.a]
+extends = "b"
+
+[templates.b]
+extends = "a"
+
+[[request]]
+name = "Create"
+extends = "a"
+path = "/users"
+`,
+			wantErr: "invalid extends for request 'Create': template cycle: a -> b -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeConfigFiles(t, map[string]string{"main.toml": tt.config})
+
+			_, err := LoadConfig(filepath.Join(dir, "main.toml"))
+			require.Error(t, err)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package rule
+
+import (
+	"fmt"
+	"time"
+)
+
+// validateThrottle checks the batch concurrency and pacing settings.
+//
+// Returns:
+//   - error: Validation error or nil if the settings are valid
+func (c *Config) validateThrottle() error {
+	if c.MaxWorkers < 0 {
+		return fmt.Errorf("max_workers must not be negative: %d", c.MaxWorkers)
+	}
+	if c.RateLimit < 0 {
+		return fmt.Errorf("rate_limit must not be negative: %g", c.RateLimit)
+	}
+	if c.RateBurst < 0 {
+		return fmt.Errorf("rate_burst must not be negative: %d", c.RateBurst)
+	}
+	if c.RateBurst > 0 && c.RateLimit == 0 {
+		return fmt.Errorf("rate_burst requires rate_limit")
+	}
+	if c.DelayBetween < 0 {
+		return fmt.Errorf("delay_between must not be negative: %s", c.DelayBetween)
+	}
+	return nil
+}
+
+// PacingTimeout returns the total time the requests of the configuration may spend waiting
+// for delay_between and rate_limit. It extends the execution deadline so that pacing is not
+// cut short by the request timeout.
+//
+// Returns:
+//   - time.Duration: Sum of the pauses between sequential requests and the rate limit waits
+func (c *Config) PacingTimeout() time.Duration {
+	if c == nil || len(c.Request) == 0 {
+		return 0
+	}
+
+	count := len(c.Request)
+	var total time.Duration
+	if !c.Concurrency {
+		total += time.Duration(count-1) * c.DelayBetween
+	}
+	if c.RateLimit > 0 {
+		total += time.Duration(float64(count) / c.RateLimit * float64(time.Second))
+	}
+	return total
+}
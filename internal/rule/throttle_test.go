@@ -0,0 +1,98 @@
+package rule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_ValidateThrottle(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{name: "defaults", modify: func(c *Config) {}},
+		{
+			name: "all settings",
+			modify: func(c *Config) {
+				c.MaxWorkers = 10
+				c.RateLimit = 2.5
+				c.RateBurst = 5
+				c.DelayBetween = 500 * time.Millisecond
+			},
+		},
+		{name: "negative max_workers", modify: func(c *Config) { c.MaxWorkers = -1 }, wantErr: "max_workers"},
+		{name: "negative rate_limit", modify: func(c *Config) { c.RateLimit = -1 }, wantErr: "rate_limit"},
+		{name: "negative rate_burst", modify: func(c *Config) { c.RateLimit = 1; c.RateBurst = -1 }, wantErr: "rate_burst"},
+		{name: "rate_burst without rate_limit", modify: func(c *Config) { c.RateBurst = 3 }, wantErr: "rate_burst requires rate_limit"},
+		{name: "negative delay_between", modify: func(c *Config) { c.DelayBetween = -time.Second }, wantErr: "delay_between"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				BaseUrl: "http://example.com",
+				Request: []Request{{Name: "test", Method: "GET", Path: "/test"}},
+			}
+			tt.modify(config)
+
+			err := config.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_Throttle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "throttle.toml")
+	content := `
+base_url = "http://example.com"
+concurrency = true
+max_workers = 2
+rate_limit = 0.5
+rate_burst = 3
+delay_between = "250ms"
+
+[[request]]
+name = "test"
+method = "GET"
+path = "/test"
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, config.MaxWorkers)
+	assert.Equal(t, 0.5, config.RateLimit)
+	assert.Equal(t, 3, config.RateBurst)
+	assert.Equal(t, 250*time.Millisecond, config.DelayBetween)
+}
+
+func TestConfig_PacingTimeout(t *testing.T) {
+	config := &Config{
+		DelayBetween: 500 * time.Millisecond,
+		RateLimit:    2,
+		Request:      []Request{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+	}
+
+	// Two pauses between three requests, and three requests at two per second
+	assert.Equal(t, time.Second+1500*time.Millisecond, config.PacingTimeout())
+
+	// Concurrent batches do not pause between requests
+	config.Concurrency = true
+	assert.Equal(t, 1500*time.Millisecond, config.PacingTimeout())
+
+	assert.Zero(t, (&Config{DelayBetween: time.Second}).PacingTimeout())
+	assert.Zero(t, (*Config)(nil).PacingTimeout())
+}
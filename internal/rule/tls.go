@@ -0,0 +1,28 @@
+package rule
+
+// TLS represents the TLS settings for connections to the target server.
+type TLS struct {
+	// CACert is the path to a PEM file with additional trusted CA certificates
+	CACert string `toml:"ca_cert"`
+
+	// ClientCert is the path to a PEM client certificate for mutual TLS
+	ClientCert string `toml:"client_cert"`
+
+	// ClientKey is the path to the PEM private key of the client certificate
+	ClientKey string `toml:"client_key"`
+
+	// InsecureSkipVerify disables server certificate verification
+	InsecureSkipVerify bool `toml:"insecure_skip_verify"`
+
+	// ServerName overrides the server name used for SNI and verification
+	ServerName string `toml:"server_name"`
+
+	// MinVersion is the minimum TLS version ("1.0", "1.1", "1.2", "1.3")
+	MinVersion string `toml:"min_version"`
+
+	// MaxVersion is the maximum TLS version ("1.0", "1.1", "1.2", "1.3")
+	MaxVersion string `toml:"max_version"`
+
+	// CipherSuites restricts the allowed cipher suites by name (TLS 1.0-1.2 only)
+	CipherSuites []string `toml:"cipher_suites"`
+}
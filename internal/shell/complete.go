@@ -0,0 +1,62 @@
+package shell
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ymatsukawa/jak/internal/http"
+)
+
+// Complete returns the possible completions of a partially typed line.
+// The first word completes to a command or HTTP method, the word after a
+// method to a path used before, and the word after set or unset to a variable.
+//
+// Parameters:
+//   - line: Line typed so far
+//
+// Returns:
+//   - []string: Completed lines in sorted order, empty if nothing matches
+func (s *Session) Complete(line string) []string {
+	word, rest, hasArgs := strings.Cut(line, " ")
+	if !hasArgs {
+		var candidates []string
+		for _, method := range methods {
+			if strings.HasPrefix(method, strings.ToUpper(word)) {
+				candidates = append(candidates, method+" ")
+			}
+		}
+		for _, command := range commands {
+			if strings.HasPrefix(command, strings.ToLower(word)) {
+				candidates = append(candidates, command+" ")
+			}
+		}
+		sort.Strings(candidates)
+		return candidates
+	}
+
+	// Only the first argument is completed
+	if strings.ContainsAny(rest, " \t") {
+		return nil
+	}
+
+	var known []string
+	switch {
+	case http.IsValidMethod(word):
+		known = s.paths
+	case strings.EqualFold(word, "set"):
+		for _, name := range s.VariableNames() {
+			known = append(known, name+"=")
+		}
+	case strings.EqualFold(word, "unset"):
+		known = s.VariableNames()
+	}
+
+	var candidates []string
+	for _, value := range known {
+		if strings.HasPrefix(value, rest) {
+			candidates = append(candidates, word+" "+value)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
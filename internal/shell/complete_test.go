@@ -0,0 +1,38 @@
+package shell
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_Complete(t *testing.T) {
+	session, _, _ := newTestSession(t)
+	for _, line := range []string{"set id=1", "set idx=2", "GET /users", "GET /users/${id}", "POST /orders"} {
+		_, err := session.Execute(context.Background(), line)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{"g", []string{"GET "}},
+		{"he", []string{"HEAD ", "header ", "headers ", "help "}},
+		{"P", []string{"PATCH ", "POST ", "PUT "}},
+		{"GET /u", []string{"GET /users", "GET /users/${id}"}},
+		{"delete /", []string{"delete /orders", "delete /users", "delete /users/${id}"}},
+		{"unset id", []string{"unset id", "unset idx"}},
+		{"set i", []string{"set id=", "set idx="}},
+		{"GET /users {", nil},
+		{"vars x", nil},
+		{"x", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.expected, session.Complete(tt.line))
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+	"github.com/ymatsukawa/jak/internal/term"
+)
+
+// DefaultMaxHistory is the number of lines kept in the history.
+const DefaultMaxHistory = 500
+
+// Completer returns the completed lines for a partially typed line.
+type Completer func(line string) []string
+
+// Editor reads lines from a terminal with editing, history and tab completion.
+// When the input is not a terminal, or raw mode is not supported on the
+// platform, lines are read as they are without editing.
+type Editor struct {
+	// in is the input, usually os.Stdin
+	in *os.File
+
+	// reader buffers the input
+	reader *bufio.Reader
+
+	// out receives the prompt and the echoed line
+	out io.Writer
+
+	// complete returns completions for the tab key, may be nil
+	complete Completer
+
+	// history holds previous lines, oldest first
+	history []string
+
+	// maxHistory is the number of lines kept in the history
+	maxHistory int
+}
+
+// NewEditor creates a line editor.
+//
+// Parameters:
+//   - in: Input, usually os.Stdin
+//   - out: Output for the prompt and the echoed line, usually os.Stdout
+//   - complete: Completion function for the tab key, may be nil
+//
+// Returns:
+//   - *Editor: Editor ready to read lines
+func NewEditor(in *os.File, out io.Writer, complete Completer) *Editor {
+	return &Editor{
+		in:         in,
+		reader:     bufio.NewReader(in),
+		out:        out,
+		complete:   complete,
+		maxHistory: DefaultMaxHistory,
+	}
+}
+
+// ReadLine prints a prompt and reads a line.
+//
+// Parameters:
+//   - prompt: Prompt printed before the line
+//
+// Returns:
+//   - string: Line without the line break
+//   - error: io.EOF at the end of the input or on Ctrl+D, se.ErrInterrupted on Ctrl+C
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	fd := int(e.in.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return e.readPlain(prompt)
+	}
+	defer term.Restore(fd, state)
+
+	return e.edit(prompt)
+}
+
+// AddHistory appends a line to the history.
+// Empty lines and repetitions of the previous line are not added.
+//
+// Parameters:
+//   - line: Line to add
+func (e *Editor) AddHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > e.maxHistory {
+		e.history = e.history[len(e.history)-e.maxHistory:]
+	}
+}
+
+// History returns the lines of the history.
+//
+// Returns:
+//   - []string: Previous lines, oldest first
+func (e *Editor) History() []string {
+	return e.history
+}
+
+// readPlain reads a line without editing.
+//
+// Parameters:
+//   - prompt: Prompt printed before the line
+//
+// Returns:
+//   - string: Line without the line break
+//   - error: io.EOF at the end of the input
+func (e *Editor) readPlain(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	line, err := e.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// edit reads a line in raw mode, handling the editing keys.
+//
+// Parameters:
+//   - prompt: Prompt printed before the line
+//
+// Returns:
+//   - string: Line typed by the user
+//   - error: io.EOF on Ctrl+D, se.ErrInterrupted on Ctrl+C, or a read error
+func (e *Editor) edit(prompt string) (string, error) {
+	var line []rune
+	pos := 0
+
+	// Lines of the history being browsed; the last entry is the line being typed
+	browse := append(append([]string(nil), e.history...), "")
+	index := len(browse) - 1
+
+	e.refresh(prompt, line, pos)
+	for {
+		key, err := term.ReadKey(e.reader)
+		if err != nil {
+			return "", err
+		}
+
+		switch key {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case term.KeyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", se.ErrInterrupted
+		case term.KeyCtrlD:
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case term.KeyDelete:
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+			}
+		case term.KeyBackspace, term.KeyCtrlH:
+			if pos > 0 {
+				line = append(line[:pos-1], line[pos:]...)
+				pos--
+			}
+		case term.KeyCtrlA, term.KeyHome:
+			pos = 0
+		case term.KeyCtrlE, term.KeyEnd:
+			pos = len(line)
+		case term.KeyCtrlB, term.KeyLeft:
+			if pos > 0 {
+				pos--
+			}
+		case term.KeyCtrlF, term.KeyRight:
+			if pos < len(line) {
+				pos++
+			}
+		case term.KeyCtrlK:
+			line = line[:pos]
+		case term.KeyCtrlU:
+			line = append([]rune(nil), line[pos:]...)
+			pos = 0
+		case term.KeyCtrlW:
+			start := pos
+			for start > 0 && line[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && line[start-1] != ' ' {
+				start--
+			}
+			line = append(line[:start], line[pos:]...)
+			pos = start
+		case term.KeyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case term.KeyCtrlP, term.KeyUp, term.KeyCtrlN, term.KeyDown:
+			next := index - 1
+			if key == term.KeyCtrlN || key == term.KeyDown {
+				next = index + 1
+			}
+			if next < 0 || next >= len(browse) {
+				continue
+			}
+			browse[index] = string(line)
+			index = next
+			line = []rune(browse[index])
+			pos = len(line)
+		case term.KeyTab:
+			line, pos = e.completeLine(prompt, line, pos)
+		default:
+			if key < unicode.MaxRune && unicode.IsPrint(key) {
+				line = append(line[:pos], append([]rune{key}, line[pos:]...)...)
+				pos++
+			}
+		}
+		e.refresh(prompt, line, pos)
+	}
+}
+
+// refresh redraws the prompt and the line and places the cursor.
+//
+// Parameters:
+//   - prompt: Prompt printed before the line
+//   - line: Line typed so far
+//   - pos: Cursor position in the line
+func (e *Editor) refresh(prompt string, line []rune, pos int) {
+	var b strings.Builder
+	b.WriteString("\r")
+	b.WriteString(prompt)
+	b.WriteString(string(line))
+	b.WriteString("\x1b[K")
+	if back := len(line) - pos; back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+	fmt.Fprint(e.out, b.String())
+}
+
+// completeLine completes the line at the cursor, which must be at the end of the line.
+// A single completion replaces the line; several completions extend the line
+// to their common prefix, or are listed below the prompt if there is none.
+//
+// Parameters:
+//   - prompt: Prompt printed before the line
+//   - line: Line typed so far
+//   - pos: Cursor position in the line
+//
+// Returns:
+//   - []rune: Completed line
+//   - int: New cursor position
+func (e *Editor) completeLine(prompt string, line []rune, pos int) ([]rune, int) {
+	if e.complete == nil || pos != len(line) {
+		return line, pos
+	}
+
+	typed := string(line)
+	candidates := e.complete(typed)
+	switch len(candidates) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+		return line, pos
+	case 1:
+		line = []rune(candidates[0])
+		return line, len(line)
+	}
+
+	if prefix := commonPrefix(candidates); len(prefix) > len(typed) {
+		line = []rune(prefix)
+		return line, len(line)
+	}
+
+	// List the completions by their last word
+	start := strings.LastIndexByte(typed, ' ') + 1
+	words := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		words[i] = strings.TrimSpace(candidate[start:])
+	}
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(words, "  "))
+	return line, pos
+}
+
+// commonPrefix returns the longest prefix shared by all strings.
+//
+// Parameters:
+//   - values: Strings to compare, at least one
+//
+// Returns:
+//   - string: Common prefix
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,165 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// newTestEditor creates an editor reading keys from a string.
+func newTestEditor(input string, complete Completer) (*Editor, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &Editor{
+		reader:     bufio.NewReader(strings.NewReader(input)),
+		out:        &out,
+		complete:   complete,
+		maxHistory: DefaultMaxHistory,
+	}, &out
+}
+
+func TestEditor_Edit(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"typing", "GET /users\r", "GET /users"},
+		{"backspace", "GET /userz\x7fs\r", "GET /users"},
+		{"cursor keys", "GET /usrs\x1b[D\x1b[De\x1b[C\r", "GET /users"},
+		{"home and end", "/users\x01GET \x05/1\r", "GET /users/1"},
+		{"home and end sequences", "b\x1b[Ha\x1b[Fc\x1b[1~0\r", "0abc"},
+		{"delete", "GET /xusers\x01\x1b[C\x1b[C\x1b[C\x1b[C\x1b[C\x1b[3~\r", "GET /users"},
+		{"kill to end", "GET /users/1\x1b[D\x1b[D\x0b\r", "GET /users"},
+		{"kill to start", "GET /users\x01junk\x15\r", "GET /users"},
+		{"delete word", "GET /users /orders\x17\x7f\r", "GET /users"},
+		{"unicode", "set name=zoë\r", "set name=zoë"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor, _ := newTestEditor(tt.input, nil)
+			line, err := editor.edit("> ")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, line)
+		})
+	}
+}
+
+func TestEditor_History(t *testing.T) {
+	editor, _ := newTestEditor("\x1b[A\x1b[A\r\x1b[A\x1b[B\x10\x0e\r", nil)
+	editor.AddHistory("GET /one")
+	editor.AddHistory("GET /two")
+	editor.AddHistory("GET /two")
+	editor.AddHistory(" ")
+	assert.Equal(t, []string{"GET /one", "GET /two"}, editor.History())
+
+	line, err := editor.edit("> ")
+	require.NoError(t, err)
+	assert.Equal(t, "GET /one", line)
+
+	// Browsing down past the newest line returns to the line being typed
+	line, err = editor.edit("> ")
+	require.NoError(t, err)
+	assert.Equal(t, "", line)
+}
+
+func TestEditor_MaxHistory(t *testing.T) {
+	editor, _ := newTestEditor("", nil)
+	editor.maxHistory = 2
+	for _, line := range []string{"a", "b", "c"} {
+		editor.AddHistory(line)
+	}
+	assert.Equal(t, []string{"b", "c"}, editor.History())
+}
+
+func TestEditor_Complete(t *testing.T) {
+	complete := func(line string) []string {
+		var candidates []string
+		for _, candidate := range []string{"GET /users", "GET /users/1", "GET /orders"} {
+			if strings.HasPrefix(candidate, line) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		return candidates
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"single", "GET /o\t\r", "GET /orders"},
+		{"common prefix", "GET /u\t\r", "GET /users"},
+		{"no match", "GET /x\t\r", "GET /x"},
+		{"cursor not at end", "GET /o\x1b[D\t\r", "GET /o"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor, _ := newTestEditor(tt.input, complete)
+			line, err := editor.edit("> ")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, line)
+		})
+	}
+
+	// Without a longer common prefix the completions are listed
+	editor, out := newTestEditor("GET /\t\r", complete)
+	_, err := editor.edit("> ")
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "\r\n/users  /users/1  /orders\r\n")
+}
+
+func TestEditor_ControlKeys(t *testing.T) {
+	editor, _ := newTestEditor("GET\x03", nil)
+	_, err := editor.edit("> ")
+	assert.True(t, errors.Is(err, se.ErrInterrupted))
+
+	editor, _ = newTestEditor("\x04", nil)
+	_, err = editor.edit("> ")
+	assert.Equal(t, io.EOF, err)
+
+	// Ctrl+D on a non-empty line deletes the character under the cursor
+	editor, _ = newTestEditor("GETX\x1b[D\x04\r", nil)
+	line, err := editor.edit("> ")
+	require.NoError(t, err)
+	assert.Equal(t, "GET", line)
+}
+
+func TestEditor_ReadPlain(t *testing.T) {
+	editor, out := newTestEditor("GET /users\r\nexit", nil)
+
+	line, err := editor.readPlain("> ")
+	require.NoError(t, err)
+	assert.Equal(t, "GET /users", line)
+
+	line, err = editor.readPlain("> ")
+	require.NoError(t, err)
+	assert.Equal(t, "exit", line)
+
+	_, err = editor.readPlain("> ")
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "> > > ", out.String())
+}
+
+func TestEditor_HistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".jak_history")
+
+	editor, _ := newTestEditor("", nil)
+	require.NoError(t, editor.LoadHistory(path))
+	assert.Empty(t, editor.History())
+
+	editor.AddHistory("GET /users")
+	editor.AddHistory("set id=1")
+	require.NoError(t, editor.SaveHistory(path))
+
+	loaded, _ := newTestEditor("", nil)
+	require.NoError(t, loaded.LoadHistory(path))
+	assert.Equal(t, []string{"GET /users", "set id=1"}, loaded.History())
+}
@@ -0,0 +1,53 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultHistoryFile is the name of the history file in the home directory.
+const DefaultHistoryFile = ".jak_history"
+
+// LoadHistory reads the history of previous sessions from a file.
+// A missing file is not an error.
+//
+// Parameters:
+//   - path: Path of the history file
+//
+// Returns:
+//   - error: Any error encountered while reading the file
+func (e *Editor) LoadHistory(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		e.AddHistory(strings.TrimRight(line, "\r"))
+	}
+	return nil
+}
+
+// SaveHistory writes the history to a file, replacing its content.
+//
+// Parameters:
+//   - path: Path of the history file
+//
+// Returns:
+//   - error: Any error encountered while writing the file
+func (e *Editor) SaveHistory(path string) error {
+	var b strings.Builder
+	for _, line := range e.history {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,420 @@
+// Package shell implements an interactive prompt for sending requests to an API.
+package shell
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ymatsukawa/jak/internal/engine"
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// methods lists the HTTP methods that can be typed as commands.
+var methods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
+// commands lists the shell commands other than HTTP methods.
+var commands = []string{"base", "exit", "header", "headers", "help", "quit", "set", "unset", "vars"}
+
+// helpText describes the commands of the shell.
+const helpText = `Requests:
+  GET /users                       send a request to the base URL
+  POST /users {"name":"x"}         send a JSON body (other text is sent as is)
+  GET https://other.example.com/   send a request to an absolute URL
+
+Variables:
+  set token=abc                    set a variable, used as ${token}
+  set id=${last.body.id}           capture a value of the last response
+  unset token                      remove a variable
+  vars                             list variables
+
+  ${last.status}, ${last.body}, ${last.body.<path>} and ${last.headers.<name>}
+  refer to the last response; <path> is a gjson path such as data.0.id.
+
+Headers:
+  header Authorization: Bearer ${token}   send a header with every request
+  header Authorization:                   stop sending a header
+  headers                                 list headers
+
+Other:
+  base [url]                       show or change the base URL
+  help                             show this help
+  exit, quit                       leave the shell (or Ctrl+D)
+`
+
+// Session is the state of an interactive shell: the base URL, the headers and
+// variables set so far, and the last response.
+type Session struct {
+	// config holds the base URL and the transport settings of the requests
+	config *rule.Config
+
+	// client sends the requests
+	client http.Client
+
+	// factory creates requests from the configuration
+	factory engine.Factory
+
+	// out receives the output of shell commands; responses are printed by the format package
+	out io.Writer
+
+	// headers are sent with every request, in format "Key: Value"
+	headers []string
+
+	// variables holds the values set with "set"
+	variables map[string]string
+
+	// last is the last response, nil before the first one
+	last *lastResponse
+
+	// paths holds the paths used so far for completion, in order of first use
+	paths []string
+}
+
+// NewSession creates a session sending requests with the settings of a configuration.
+//
+// Parameters:
+//   - config: Configuration with the base URL, timeout and transport settings; requests are ignored
+//   - client: Client sending the requests
+//   - out: Writer receiving the output of shell commands
+//
+// Returns:
+//   - *Session: Session ready to execute commands
+func NewSession(config *rule.Config, client http.Client, out io.Writer) *Session {
+	return &Session{
+		config:    config,
+		client:    client,
+		factory:   engine.NewFactory(),
+		out:       out,
+		variables: make(map[string]string),
+	}
+}
+
+// Execute runs a single line typed at the prompt.
+//
+// Parameters:
+//   - ctx: Context for cancellation of a request
+//   - line: Line typed by the user
+//
+// Returns:
+//   - bool: True if the user asked to leave the shell
+//   - error: Error of an invalid command; failed requests are printed instead
+func (s *Session) Execute(ctx context.Context, line string) (bool, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false, nil
+	}
+
+	word, args := cutSpace(line)
+	if http.IsValidMethod(word) {
+		return false, s.send(ctx, strings.ToUpper(word), args)
+	}
+
+	switch strings.ToLower(word) {
+	case "exit", "quit":
+		return true, nil
+	case "help":
+		fmt.Fprint(s.out, helpText)
+		return false, nil
+	case "set":
+		return false, s.set(args)
+	case "unset":
+		return false, s.unset(args)
+	case "vars":
+		s.printVariables()
+		return false, nil
+	case "header":
+		if args == "" {
+			s.printHeaders()
+			return false, nil
+		}
+		return false, s.SetHeader(args)
+	case "headers":
+		s.printHeaders()
+		return false, nil
+	case "base":
+		return false, s.setBase(args)
+	default:
+		return false, fmt.Errorf("%w: %s (type help for a list of commands)", se.ErrUnknownCommand, word)
+	}
+}
+
+// send sends a request and prints the response.
+// The response is kept for ${last.*} references and the path for completion.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - method: HTTP method in upper case
+//   - args: Path or URL, optionally followed by a body
+//
+// Returns:
+//   - error: Error of an invalid command or unresolved reference; failed requests are printed instead
+func (s *Session) send(ctx context.Context, method, args string) error {
+	rawPath, rawBody := cutSpace(args)
+	if rawPath == "" {
+		return fmt.Errorf("%w: %s <path> [body]", se.ErrCommandUsage, method)
+	}
+
+	path, err := s.resolve(rawPath)
+	if err != nil {
+		return err
+	}
+	req := rule.Request{Name: method + " " + path, Method: method, Path: path}
+	for _, header := range s.headers {
+		resolved, err := s.resolve(header)
+		if err != nil {
+			return err
+		}
+		req.Headers = append(req.Headers, resolved)
+	}
+	if rawBody != "" {
+		body, err := s.resolve(rawBody)
+		if err != nil {
+			return err
+		}
+		if json.Valid([]byte(body)) {
+			req.JsonBody = &body
+		} else {
+			req.RawBody = &body
+		}
+	}
+
+	config := *s.config
+	config.Request = []rule.Request{req}
+	if config.BaseUrl == "" {
+		if !strings.Contains(path, "://") {
+			return fmt.Errorf("%w: no base URL; set one with 'base <url>' or use an absolute URL", se.ErrCommandUsage)
+		}
+		// Absolute paths replace the base URL, which only needs to be present
+		config.BaseUrl = path
+	}
+
+	httpReq, err := s.factory.CreateFromConfig(&config, &config.Request[0])
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = time.Duration(rule.DefaultTimeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	resp, err := s.client.Do(httpReq.WithContext(ctx))
+	result := format.ReqResult{
+		Method:   method,
+		URL:      engine.RequestURL(&config, &req),
+		Duration: time.Since(startTime),
+		Success:  err == nil,
+		Error:    err,
+	}
+	s.remember(rawPath)
+
+	if err != nil {
+		format.PrintRequestResult(result)
+		return nil
+	}
+	result.StatusCode = resp.StatusCode
+	result.Timing = resp.Timing
+
+	var body []byte
+	if resp.Body != nil {
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			result.Success = false
+			result.Error = se.WrapError(err, "failed to read response body")
+			format.PrintRequestResult(result)
+			return nil
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	s.last = &lastResponse{status: resp.StatusCode, header: resp.Header, body: body}
+
+	format.PrintRequestResult(result)
+	format.PrintResponse(resp)
+	return nil
+}
+
+// set sets a variable from an argument in format "name=value".
+// References in the value are resolved immediately, so ${last.*} captures
+// a value of the current last response.
+//
+// Parameters:
+//   - args: Argument of the command
+//
+// Returns:
+//   - error: Error if the argument or the name is invalid or a reference cannot be resolved
+func (s *Session) set(args string) error {
+	name, value, ok := strings.Cut(args, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return fmt.Errorf("%w: set <name>=<value>", se.ErrCommandUsage)
+	}
+	if !variableNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: invalid variable name '%s'", se.ErrCommandUsage, name)
+	}
+
+	resolved, err := s.resolve(strings.TrimSpace(value))
+	if err != nil {
+		return err
+	}
+	s.variables[name] = resolved
+	fmt.Fprintf(s.out, "%s = %s\n", name, resolved)
+	return nil
+}
+
+// unset removes a variable.
+//
+// Parameters:
+//   - args: Name of the variable
+//
+// Returns:
+//   - error: Error if the variable does not exist
+func (s *Session) unset(args string) error {
+	if args == "" {
+		return fmt.Errorf("%w: unset <name>", se.ErrCommandUsage)
+	}
+	if _, ok := s.variables[args]; !ok {
+		return fmt.Errorf("%w: ${%s}", se.ErrUndefinedVariable, args)
+	}
+	delete(s.variables, args)
+	return nil
+}
+
+// SetHeader adds or replaces a header sent with every request.
+// A header without value is removed.
+//
+// Parameters:
+//   - args: Header in format "Key: Value", or "Key:" to remove it
+//
+// Returns:
+//   - error: Error if the argument is not a header
+func (s *Session) SetHeader(args string) error {
+	name, value, ok := strings.Cut(args, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("%w: header <Name>: <value>", se.ErrCommandUsage)
+	}
+	name = textproto.CanonicalMIMEHeaderKey(name)
+	value = strings.TrimSpace(value)
+
+	headers := s.headers[:0]
+	for _, header := range s.headers {
+		existing, _, _ := strings.Cut(header, ":")
+		if textproto.CanonicalMIMEHeaderKey(existing) != name {
+			headers = append(headers, header)
+		}
+	}
+	if value != "" {
+		headers = append(headers, name+": "+value)
+	}
+	s.headers = headers
+	return nil
+}
+
+// setBase prints or changes the base URL.
+//
+// Parameters:
+//   - args: New base URL, or empty to print the current one
+//
+// Returns:
+//   - error: Error if the URL is not absolute
+func (s *Session) setBase(args string) error {
+	if args == "" {
+		if s.config.BaseUrl == "" {
+			fmt.Fprintln(s.out, "(no base URL)")
+		} else {
+			fmt.Fprintln(s.out, s.config.BaseUrl)
+		}
+		return nil
+	}
+	if !strings.Contains(args, "://") {
+		return fmt.Errorf("%w: base URL must be absolute, e.g. https://api.example.com", se.ErrCommandUsage)
+	}
+	s.config.BaseUrl = args
+	return nil
+}
+
+// printVariables prints the variables sorted by name.
+func (s *Session) printVariables() {
+	if len(s.variables) == 0 {
+		fmt.Fprintln(s.out, "(no variables)")
+		return
+	}
+	for _, name := range s.VariableNames() {
+		fmt.Fprintf(s.out, "%s = %s\n", name, s.variables[name])
+	}
+}
+
+// printHeaders prints the headers sent with every request.
+func (s *Session) printHeaders() {
+	if len(s.headers) == 0 {
+		fmt.Fprintln(s.out, "(no headers)")
+		return
+	}
+	for _, header := range s.headers {
+		fmt.Fprintln(s.out, header)
+	}
+}
+
+// remember keeps a path for completion.
+//
+// Parameters:
+//   - path: Path as typed, with unresolved references
+func (s *Session) remember(path string) {
+	for _, known := range s.paths {
+		if known == path {
+			return
+		}
+	}
+	s.paths = append(s.paths, path)
+}
+
+// VariableNames returns the names of the variables.
+//
+// Returns:
+//   - []string: Variable names in sorted order
+func (s *Session) VariableNames() []string {
+	names := make([]string, 0, len(s.variables))
+	for name := range s.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cutSpace splits a line at the first run of whitespace.
+//
+// Parameters:
+//   - line: Line to split
+//
+// Returns:
+//   - string: Text before the whitespace
+//   - string: Text after the whitespace, trimmed
+func cutSpace(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	index := strings.IndexAny(line, " \t")
+	if index < 0 {
+		return line, ""
+	}
+	return line[:index], strings.TrimSpace(line[index:])
+}
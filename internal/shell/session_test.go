@@ -0,0 +1,177 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// recordedRequest is a request received by the test server.
+type recordedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+func newTestSession(t *testing.T) (*Session, *bytes.Buffer, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{method: r.Method, path: r.URL.RequestURI(), header: r.Header, body: string(body)})
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/users/42")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":42,"token":"abc","tags":["a","b"]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var out bytes.Buffer
+	config := &rule.Config{BaseUrl: server.URL, Timeout: rule.DefaultTimeout}
+	return NewSession(config, jakhttp.NewClient(), &out), &out, &requests
+}
+
+func execute(t *testing.T, session *Session, line string) {
+	t.Helper()
+	quit, err := session.Execute(context.Background(), line)
+	require.NoError(t, err)
+	assert.False(t, quit)
+}
+
+func TestSession_SendRequest(t *testing.T) {
+	session, _, requests := newTestSession(t)
+
+	execute(t, session, `post /users {"name":"x"}`)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "POST", req.method)
+	assert.Equal(t, "/users", req.path)
+	assert.JSONEq(t, `{"name":"x"}`, req.body)
+	assert.Contains(t, req.header.Get("Content-Type"), "application/json")
+}
+
+func TestSession_LastResponseReferences(t *testing.T) {
+	session, out, requests := newTestSession(t)
+
+	execute(t, session, "POST /users")
+	execute(t, session, "set id=${last.body.id}")
+	execute(t, session, "set token=${last.body.token}")
+	execute(t, session, "header authorization: Bearer ${token}")
+	execute(t, session, "GET /users/${id}?status=${last.status}&first=${last.body.tags.0}&loc=${last.headers.Location}")
+
+	assert.Contains(t, out.String(), "id = 42\n")
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "/users/42?status=201&first=a&loc=/users/42", (*requests)[1].path)
+	assert.Equal(t, "Bearer abc", (*requests)[1].header.Get("Authorization"))
+}
+
+func TestSession_ReferenceErrors(t *testing.T) {
+	session, _, requests := newTestSession(t)
+
+	_, err := session.Execute(context.Background(), "GET /users/${id}")
+	assert.True(t, errors.Is(err, se.ErrUndefinedVariable))
+
+	_, err = session.Execute(context.Background(), "set id=${last.body.id}")
+	assert.True(t, errors.Is(err, se.ErrNoLastResponse))
+
+	execute(t, session, "GET /users")
+	_, err = session.Execute(context.Background(), "set id=${last.body.missing}")
+	assert.True(t, errors.Is(err, se.ErrUndefinedVariable))
+
+	// Requests with unresolved references are not sent
+	assert.Len(t, *requests, 1)
+}
+
+func TestSession_Headers(t *testing.T) {
+	session, out, requests := newTestSession(t)
+
+	execute(t, session, "header X-Trace: one")
+	execute(t, session, "header x-trace: two")
+	execute(t, session, "header Accept: text/plain")
+	execute(t, session, "headers")
+	assert.Equal(t, "X-Trace: two\nAccept: text/plain\n", out.String())
+
+	execute(t, session, "header Accept:")
+	execute(t, session, "GET /")
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "two", (*requests)[0].header.Get("X-Trace"))
+	assert.NotEqual(t, "text/plain", (*requests)[0].header.Get("Accept"))
+}
+
+func TestSession_Variables(t *testing.T) {
+	session, out, _ := newTestSession(t)
+
+	execute(t, session, "set b = 2")
+	execute(t, session, "set a=x=y")
+	assert.Equal(t, []string{"a", "b"}, session.VariableNames())
+
+	out.Reset()
+	execute(t, session, "vars")
+	assert.Equal(t, "a = x=y\nb = 2\n", out.String())
+
+	execute(t, session, "unset a")
+	assert.Equal(t, []string{"b"}, session.VariableNames())
+}
+
+func TestSession_Errors(t *testing.T) {
+	session, _, _ := newTestSession(t)
+
+	tests := []struct {
+		line string
+		err  error
+	}{
+		{"fetch /users", se.ErrUnknownCommand},
+		{"GET", se.ErrCommandUsage},
+		{"set token", se.ErrCommandUsage},
+		{"set 1x=a", se.ErrCommandUsage},
+		{"unset missing", se.ErrUndefinedVariable},
+		{"header Authorization", se.ErrCommandUsage},
+		{"base api.example.com", se.ErrCommandUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			_, err := session.Execute(context.Background(), tt.line)
+			assert.True(t, errors.Is(err, tt.err), "got %v", err)
+		})
+	}
+}
+
+func TestSession_Exit(t *testing.T) {
+	session, _, _ := newTestSession(t)
+
+	for _, line := range []string{"exit", "QUIT"} {
+		quit, err := session.Execute(context.Background(), line)
+		require.NoError(t, err)
+		assert.True(t, quit)
+	}
+}
+
+func TestSession_BaseURL(t *testing.T) {
+	session, out, requests := newTestSession(t)
+	serverURL := session.config.BaseUrl
+
+	execute(t, session, "base")
+	assert.Equal(t, serverURL+"\n", out.String())
+
+	session.config.BaseUrl = ""
+	_, err := session.Execute(context.Background(), "GET /users")
+	assert.True(t, errors.Is(err, se.ErrCommandUsage))
+
+	execute(t, session, "GET "+serverURL+"/absolute")
+	execute(t, session, "base "+serverURL+"/api")
+	execute(t, session, "GET /users")
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "/absolute", (*requests)[0].path)
+	assert.Equal(t, "/api/users", (*requests)[1].path)
+}
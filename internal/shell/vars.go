@@ -0,0 +1,133 @@
+package shell
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// referencePattern matches ${...} references in commands
+var referencePattern = regexp.MustCompile(`\${([^}]+)}`)
+
+// variableNamePattern matches valid variable names
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// References to the last response.
+const (
+	// lastPrefix starts every reference to the last response
+	lastPrefix = "last."
+
+	// lastStatus refers to the status code of the last response, e.g. ${last.status}
+	lastStatus = "last.status"
+
+	// lastBody refers to the whole body of the last response, e.g. ${last.body}
+	lastBody = "last.body"
+
+	// lastBodyPrefix refers to fields of a JSON body by gjson path, e.g. ${last.body.id}
+	lastBodyPrefix = "last.body."
+
+	// lastHeadersPrefix refers to response headers, e.g. ${last.headers.Location}
+	lastHeadersPrefix = "last.headers."
+)
+
+// lastResponse holds the parts of the last response that references can refer to.
+type lastResponse struct {
+	// status is the status code
+	status int
+
+	// header contains the response headers
+	header http.Header
+
+	// body is the response body
+	body []byte
+}
+
+// lookup returns the value of a reference to the last response.
+//
+// Parameters:
+//   - name: Reference without ${ and }, starting with "last."
+//
+// Returns:
+//   - string: Referenced value
+//   - bool: True if the value exists
+func (r *lastResponse) lookup(name string) (string, bool) {
+	switch {
+	case name == lastStatus:
+		return strconv.Itoa(r.status), true
+	case name == lastBody:
+		return string(r.body), true
+	case strings.HasPrefix(name, lastBodyPrefix):
+		value := gjson.GetBytes(r.body, strings.TrimPrefix(name, lastBodyPrefix))
+		if !value.Exists() {
+			return "", false
+		}
+		return value.String(), true
+	case strings.HasPrefix(name, lastHeadersPrefix):
+		values := r.header.Values(strings.TrimPrefix(name, lastHeadersPrefix))
+		if len(values) == 0 {
+			return "", false
+		}
+		return strings.Join(values, ", "), true
+	default:
+		return "", false
+	}
+}
+
+// resolve replaces ${name} references with session variables and ${last.*}
+// references with values of the last response.
+// Unlike chain variables, references that cannot be resolved are an error,
+// so mistyped names are noticed before a request is sent.
+//
+// Parameters:
+//   - input: Text containing references
+//
+// Returns:
+//   - string: Text with references replaced
+//   - error: se.ErrUndefinedVariable or se.ErrNoLastResponse if a reference cannot be resolved
+func (s *Session) resolve(input string) (string, error) {
+	var resolveErr error
+	resolved := referencePattern.ReplaceAllStringFunc(input, func(match string) string {
+		name := referencePattern.FindStringSubmatch(match)[1]
+		value, err := s.lookup(name)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return value
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+// lookup returns the value of a single reference.
+//
+// Parameters:
+//   - name: Reference without ${ and }
+//
+// Returns:
+//   - string: Referenced value
+//   - error: Error if the reference cannot be resolved
+func (s *Session) lookup(name string) (string, error) {
+	if strings.HasPrefix(name, lastPrefix) {
+		if s.last == nil {
+			return "", fmt.Errorf("%w: ${%s}", se.ErrNoLastResponse, name)
+		}
+		value, ok := s.last.lookup(name)
+		if !ok {
+			return "", fmt.Errorf("%w: ${%s} is not in the last response", se.ErrUndefinedVariable, name)
+		}
+		return value, nil
+	}
+
+	value, ok := s.variables[name]
+	if !ok {
+		return "", fmt.Errorf("%w: ${%s}", se.ErrUndefinedVariable, name)
+	}
+	return value, nil
+}
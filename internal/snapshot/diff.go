@@ -0,0 +1,219 @@
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change in a diff.
+const contextLines = 3
+
+// editKind is the kind of a line in an edit script.
+type editKind byte
+
+// Kinds of lines in an edit script, written as the unified diff line prefix.
+const (
+	// editEqual is a line present in both texts
+	editEqual editKind = ' '
+
+	// editDelete is a line only present in the old text
+	editDelete editKind = '-'
+
+	// editInsert is a line only present in the new text
+	editInsert editKind = '+'
+)
+
+// edit is a line of an edit script turning one text into another.
+type edit struct {
+	kind editKind
+	line string
+}
+
+// Unified returns a unified diff between two texts.
+//
+// Parameters:
+//   - expected: Old text
+//   - actual: New text
+//   - fromName: Name of the old text in the diff header
+//   - toName: Name of the new text in the diff header
+//
+// Returns:
+//   - string: Unified diff with three lines of context, or empty if the texts are equal
+func Unified(expected, actual, fromName, toName string) string {
+	if expected == actual {
+		return ""
+	}
+
+	edits := editScript(splitLines(expected), splitLines(actual))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+
+	for start := 0; start < len(edits); {
+		// Find the next change and the end of the hunk around it
+		first := start
+		for first < len(edits) && edits[first].kind == editEqual {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		last := first
+		for i := first; i < len(edits) && i-last <= 2*contextLines+1; i++ {
+			if edits[i].kind != editEqual {
+				last = i
+			}
+		}
+
+		from := max(first-contextLines, 0)
+		to := min(last+contextLines+1, len(edits))
+		writeHunk(&b, edits, from, to)
+		start = to
+	}
+	return b.String()
+}
+
+// writeHunk writes the lines of an edit script between from and to as a hunk.
+//
+// Parameters:
+//   - b: Builder receiving the hunk
+//   - edits: Complete edit script
+//   - from: Index of the first edit of the hunk
+//   - to: Index after the last edit of the hunk
+func writeHunk(b *strings.Builder, edits []edit, from, to int) {
+	// Line numbers before the hunk
+	oldStart, newStart := 0, 0
+	for _, e := range edits[:from] {
+		if e.kind != editInsert {
+			oldStart++
+		}
+		if e.kind != editDelete {
+			newStart++
+		}
+	}
+
+	oldLength, newLength := 0, 0
+	for _, e := range edits[from:to] {
+		if e.kind != editInsert {
+			oldLength++
+		}
+		if e.kind != editDelete {
+			newLength++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLength), hunkRange(newStart, newLength))
+	for _, e := range edits[from:to] {
+		b.WriteByte(byte(e.kind))
+		b.WriteString(e.line)
+		b.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the line range of a hunk header.
+//
+// Parameters:
+//   - start: Number of lines before the hunk
+//   - length: Number of lines in the hunk
+//
+// Returns:
+//   - string: Range such as "3,4", "3" for a single line or "2,0" for no lines
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, length)
+	}
+}
+
+// splitLines splits a text into lines without their line endings.
+//
+// Parameters:
+//   - text: Text to split
+//
+// Returns:
+//   - []string: Lines, empty for an empty text
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// editScript computes a shortest edit script between two line slices
+// with the Myers difference algorithm.
+//
+// Parameters:
+//   - a: Old lines
+//   - b: New lines
+//
+// Returns:
+//   - []edit: Edit script in order, deletions before insertions
+func editScript(a, b []string) []edit {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+
+	// Forward pass: find the furthest reaching path for each number of edits
+search:
+	for d := 0; d <= offset; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backward pass: walk the trace from the end to recover the edits
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{kind: editEqual, line: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{kind: editInsert, line: b[prevY]})
+			} else {
+				edits = append(edits, edit{kind: editDelete, line: a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
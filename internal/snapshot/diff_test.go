@@ -0,0 +1,96 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		want     string
+	}{
+		{name: "equal", expected: "a\nb\n", actual: "a\nb\n", want: ""},
+		{
+			name:     "changed line",
+			expected: "a\nb\nc\n",
+			actual:   "a\nB\nc\n",
+			want:     "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:     "added lines to empty",
+			expected: "",
+			actual:   "a\nb\n",
+			want:     "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:     "removed line",
+			expected: "a\nb\n",
+			actual:   "a\n",
+			want:     "--- old\n+++ new\n@@ -1,2 +1 @@\n a\n-b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Unified(tt.expected, tt.actual, "old", "new"))
+		})
+	}
+}
+
+func TestUnified_Hunks(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, string(rune('a'+i-1)))
+	}
+	expected := strings.Join(lines, "\n") + "\n"
+
+	lines[1] = "B"
+	lines[17] = "R"
+	actual := strings.Join(lines, "\n") + "\n"
+
+	want := "--- old\n+++ new\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -15,6 +15,6 @@\n o\n p\n q\n-r\n+R\n s\n t\n"
+	assert.Equal(t, want, Unified(expected, actual, "old", "new"))
+}
+
+func TestUnified_MergesCloseChanges(t *testing.T) {
+	// Six unchanged lines between the changes: the contexts of both touch
+	expected := "1\n2\n3\n4\n5\n6\n7\n8\n"
+	actual := "x\n2\n3\n4\n5\n6\n7\ny\n"
+
+	diff := Unified(expected, actual, "old", "new")
+
+	assert.Equal(t, 1, strings.Count(diff, "@@ -"))
+	assert.Contains(t, diff, "@@ -1,8 +1,8 @@\n")
+}
+
+func TestEditScript(t *testing.T) {
+	a := []string{"a", "b", "c", "a", "b", "b", "a"}
+	b := []string{"c", "b", "a", "b", "a", "c"}
+
+	edits := editScript(a, b)
+
+	// Applying the script must turn a into b with the minimal 5 edits
+	var old, new []string
+	changes := 0
+	for _, e := range edits {
+		if e.kind != editInsert {
+			old = append(old, e.line)
+		}
+		if e.kind != editDelete {
+			new = append(new, e.line)
+		}
+		if e.kind != editEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, a, old)
+	assert.Equal(t, b, new)
+	assert.Equal(t, 5, changes)
+}
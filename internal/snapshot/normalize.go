@@ -0,0 +1,150 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// IgnoredValue replaces the values of ignored paths in normalized bodies.
+const IgnoredValue = "[IGNORED]"
+
+// Normalize converts a response body into the stable text stored in snapshots.
+// JSON bodies are pretty-printed with sorted keys and the values of the ignore
+// paths replaced by IgnoredValue; other bodies are kept with unified line endings.
+//
+// Ignore paths are dot-separated, e.g. "data.#.created_at": "#" or "*" matches every
+// array element, "*" every object key, and "\." escapes a dot in a key.
+//
+// Parameters:
+//   - body: Response body
+//   - ignore: Paths whose values are masked
+//
+// Returns:
+//   - []byte: Normalized body ending with a newline, or empty for an empty body
+func Normalize(body []byte, ignore []string) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	value, ok := DecodeJSON(body)
+	if !ok {
+		text := strings.ReplaceAll(string(body), "\r\n", "\n")
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		return []byte(text)
+	}
+
+	value = Mask(value, ignore)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return body
+	}
+	return buf.Bytes()
+}
+
+// DecodeJSON decodes a body holding exactly one JSON value, keeping numbers as written
+// (json.Number).
+//
+// Parameters:
+//   - body: Body to decode
+//
+// Returns:
+//   - interface{}: Decoded value
+//   - bool: True if the body is a single JSON value
+func DecodeJSON(body []byte) (interface{}, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	if decoder.More() {
+		return nil, false
+	}
+	return value, true
+}
+
+// Mask replaces the values at the ignore paths of a decoded JSON value with IgnoredValue.
+// Paths use the syntax described at Normalize; paths that do not exist are skipped.
+//
+// Parameters:
+//   - value: Value decoded by DecodeJSON, modified in place
+//   - ignore: Paths whose values are masked
+//
+// Returns:
+//   - interface{}: Masked value
+func Mask(value interface{}, ignore []string) interface{} {
+	for _, path := range ignore {
+		value = mask(value, splitPath(path))
+	}
+	return value
+}
+
+// splitPath splits an ignore path into its components, unescaping "\.".
+//
+// Parameters:
+//   - path: Dot-separated path
+//
+// Returns:
+//   - []string: Path components
+func splitPath(path string) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path) && path[i+1] == '.':
+			current.WriteByte('.')
+			i++
+		case path[i] == '.':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(path[i])
+		}
+	}
+	return append(parts, current.String())
+}
+
+// mask replaces the values at a path with IgnoredValue.
+// Paths that do not exist in the value are ignored.
+//
+// Parameters:
+//   - value: Decoded JSON value, modified in place
+//   - parts: Remaining path components
+//
+// Returns:
+//   - interface{}: Value with the matching values replaced
+func mask(value interface{}, parts []string) interface{} {
+	if len(parts) == 0 {
+		return IgnoredValue
+	}
+
+	part, rest := parts[0], parts[1:]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if part == "*" {
+			for key, child := range v {
+				v[key] = mask(child, rest)
+			}
+		} else if child, ok := v[part]; ok {
+			v[part] = mask(child, rest)
+		}
+	case []interface{}:
+		if part == "#" || part == "*" {
+			for i, child := range v {
+				v[i] = mask(child, rest)
+			}
+		} else if index, err := strconv.Atoi(part); err == nil && index >= 0 && index < len(v) {
+			v[index] = mask(v[index], rest)
+		}
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		ignore []string
+		want   string
+	}{
+		{name: "empty body", body: "", want: ""},
+		{name: "whitespace body", body: " \n", want: ""},
+		{
+			name: "sorted keys and indentation",
+			body: `{"b":1,"a":{"d":true,"c":null}}`,
+			want: "{\n  \"a\": {\n    \"c\": null,\n    \"d\": true\n  },\n  \"b\": 1\n}\n",
+		},
+		{name: "numbers kept as written", body: `[1.50, 12345678901234567890]`, want: "[\n  1.50,\n  12345678901234567890\n]\n"},
+		{name: "html not escaped", body: `{"html":"<b>&</b>"}`, want: "{\n  \"html\": \"<b>&</b>\"\n}\n"},
+		{
+			name:   "ignore field",
+			body:   `{"id":42,"name":"alice"}`,
+			ignore: []string{"id"},
+			want:   "{\n  \"id\": \"[IGNORED]\",\n  \"name\": \"alice\"\n}\n",
+		},
+		{
+			name:   "ignore every array element",
+			body:   `{"data":[{"id":1,"at":"x"},{"id":2,"at":"y"}]}`,
+			ignore: []string{"data.#.at"},
+			want:   "{\n  \"data\": [\n    {\n      \"at\": \"[IGNORED]\",\n      \"id\": 1\n    },\n    {\n      \"at\": \"[IGNORED]\",\n      \"id\": 2\n    }\n  ]\n}\n",
+		},
+		{
+			name:   "ignore by index and wildcard key",
+			body:   `[{"a":1,"b":2},{"a":3}]`,
+			ignore: []string{"0.*"},
+			want:   "[\n  {\n    \"a\": \"[IGNORED]\",\n    \"b\": \"[IGNORED]\"\n  },\n  {\n    \"a\": 3\n  }\n]\n",
+		},
+		{
+			name:   "escaped dot",
+			body:   `{"x.request.id":"abc","x":{"request":{"id":"def"}}}`,
+			ignore: []string{`x\.request\.id`},
+			want:   "{\n  \"x\": {\n    \"request\": {\n      \"id\": \"def\"\n    }\n  },\n  \"x.request.id\": \"[IGNORED]\"\n}\n",
+		},
+		{
+			name:   "missing path",
+			body:   `{"a":1}`,
+			ignore: []string{"b.c", "a.b", "5"},
+			want:   "{\n  \"a\": 1\n}\n",
+		},
+		{name: "text body", body: "hello\r\nworld", want: "hello\nworld\n"},
+		{name: "concatenated JSON is text", body: `{"a":1}{"b":2}`, want: "{\"a\":1}{\"b\":2}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(Normalize([]byte(tt.body), tt.ignore)))
+		})
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	assert.Equal(t, []string{"a"}, splitPath("a"))
+	assert.Equal(t, []string{"data", "#", "id"}, splitPath("data.#.id"))
+	assert.Equal(t, []string{"a.b", "c"}, splitPath(`a\.b.c`))
+	assert.Equal(t, []string{`a\b`}, splitPath(`a\b`))
+}
@@ -0,0 +1,198 @@
+// Package snapshot stores normalized response bodies and compares later
+// responses against them.
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// fileExtension is the extension of snapshot files.
+const fileExtension = ".snap"
+
+// fileNamePattern matches characters replaced in snapshot file names
+var fileNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Status is the outcome of comparing a response with its snapshot.
+type Status int
+
+// Outcomes of a snapshot comparison.
+const (
+	// StatusMatched means the response matches the stored snapshot
+	StatusMatched Status = iota
+
+	// StatusCreated means no snapshot existed and the response was stored
+	StatusCreated
+
+	// StatusUpdated means the snapshot differed and was overwritten
+	StatusUpdated
+
+	// StatusMismatched means the snapshot differed and was kept
+	StatusMismatched
+)
+
+// MismatchError reports a response that differs from its snapshot.
+type MismatchError struct {
+	// Path is the snapshot file
+	Path string
+
+	// Diff is the unified diff from the snapshot to the response
+	Diff string
+}
+
+// Error returns the error message.
+//
+// Returns:
+//   - string: Message naming the snapshot file
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s: %s", se.ErrSnapshotMismatch, e.Path)
+}
+
+// Unwrap returns the sentinel error of snapshot mismatches.
+//
+// Returns:
+//   - error: se.ErrSnapshotMismatch
+func (e *MismatchError) Unwrap() error {
+	return se.ErrSnapshotMismatch
+}
+
+// Store compares responses with the snapshots in a directory.
+// It is safe for concurrent use.
+type Store struct {
+	// dir is the directory holding the snapshot files
+	dir string
+
+	// update overwrites differing snapshots instead of reporting them
+	update bool
+
+	// mu guards files
+	mu sync.Mutex
+
+	// files maps the snapshot files used in this run to their request names
+	files map[string]string
+}
+
+// NewStore creates a snapshot store.
+//
+// Parameters:
+//   - dir: Directory holding the snapshot files, created on first write
+//   - update: Whether differing snapshots are overwritten
+//
+// Returns:
+//   - *Store: Snapshot store
+func NewStore(dir string, update bool) *Store {
+	return &Store{dir: dir, update: update, files: make(map[string]string)}
+}
+
+// Path returns the snapshot file of a request.
+//
+// Parameters:
+//   - name: Request name
+//
+// Returns:
+//   - string: Path of the snapshot file, e.g. "snapshots/get_users.snap" for "Get Users"
+func (s *Store) Path(name string) string {
+	return filepath.Join(s.dir, FileName(name))
+}
+
+// Check compares a response body with the snapshot of a request.
+// A missing snapshot is created; a differing one is overwritten in update mode
+// and reported as *MismatchError otherwise.
+//
+// Parameters:
+//   - name: Request name
+//   - body: Response body
+//   - ignore: Paths whose values are masked before comparing
+//
+// Returns:
+//   - Status: Outcome of the comparison
+//   - error: *MismatchError if the response differs, or any error accessing the snapshot
+func (s *Store) Check(name string, body []byte, ignore []string) (Status, error) {
+	path := s.Path(name)
+	if err := s.claim(path, name); err != nil {
+		return StatusMismatched, err
+	}
+
+	actual := Normalize(body, ignore)
+
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return StatusCreated, write(path, actual)
+	}
+	if err != nil {
+		return StatusMismatched, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	if bytes.Equal(expected, actual) {
+		return StatusMatched, nil
+	}
+	if s.update {
+		return StatusUpdated, write(path, actual)
+	}
+	return StatusMismatched, &MismatchError{
+		Path: path,
+		Diff: Unified(string(expected), string(actual), path, name+" (response)"),
+	}
+}
+
+// claim records that a request uses a snapshot file, so that two requests
+// whose names map to the same file do not overwrite each other's snapshot.
+//
+// Parameters:
+//   - path: Snapshot file
+//   - name: Request name
+//
+// Returns:
+//   - error: Error if another request of the run uses the same file
+func (s *Store) claim(path, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if other, ok := s.files[path]; ok && other != name {
+		return fmt.Errorf("requests '%s' and '%s' share snapshot file %s; rename one of them", other, name, path)
+	}
+	s.files[path] = name
+	return nil
+}
+
+// FileName returns the snapshot file name of a request.
+//
+// Parameters:
+//   - name: Request name
+//
+// Returns:
+//   - string: Lower-case name with other characters replaced by "_", e.g. "get_users.snap"
+func FileName(name string) string {
+	base := strings.Trim(fileNamePattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" {
+		base = "request"
+	}
+	return base + fileExtension
+}
+
+// write writes a snapshot file, creating its directory.
+//
+// Parameters:
+//   - path: Snapshot file
+//   - content: Normalized body
+//
+// Returns:
+//   - error: Any error encountered while writing
+func write(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
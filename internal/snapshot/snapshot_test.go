@@ -0,0 +1,103 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "get_users.snap", FileName("Get Users"))
+	assert.Equal(t, "get_user_1.snap", FileName("Get User [1]"))
+	assert.Equal(t, "login.snap", FileName("  /login/ "))
+	assert.Equal(t, "request.snap", FileName("!!"))
+}
+
+func TestStore_Check(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	store := NewStore(dir, false)
+	path := filepath.Join(dir, "get_user.snap")
+
+	// First run stores the normalized body
+	status, err := store.Check("Get User", []byte(`{"name":"alice","id":1}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCreated, status)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"id\": 1,\n  \"name\": \"alice\"\n}\n", string(content))
+
+	// Key order and formatting do not matter
+	status, err = store.Check("Get User", []byte(`{ "id": 1, "name": "alice" }`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMatched, status)
+
+	// A changed value fails with a diff and keeps the snapshot
+	status, err = store.Check("Get User", []byte(`{"id":1,"name":"bob"}`), nil)
+	assert.Equal(t, StatusMismatched, status)
+	assert.ErrorIs(t, err, se.ErrSnapshotMismatch)
+	var mismatch *MismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, path, mismatch.Path)
+	assert.Contains(t, mismatch.Diff, "-  \"name\": \"alice\"\n+  \"name\": \"bob\"\n")
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, after)
+}
+
+func TestStore_CheckIgnore(t *testing.T) {
+	store := NewStore(t.TempDir(), false)
+	ignore := []string{"id", "created_at"}
+
+	_, err := store.Check("Create", []byte(`{"id":1,"created_at":"2024-01-01","name":"alice"}`), ignore)
+	require.NoError(t, err)
+
+	status, err := store.Check("Create", []byte(`{"id":2,"created_at":"2024-02-02","name":"alice"}`), ignore)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMatched, status)
+}
+
+func TestStore_CheckUpdate(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewStore(dir, false).Check("Get", []byte(`{"v":1}`), nil)
+	require.NoError(t, err)
+
+	store := NewStore(dir, true)
+	status, err := store.Check("Get", []byte(`{"v":2}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUpdated, status)
+
+	status, err = NewStore(dir, false).Check("Get", []byte(`{"v":2}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMatched, status)
+}
+
+func TestStore_CheckSharedFile(t *testing.T) {
+	store := NewStore(t.TempDir(), false)
+
+	_, err := store.Check("Get User", []byte(`{}`), nil)
+	require.NoError(t, err)
+	_, err = store.Check("get-user", []byte(`{}`), nil)
+
+	assert.ErrorContains(t, err, "share snapshot file")
+}
+
+func TestStore_CheckConcurrent(t *testing.T) {
+	store := NewStore(t.TempDir(), false)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			status, err := store.Check(name, []byte(`{"name":"`+name+`"}`), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, StatusCreated, status)
+		}(name)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,129 @@
+package stub
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// route is a compiled stub route.
+type route struct {
+	// name identifies the route in the log
+	name string
+
+	// method is the upper-cased method to match, empty for any method
+	method string
+
+	// segments are the segments of the path pattern
+	segments []string
+
+	// responses are the responses returned in order; the last one repeats
+	responses []rule.StubResponse
+
+	// mu guards calls
+	mu sync.Mutex
+
+	// calls is the number of requests answered by the route
+	calls int
+}
+
+// newRoute compiles a route definition.
+//
+// Parameters:
+//   - definition: Route definition
+//   - index: Index of the route in the configuration
+//
+// Returns:
+//   - *route: Compiled route
+func newRoute(definition rule.Route, index int) *route {
+	responses := definition.Responses
+	if len(responses) == 0 {
+		responses = []rule.StubResponse{definition.StubResponse}
+	}
+
+	return &route{
+		name:      definition.Label(index),
+		method:    strings.ToUpper(definition.Method),
+		segments:  splitPath(definition.Path),
+		responses: responses,
+	}
+}
+
+// match matches a request path against the path pattern.
+//
+// Parameters:
+//   - path: Request path
+//
+// Returns:
+//   - map[string]string: Path parameters, with the remainder matched by "*" under "*"
+//   - bool: True if the path matches
+func (r *route) match(path string) (map[string]string, bool) {
+	segments := splitPath(path)
+	params := make(map[string]string)
+
+	for i, pattern := range r.segments {
+		if pattern == "*" {
+			params["*"] = strings.Join(segments[i:], "/")
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}") {
+			params[pattern[1:len(pattern)-1]] = segments[i]
+			continue
+		}
+		if pattern != segments[i] {
+			return nil, false
+		}
+	}
+
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	return params, true
+}
+
+// allows reports whether the route answers a method.
+//
+// Parameters:
+//   - method: Request method
+//
+// Returns:
+//   - bool: True if the route matches any method or the given one
+func (r *route) allows(method string) bool {
+	return r.method == "" || r.method == method
+}
+
+// next returns the response for the next call of the route.
+//
+// Returns:
+//   - rule.StubResponse: Response of the current call in the sequence
+func (r *route) next() rule.StubResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := r.calls
+	if index >= len(r.responses) {
+		index = len(r.responses) - 1
+	}
+	r.calls++
+	return r.responses[index]
+}
+
+// splitPath splits a path into its segments, ignoring a trailing slash.
+//
+// Parameters:
+//   - path: Path to split
+//
+// Returns:
+//   - []string: Path segments
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	path = strings.TrimSuffix(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,176 @@
+// Package stub provides a mock HTTP server answering requests from stub route definitions.
+package stub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// maxBodySize limits the size of a request body read for templates.
+const maxBodySize = 10 * 1024 * 1024 // 10MB
+
+// HitCollector is called for every request answered by the server.
+//
+// Parameters:
+//   - route: Label of the matched route, empty if no route matched
+//   - method: Request method
+//   - url: Request path and query
+//   - statusCode: Status code of the response
+//   - duration: Time taken to answer, including the configured delay
+type HitCollector func(route, method, url string, statusCode int, duration time.Duration)
+
+// Server answers requests with the responses of the first matching route.
+// Requests matching no route get 404, or 405 if only the method differs.
+type Server struct {
+	// config holds the route definitions
+	config *rule.StubConfig
+
+	// routes are the compiled routes in match order
+	routes []*route
+
+	// hitCollector receives every answered request
+	hitCollector HitCollector
+}
+
+// NewServer creates a server from route definitions.
+//
+// Parameters:
+//   - config: Validated route definitions
+//
+// Returns:
+//   - *Server: Server ready to be used as an http.Handler
+func NewServer(config *rule.StubConfig) *Server {
+	routes := make([]*route, len(config.Route))
+	for i, definition := range config.Route {
+		routes[i] = newRoute(definition, i)
+	}
+	return &Server{config: config, routes: routes}
+}
+
+// SetHitCollector sets the function called for every answered request.
+//
+// Parameters:
+//   - collector: Function receiving the hits
+func (s *Server) SetHitCollector(collector HitCollector) {
+	s.hitCollector = collector
+}
+
+// ServeHTTP answers a request.
+//
+// Parameters:
+//   - w: Response writer
+//   - r: Incoming request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	label, status := s.serve(w, r)
+
+	if s.hitCollector != nil {
+		s.hitCollector(label, r.Method, r.URL.RequestURI(), status, time.Since(start))
+	}
+}
+
+// serve finds the matching route and writes its response.
+//
+// Parameters:
+//   - w: Response writer
+//   - r: Incoming request
+//
+// Returns:
+//   - string: Label of the matched route, empty if no route matched
+//   - int: Status code written
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) (string, int) {
+	var allowed []string
+
+	for _, route := range s.routes {
+		params, ok := route.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+		if !route.allows(r.Method) {
+			allowed = append(allowed, route.method)
+			continue
+		}
+		return route.name, s.respond(w, r, route.next(), params)
+	}
+
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		return "", writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed for %s", r.Method, r.URL.Path))
+	}
+	return "", writeError(w, http.StatusNotFound, fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+}
+
+// respond writes a stub response, rendering its templates with request values.
+//
+// Parameters:
+//   - w: Response writer
+//   - r: Incoming request
+//   - resp: Stub response to write
+//   - params: Path parameters of the matched route
+//
+// Returns:
+//   - int: Status code written
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, resp rule.StubResponse, params map[string]string) int {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %s", err))
+	}
+	data := &templateData{request: r, params: params, body: body}
+
+	content := resp.Body
+	if resp.BodyFile != "" {
+		raw, err := os.ReadFile(s.config.ResolvePath(resp.BodyFile))
+		if err != nil {
+			return writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read body_file: %s", err))
+		}
+		content = string(raw)
+	}
+	content = data.render(content)
+
+	if resp.Delay > 0 {
+		timer := time.NewTimer(resp.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return 0
+		}
+	}
+
+	for _, header := range resp.Headers {
+		key, value, _ := strings.Cut(header, ":")
+		w.Header().Add(strings.TrimSpace(key), data.render(strings.TrimSpace(value)))
+	}
+	if w.Header().Get("Content-Type") == "" && content != "" && json.Valid([]byte(content)) {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	status := resp.StatusCode()
+	w.WriteHeader(status)
+	io.WriteString(w, content)
+	return status
+}
+
+// writeError writes a JSON error response.
+//
+// Parameters:
+//   - w: Response writer
+//   - status: Status code
+//   - message: Error message
+//
+// Returns:
+//   - int: Status code written
+func writeError(w http.ResponseWriter, status int, message string) int {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+	return status
+}
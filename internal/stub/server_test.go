@@ -0,0 +1,223 @@
+package stub
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// hit is a request received by the hit collector in tests
+type hit struct {
+	route  string
+	method string
+	url    string
+	status int
+}
+
+// newTestServer starts a server for route definitions and records its hits
+func newTestServer(t *testing.T, config *rule.StubConfig) (*httptest.Server, func() []hit) {
+	t.Helper()
+	require.NoError(t, config.Validate())
+
+	var mu sync.Mutex
+	var hits []hit
+	server := NewServer(config)
+	server.SetHitCollector(func(route, method, url string, statusCode int, duration time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		hits = append(hits, hit{route: route, method: method, url: url, status: statusCode})
+	})
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return ts, func() []hit {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]hit(nil), hits...)
+	}
+}
+
+// send sends a request and returns the status, headers and body of the response
+func send(t *testing.T, method, url, body string, headers map[string]string) (int, http.Header, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, resp.Header, string(content)
+}
+
+func TestServer_Routes(t *testing.T) {
+	ts, hits := newTestServer(t, &rule.StubConfig{Route: []rule.Route{
+		{Name: "List", Method: "GET", Path: "/users", StubResponse: rule.StubResponse{Body: `[]`}},
+		{Name: "Create", Method: "post", Path: "/users", StubResponse: rule.StubResponse{Status: 201, Headers: []string{"Location: /users/1"}}},
+		{Name: "Get", Method: "GET", Path: "/users/{id}", StubResponse: rule.StubResponse{Body: "user ${params.id}"}},
+		{Path: "/static/*", StubResponse: rule.StubResponse{Body: "${params.*}"}},
+	}})
+
+	status, header, body := send(t, "GET", ts.URL+"/users", "", nil)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "[]", body)
+
+	status, header, _ = send(t, "POST", ts.URL+"/users/", "", nil)
+	assert.Equal(t, 201, status)
+	assert.Equal(t, "/users/1", header.Get("Location"))
+
+	_, header, body = send(t, "GET", ts.URL+"/users/7", "", nil)
+	assert.Equal(t, "user 7", body)
+	assert.NotEqual(t, "application/json", header.Get("Content-Type"))
+
+	_, _, body = send(t, "DELETE", ts.URL+"/static/css/site.css", "", nil)
+	assert.Equal(t, "css/site.css", body)
+
+	status, header, body = send(t, "DELETE", ts.URL+"/users", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+	assert.Equal(t, "GET, POST", header.Get("Allow"))
+	assert.JSONEq(t, `{"error": "method DELETE not allowed for /users"}`, body)
+
+	status, _, body = send(t, "GET", ts.URL+"/users/7/posts?page=2", "", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.JSONEq(t, `{"error": "no route for GET /users/7/posts"}`, body)
+
+	assert.Equal(t, []hit{
+		{route: "List", method: "GET", url: "/users", status: 200},
+		{route: "Create", method: "POST", url: "/users/", status: 201},
+		{route: "Get", method: "GET", url: "/users/7", status: 200},
+		{route: "/static/*", method: "DELETE", url: "/static/css/site.css", status: 200},
+		{route: "", method: "DELETE", url: "/users", status: 405},
+		{route: "", method: "GET", url: "/users/7/posts?page=2", status: 404},
+	}, hits())
+}
+
+func TestServer_Template(t *testing.T) {
+	ts, _ := newTestServer(t, &rule.StubConfig{Route: []rule.Route{
+		{
+			Method: "POST",
+			Path:   "/orgs/{org}/users",
+			StubResponse: rule.StubResponse{
+				Headers: []string{"X-Echo: ${headers.X-Request-Id}", "Content-Type: application/json"},
+				Body:    `{"org": "${params.org}", "name": "${body.user.name}", "page": "${query.page}", "method": "${method}", "path": "${path}", "missing": "${unknown}", "raw": ${body}}`,
+			},
+		},
+	}})
+
+	status, header, body := send(t, "POST", ts.URL+"/orgs/acme/users?page=3", `{"user": {"name": "alice"}}`, map[string]string{"X-Request-Id": "r-1"})
+
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "r-1", header.Get("X-Echo"))
+	assert.JSONEq(t, `{
+		"org": "acme", "name": "alice", "page": "3", "method": "POST", "path": "/orgs/acme/users",
+		"missing": "", "raw": {"user": {"name": "alice"}}
+	}`, body)
+}
+
+func TestServer_Sequence(t *testing.T) {
+	ts, _ := newTestServer(t, &rule.StubConfig{Route: []rule.Route{
+		{Path: "/jobs/{id}", Responses: []rule.StubResponse{
+			{Status: 202, Body: "pending"},
+			{Status: 202, Body: "running"},
+			{Body: "done ${params.id}"},
+		}},
+	}})
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		status, _, body := send(t, "GET", ts.URL+"/jobs/9", "", nil)
+		got = append(got, body)
+		if i < 2 {
+			assert.Equal(t, 202, status)
+		} else {
+			assert.Equal(t, 200, status)
+		}
+	}
+	assert.Equal(t, []string{"pending", "running", "done 9", "done 9"}, got)
+}
+
+func TestServer_BodyFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{"id": "${params.id}"}`), 0644))
+	stubPath := filepath.Join(dir, "mocks.toml")
+	require.NoError(t, os.WriteFile(stubPath, []byte(`
+[[route]]
+path = "/users/{id}"
+body_file = "user.json"
+
+[[route]]
+path = "/missing"
+body_file = "missing.json"
+`), 0644))
+
+	config, err := rule.LoadStubConfig(stubPath)
+	require.NoError(t, err)
+	ts, _ := newTestServer(t, config)
+
+	status, header, body := send(t, "GET", ts.URL+"/users/5", "", nil)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.JSONEq(t, `{"id": "5"}`, body)
+
+	status, _, body = send(t, "GET", ts.URL+"/missing", "", nil)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(t, body, "failed to read body_file")
+}
+
+func TestServer_Delay(t *testing.T) {
+	ts, _ := newTestServer(t, &rule.StubConfig{Route: []rule.Route{
+		{Path: "/slow", StubResponse: rule.StubResponse{Delay: 100 * time.Millisecond}},
+	}})
+
+	start := time.Now()
+	status, _, _ := send(t, "GET", ts.URL+"/slow", "", nil)
+
+	assert.Equal(t, 200, status)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRoute_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    map[string]string
+		match   bool
+	}{
+		{pattern: "/", path: "/", want: map[string]string{}, match: true},
+		{pattern: "/users", path: "/users/", want: map[string]string{}, match: true},
+		{pattern: "/users", path: "/user", match: false},
+		{pattern: "/users/{id}", path: "/users", match: false},
+		{pattern: "/users/{id}", path: "/users/1/posts", match: false},
+		{pattern: "/users/{id}/posts/{post}", path: "/users/1/posts/2", want: map[string]string{"id": "1", "post": "2"}, match: true},
+		{pattern: "/files/*", path: "/files", want: map[string]string{"*": ""}, match: true},
+		{pattern: "/files/*", path: "/files/a/b", want: map[string]string{"*": "a/b"}, match: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			r := newRoute(rule.Route{Path: tt.pattern}, 0)
+
+			params, ok := r.match(tt.path)
+
+			assert.Equal(t, tt.match, ok)
+			assert.Equal(t, tt.want, params)
+		})
+	}
+}
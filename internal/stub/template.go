@@ -0,0 +1,86 @@
+package stub
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// referencePattern matches ${...} references in responses
+var referencePattern = regexp.MustCompile(`\${([^}]+)}`)
+
+// Reference prefixes available in response templates.
+const (
+	// paramsPrefix refers to path parameters, e.g. ${params.id}
+	paramsPrefix = "params."
+
+	// queryPrefix refers to query parameters, e.g. ${query.page}
+	queryPrefix = "query."
+
+	// headersPrefix refers to request headers, e.g. ${headers.X-Request-Id}
+	headersPrefix = "headers."
+
+	// bodyPrefix refers to fields of a JSON request body by gjson path, e.g. ${body.user.name}
+	bodyPrefix = "body."
+)
+
+// templateData holds the request values a response template can refer to.
+type templateData struct {
+	// request is the incoming request
+	request *http.Request
+
+	// params are the path parameters
+	params map[string]string
+
+	// body is the request body
+	body []byte
+}
+
+// lookup returns the value of a reference.
+// Besides the prefixed references, ${method}, ${path} and ${body} refer to the
+// request method, path and whole body.
+//
+// Parameters:
+//   - name: Reference without ${ and }
+//
+// Returns:
+//   - string: Referenced value, or empty if it does not exist
+func (d *templateData) lookup(name string) string {
+	switch {
+	case name == "method":
+		return d.request.Method
+	case name == "path":
+		return d.request.URL.Path
+	case name == "body":
+		return string(d.body)
+	case strings.HasPrefix(name, paramsPrefix):
+		return d.params[strings.TrimPrefix(name, paramsPrefix)]
+	case strings.HasPrefix(name, queryPrefix):
+		return d.request.URL.Query().Get(strings.TrimPrefix(name, queryPrefix))
+	case strings.HasPrefix(name, headersPrefix):
+		return d.request.Header.Get(strings.TrimPrefix(name, headersPrefix))
+	case strings.HasPrefix(name, bodyPrefix):
+		return gjson.GetBytes(d.body, strings.TrimPrefix(name, bodyPrefix)).String()
+	default:
+		return ""
+	}
+}
+
+// render replaces the references in a template with request values.
+//
+// Parameters:
+//   - template: Body or header value containing ${...} references
+//
+// Returns:
+//   - string: Rendered value
+func (d *templateData) render(template string) string {
+	if !strings.Contains(template, "${") {
+		return template
+	}
+
+	return referencePattern.ReplaceAllStringFunc(template, func(match string) string {
+		return d.lookup(strings.TrimSpace(match[2 : len(match)-1]))
+	})
+}
@@ -0,0 +1,11 @@
+package sys_error
+
+import (
+	"errors"
+)
+
+var (
+	// Cassette related errors
+	ErrInvalidCassette  = errors.New("invalid cassette")
+	ErrCassetteMismatch = errors.New("no matching interaction in cassette")
+)
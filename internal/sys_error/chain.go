@@ -12,6 +12,7 @@ var (
 	// Request execution errors
 	ErrRequestExecution = errors.New("request execution failed")
 	ErrCreateRequest    = errors.New("failed to create request")
+	ErrRequestSkipped   = errors.New("request skipped")
 
 	// Variable handling errors
 	ErrVariableExtraction   = errors.New("failed to extract variables")
@@ -0,0 +1,10 @@
+package sys_error
+
+import (
+	"errors"
+)
+
+var (
+	// Dataset related errors
+	ErrInvalidDataset = errors.New("invalid dataset")
+)
@@ -0,0 +1,10 @@
+package sys_error
+
+import (
+	"errors"
+)
+
+var (
+	// Diff related errors
+	ErrResponsesDiffer = errors.New("responses of the environments differ")
+)
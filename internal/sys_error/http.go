@@ -11,4 +11,6 @@ var (
 	ErrInvalidJSONFormat  = errors.New("invalid JSON body")
 	ErrRequestCreation    = errors.New("failed to create HTTP request")
 	ErrResponseReadFailed = errors.New("failed to read response body")
+	ErrPagination         = errors.New("pagination failed")
+	ErrPollTimeout        = errors.New("polling timed out")
 )
@@ -0,0 +1,14 @@
+package sys_error
+
+import (
+	"errors"
+)
+
+var (
+	// Shell related errors
+	ErrUnknownCommand    = errors.New("unknown command")
+	ErrCommandUsage      = errors.New("invalid command usage")
+	ErrUndefinedVariable = errors.New("undefined variable")
+	ErrNoLastResponse    = errors.New("no response received yet")
+	ErrInterrupted       = errors.New("interrupted")
+)
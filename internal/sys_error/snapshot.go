@@ -0,0 +1,10 @@
+package sys_error
+
+import (
+	"errors"
+)
+
+var (
+	// Snapshot related errors
+	ErrSnapshotMismatch = errors.New("response does not match snapshot")
+)
@@ -0,0 +1,113 @@
+// Package term controls terminals: raw mode, size and decoding of the keys read from them.
+package term
+
+import (
+	"bufio"
+	"unicode"
+)
+
+// Control characters.
+const (
+	KeyCtrlA     = 1
+	KeyCtrlB     = 2
+	KeyCtrlC     = 3
+	KeyCtrlD     = 4
+	KeyCtrlE     = 5
+	KeyCtrlF     = 6
+	KeyCtrlH     = 8
+	KeyTab       = 9
+	KeyCtrlK     = 11
+	KeyCtrlL     = 12
+	KeyEnter     = 13
+	KeyCtrlN     = 14
+	KeyCtrlP     = 16
+	KeyCtrlU     = 21
+	KeyCtrlW     = 23
+	KeyEscape    = 27
+	KeyBackspace = 127
+)
+
+// Keys sent as escape sequences, mapped to values outside the Unicode range.
+const (
+	KeyUp rune = unicode.MaxRune + 1 + iota
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyDelete
+	KeyPageUp
+	KeyPageDown
+	KeyShiftTab
+	KeyUnknown
+)
+
+// ReadKey reads a key, decoding the escape sequences of cursor and editing keys.
+// An escape that is not followed by more input is returned as KeyEscape.
+//
+// Parameters:
+//   - r: Reader of the terminal input
+//
+// Returns:
+//   - rune: Character, or one of the key constants
+//   - error: Any error encountered while reading
+func ReadKey(r *bufio.Reader) (rune, error) {
+	key, _, err := r.ReadRune()
+	if err != nil || key != KeyEscape {
+		return key, err
+	}
+
+	if r.Buffered() == 0 {
+		return KeyEscape, nil
+	}
+	introducer, _, err := r.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if introducer != '[' && introducer != 'O' {
+		return KeyUnknown, nil
+	}
+
+	var params []rune
+	for {
+		final, _, err := r.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if final >= '0' && final <= '9' || final == ';' {
+			params = append(params, final)
+			continue
+		}
+
+		switch final {
+		case 'A':
+			return KeyUp, nil
+		case 'B':
+			return KeyDown, nil
+		case 'C':
+			return KeyRight, nil
+		case 'D':
+			return KeyLeft, nil
+		case 'H':
+			return KeyHome, nil
+		case 'F':
+			return KeyEnd, nil
+		case 'Z':
+			return KeyShiftTab, nil
+		case '~':
+			switch string(params) {
+			case "1", "7":
+				return KeyHome, nil
+			case "4", "8":
+				return KeyEnd, nil
+			case "3":
+				return KeyDelete, nil
+			case "5":
+				return KeyPageUp, nil
+			case "6":
+				return KeyPageDown, nil
+			}
+		}
+		return KeyUnknown, nil
+	}
+}
@@ -0,0 +1,47 @@
+package term
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []rune
+	}{
+		{name: "characters", input: "aé", want: []rune{'a', 'é'}},
+		{name: "control characters", input: "\x01\r\x7f", want: []rune{KeyCtrlA, KeyEnter, KeyBackspace}},
+		{name: "cursor keys", input: "\x1b[A\x1b[B\x1b[C\x1b[D", want: []rune{KeyUp, KeyDown, KeyRight, KeyLeft}},
+		{name: "application cursor keys", input: "\x1bOA\x1bOH", want: []rune{KeyUp, KeyHome}},
+		{name: "editing keys", input: "\x1b[1~\x1b[4~\x1b[3~", want: []rune{KeyHome, KeyEnd, KeyDelete}},
+		{name: "page keys", input: "\x1b[5~\x1b[6~", want: []rune{KeyPageUp, KeyPageDown}},
+		{name: "shift tab", input: "\x1b[Z", want: []rune{KeyShiftTab}},
+		{name: "modified key", input: "\x1b[1;5Ax", want: []rune{KeyUp, 'x'}},
+		{name: "unknown sequence", input: "\x1b[9~\x1bx", want: []rune{KeyUnknown, KeyUnknown}},
+		{name: "lone escape", input: "\x1b", want: []rune{KeyEscape}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+
+			var keys []rune
+			for {
+				key, err := ReadKey(reader)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				keys = append(keys, key)
+			}
+			assert.Equal(t, tt.want, keys)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build linux
+
+package term
+
+import "syscall"
+
+// ioctl requests for the terminal attributes.
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
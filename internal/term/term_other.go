@@ -0,0 +1,59 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package term
+
+import "errors"
+
+// errUnsupported is returned on platforms without raw mode support
+var errUnsupported = errors.New("terminal control is not supported on this platform")
+
+// State is the terminal state restored by Restore.
+type State struct{}
+
+// IsTerminal reports that terminals cannot be controlled on this platform.
+//
+// Parameters:
+//   - fd: File descriptor to check
+//
+// Returns:
+//   - bool: Always false
+func IsTerminal(fd int) bool {
+	return false
+}
+
+// MakeRaw reports that raw mode is not supported on this platform.
+//
+// Parameters:
+//   - fd: File descriptor of the terminal
+//
+// Returns:
+//   - *State: Always nil
+//   - error: Always an error
+func MakeRaw(fd int) (*State, error) {
+	return nil, errUnsupported
+}
+
+// Restore does nothing on platforms without raw mode.
+//
+// Parameters:
+//   - fd: File descriptor of the terminal
+//   - state: State returned by MakeRaw
+//
+// Returns:
+//   - error: Always nil
+func Restore(fd int, state *State) error {
+	return nil
+}
+
+// Size reports that the terminal size is not available on this platform.
+//
+// Parameters:
+//   - fd: File descriptor of the terminal
+//
+// Returns:
+//   - int: Always 0
+//   - int: Always 0
+//   - error: Always an error
+func Size(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,106 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// State is the terminal state restored by Restore.
+type State struct {
+	// termios holds the terminal attributes before raw mode
+	termios syscall.Termios
+}
+
+// winsize is the terminal size reported by TIOCGWINSZ.
+type winsize struct {
+	rows   uint16
+	cols   uint16
+	xpixel uint16
+	ypixel uint16
+}
+
+// IsTerminal reports whether a file descriptor refers to a terminal.
+//
+// Parameters:
+//   - fd: File descriptor to check
+//
+// Returns:
+//   - bool: True for a terminal
+func IsTerminal(fd int) bool {
+	var termios syscall.Termios
+	return ioctl(fd, ioctlGetTermios, unsafe.Pointer(&termios)) == nil
+}
+
+// MakeRaw puts a terminal into raw mode, so keys are read one at a time
+// without echo and Ctrl+C is read as a key. Output processing is left enabled.
+//
+// Parameters:
+//   - fd: File descriptor of the terminal
+//
+// Returns:
+//   - *State: State to restore with Restore
+//   - error: Error if fd is not a terminal
+func MakeRaw(fd int) (*State, error) {
+	var termios syscall.Termios
+	if err := ioctl(fd, ioctlGetTermios, unsafe.Pointer(&termios)); err != nil {
+		return nil, err
+	}
+	state := &State{termios: termios}
+
+	termios.Iflag &^= syscall.BRKINT | syscall.ICRNL | syscall.INPCK | syscall.ISTRIP | syscall.IXON
+	termios.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, ioctlSetTermios, unsafe.Pointer(&termios)); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Restore restores the state of a terminal saved by MakeRaw.
+//
+// Parameters:
+//   - fd: File descriptor of the terminal
+//   - state: State returned by MakeRaw
+//
+// Returns:
+//   - error: Error of the system call
+func Restore(fd int, state *State) error {
+	return ioctl(fd, ioctlSetTermios, unsafe.Pointer(&state.termios))
+}
+
+// Size returns the size of a terminal.
+//
+// Parameters:
+//   - fd: File descriptor of the terminal
+//
+// Returns:
+//   - int: Width in columns
+//   - int: Height in rows
+//   - error: Error if fd is not a terminal
+func Size(fd int) (int, int, error) {
+	var size winsize
+	if err := ioctl(fd, syscall.TIOCGWINSZ, unsafe.Pointer(&size)); err != nil {
+		return 0, 0, err
+	}
+	return int(size.cols), int(size.rows), nil
+}
+
+// ioctl performs an ioctl system call on a terminal.
+//
+// Parameters:
+//   - fd: File descriptor of the terminal
+//   - request: ioctl request
+//   - arg: Pointer to the argument of the request
+//
+// Returns:
+//   - error: Error of the system call
+func ioctl(fd int, request uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
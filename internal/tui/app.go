@@ -0,0 +1,909 @@
+// Package tui implements a full-screen terminal interface for browsing and running configs.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ymatsukawa/jak/internal/format"
+	"github.com/ymatsukawa/jak/internal/rule"
+	"github.com/ymatsukawa/jak/internal/term"
+)
+
+// Panes of the interface, in focus order.
+const (
+	paneRequests = iota
+	paneResponse
+	paneVariables
+	paneCount
+)
+
+// Layout limits.
+const (
+	// minWidth and minHeight are the smallest terminal size the interface is drawn in
+	minWidth  = 40
+	minHeight = 8
+
+	// maxSideWidth is the largest width of the requests and variables panes
+	maxSideWidth = 40
+
+	// minVariablesWidth is the terminal width below which the variables pane is hidden
+	minVariablesWidth = 100
+)
+
+// separator is drawn between panes
+const separator = "│"
+
+// lineStyles maps the styles of response lines to escape codes.
+var lineStyles = map[int]string{
+	styleNormal:  "",
+	styleHeading: format.Bold + format.Blue,
+	styleSuccess: format.Green,
+	styleWarning: format.Yellow,
+	styleError:   format.Red,
+	styleKey:     format.Cyan,
+}
+
+// helpTexts lists the keys of each pane.
+var helpTexts = [paneCount]string{
+	paneRequests:  "enter run  space select  s run selected  r run all  tab next pane  / search  q quit",
+	paneResponse:  "↑↓ move  enter fold  z/Z fold/unfold all  h headers  / search  n/N next/prev  tab next pane  q quit",
+	paneVariables: "↑↓ scroll  r run all  tab next pane  q quit",
+}
+
+// segment is a part of a row drawn with one style.
+type segment struct {
+	// text is the text of the segment
+	text string
+
+	// style is the escape code of the style, empty for the default style
+	style string
+}
+
+// App is the state of the interface: the requests of a configuration, their
+// results and how they are browsed. Keys and results change the state and
+// Render draws it; the program connects both to a terminal.
+type App struct {
+	// title is shown in the title bar, usually the config path
+	title string
+
+	// config holds the requests
+	config *rule.Config
+
+	// names are the request names in configuration order
+	names []string
+
+	// requests maps request names to their configuration
+	requests map[string]*rule.Request
+
+	// cursor is the index of the request under the cursor
+	cursor int
+
+	// offset is the index of the first request shown
+	offset int
+
+	// selected holds the names of the selected requests
+	selected map[string]bool
+
+	// results holds the last result of each request by request name
+	results map[string]*Result
+
+	// views holds the response view of each result by request name
+	views map[string]*responseView
+
+	// pending holds the names of requests of the current run that have not finished
+	pending map[string]bool
+
+	// variables is the extracted-variable store
+	variables map[string]string
+
+	// variablesOffset is the index of the first variable shown
+	variablesOffset int
+
+	// focus is the focused pane
+	focus int
+
+	// running tells whether a run is in progress
+	running bool
+
+	// runRequested tells whether a run was started and not yet taken by the program
+	runRequested bool
+
+	// runNames are the requests of the requested run, nil for all
+	runNames []string
+
+	// counts are the numbers of passed, failed and skipped requests of the current run
+	counts [3]int
+
+	// searching tells whether a search is being typed
+	searching bool
+
+	// input is the search being typed
+	input []rune
+
+	// query is the last search
+	query string
+
+	// message is shown in the status line
+	message string
+
+	// messageStyle is the style of the message
+	messageStyle string
+
+	// bodyHeight is the number of rows of the panes in the last rendered frame
+	bodyHeight int
+
+	// quit tells whether the user asked to leave
+	quit bool
+}
+
+// NewApp creates the state of the interface for a configuration.
+//
+// Parameters:
+//   - title: Text shown in the title bar, e.g. the config path
+//   - config: Configuration whose requests are listed
+//
+// Returns:
+//   - *App: State showing the requests, none of them run
+func NewApp(title string, config *rule.Config) *App {
+	app := &App{
+		title:     title,
+		config:    config,
+		requests:  make(map[string]*rule.Request),
+		selected:  make(map[string]bool),
+		results:   make(map[string]*Result),
+		views:     make(map[string]*responseView),
+		pending:   make(map[string]bool),
+		variables: make(map[string]string),
+		message:   "Press enter to run the request under the cursor, r to run the whole chain",
+	}
+	for i := range config.Request {
+		req := &config.Request[i]
+		app.names = append(app.names, req.Name)
+		app.requests[req.Name] = req
+	}
+	return app
+}
+
+// Quit reports whether the user asked to leave.
+//
+// Returns:
+//   - bool: True after q or Ctrl+C
+func (a *App) Quit() bool {
+	return a.quit
+}
+
+// TakeRun returns a run started by a key and not yet executed.
+//
+// Returns:
+//   - []string: Names of the requests to run, nil for the whole chain
+//   - bool: True if a run was started
+func (a *App) TakeRun() ([]string, bool) {
+	if !a.runRequested {
+		return nil, false
+	}
+	a.runRequested = false
+	return a.runNames, true
+}
+
+// AddResult shows the result of a request of the current run.
+//
+// Parameters:
+//   - result: Result reported by the runner
+func (a *App) AddResult(result *Result) {
+	name := a.baseName(result.Name)
+	a.results[name] = result
+	a.views[name] = newResponseView(result)
+	delete(a.pending, name)
+
+	switch {
+	case result.Skipped:
+		a.counts[2]++
+	case result.Err != nil:
+		a.counts[1]++
+	default:
+		a.counts[0]++
+	}
+	for key, value := range result.Variables {
+		a.variables[key] = value
+	}
+}
+
+// FinishRun ends the current run.
+//
+// Parameters:
+//   - err: Error that stopped the chain, nil if it completed
+func (a *App) FinishRun(err error) {
+	a.running = false
+	a.pending = make(map[string]bool)
+
+	summary := fmt.Sprintf("%d passed, %d failed, %d skipped", a.counts[0], a.counts[1], a.counts[2])
+	switch {
+	case err != nil:
+		a.setMessage("Stopped after "+summary+": "+err.Error(), format.Red)
+	case a.counts[1] > 0:
+		a.setMessage("Done: "+summary, format.Yellow)
+	default:
+		a.setMessage("Done: "+summary, format.Green)
+	}
+}
+
+// HandleKey changes the state for a key pressed by the user.
+//
+// Parameters:
+//   - key: Key read from the terminal
+func (a *App) HandleKey(key rune) {
+	if a.searching {
+		a.handleSearchKey(key)
+		return
+	}
+
+	switch key {
+	case 'q', term.KeyCtrlC:
+		a.quit = true
+		return
+	case term.KeyTab:
+		a.focus = (a.focus + 1) % paneCount
+		return
+	case term.KeyShiftTab:
+		a.focus = (a.focus + paneCount - 1) % paneCount
+		return
+	case 'r':
+		a.startRun(nil)
+		return
+	case '/':
+		a.searching = true
+		a.input = nil
+		return
+	case 'n', 'N':
+		a.findNext(key == 'N')
+		return
+	}
+
+	switch a.focus {
+	case paneRequests:
+		a.handleRequestsKey(key)
+	case paneResponse:
+		a.handleResponseKey(key)
+	case paneVariables:
+		a.handleVariablesKey(key)
+	}
+}
+
+// handleSearchKey edits the search being typed.
+//
+// Parameters:
+//   - key: Key read from the terminal
+func (a *App) handleSearchKey(key rune) {
+	switch key {
+	case term.KeyEnter, '\n':
+		a.searching = false
+		a.query = string(a.input)
+		a.focus = paneResponse
+		a.findNext(false)
+	case term.KeyEscape, term.KeyCtrlC:
+		a.searching = false
+	case term.KeyBackspace, term.KeyCtrlH:
+		if len(a.input) > 0 {
+			a.input = a.input[:len(a.input)-1]
+		}
+	default:
+		if key < unicode.MaxRune && unicode.IsPrint(key) {
+			a.input = append(a.input, key)
+		}
+	}
+}
+
+// handleRequestsKey handles the keys of the requests pane.
+//
+// Parameters:
+//   - key: Key read from the terminal
+func (a *App) handleRequestsKey(key rune) {
+	switch key {
+	case term.KeyUp, 'k':
+		a.moveCursor(-1)
+	case term.KeyDown, 'j':
+		a.moveCursor(1)
+	case term.KeyPageUp:
+		a.moveCursor(-a.pageSize())
+	case term.KeyPageDown:
+		a.moveCursor(a.pageSize())
+	case term.KeyHome, 'g':
+		a.moveCursor(-len(a.names))
+	case term.KeyEnd, 'G':
+		a.moveCursor(len(a.names))
+	case ' ':
+		if name := a.current(); name != "" {
+			if a.selected[name] {
+				delete(a.selected, name)
+			} else {
+				a.selected[name] = true
+			}
+			a.moveCursor(1)
+		}
+	case 'a':
+		if len(a.selected) == len(a.names) {
+			a.selected = make(map[string]bool)
+		} else {
+			for _, name := range a.names {
+				a.selected[name] = true
+			}
+		}
+	case term.KeyEnter, '\n':
+		if name := a.current(); name != "" {
+			a.startRun([]string{name})
+		}
+	case 's':
+		var names []string
+		for _, name := range a.names {
+			if a.selected[name] {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			a.setMessage("No requests selected; select them with space", format.Yellow)
+			return
+		}
+		a.startRun(names)
+	}
+}
+
+// handleResponseKey handles the keys of the response pane.
+//
+// Parameters:
+//   - key: Key read from the terminal
+func (a *App) handleResponseKey(key rune) {
+	view := a.currentView()
+	if view == nil {
+		return
+	}
+
+	switch key {
+	case term.KeyUp, 'k':
+		view.cursor--
+	case term.KeyDown, 'j':
+		view.cursor++
+	case term.KeyPageUp:
+		view.cursor -= a.pageSize()
+	case term.KeyPageDown:
+		view.cursor += a.pageSize()
+	case term.KeyHome, 'g':
+		view.cursor = 0
+	case term.KeyEnd, 'G':
+		view.cursor = len(view.lines)
+	case term.KeyEnter, '\n', ' ':
+		view.toggle()
+	case 'z':
+		view.setFolded(true)
+	case 'Z':
+		view.setFolded(false)
+	case 'h':
+		view.headersFolded = !view.headersFolded
+	}
+	view.cursor = max(0, min(view.cursor, len(view.visibleLines())-1))
+}
+
+// handleVariablesKey handles the keys of the variables pane.
+//
+// Parameters:
+//   - key: Key read from the terminal
+func (a *App) handleVariablesKey(key rune) {
+	switch key {
+	case term.KeyUp, 'k':
+		a.variablesOffset--
+	case term.KeyDown, 'j':
+		a.variablesOffset++
+	case term.KeyPageUp:
+		a.variablesOffset -= a.pageSize()
+	case term.KeyPageDown:
+		a.variablesOffset += a.pageSize()
+	}
+	a.variablesOffset = max(0, min(a.variablesOffset, len(a.variables)-1))
+}
+
+// startRun requests a run of some or all requests.
+//
+// Parameters:
+//   - names: Names of the requests to run, nil for the whole chain
+func (a *App) startRun(names []string) {
+	if a.running {
+		a.setMessage("A run is in progress", format.Yellow)
+		return
+	}
+
+	a.running = true
+	a.runRequested = true
+	a.runNames = names
+	a.counts = [3]int{}
+
+	if names == nil {
+		names = a.names
+	}
+	for _, name := range names {
+		a.pending[name] = true
+	}
+	a.setMessage(fmt.Sprintf("Running %d %s…", len(names), plural(len(names), "request")), format.Cyan)
+}
+
+// findNext selects the next line of the response matching the search.
+//
+// Parameters:
+//   - backward: True to search towards the beginning
+func (a *App) findNext(backward bool) {
+	view := a.currentView()
+	if a.query == "" || view == nil {
+		return
+	}
+	if view.find(a.query, backward) {
+		a.setMessage("/"+a.query, "")
+	} else {
+		a.setMessage("Not found: "+a.query, format.Yellow)
+	}
+}
+
+// moveCursor moves the request cursor, keeping it within the list.
+//
+// Parameters:
+//   - delta: Number of requests to move by
+func (a *App) moveCursor(delta int) {
+	a.cursor = max(0, min(a.cursor+delta, len(a.names)-1))
+}
+
+// current returns the name of the request under the cursor.
+//
+// Returns:
+//   - string: Request name, empty if there are no requests
+func (a *App) current() string {
+	if a.cursor >= len(a.names) {
+		return ""
+	}
+	return a.names[a.cursor]
+}
+
+// currentView returns the response view of the request under the cursor.
+//
+// Returns:
+//   - *responseView: View, nil if the request has not been run
+func (a *App) currentView() *responseView {
+	return a.views[a.current()]
+}
+
+// pageSize returns the number of lines moved by page up and page down.
+//
+// Returns:
+//   - int: Number of rows of a pane without its title, at least 1
+func (a *App) pageSize() int {
+	return max(1, a.bodyHeight-1)
+}
+
+// baseName returns the request name of a reported name, removing the row
+// indexes of dataset iterations, e.g. "Get User[2]" becomes "Get User".
+//
+// Parameters:
+//   - name: Name reported by the chain executor
+//
+// Returns:
+//   - string: Name of the configured request, or name if none matches
+func (a *App) baseName(name string) string {
+	for base := name; ; {
+		if _, ok := a.requests[base]; ok {
+			return base
+		}
+		index := strings.LastIndexByte(base, '[')
+		if index < 0 || !strings.HasSuffix(base, "]") {
+			return name
+		}
+		base = base[:index]
+	}
+}
+
+// setMessage sets the text of the status line.
+//
+// Parameters:
+//   - message: Text to show
+//   - style: Escape code of the style, empty for the default style
+func (a *App) setMessage(message, style string) {
+	a.message = message
+	a.messageStyle = style
+}
+
+// Render draws the interface.
+//
+// Parameters:
+//   - width: Width of the terminal in columns
+//   - height: Height of the terminal in rows
+//
+// Returns:
+//   - []string: One row per terminal row, each exactly width columns wide
+func (a *App) Render(width, height int) []string {
+	if width < minWidth || height < minHeight {
+		rows := make([]string, height)
+		for i := range rows {
+			rows[i] = fit(nil, width)
+		}
+		if height > 0 {
+			rows[0] = fit([]segment{{text: "Terminal too small"}}, width)
+		}
+		return rows
+	}
+
+	a.bodyHeight = height - 3
+	left := min(maxSideWidth, width/4)
+	right := 0
+	if width >= minVariablesWidth {
+		right = min(maxSideWidth, width/4)
+	}
+	middle := width - left - 1
+	if right > 0 {
+		middle -= right + 1
+	}
+
+	requests := a.renderRequests(left, a.bodyHeight)
+	response := a.renderResponse(middle, a.bodyHeight)
+	var variables []string
+	if right > 0 {
+		variables = a.renderVariables(right, a.bodyHeight)
+	}
+
+	rows := []string{a.renderTitle(width)}
+	for i := 0; i < a.bodyHeight; i++ {
+		row := requests[i] + separator + response[i]
+		if right > 0 {
+			row += separator + variables[i]
+		}
+		rows = append(rows, row)
+	}
+	return append(rows, a.renderStatus(width), a.renderHelp(width))
+}
+
+// renderTitle draws the title bar.
+//
+// Parameters:
+//   - width: Width of the bar
+//
+// Returns:
+//   - string: Title row
+func (a *App) renderTitle(width int) string {
+	text := " jak tui │ " + a.title
+	if a.config.BaseUrl != "" {
+		text += " │ " + a.config.BaseUrl
+	}
+	return fit([]segment{{text: text, style: format.Reverse}}, width)
+}
+
+// renderPaneTitle draws the title row of a pane, highlighted when the pane has the focus.
+//
+// Parameters:
+//   - title: Title of the pane
+//   - pane: Pane the title belongs to
+//   - width: Width of the pane
+//
+// Returns:
+//   - string: Title row
+func (a *App) renderPaneTitle(title string, pane, width int) string {
+	style := format.Bold
+	if a.focus == pane {
+		style = format.Bold + format.Reverse
+	}
+	return fit([]segment{{text: " " + title, style: style}}, width)
+}
+
+// renderRequests draws the requests pane.
+//
+// Parameters:
+//   - width: Width of the pane
+//   - height: Height of the pane
+//
+// Returns:
+//   - []string: Rows of the pane
+func (a *App) renderRequests(width, height int) []string {
+	title := fmt.Sprintf("Requests (%d)", len(a.names))
+	if len(a.selected) > 0 {
+		title = fmt.Sprintf("Requests (%d, %d selected)", len(a.names), len(a.selected))
+	}
+	rows := []string{a.renderPaneTitle(title, paneRequests, width)}
+
+	a.offset = scrollOffset(a.offset, a.cursor, height-1)
+	for i := a.offset; i < len(a.names) && len(rows) < height; i++ {
+		name := a.names[i]
+
+		check := "[ ] "
+		if a.selected[name] {
+			check = "[x] "
+		}
+		status, statusStyle := a.requestStatus(name)
+		segments := []segment{
+			{text: check},
+			{text: status, style: statusStyle},
+			{text: fmt.Sprintf(" %-6s %s", a.requests[name].Method, name)},
+		}
+		if i == a.cursor {
+			highlight := format.Bold
+			if a.focus == paneRequests {
+				highlight = format.Reverse
+			}
+			for j := range segments {
+				segments[j].style = highlight + segments[j].style
+			}
+		}
+		rows = append(rows, fit(segments, width))
+	}
+	return fill(rows, width, height)
+}
+
+// requestStatus returns the status shown in front of a request.
+//
+// Parameters:
+//   - name: Request name
+//
+// Returns:
+//   - string: Three-character status: code, ERR, SKP, an ellipsis while pending, or blank
+//   - string: Escape code of the style of the status
+func (a *App) requestStatus(name string) (string, string) {
+	if a.pending[name] {
+		return " … ", format.Cyan
+	}
+	result, ok := a.results[name]
+	switch {
+	case !ok:
+		return "   ", ""
+	case result.Skipped:
+		return "SKP", format.Yellow
+	case result.StatusCode > 0:
+		style := lineStyles[statusStyle(result.StatusCode)]
+		if result.Err != nil {
+			style = format.Red
+		}
+		return fmt.Sprintf("%3d", result.StatusCode), style
+	default:
+		return "ERR", format.Red
+	}
+}
+
+// renderResponse draws the response pane for the request under the cursor.
+//
+// Parameters:
+//   - width: Width of the pane
+//   - height: Height of the pane
+//
+// Returns:
+//   - []string: Rows of the pane
+func (a *App) renderResponse(width, height int) []string {
+	name := a.current()
+	rows := []string{a.renderPaneTitle("Response "+name, paneResponse, width)}
+
+	view := a.views[name]
+	if view == nil {
+		for _, text := range a.requestSummary(name) {
+			rows = append(rows, fit([]segment{{text: " " + text}}, width))
+		}
+		return fill(rows, width, height)
+	}
+
+	lines := view.visibleLines()
+	view.cursor = max(0, min(view.cursor, len(lines)-1))
+	view.offset = scrollOffset(view.offset, view.cursor, height-1)
+	for i := view.offset; i < len(lines) && len(rows) < height; i++ {
+		style := lineStyles[lines[i].style]
+		if i == view.cursor && a.focus == paneResponse {
+			style = format.Reverse + style
+		}
+		rows = append(rows, fitHighlighted(" "+lines[i].display(), style, a.query, width))
+	}
+	return fill(rows, width, height)
+}
+
+// requestSummary describes a request that has not been run.
+//
+// Parameters:
+//   - name: Request name
+//
+// Returns:
+//   - []string: Lines describing the request
+func (a *App) requestSummary(name string) []string {
+	req, ok := a.requests[name]
+	if !ok {
+		return nil
+	}
+
+	lines := []string{req.Method + " " + req.Path}
+	if req.DependsOn != "" {
+		lines = append(lines, "depends on: "+req.DependsOn)
+	}
+	for _, key := range sortedKeys(req.Extract) {
+		lines = append(lines, fmt.Sprintf("extract: %s = %s", key, req.Extract[key]))
+	}
+	if a.pending[name] {
+		return append(lines, "", "Running…")
+	}
+	return append(lines, "", "Not run yet; press enter to run it")
+}
+
+// renderVariables draws the extracted-variable store.
+//
+// Parameters:
+//   - width: Width of the pane
+//   - height: Height of the pane
+//
+// Returns:
+//   - []string: Rows of the pane
+func (a *App) renderVariables(width, height int) []string {
+	rows := []string{a.renderPaneTitle(fmt.Sprintf("Variables (%d)", len(a.variables)), paneVariables, width)}
+
+	names := sortedKeys(a.variables)
+	a.variablesOffset = max(0, min(a.variablesOffset, len(names)-1))
+	for i := a.variablesOffset; i < len(names) && len(rows) < height; i++ {
+		rows = append(rows, fit([]segment{
+			{text: " " + names[i], style: format.Cyan},
+			{text: " = " + a.variables[names[i]]},
+		}, width))
+	}
+	return fill(rows, width, height)
+}
+
+// renderStatus draws the status line, or the search being typed.
+//
+// Parameters:
+//   - width: Width of the line
+//
+// Returns:
+//   - string: Status row
+func (a *App) renderStatus(width int) string {
+	if a.searching {
+		return fit([]segment{{text: "/" + string(a.input)}, {text: " ", style: format.Reverse}}, width)
+	}
+	return fit([]segment{{text: a.message, style: a.messageStyle}}, width)
+}
+
+// renderHelp draws the keys of the focused pane.
+//
+// Parameters:
+//   - width: Width of the line
+//
+// Returns:
+//   - string: Help row
+func (a *App) renderHelp(width int) string {
+	return fit([]segment{{text: helpTexts[a.focus], style: format.Dim}}, width)
+}
+
+// fit draws segments in exactly width columns, cutting off the text that does
+// not fit and padding the rest with spaces.
+//
+// Parameters:
+//   - segments: Segments of the row
+//   - width: Width of the row
+//
+// Returns:
+//   - string: Row with escape codes
+func fit(segments []segment, width int) string {
+	var b strings.Builder
+	used := 0
+	for _, seg := range segments {
+		runes := []rune(seg.text)
+		if used+len(runes) > width {
+			runes = runes[:max(0, width-used-1)]
+			runes = append(runes, '…')
+		}
+		if len(runes) == 0 {
+			continue
+		}
+		if seg.style != "" {
+			b.WriteString(seg.style + string(runes) + format.Reset)
+		} else {
+			b.WriteString(string(runes))
+		}
+		used += len(runes)
+		if used >= width {
+			break
+		}
+	}
+	b.WriteString(strings.Repeat(" ", max(0, width-used)))
+	return b.String()
+}
+
+// fitHighlighted draws a line in exactly width columns, highlighting the
+// occurrences of a search in reverse video.
+//
+// Parameters:
+//   - text: Text of the line
+//   - style: Escape code of the style of the line
+//   - query: Search to highlight, case-insensitive; empty for none
+//   - width: Width of the row
+//
+// Returns:
+//   - string: Row with escape codes
+func fitHighlighted(text, style, query string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		runes = append(runes[:max(0, width-1)], '…')
+	}
+	text = string(runes) + strings.Repeat(" ", max(0, width-len(runes)))
+
+	lower := strings.ToLower(text)
+	if query == "" || len(lower) != len(text) {
+		return fit([]segment{{text: text, style: style}}, width)
+	}
+	query = strings.ToLower(query)
+
+	var segments []segment
+	for start := 0; start < len(text); {
+		index := strings.Index(lower[start:], query)
+		if index < 0 {
+			segments = append(segments, segment{text: text[start:], style: style})
+			break
+		}
+		if index > 0 {
+			segments = append(segments, segment{text: text[start : start+index], style: style})
+		}
+		end := start + index + len(query)
+		segments = append(segments, segment{text: text[start+index : end], style: style + format.Reverse})
+		start = end
+	}
+	return fit(segments, width)
+}
+
+// fill pads the rows of a pane with empty rows up to its height.
+//
+// Parameters:
+//   - rows: Rows drawn so far
+//   - width: Width of the pane
+//   - height: Height of the pane
+//
+// Returns:
+//   - []string: Exactly height rows
+func fill(rows []string, width, height int) []string {
+	for len(rows) < height {
+		rows = append(rows, strings.Repeat(" ", width))
+	}
+	return rows[:height]
+}
+
+// scrollOffset returns the first line shown so that the cursor is visible.
+//
+// Parameters:
+//   - offset: First line shown so far
+//   - cursor: Index of the line under the cursor
+//   - height: Number of lines shown
+//
+// Returns:
+//   - int: New first line shown
+func scrollOffset(offset, cursor, height int) int {
+	if height <= 0 {
+		return cursor
+	}
+	if cursor < offset {
+		return cursor
+	}
+	if cursor >= offset+height {
+		return cursor - height + 1
+	}
+	return offset
+}
+
+// formatDuration formats the duration of a request for display.
+//
+// Parameters:
+//   - duration: Duration to format
+//
+// Returns:
+//   - string: Duration rounded to milliseconds
+func formatDuration(duration time.Duration) string {
+	return duration.Round(time.Millisecond).String()
+}
+
+// plural returns a noun in plural form unless the count is one.
+//
+// Parameters:
+//   - count: Number of things
+//   - noun: Noun in singular form
+//
+// Returns:
+//   - string: Noun, with an s appended unless count is one
+func plural(count int, noun string) string {
+	if count == 1 {
+		return noun
+	}
+	return noun + "s"
+}
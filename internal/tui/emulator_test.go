@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// emulator is a headless terminal for tests. It interprets the escape
+// sequences written by the program into a grid of characters, so tests can
+// assert what a user would see.
+type emulator struct {
+	// mu guards the screen, written by the program and read by the test
+	mu sync.Mutex
+
+	// width and height are the size of the screen
+	width  int
+	height int
+
+	// grid holds the characters of the screen
+	grid [][]rune
+
+	// row and col are the cursor position
+	row int
+	col int
+
+	// alternate tells whether the alternate screen is active
+	alternate bool
+
+	// cursorHidden tells whether the cursor is hidden
+	cursorHidden bool
+
+	// pending holds an incomplete escape sequence of the last write
+	pending []rune
+}
+
+// newEmulator creates a blank screen.
+func newEmulator(width, height int) *emulator {
+	e := &emulator{width: width, height: height}
+	e.clear()
+	return e
+}
+
+// clear blanks the whole screen.
+func (e *emulator) clear() {
+	e.grid = make([][]rune, e.height)
+	for i := range e.grid {
+		e.grid[i] = []rune(strings.Repeat(" ", e.width))
+	}
+}
+
+// Write interprets output of the program.
+func (e *emulator) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	input := append(e.pending, []rune(string(p))...)
+	e.pending = nil
+	for i := 0; i < len(input); i++ {
+		switch r := input[i]; r {
+		case '\x1b':
+			end := e.sequenceEnd(input, i)
+			if end < 0 {
+				e.pending = append([]rune(nil), input[i:]...)
+				return len(p), nil
+			}
+			e.control(string(input[i+2:end]), input[end])
+			i = end
+		case '\r':
+			e.col = 0
+		case '\n':
+			e.row = min(e.row+1, e.height-1)
+		default:
+			if e.col < e.width {
+				e.grid[e.row][e.col] = r
+				e.col++
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// sequenceEnd returns the index of the final character of the control sequence starting at start.
+func (e *emulator) sequenceEnd(input []rune, start int) int {
+	if start+1 >= len(input) {
+		return -1
+	}
+	for i := start + 2; i < len(input); i++ {
+		if input[i] >= '@' && input[i] <= '~' {
+			return i
+		}
+	}
+	return -1
+}
+
+// control applies a control sequence with its parameters and final character.
+func (e *emulator) control(params string, final rune) {
+	switch {
+	case params == "?1049" && final == 'h':
+		e.alternate = true
+		e.clear()
+	case params == "?1049" && final == 'l':
+		e.alternate = false
+	case params == "?25":
+		e.cursorHidden = final == 'l'
+	case final == 'H':
+		e.row, e.col = 0, 0
+		if row, col, ok := strings.Cut(params, ";"); ok {
+			r, _ := strconv.Atoi(row)
+			c, _ := strconv.Atoi(col)
+			e.row, e.col = max(0, min(r-1, e.height-1)), max(0, min(c-1, e.width-1))
+		}
+	case final == 'K':
+		for col := e.col; col < e.width; col++ {
+			e.grid[e.row][col] = ' '
+		}
+	case final == 'J' && params == "2":
+		e.clear()
+	}
+	// Other sequences, such as styles, do not change the characters
+}
+
+// screen returns the rows of the screen without trailing spaces.
+func (e *emulator) screen() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rows := make([]string, len(e.grid))
+	for i, row := range e.grid {
+		rows[i] = strings.TrimRight(string(row), " ")
+	}
+	return rows
+}
+
+// text returns the screen as a single string.
+func (e *emulator) text() string {
+	return strings.Join(e.screen(), "\n")
+}
+
+// state returns whether the alternate screen is active and the cursor hidden.
+func (e *emulator) state() (bool, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.alternate, e.cursorHidden
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ymatsukawa/jak/internal/term"
+)
+
+// Escape sequences controlling the terminal.
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	cursorHome  = "\x1b[H"
+	clearLine   = "\x1b[K"
+)
+
+// Default terminal size used when the size cannot be determined.
+const (
+	DefaultWidth  = 80
+	DefaultHeight = 24
+)
+
+// Options attach a program to a terminal.
+type Options struct {
+	// In is the terminal input; a terminal is put into raw mode
+	In io.Reader
+
+	// Out receives the frames
+	Out io.Writer
+
+	// Width and Height fix the size of the terminal; zero asks the terminal for its size
+	Width  int
+	Height int
+}
+
+// event is a message of a run to the program loop.
+type event struct {
+	// result is the result of a request, nil when the run finished
+	result *Result
+
+	// err is the error that stopped the run
+	err error
+}
+
+// program connects the state of the interface to a terminal.
+type program struct {
+	// app is the state of the interface
+	app *App
+
+	// runner executes the requests
+	runner *Runner
+
+	// opts attach the program to the terminal
+	opts Options
+
+	// width and height are the size of the terminal
+	width  int
+	height int
+}
+
+// Run shows the interface until the user leaves it.
+// Keys are read from the input and runs execute in the background, so the
+// interface stays responsive while requests are in flight.
+//
+// Parameters:
+//   - ctx: Context for cancellation; cancelling it leaves the interface
+//   - app: State of the interface
+//   - runner: Runner executing the requests of the configuration
+//   - opts: Terminal input, output and size
+//
+// Returns:
+//   - error: Error of the terminal input or output
+func Run(ctx context.Context, app *App, runner *Runner, opts Options) error {
+	p := &program{app: app, runner: runner, opts: opts}
+
+	if file, ok := opts.In.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		state, err := term.MakeRaw(int(file.Fd()))
+		if err != nil {
+			return err
+		}
+		defer term.Restore(int(file.Fd()), state)
+	}
+	p.resize()
+
+	io.WriteString(opts.Out, enterScreen)
+	defer io.WriteString(opts.Out, leaveScreen)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keys := make(chan rune)
+	readErr := make(chan error, 1)
+	go readKeys(ctx, bufio.NewReader(opts.In), keys, readErr)
+
+	events := make(chan event)
+	resized, stopResize := notifyResize()
+	defer stopResize()
+
+	if err := p.draw(); err != nil {
+		return err
+	}
+	for !app.Quit() {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case key := <-keys:
+			app.HandleKey(key)
+		case ev := <-events:
+			if ev.result != nil {
+				app.AddResult(ev.result)
+			} else {
+				app.FinishRun(ev.err)
+			}
+		case <-resized:
+			p.resize()
+		}
+
+		if names, ok := app.TakeRun(); ok {
+			go p.run(ctx, names, events)
+		}
+		if err := p.draw(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run executes requests and sends their results to the program loop.
+//
+// Parameters:
+//   - ctx: Context cancelled when the program ends
+//   - names: Names of the requests to run, nil for the whole chain
+//   - events: Channel of the program loop
+func (p *program) run(ctx context.Context, names []string, events chan<- event) {
+	send := func(ev event) {
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+		}
+	}
+
+	err := p.runner.Run(ctx, names, func(result *Result) {
+		send(event{result: result})
+	})
+	send(event{err: err})
+}
+
+// resize updates the size of the terminal.
+func (p *program) resize() {
+	p.width, p.height = p.opts.Width, p.opts.Height
+	if p.width > 0 && p.height > 0 {
+		return
+	}
+
+	p.width, p.height = DefaultWidth, DefaultHeight
+	if file, ok := p.opts.Out.(*os.File); ok {
+		if width, height, err := term.Size(int(file.Fd())); err == nil && width > 0 && height > 0 {
+			p.width, p.height = width, height
+		}
+	}
+}
+
+// draw writes a frame. Every row is redrawn, so output written to the
+// terminal by others, such as warnings, is overwritten.
+//
+// Returns:
+//   - error: Error writing to the terminal
+func (p *program) draw() error {
+	rows := p.app.Render(p.width, p.height)
+
+	var b strings.Builder
+	b.WriteString(cursorHome)
+	for i, row := range rows {
+		b.WriteString(row)
+		b.WriteString(clearLine)
+		if i < len(rows)-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	_, err := io.WriteString(p.opts.Out, b.String())
+	return err
+}
+
+// readKeys reads keys until the input ends or the context is cancelled.
+//
+// Parameters:
+//   - ctx: Context cancelled when the program ends
+//   - reader: Reader of the terminal input
+//   - keys: Channel receiving the keys
+//   - readErr: Channel receiving the error that ended the input
+func readKeys(ctx context.Context, reader *bufio.Reader, keys chan<- rune, readErr chan<- error) {
+	for {
+		key, err := term.ReadKey(reader)
+		if err != nil {
+			readErr <- err
+			return
+		}
+		select {
+		case keys <- key:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+)
+
+// terminal drives a program running on a headless terminal
+type terminal struct {
+	t      *testing.T
+	screen *emulator
+	keys   *io.PipeWriter
+	done   chan error
+}
+
+// startTerminal runs the interface for chainConfig on a 120x30 headless terminal
+func startTerminal(t *testing.T) (*terminal, func() int) {
+	t.Helper()
+
+	server, logins := newChainServer(t)
+	config := loadChainConfig(t, server)
+
+	input, keys := io.Pipe()
+	term := &terminal{t: t, screen: newEmulator(120, 30), keys: keys, done: make(chan error, 1)}
+	go func() {
+		app := NewApp("chain.toml", config)
+		runner := NewRunner(config, jakhttp.NewClient())
+		term.done <- Run(context.Background(), app, runner, Options{In: input, Out: term.screen, Width: 120, Height: 30})
+	}()
+	t.Cleanup(func() { keys.Close() })
+
+	term.waitFor("Requests (3)")
+	return term, logins
+}
+
+// press sends keys to the program
+func (term *terminal) press(keys string) {
+	term.t.Helper()
+	_, err := io.WriteString(term.keys, keys)
+	require.NoError(term.t, err)
+}
+
+// waitFor waits until the screen shows all texts
+func (term *terminal) waitFor(texts ...string) {
+	term.t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		screen := term.screen.text()
+		missing := ""
+		for _, text := range texts {
+			if !strings.Contains(screen, text) {
+				missing = text
+				break
+			}
+		}
+		if missing == "" {
+			return
+		}
+		if time.Now().After(deadline) {
+			term.t.Fatalf("screen does not show %q:\n%s", missing, screen)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// exit waits for the program to end
+func (term *terminal) exit() error {
+	term.t.Helper()
+	select {
+	case err := <-term.done:
+		return err
+	case <-time.After(5 * time.Second):
+		term.t.Fatal("program did not end")
+		return nil
+	}
+}
+
+func TestRun_BrowseAndRun(t *testing.T) {
+	term, logins := startTerminal(t)
+
+	alternate, hidden := term.screen.state()
+	assert.True(t, alternate)
+	assert.True(t, hidden)
+	term.waitFor("jak tui │ chain.toml", "POST   Login", "GET    Profile", "GET    Broken", "Not run yet", "Variables (0)")
+
+	// Run the request under the cursor
+	term.press("\r")
+	term.waitFor("200 POST   Login", "200 OK", "Variables (1)", "token = t-1", "Done: 1 passed, 0 failed, 0 skipped")
+
+	// The next request uses the token extracted by the previous run
+	term.press("j\r")
+	term.waitFor("200 GET    Profile", `"name": "Alice"`, `"city": "Tokyo"`, "city = Tokyo", "▸ Headers (3)")
+
+	// Fold the body and search in it, which expands the matching object
+	term.press("\tz")
+	term.waitFor(`▸ "roles": […] 2 items`, `▸ "address": {…} 2 keys`)
+	term.press("/zip\r")
+	term.waitFor(`"zip": "100-0001"`, "/zip")
+	assert.Contains(t, term.screen.text(), `▸ "roles": […] 2 items`)
+
+	// Show the headers
+	term.press("h")
+	term.waitFor("▾ Headers (3)", "Content-Type: application/json")
+
+	// Run the whole chain from the response pane
+	term.press("r")
+	term.waitFor("500 GET    Broken", "Done: 3 passed, 0 failed, 0 skipped")
+
+	// Select the login and run the selection
+	term.press("\x1b[Zgg ")
+	term.waitFor("Requests (3, 1 selected)", "[x] 200 POST   Login")
+	term.press("s")
+	term.waitFor("Done: 1 passed, 0 failed, 0 skipped")
+	assert.Equal(t, 3, logins())
+
+	term.press("q")
+	require.NoError(t, term.exit())
+	alternate, hidden = term.screen.state()
+	assert.False(t, alternate)
+	assert.False(t, hidden)
+}
+
+func TestRun_EndOfInput(t *testing.T) {
+	term, logins := startTerminal(t)
+
+	term.keys.Close()
+	require.NoError(t, term.exit())
+	assert.Equal(t, 0, logins())
+}
@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package tui
+
+import "os"
+
+// notifyResize reports no resizes on platforms without SIGWINCH.
+//
+// Returns:
+//   - <-chan os.Signal: Channel that never receives a value
+//   - func(): Function doing nothing
+func notifyResize() (<-chan os.Signal, func()) {
+	return nil, func() {}
+}
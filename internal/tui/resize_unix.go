@@ -0,0 +1,20 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package tui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize reports changes of the terminal size.
+//
+// Returns:
+//   - <-chan os.Signal: Channel receiving a value when the terminal is resized
+//   - func(): Function stopping the notifications
+func notifyResize() (<-chan os.Signal, func()) {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	return resized, func() { signal.Stop(resized) }
+}
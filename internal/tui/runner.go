@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	nethttp "net/http"
+	"sync"
+	"time"
+
+	"github.com/ymatsukawa/jak/internal/chain"
+	"github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// Result is the outcome of a request executed from the interface.
+type Result struct {
+	// Name is the name of the request as reported by the chain executor, e.g. "Get User[0]" for a dataset row
+	Name string
+
+	// Method is the HTTP method
+	Method string
+
+	// URL is the full URL of the request, with variables substituted once it was sent
+	URL string
+
+	// StatusCode is the status code, 0 if no response was received
+	StatusCode int
+
+	// Err is the error of a failed or skipped request
+	Err error
+
+	// Skipped tells whether the request was skipped by its conditions
+	Skipped bool
+
+	// Duration is the time taken by the request
+	Duration time.Duration
+
+	// Header contains the response headers, nil if no response was received
+	Header nethttp.Header
+
+	// Body is the response body
+	Body []byte
+
+	// Variables are the variables extracted from the response
+	Variables map[string]string
+}
+
+// Runner executes the requests of a configuration with the chain executor.
+// Variables extracted by earlier runs are kept, so a single request can be
+// run after the requests it depends on.
+type Runner struct {
+	// config is the configuration of the requests
+	config *rule.Config
+
+	// client performs the requests and keeps the last response
+	client *captureClient
+
+	// variables is the variable store shared by all runs
+	variables *chain.DefaultVariableResolver
+
+	// timeout limits the duration of a run
+	timeout time.Duration
+}
+
+// NewRunner creates a runner for a configuration.
+//
+// Parameters:
+//   - config: Validated configuration
+//   - client: Client performing the requests
+//
+// Returns:
+//   - *Runner: Runner ready to execute requests
+func NewRunner(config *rule.Config, client http.Client) *Runner {
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = time.Duration(rule.DefaultTimeout) * time.Second
+	}
+
+	return &Runner{
+		config:    config,
+		client:    &captureClient{client: client},
+		variables: chain.NewVariableResolver(),
+		timeout:   timeout + config.PollTimeout(),
+	}
+}
+
+// Run executes requests in dependency order, reporting each result as soon as it is known.
+// Dependencies on requests that are not run are dropped; their variables come from earlier runs.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - names: Names of the requests to run, nil for the whole chain
+//   - report: Function receiving each result
+//
+// Returns:
+//   - error: Error that stopped the chain
+func (r *Runner) Run(ctx context.Context, names []string, report func(*Result)) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	executor := chain.NewChainExecutor().WithClient(r.client).WithVariables(r.variables)
+	executor.SetResultCollector(func(name, method, url string, statusCode int, err error, duration time.Duration, timing *http.Timing, variables map[string]string) {
+		result := &Result{
+			Name:       name,
+			Method:     method,
+			URL:        url,
+			StatusCode: statusCode,
+			Err:        err,
+			Skipped:    errors.Is(err, se.ErrRequestSkipped),
+			Duration:   duration,
+			Variables:  variables,
+		}
+		if captured := r.client.take(); captured != nil && !result.Skipped {
+			result.URL = captured.url
+			result.Header = captured.header
+			result.Body = captured.body
+			if result.StatusCode == 0 {
+				result.StatusCode = captured.statusCode
+			}
+		}
+		report(result)
+	})
+
+	return executor.Execute(ctx, subset(r.config, names))
+}
+
+// subset returns a copy of a configuration with only some of its requests.
+// Requests keep their configuration order; dependencies on left out requests are dropped.
+// Dataset rows run one after another so results arrive in order.
+//
+// Parameters:
+//   - config: Configuration to copy
+//   - names: Names of the requests to keep, nil for all
+//
+// Returns:
+//   - *rule.Config: Configuration to execute
+func subset(config *rule.Config, names []string) *rule.Config {
+	copied := *config
+	copied.Concurrency = false
+	if names == nil {
+		return &copied
+	}
+
+	keep := make(map[string]bool, len(names))
+	for _, name := range names {
+		keep[name] = true
+	}
+
+	copied.Request = nil
+	for _, req := range config.Request {
+		if !keep[req.Name] {
+			continue
+		}
+		if req.DependsOn != "" && !keep[req.DependsOn] {
+			req.DependsOn = ""
+		}
+		copied.Request = append(copied.Request, req)
+	}
+	return &copied
+}
+
+// capturedResponse is the part of a response kept for display.
+type capturedResponse struct {
+	// url is the URL the request was sent to
+	url string
+
+	// statusCode is the status code
+	statusCode int
+
+	// header contains the response headers
+	header nethttp.Header
+
+	// body is the response body
+	body []byte
+}
+
+// captureClient is a client keeping the last response performed by another client,
+// so the result collector can show the response of the request it reports.
+type captureClient struct {
+	// client performs the requests
+	client http.Client
+
+	// mu guards last
+	mu sync.Mutex
+
+	// last is the last response not yet taken
+	last *capturedResponse
+}
+
+// Do performs a request and keeps a copy of the response.
+//
+// Parameters:
+//   - req: Request to execute
+//
+// Returns:
+//   - *http.Response: Response with a body that can still be read
+//   - error: Any error encountered during execution or while reading the response
+func (c *captureClient) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, se.WrapError(err, "failed to read response body")
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last = &capturedResponse{url: req.URL, statusCode: resp.StatusCode, header: resp.Header, body: body}
+	return resp, nil
+}
+
+// SetTimeout sets the timeout of the underlying client.
+//
+// Parameters:
+//   - timeout: Timeout duration
+func (c *captureClient) SetTimeout(timeout time.Duration) {
+	c.client.SetTimeout(timeout)
+}
+
+// take returns the last response and forgets it.
+//
+// Returns:
+//   - *capturedResponse: Last response, nil if none was received since the last call
+func (c *captureClient) take() *capturedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := c.last
+	c.last = nil
+	return last
+}
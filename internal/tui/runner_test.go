@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jakhttp "github.com/ymatsukawa/jak/internal/http"
+	"github.com/ymatsukawa/jak/internal/rule"
+)
+
+// chainConfig is a chain of a login, a request using its token and a failing request
+const chainConfig = `
+base_url = "%s"
+timeout = 5
+ignore_fail = true
+
+[[request]]
+name = "Login"
+method = "POST"
+path = "/login"
+json_body = '{"user": "alice"}'
+extract = { token = "token" }
+
+[[request]]
+name = "Profile"
+method = "GET"
+path = "/me"
+query = { user = "${token}" }
+headers = ["Authorization: Bearer ${token}"]
+depends_on = "Login"
+extract = { city = "address.city" }
+
+[[request]]
+name = "Broken"
+method = "GET"
+path = "/broken"
+`
+
+// newChainServer returns a server for chainConfig counting the logins
+func newChainServer(t *testing.T) (*httptest.Server, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			mu.Lock()
+			logins++
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"token": "t-1"}`)
+		case "/me":
+			if r.Header.Get("Authorization") != "Bearer t-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name": "Alice", "roles": ["admin", "dev"], "address": {"city": "Tokyo", "zip": "100-0001"}}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "boom")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return logins
+	}
+}
+
+// loadChainConfig writes chainConfig for a server and loads it
+func loadChainConfig(t *testing.T, server *httptest.Server) *rule.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "chain.toml")
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(chainConfig, server.URL)), 0o644))
+	config, err := rule.LoadConfig(path)
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	return config
+}
+
+// runAndCollect runs requests and returns their results by name
+func runAndCollect(t *testing.T, runner *Runner, names []string) map[string]*Result {
+	t.Helper()
+
+	results := make(map[string]*Result)
+	err := runner.Run(context.Background(), names, func(result *Result) {
+		results[result.Name] = result
+	})
+	require.NoError(t, err)
+	return results
+}
+
+func TestRunner_RunAll(t *testing.T) {
+	server, _ := newChainServer(t)
+	runner := NewRunner(loadChainConfig(t, server), jakhttp.NewClient())
+
+	results := runAndCollect(t, runner, nil)
+	require.Len(t, results, 3)
+
+	login := results["Login"]
+	assert.Equal(t, "POST", login.Method)
+	assert.Equal(t, server.URL+"/login", login.URL)
+	assert.Equal(t, 200, login.StatusCode)
+	assert.Equal(t, map[string]string{"token": "t-1"}, login.Variables)
+
+	profile := results["Profile"]
+	assert.Equal(t, 200, profile.StatusCode)
+	assert.Equal(t, server.URL+"/me?user=t-1", profile.URL)
+	assert.Equal(t, "application/json", profile.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"name": "Alice", "roles": ["admin", "dev"], "address": {"city": "Tokyo", "zip": "100-0001"}}`, string(profile.Body))
+	assert.Equal(t, map[string]string{"city": "Tokyo"}, profile.Variables)
+
+	broken := results["Broken"]
+	assert.Equal(t, 500, broken.StatusCode)
+	assert.Equal(t, "boom", string(broken.Body))
+}
+
+func TestRunner_KeepsVariablesBetweenRuns(t *testing.T) {
+	server, logins := newChainServer(t)
+	runner := NewRunner(loadChainConfig(t, server), jakhttp.NewClient())
+
+	// Without a login the token is not defined
+	results := runAndCollect(t, runner, []string{"Profile"})
+	require.Contains(t, results, "Profile")
+	assert.NotEqual(t, 200, results["Profile"].StatusCode)
+
+	runAndCollect(t, runner, []string{"Login"})
+	results = runAndCollect(t, runner, []string{"Profile"})
+	require.Len(t, results, 1)
+	assert.Equal(t, 200, results["Profile"].StatusCode)
+	assert.NoError(t, results["Profile"].Err)
+	assert.Equal(t, map[string]string{"city": "Tokyo"}, results["Profile"].Variables)
+	assert.Equal(t, 1, logins())
+}
+
+func TestSubset(t *testing.T) {
+	config := &rule.Config{
+		Concurrency: true,
+		Request: []rule.Request{
+			{Name: "A", Method: "GET", Path: "/a"},
+			{Name: "B", Method: "GET", Path: "/b", DependsOn: "A"},
+			{Name: "C", Method: "GET", Path: "/c", DependsOn: "B"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		names     []string
+		wantNames []string
+		wantDeps  []string
+	}{
+		{name: "all", names: nil, wantNames: []string{"A", "B", "C"}, wantDeps: []string{"", "A", "B"}},
+		{name: "configuration order", names: []string{"C", "B"}, wantNames: []string{"B", "C"}, wantDeps: []string{"", "B"}},
+		{name: "single", names: []string{"C"}, wantNames: []string{"C"}, wantDeps: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subset := subset(config, tt.names)
+
+			var names, deps []string
+			for _, req := range subset.Request {
+				names = append(names, req.Name)
+				deps = append(deps, req.DependsOn)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantDeps, deps)
+			assert.False(t, subset.Concurrency)
+		})
+	}
+
+	// The configuration is left unchanged
+	assert.True(t, config.Concurrency)
+	assert.Equal(t, "B", config.Request[2].DependsOn)
+}
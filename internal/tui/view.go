@@ -0,0 +1,409 @@
+package tui
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Markers shown in front of foldable lines.
+const (
+	// markerOpen marks an expanded section, object or array
+	markerOpen = "▾ "
+
+	// markerFolded marks a folded section, object or array
+	markerFolded = "▸ "
+
+	// markerNone aligns lines that cannot be folded
+	markerNone = "  "
+)
+
+// Styles of the lines of the response pane.
+const (
+	styleNormal = iota
+	styleHeading
+	styleSuccess
+	styleWarning
+	styleError
+	styleKey
+)
+
+// line is a line of the response pane.
+// Lines are built for the fully expanded response; a line is shown when none of
+// the sections, objects or arrays containing it are folded.
+type line struct {
+	// text is the text of the line when it is shown expanded
+	text string
+
+	// foldedText is the text shown when fold is set, for foldable lines
+	foldedText string
+
+	// style is the style of the line
+	style int
+
+	// fold is the fold state the line toggles, nil for lines that cannot be folded
+	fold *bool
+
+	// parents are the fold states of the sections, objects and arrays containing the line
+	parents []*bool
+}
+
+// visible reports whether the line is shown.
+//
+// Returns:
+//   - bool: True if no containing section, object or array is folded
+func (l *line) visible() bool {
+	for _, folded := range l.parents {
+		if *folded {
+			return false
+		}
+	}
+	return true
+}
+
+// display returns the text shown for the line.
+//
+// Returns:
+//   - string: Folded or expanded text
+func (l *line) display() string {
+	if l.fold != nil && *l.fold {
+		return l.foldedText
+	}
+	return l.text
+}
+
+// unfold expands every section, object or array containing the line.
+func (l *line) unfold() {
+	for _, folded := range l.parents {
+		*folded = false
+	}
+}
+
+// node is a value of a JSON body.
+type node struct {
+	// key is the object key or array index of the value, empty for the root
+	key string
+
+	// value is the JSON value
+	value gjson.Result
+
+	// children are the members of an object or the elements of an array
+	children []*node
+
+	// folded hides the children of an object or array
+	folded bool
+}
+
+// parseTree parses a JSON body into a tree, keeping the order of object keys.
+//
+// Parameters:
+//   - body: Response body
+//
+// Returns:
+//   - *node: Root value, or nil if the body is not a JSON object or array
+func parseTree(body []byte) *node {
+	trimmed := strings.TrimSpace(string(body))
+	if !gjson.Valid(trimmed) || (!strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[")) {
+		return nil
+	}
+	return newNode("", gjson.Parse(trimmed))
+}
+
+// newNode creates the tree of a JSON value.
+//
+// Parameters:
+//   - key: Object key or array index of the value
+//   - value: JSON value
+//
+// Returns:
+//   - *node: Tree of the value
+func newNode(key string, value gjson.Result) *node {
+	n := &node{key: key, value: value}
+	if value.IsObject() || value.IsArray() {
+		index := 0
+		value.ForEach(func(k, child gjson.Result) bool {
+			childKey := strconv.Itoa(index)
+			if value.IsObject() {
+				childKey = strconv.Quote(k.String())
+			}
+			index++
+			n.children = append(n.children, newNode(childKey, child))
+			return true
+		})
+	}
+	return n
+}
+
+// setFolded folds or expands every object and array of the tree below the root.
+//
+// Parameters:
+//   - folded: True to fold, false to expand
+//   - root: True for the root, which stays expanded
+func (n *node) setFolded(folded bool, root bool) {
+	if len(n.children) > 0 && !root {
+		n.folded = folded
+	}
+	for _, child := range n.children {
+		child.setFolded(folded, false)
+	}
+}
+
+// appendLines appends the lines of a JSON value.
+//
+// Parameters:
+//   - lines: Lines built so far
+//   - depth: Indentation depth of the value
+//   - parents: Fold states of the containers of the value
+//
+// Returns:
+//   - []line: Lines including the value
+func (n *node) appendLines(lines []line, depth int, parents []*bool) []line {
+	indent := strings.Repeat("  ", depth)
+	prefix := ""
+	if n.key != "" {
+		prefix = n.key + ": "
+	}
+
+	if !n.value.IsObject() && !n.value.IsArray() {
+		return append(lines, line{text: indent + markerNone + prefix + n.value.Raw, parents: parents})
+	}
+
+	open, close, unit := "{", "}", "key"
+	if n.value.IsArray() {
+		open, close, unit = "[", "]", "item"
+	}
+	if len(n.children) == 0 {
+		return append(lines, line{text: indent + markerNone + prefix + open + close, parents: parents})
+	}
+	if len(n.children) != 1 {
+		unit += "s"
+	}
+
+	lines = append(lines, line{
+		text:       indent + markerOpen + prefix + open,
+		foldedText: fmt.Sprintf("%s%s%s%s…%s %d %s", indent, markerFolded, prefix, open, close, len(n.children), unit),
+		fold:       &n.folded,
+		parents:    parents,
+	})
+	inner := append(append([]*bool(nil), parents...), &n.folded)
+	for _, child := range n.children {
+		lines = child.appendLines(lines, depth+1, inner)
+	}
+	return append(lines, line{text: indent + markerNone + close, parents: inner})
+}
+
+// responseView holds the lines of a response and how far they have been browsed.
+type responseView struct {
+	// lines are the lines of the fully expanded response
+	lines []line
+
+	// headersFolded hides the response headers
+	headersFolded bool
+
+	// tree is the JSON body, nil for other bodies
+	tree *node
+
+	// cursor is the index of the selected line among the visible lines
+	cursor int
+
+	// offset is the index of the first visible line shown
+	offset int
+}
+
+// newResponseView builds the view of a result.
+// Headers start folded, so the body is visible first.
+//
+// Parameters:
+//   - result: Result of a request
+//
+// Returns:
+//   - *responseView: View of the response
+func newResponseView(result *Result) *responseView {
+	view := &responseView{headersFolded: true, tree: parseTree(result.Body)}
+	view.lines = view.build(result)
+	return view
+}
+
+// build creates the lines of a result.
+//
+// Parameters:
+//   - result: Result of a request
+//
+// Returns:
+//   - []line: Lines of the fully expanded response
+func (v *responseView) build(result *Result) []line {
+	lines := []line{{text: result.Method + " " + result.URL, style: styleHeading}}
+
+	switch {
+	case result.Skipped:
+		lines = append(lines, line{text: result.Err.Error(), style: styleWarning})
+	case result.Err != nil && result.StatusCode == 0:
+		lines = append(lines, line{text: "Error: " + result.Err.Error(), style: styleError})
+	default:
+		status := fmt.Sprintf("%d %s  %s", result.StatusCode, http.StatusText(result.StatusCode), formatDuration(result.Duration))
+		lines = append(lines, line{text: status, style: statusStyle(result.StatusCode)})
+		if result.Err != nil {
+			lines = append(lines, line{text: "Error: " + result.Err.Error(), style: styleError})
+		}
+	}
+
+	if len(result.Variables) > 0 {
+		lines = append(lines, line{})
+		lines = append(lines, line{text: "Extracted", style: styleHeading})
+		for _, name := range sortedKeys(result.Variables) {
+			lines = append(lines, line{text: markerNone + name + " = " + result.Variables[name], style: styleKey})
+		}
+	}
+
+	if result.Header == nil {
+		return lines
+	}
+
+	lines = append(lines, line{})
+	headers := []*bool{&v.headersFolded}
+	lines = append(lines, line{
+		text:       fmt.Sprintf("%sHeaders (%d)", markerOpen, len(result.Header)),
+		foldedText: fmt.Sprintf("%sHeaders (%d)", markerFolded, len(result.Header)),
+		style:      styleHeading,
+		fold:       &v.headersFolded,
+	})
+	for _, name := range sortedKeys(result.Header) {
+		for _, value := range result.Header[name] {
+			lines = append(lines, line{text: markerNone + name + ": " + value, parents: headers})
+		}
+	}
+
+	lines = append(lines, line{})
+	lines = append(lines, line{text: "Body", style: styleHeading})
+	switch {
+	case v.tree != nil:
+		lines = v.tree.appendLines(lines, 0, nil)
+	case len(result.Body) == 0:
+		lines = append(lines, line{text: markerNone + "(empty)"})
+	default:
+		text := strings.ReplaceAll(string(result.Body), "\r\n", "\n")
+		for _, bodyLine := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+			lines = append(lines, line{text: markerNone + strings.ReplaceAll(bodyLine, "\t", "    ")})
+		}
+	}
+	return lines
+}
+
+// visibleLines returns the lines that are shown.
+//
+// Returns:
+//   - []*line: Visible lines in order
+func (v *responseView) visibleLines() []*line {
+	var visible []*line
+	for i := range v.lines {
+		if v.lines[i].visible() {
+			visible = append(visible, &v.lines[i])
+		}
+	}
+	return visible
+}
+
+// toggle folds or expands the selected line.
+func (v *responseView) toggle() {
+	visible := v.visibleLines()
+	if v.cursor < len(visible) && visible[v.cursor].fold != nil {
+		*visible[v.cursor].fold = !*visible[v.cursor].fold
+	}
+}
+
+// setFolded folds or expands every object and array of the body.
+//
+// Parameters:
+//   - folded: True to fold, false to expand
+func (v *responseView) setFolded(folded bool) {
+	if v.tree != nil {
+		v.tree.setFolded(folded, true)
+	}
+	v.cursor = min(v.cursor, len(v.visibleLines())-1)
+}
+
+// find selects the next line containing a text, expanding the containers of the line.
+// The search wraps around at the end of the response.
+//
+// Parameters:
+//   - query: Text to find, case-insensitive
+//   - backward: True to search towards the beginning
+//
+// Returns:
+//   - bool: True if a line was found
+func (v *responseView) find(query string, backward bool) bool {
+	if query == "" || len(v.lines) == 0 {
+		return false
+	}
+	query = strings.ToLower(query)
+
+	// Start after the selected line, in terms of all lines
+	current := -1
+	visible := v.visibleLines()
+	if v.cursor < len(visible) {
+		for i := range v.lines {
+			if &v.lines[i] == visible[v.cursor] {
+				current = i
+				break
+			}
+		}
+	}
+
+	count := len(v.lines)
+	for step := 1; step <= count; step++ {
+		index := (current + step) % count
+		if backward {
+			index = ((current-step)%count + count) % count
+		}
+		if !strings.Contains(strings.ToLower(v.lines[index].text), query) {
+			continue
+		}
+
+		v.lines[index].unfold()
+		for i, l := range v.visibleLines() {
+			if l == &v.lines[index] {
+				v.cursor = i
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// statusStyle returns the style of a status code.
+//
+// Parameters:
+//   - statusCode: HTTP status code
+//
+// Returns:
+//   - int: Success style for 2xx, warning style for 3xx, error style otherwise
+func statusStyle(statusCode int) int {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return styleSuccess
+	case statusCode >= 300 && statusCode < 400:
+		return styleWarning
+	default:
+		return styleError
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+//
+// Parameters:
+//   - values: Map with string keys
+//
+// Returns:
+//   - []string: Sorted keys
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	se "github.com/ymatsukawa/jak/internal/sys_error"
+)
+
+// visibleTexts returns the displayed text of the visible lines of a view.
+func visibleTexts(view *responseView) []string {
+	var texts []string
+	for _, l := range view.visibleLines() {
+		texts = append(texts, l.display())
+	}
+	return texts
+}
+
+func newJSONResult(body string) *Result {
+	return &Result{
+		Method:     "GET",
+		URL:        "http://localhost/users/1",
+		StatusCode: 200,
+		Duration:   12 * time.Millisecond,
+		Header:     http.Header{"Content-Type": {"application/json"}, "X-Request-Id": {"abc"}},
+		Body:       []byte(body),
+		Variables:  map[string]string{"name": "Alice"},
+	}
+}
+
+func TestResponseView_JSONBody(t *testing.T) {
+	view := newResponseView(newJSONResult(`{"id": 1, "name": "Alice", "tags": ["a", "b"], "address": {"city": "Tokyo"}}`))
+
+	assert.Equal(t, []string{
+		"GET http://localhost/users/1",
+		"200 OK  12ms",
+		"",
+		"Extracted",
+		"  name = Alice",
+		"",
+		"▸ Headers (2)",
+		"",
+		"Body",
+		"▾ {",
+		"    \"id\": 1",
+		"    \"name\": \"Alice\"",
+		"  ▾ \"tags\": [",
+		"      0: \"a\"",
+		"      1: \"b\"",
+		"    ]",
+		"  ▾ \"address\": {",
+		"      \"city\": \"Tokyo\"",
+		"    }",
+		"  }",
+	}, visibleTexts(view))
+}
+
+func TestResponseView_Folding(t *testing.T) {
+	view := newResponseView(newJSONResult(`{"tags": ["a", "b"], "address": {"city": "Tokyo"}}`))
+
+	view.setFolded(true)
+	assert.Equal(t, []string{
+		"▾ {",
+		"  ▸ \"tags\": […] 2 items",
+		"  ▸ \"address\": {…} 1 key",
+		"  }",
+	}, visibleTexts(view)[9:])
+
+	// Expanding the headers shows them sorted by name
+	view.cursor = 6
+	view.toggle()
+	assert.Equal(t, []string{
+		"▾ Headers (2)",
+		"  Content-Type: application/json",
+		"  X-Request-Id: abc",
+	}, visibleTexts(view)[6:9])
+
+	view.setFolded(false)
+	assert.Contains(t, visibleTexts(view), "      \"city\": \"Tokyo\"")
+}
+
+func TestResponseView_Find(t *testing.T) {
+	view := newResponseView(newJSONResult(`{"items": [{"id": 1}, {"id": 2, "secret": "needle"}]}`))
+	view.setFolded(true)
+
+	assert.True(t, view.find("NEEDLE", false))
+	visible := view.visibleLines()
+	assert.Equal(t, "        \"secret\": \"needle\"", visible[view.cursor].display())
+
+	// The search continues after the selected line and wraps around
+	assert.True(t, view.find("x-request-id", false))
+	assert.Equal(t, "  X-Request-Id: abc", view.visibleLines()[view.cursor].display())
+	assert.True(t, view.find("needle", false))
+	assert.Equal(t, "        \"secret\": \"needle\"", view.visibleLines()[view.cursor].display())
+
+	assert.True(t, view.find("GET", true))
+	assert.Equal(t, 0, view.cursor)
+
+	assert.False(t, view.find("missing", false))
+}
+
+func TestResponseView_OtherResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *Result
+		want   []string
+	}{
+		{
+			name: "plain text body",
+			result: &Result{
+				Method: "GET", URL: "http://localhost/", StatusCode: 404, Duration: 3 * time.Millisecond,
+				Header: http.Header{}, Body: []byte("not\r\nfound\n"),
+			},
+			want: []string{"GET http://localhost/", "404 Not Found  3ms", "", "▸ Headers (0)", "", "Body", "  not", "  found"},
+		},
+		{
+			name: "empty body",
+			result: &Result{
+				Method: "DELETE", URL: "http://localhost/users/1", StatusCode: 204, Duration: time.Second,
+				Header: http.Header{},
+			},
+			want: []string{"DELETE http://localhost/users/1", "204 No Content  1s", "", "▸ Headers (0)", "", "Body", "  (empty)"},
+		},
+		{
+			name:   "connection error",
+			result: &Result{Method: "GET", URL: "http://localhost/", Err: errors.New("connection refused")},
+			want:   []string{"GET http://localhost/", "Error: connection refused"},
+		},
+		{
+			name:   "skipped",
+			result: &Result{Method: "GET", URL: "http://localhost/", Err: se.ErrRequestSkipped, Skipped: true},
+			want:   []string{"GET http://localhost/", se.ErrRequestSkipped.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, visibleTexts(newResponseView(tt.result)))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package watch detects changes of files by polling their modification times.
+//
+// Polling is the only detection method, on purpose. The standard library has no
+// file notification API, so notifications would need a dependency such as fsnotify
+// or inotify, kqueue and ReadDirectoryChangesW code behind build tags for each OS.
+// Notifications are also tied to the watched inode on Linux, so they stop when an
+// editor saves by writing a new file and renaming it over the old one; a notifying
+// watcher has to watch the parent directories and filter their events to cope.
+// A run watches a handful of config, data and certificate files, for which checking
+// modification time and size every DefaultInterval is cheap and behaves the same
+// on every platform.
+package watch
+
+import (
+	"context"
+	"os"
+	"sort"
+	"time"
+)
+
+// Default polling settings.
+const (
+	// DefaultInterval is the time between two checks of the watched files
+	DefaultInterval = 250 * time.Millisecond
+
+	// DefaultDebounce is how long files must stay unchanged before a change is reported,
+	// so that editors writing a file in several steps trigger a single run
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// fileState is the state of a watched file at a point in time.
+type fileState struct {
+	// exists tells whether the file exists
+	exists bool
+
+	// modTime is the modification time
+	modTime time.Time
+
+	// size is the size in bytes
+	size int64
+}
+
+// Watcher reports changes of a set of files.
+// Files are polled instead of using OS notifications, which works the same on
+// every platform and for files replaced by editors that write a new file.
+type Watcher struct {
+	// files are the watched files
+	files []string
+
+	// interval is the time between two checks
+	interval time.Duration
+
+	// debounce is how long files must stay unchanged before a change is reported
+	debounce time.Duration
+
+	// states holds the last seen state of each file
+	states map[string]fileState
+}
+
+// NewWatcher creates a watcher and records the current state of the files.
+// Missing files are watched too and reported once they are created.
+//
+// Parameters:
+//   - files: Files to watch
+//   - interval: Time between two checks, e.g. DefaultInterval
+//   - debounce: Time files must stay unchanged before a change is reported, e.g. DefaultDebounce
+//
+// Returns:
+//   - *Watcher: Watcher ready to wait for changes
+func NewWatcher(files []string, interval, debounce time.Duration) *Watcher {
+	w := &Watcher{files: files, interval: interval, debounce: debounce}
+	w.states = w.scan()
+	return w
+}
+
+// Files returns the watched files.
+//
+// Returns:
+//   - []string: Watched files
+func (w *Watcher) Files() []string {
+	return w.files
+}
+
+// Wait blocks until at least one file changed and all files stayed unchanged
+// for the debounce time.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//
+// Returns:
+//   - []string: Changed files, sorted
+//   - error: Context error if the context is done before a change
+func (w *Watcher) Wait(ctx context.Context) ([]string, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	changed := make(map[string]bool)
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		states := w.scan()
+		modified := false
+		for _, file := range w.files {
+			if states[file] != w.states[file] {
+				changed[file] = true
+				modified = true
+			}
+		}
+		w.states = states
+
+		if modified {
+			lastChange = time.Now()
+			continue
+		}
+		if len(changed) > 0 && time.Since(lastChange) >= w.debounce {
+			files := make([]string, 0, len(changed))
+			for file := range changed {
+				files = append(files, file)
+			}
+			sort.Strings(files)
+			return files, nil
+		}
+	}
+}
+
+// scan reads the current state of all watched files.
+//
+// Returns:
+//   - map[string]fileState: State of each file
+func (w *Watcher) scan() map[string]fileState {
+	states := make(map[string]fileState, len(w.files))
+	for _, file := range w.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			states[file] = fileState{}
+			continue
+		}
+		states[file] = fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+	}
+	return states
+}
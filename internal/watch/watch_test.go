@@ -0,0 +1,93 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testInterval = 10 * time.Millisecond
+	testDebounce = 50 * time.Millisecond
+)
+
+func TestWatcher_Wait(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.toml")
+	data := filepath.Join(dir, "users.csv")
+	require.NoError(t, os.WriteFile(config, []byte("a"), 0644))
+	require.NoError(t, os.WriteFile(data, []byte("id\n1\n"), 0644))
+
+	watcher := NewWatcher([]string{config, data}, testInterval, testDebounce)
+	assert.Equal(t, []string{config, data}, watcher.Files())
+
+	go func() {
+		time.Sleep(2 * testInterval)
+		os.WriteFile(data, []byte("id\n1\n2\n"), 0644)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed, err := watcher.Wait(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{data}, changed)
+}
+
+func TestWatcher_Debounce(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.toml")
+	b := filepath.Join(dir, "b.csv")
+	require.NoError(t, os.WriteFile(a, []byte("a"), 0644))
+
+	// b does not exist yet and is reported once it is created
+	watcher := NewWatcher([]string{a, b}, testInterval, testDebounce)
+
+	start := time.Now()
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(2 * testInterval)
+			os.WriteFile(a, []byte(string(rune('b'+i))), 0644)
+		}
+		os.WriteFile(b, []byte("x"), 0644)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed, err := watcher.Wait(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{a, b}, changed)
+	assert.GreaterOrEqual(t, time.Since(start), 6*testInterval+testDebounce)
+}
+
+func TestWatcher_Removed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(file, []byte("a"), 0644))
+	watcher := NewWatcher([]string{file}, testInterval, testDebounce)
+
+	require.NoError(t, os.Remove(file))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed, err := watcher.Wait(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{file}, changed)
+}
+
+func TestWatcher_Cancel(t *testing.T) {
+	watcher := NewWatcher([]string{filepath.Join(t.TempDir(), "config.toml")}, testInterval, testDebounce)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*testInterval)
+	defer cancel()
+	changed, err := watcher.Wait(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, changed)
+}